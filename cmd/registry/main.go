@@ -49,6 +49,14 @@ func main() {
 
 	log.Printf("Starting MCP Registry Application v%s (commit: %s)", Version, GitCommit)
 
+	// Set on fatal startup errors; deferred first so it runs after all other cleanup
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	var (
 		registryService service.RegistryService
 		db              database.Database
@@ -60,42 +68,147 @@ func main() {
 	// Initialize configuration
 	cfg := config.NewConfig()
 
+	// Identify this registry build in the user agent of its S3 and SQS requests, which use the configured credentials
+	aws.Configure(aws.ClientSettings{Version: Version, Profile: cfg.AWSProfile, RoleARN: cfg.AWSRoleARN})
+
 	// Create a context with timeout for database connection
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Connect to database based on DatabaseType
-	switch cfg.DatabaseType {
-	case "jsonfile":
+	// Connect to database based on DatabaseType, preferring registered custom backends
+	_, registered := database.Lookup(cfg.DatabaseType)
+	switch {
+	case registered:
+		log.Printf("Using registered %s database", cfg.DatabaseType)
+		db, err = database.Open(ctx, cfg.DatabaseType, cfg)
+		if err != nil {
+			log.Printf("Failed to initialize database: %v", err)
+			return
+		}
+	case cfg.DatabaseType == "jsonfile":
 		log.Printf("Using JSON file database at %s", cfg.JSONFilePath)
-		jsonDB, err = database.NewJSONFileDB(ctx, cfg.JSONFilePath)
+		// Merging reloaded data picks latest versions the same way publishing does
+		opts := []database.JSONFileOption{database.WithLatestPicker(service.LatestVersion)}
+		if cfg.JSONTolerantLoad {
+			opts = append(opts, database.WithTolerantLoad())
+		}
+		if cfg.JSONCompact {
+			opts = append(opts, database.WithCompactJSON())
+		}
+		if cfg.JSONWriteAheadLog {
+			opts = append(opts, database.WithWriteAheadLog())
+		}
+		if cfg.JSONLockTimeout > 0 {
+			opts = append(opts, database.WithLockTimeout(cfg.JSONLockTimeout))
+		}
+		jsonDB, err = database.NewJSONFileDB(ctx, cfg.JSONFilePath, opts...)
 		if err != nil {
 			log.Printf("Failed to initialize JSON file database: %v", err)
 			return
 		}
 		db = jsonDB
-	case "postgres":
+	case cfg.DatabaseType == "postgres":
 		log.Printf("Using PostgreSQL database")
-		db, err = database.NewPostgreSQL(ctx, cfg.DatabaseURL)
+		var opts []database.PostgreSQLOption
+		if cfg.DatabaseReplicaURL != "" {
+			log.Printf("Reading from the PostgreSQL read replica")
+			opts = append(opts, database.WithReadReplica(cfg.DatabaseReplicaURL))
+		}
+		db, err = database.NewPostgreSQL(ctx, cfg.DatabaseURL, opts...)
 		if err != nil {
 			log.Printf("Failed to connect to PostgreSQL: %v", err)
 			return
 		}
 	default:
-		log.Printf("Invalid database type: %s (must be 'postgres' or 'jsonfile')", cfg.DatabaseType)
+		log.Printf("Invalid database type: %s (must be 'postgres', 'jsonfile' or a registered backend %v)", cfg.DatabaseType, database.Registered())
 		return
 	}
 
-	// Store the database instance for later cleanup
+	// Store the database instance for later cleanup. Queries still in flight get until the shutdown
+	// deadline to finish before the connections are closed.
+	var shutdownDeadline time.Time
 	defer func() {
-		if err := db.Close(); err != nil {
+		deadline := shutdownDeadline
+		if deadline.IsZero() {
+			deadline = time.Now().Add(cfg.ShutdownTimeout)
+		}
+		closeCtx, closeCancel := context.WithDeadline(context.Background(), deadline)
+		defer closeCancel()
+		if err := database.CloseContext(closeCtx, db); err != nil {
 			log.Printf("Error closing database connection: %v", err)
 		} else {
 			log.Println("Database connection closed successfully")
 		}
 	}()
 
-	registryService = service.NewRegistryService(db, cfg)
+	// Record an audit trail of every change if configured
+	var registryOpts []service.RegistryOption
+	if cfg.AuditLog != "" {
+		auditSink, closeAuditLog, err := service.OpenAuditSink(cfg.AuditLog)
+		if err != nil {
+			log.Printf("Failed to open audit log: %v", err)
+			return
+		}
+		defer func() {
+			if err := closeAuditLog(); err != nil {
+				log.Printf("Error closing audit log: %v", err)
+			}
+		}()
+		registryOpts = append(registryOpts, service.WithAuditSink(auditSink))
+		log.Printf("Recording audit log to %s", cfg.AuditLog)
+	}
+
+	// Announce every change to downstream consumers if configured
+	if cfg.NotifySQSQueueURL != "" {
+		publisher, err := aws.NewSQSPublisher(ctx, aws.SQSPublisherConfig{
+			QueueURL:  cfg.NotifySQSQueueURL,
+			ExportURL: cfg.NotifyS3URL,
+		})
+		if err != nil {
+			log.Printf("Failed to create SQS change publisher: %v", err)
+			return
+		}
+
+		// The announced file is uploaded again before changes are announced, so it reflects them
+		var notifierOpts []service.ChangeNotifierOption
+		if cfg.NotifyS3URL != "" {
+			bucket, key, err := aws.ParseS3URL(cfg.NotifyS3URL)
+			if err != nil {
+				log.Printf("Invalid MCP_REGISTRY_NOTIFY_S3_URL: %v", err)
+				return
+			}
+			filter := service.NewCatalogFilter(cfg.CatalogStatus, cfg.CatalogLatestOnly)
+			notifierOpts = append(notifierOpts, service.WithExportRefresh(func(ctx context.Context) error {
+				_, err := registryService.PublishCatalog(ctx, filter, bucket, key)
+				return err
+			}))
+		}
+		notifier, closeNotifier := service.NewChangeNotifierSink(publisher, notifierOpts...)
+		defer func() {
+			closeCtx, closeCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer closeCancel()
+			if err := closeNotifier(closeCtx); err != nil {
+				log.Printf("Error sending pending change notifications: %v", err)
+			}
+		}()
+		registryOpts = append(registryOpts, service.WithAuditSink(notifier))
+		log.Printf("Publishing changes to SQS queue %s", cfg.NotifySQSQueueURL)
+	}
+
+	registryService = service.NewRegistryService(db, cfg, registryOpts...)
+
+	var metrics telemetry.Recorder = telemetry.NoopRecorder{}
+	shutdownTelemetry := telemetry.ShutdownFunc(func(_ context.Context) error { return nil })
+	if cfg.MetricsEnabled {
+		shutdownTelemetry, metrics = telemetry.InitMetricsOrNoop(cfg.Version, telemetry.InitMetrics)
+	} else {
+		log.Println("Metrics are disabled")
+	}
+	defer func() {
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Printf("Failed to shutdown telemetry: %v", err)
+		}
+	}()
 
 	// Import seed data if seed source is provided
 	if cfg.SeedFrom != "" {
@@ -103,32 +216,69 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
 
-		importerService := importer.NewService(registryService)
-		if err := importerService.ImportFromPath(ctx, cfg.SeedFrom); err != nil {
-			log.Printf("Failed to import seed data: %v", err)
+		importerService := importer.NewService(registryService, metrics)
+		if err := importerService.Seed(ctx, cfg.SeedFrom, cfg.SeedRequired); err != nil {
+			// Exit non-zero so orchestrators don't route traffic to an incompletely seeded instance
+			log.Printf("Failed to start: %v", err)
+			exitCode = 1
+			return
 		}
 	}
 
-	// Initialize SQS listener if enabled and using JSON file database
-	if cfg.SQSEnabled && cfg.DatabaseType == "jsonfile" {
+	// Fix any servers whose latest flags were left inconsistent, e.g. by hand-edited or imported data
+	if cfg.ReconcileLatestOnStartup {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		corrected, err := registryService.ReconcileLatest(ctx)
+		if err != nil {
+			log.Printf("Failed to reconcile latest versions: %v", err)
+		} else {
+			log.Printf("Reconciled latest versions, corrected %d", corrected)
+		}
+	}
+
+	// Initialize SQS listener if enabled
+	if cfg.SQSEnabled {
 		if cfg.SQSQueueURL == "" {
 			log.Printf("SQS is enabled but SQS_QUEUE_URL is not configured")
-		} else if jsonDB == nil {
-			log.Printf("SQS listener can only be used with JSON file database")
 		} else {
 			log.Printf("Initializing SQS listener for queue: %s", cfg.SQSQueueURL)
 
 			// Create context for SQS listener
 			sqsCtx := context.Background()
 
+			// The JSON file database serves the downloaded file directly; other databases import its records
+			var reload func(source string) error
+			if jsonDB != nil {
+				reload = func(source string) error {
+					return registryService.ReloadFile(sqsCtx, source)
+				}
+			} else {
+				log.Printf("SQS updates will be imported into the %s database via %s", cfg.DatabaseType, cfg.JSONFilePath)
+				reload = func(source string) error {
+					imported, err := registryService.ImportJSONFile(sqsCtx, cfg.JSONFilePath, source, database.UpsertOptions{
+						LatestPolicy: database.LatestPolicy(cfg.ImportLatestPolicy),
+						PickLatest:   service.LatestVersion,
+					})
+					if err != nil {
+						return err
+					}
+					log.Printf("Imported %d records from %s", imported, source)
+					return nil
+				}
+			}
+
 			sqsListener, err = aws.NewSQSListener(sqsCtx, aws.SQSListenerConfig{
-				QueueURL:       cfg.SQSQueueURL,
-				TargetFilePath: cfg.JSONFilePath,
-				ReloadCallback: func() error {
-					return jsonDB.Reload()
-				},
-				MaxMessages:     1,
+				QueueURL:        cfg.SQSQueueURL,
+				TargetFilePath:  cfg.JSONFilePath,
+				ReloadCallback:  reload,
+				ValidateFile:    database.ValidateJSONFile,
+				ResolvePrefix:   cfg.SQSResolvePrefix,
+				Concurrency:     cfg.SQSConcurrency,
+				MaxMessages:     10,
 				WaitTimeSeconds: 20,
+				Metrics:         metrics,
 			})
 			if err != nil {
 				log.Printf("Failed to initialize SQS listener: %v", err)
@@ -140,17 +290,43 @@ func main() {
 		}
 	}
 
-	shutdownTelemetry, metrics, err := telemetry.InitMetrics(cfg.Version)
+	// Scheduled jobs run until shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// Periodically publish the catalog to S3 if configured
+	if cfg.CatalogInterval > 0 {
+		bucket, key, err := aws.ParseS3URL(cfg.CatalogS3URL)
+		if err != nil {
+			log.Printf("Catalog publishing is scheduled but MCP_REGISTRY_CATALOG_S3_URL is invalid: %v", err)
+		} else {
+			filter := service.NewCatalogFilter(cfg.CatalogStatus, cfg.CatalogLatestOnly)
+			log.Printf("Publishing catalog to %s every %s", cfg.CatalogS3URL, cfg.CatalogInterval)
+			go service.RunPeriodically(jobsCtx, "catalog publish", cfg.CatalogInterval, func(ctx context.Context) error {
+				published, err := registryService.PublishCatalog(ctx, filter, bucket, key)
+				if err != nil {
+					return err
+				}
+				log.Printf("Published %d servers to catalog %s", published, cfg.CatalogS3URL)
+				return nil
+			})
+		}
+	}
+
+	// Compact the registry on the configured schedule
+	compactSchedule, err := service.ParseSchedule(cfg.CompactSchedule)
 	if err != nil {
-		log.Printf("Failed to initialize metrics: %v", err)
-		return
+		log.Printf("Compaction is not scheduled: MCP_REGISTRY_COMPACT_SCHEDULE is invalid: %v", err)
+	} else if compactSchedule != nil {
+		log.Printf("Compacting registry data on schedule %s", cfg.CompactSchedule)
+		go service.RunOnSchedule(jobsCtx, "compaction", compactSchedule, service.NewCompactionJob(registryService, metrics))
 	}
 
-	defer func() {
-		if err := shutdownTelemetry(context.Background()); err != nil {
-			log.Printf("Failed to shutdown telemetry: %v", err)
-		}
-	}()
+	// Watch for publish locks that are never released
+	if monitor, ok := db.(database.LockMonitor); ok && cfg.JSONStaleLockThreshold > 0 {
+		go service.RunPeriodically(jobsCtx, "stale lock check", cfg.JSONStaleLockThreshold,
+			service.NewStaleLockJob(monitor, cfg.JSONStaleLockThreshold, metrics))
+	}
 
 	// Prepare version information
 	versionInfo := &v0.VersionBody{
@@ -177,19 +353,17 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// Stop SQS listener if running
+	// Stop taking SQS updates, scheduled jobs and requests, within a deadline shared with closing the database.
+	// In-flight writes finish before the deferred database close.
+	var steps []shutdownStep
 	if sqsListener != nil {
-		sqsListener.Stop()
-	}
-
-	// Create context with timeout for shutdown
-	sctx, scancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer scancel()
-
-	// Gracefully shutdown the server
-	if err := server.Shutdown(sctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		steps = append(steps, shutdownStep{name: "SQS listener", stop: sqsListener.Stop})
 	}
+	steps = append(steps,
+		shutdownStep{name: "scheduled jobs", stop: func(context.Context) error { stopJobs(); return nil }},
+		shutdownStep{name: "server", stop: server.Shutdown},
+	)
+	shutdownDeadline = shutdown(cfg.ShutdownTimeout, steps...)
 
 	log.Println("Server exiting")
 }
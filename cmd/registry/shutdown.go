@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// shutdownStep stops one component during graceful shutdown
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// shutdown runs the steps in order within a deadline timeout from now, logging the ones that fail, and returns
+// the deadline so cleanup that runs afterwards, such as closing the database, can share it
+func shutdown(timeout time.Duration, steps ...shutdownStep) time.Time {
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	for _, step := range steps {
+		if err := step.stop(ctx); err != nil {
+			log.Printf("Failed to stop %s gracefully: %v", step.name, err)
+		}
+	}
+	return deadline
+}
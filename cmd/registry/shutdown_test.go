@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdown_UsesConfiguredTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+
+	var deadlines []time.Time
+	recordDeadline := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Error("shutdown step context has no deadline")
+		}
+		deadlines = append(deadlines, deadline)
+		return nil
+	}
+	var slowErr error
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		slowErr = ctx.Err()
+		return slowErr
+	}
+
+	start := time.Now()
+	deadline := shutdown(timeout,
+		shutdownStep{name: "sqs", stop: recordDeadline},
+		shutdownStep{name: "slow", stop: slow},
+		shutdownStep{name: "server", stop: recordDeadline},
+	)
+	elapsed := time.Since(start)
+
+	if got := deadline.Sub(start); got < timeout || got > timeout+50*time.Millisecond {
+		t.Errorf("deadline is %v after start, want about %v", got, timeout)
+	}
+	if len(deadlines) != 2 {
+		t.Fatalf("ran %d recording steps, want 2", len(deadlines))
+	}
+	for i, d := range deadlines {
+		if !d.Equal(deadline) {
+			t.Errorf("step %d deadline = %v, want the shared deadline %v", i, d, deadline)
+		}
+	}
+	// A step that outlasts the timeout is cut off at the deadline and the remaining steps still run
+	if !errors.Is(slowErr, context.DeadlineExceeded) {
+		t.Errorf("slow step error = %v, want %v", slowErr, context.DeadlineExceeded)
+	}
+	if elapsed < timeout || elapsed > time.Second {
+		t.Errorf("shutdown took %v, want about %v", elapsed, timeout)
+	}
+}
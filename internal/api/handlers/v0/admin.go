@@ -0,0 +1,589 @@
+package v0
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/aws"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// AdminSetStatusInput represents the input for changing a server version's status
+type AdminSetStatusInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+	ServerName    string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version       string `path:"version" doc:"URL-encoded version" example:"1.0.0"`
+	Body          struct {
+		Status string `json:"status" doc:"New status for the server version" enum:"active,deprecated,deleted"`
+		Reason string `json:"reason,omitempty" required:"false" maxLength:"500" doc:"Human-readable reason for the change, shown to users as statusReason" example:"Contains a known security vulnerability"`
+	}
+}
+
+// AdminDeprecateInput represents the input for deprecating a server version
+type AdminDeprecateInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+	ServerName    string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version       string `path:"version" doc:"URL-encoded version" example:"1.0.0"`
+	Body          struct {
+		ReplacedBy string `json:"replacedBy,omitempty" required:"false" doc:"Name of an existing server that replaces this one" example:"com.example/my-server-v2"`
+	}
+}
+
+// AdminSetLatestInput represents the input for designating a server version as the latest
+type AdminSetLatestInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+	ServerName    string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version       string `path:"version" doc:"URL-encoded version" example:"1.0.0"`
+}
+
+// AdminTransferInput represents the input for transferring a server to a new name
+type AdminTransferInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+	ServerName    string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Body          struct {
+		NewName   string `json:"newName" doc:"Name to move every version of the server to. Must not already exist." example:"org.example/my-server"`
+		Tombstone bool   `json:"tombstone,omitempty" required:"false" doc:"Leave a deprecated copy of the latest version at the old name, pointing to the new name. The copy has no remotes, which stay with the transferred server."`
+	}
+}
+
+// AdminAuthInput represents the input for admin endpoints that take no other parameters
+type AdminAuthInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+}
+
+// AdminFlushBody represents the response body of the flush endpoint
+type AdminFlushBody struct {
+	Status string `json:"status" example:"flushed" doc:"Result of the flush"`
+}
+
+// AdminReloadRequest represents the optional request body of the reload endpoint
+type AdminReloadRequest struct {
+	URL string `json:"url,omitempty" doc:"S3 URL of the registry data file. Defaults to the configured MCP_REGISTRY_S3_URL." example:"s3://my-bucket/registry.json"`
+}
+
+// AdminReloadInput represents the input for reloading registry data from S3
+type AdminReloadInput struct {
+	Authorization string              `header:"Authorization" doc:"Admin API key" required:"true"`
+	Body          *AdminReloadRequest `body:""`
+}
+
+// AdminReloadBody represents the response body of the reload endpoint
+type AdminReloadBody struct {
+	Records int `json:"records" example:"1234" doc:"Number of server records loaded"`
+}
+
+// AdminCatalogRequest represents the optional request body of the catalog endpoint
+type AdminCatalogRequest struct {
+	URL        string  `json:"url,omitempty" doc:"S3 URL to upload the catalog to. Defaults to the configured MCP_REGISTRY_CATALOG_S3_URL." example:"s3://my-bucket/catalog.json"`
+	Status     *string `json:"status,omitempty" doc:"Only publish server versions with this status, or every status when empty. Defaults to the configured MCP_REGISTRY_CATALOG_STATUS." enum:",active,deprecated,deleted"`
+	LatestOnly *bool   `json:"latestOnly,omitempty" doc:"Only publish the latest version of each server. Defaults to the configured MCP_REGISTRY_CATALOG_LATEST_ONLY."`
+}
+
+// AdminCatalogInput represents the input for publishing a catalog to S3
+type AdminCatalogInput struct {
+	Authorization string               `header:"Authorization" doc:"Admin API key" required:"true"`
+	Body          *AdminCatalogRequest `body:""`
+}
+
+// AdminCatalogBody represents the response body of the catalog endpoint
+type AdminCatalogBody struct {
+	URL     string `json:"url" example:"s3://my-bucket/catalog.json" doc:"S3 URL the catalog was uploaded to"`
+	Servers int    `json:"servers" example:"1234" doc:"Number of server versions published"`
+}
+
+// AdminSnapshotRequest represents the optional request body of the snapshot endpoint
+type AdminSnapshotRequest struct {
+	ID string `json:"id,omitempty" doc:"ID to store the snapshot under. Defaults to a digest of the snapshot's content." example:"2026-10-release"`
+}
+
+// AdminSnapshotInput represents the input for creating a snapshot
+type AdminSnapshotInput struct {
+	Authorization string                `header:"Authorization" doc:"Admin API key" required:"true"`
+	Body          *AdminSnapshotRequest `body:""`
+}
+
+// AdminSnapshotBody represents the response body of the snapshot endpoint
+type AdminSnapshotBody struct {
+	ID        string    `json:"id" example:"2026-10-release" doc:"ID the snapshot is served under at /v0/snapshots/{id}"`
+	SHA256    string    `json:"sha256" doc:"Hex SHA-256 digest of the snapshot's content"`
+	CreatedAt time.Time `json:"createdAt" doc:"When the snapshot was taken"`
+	Servers   int       `json:"servers" example:"1234" doc:"Number of server versions in the snapshot"`
+}
+
+// AdminReconcileLatestBody represents the response body of the reconcile latest endpoint
+type AdminReconcileLatestBody struct {
+	Corrected int `json:"corrected" example:"2" doc:"Number of server versions whose latest flag was corrected"`
+}
+
+// AdminCompactInput represents the input for compacting registry data
+type AdminCompactInput struct {
+	Authorization string `header:"Authorization" doc:"Admin API key" required:"true"`
+	DryRun        bool   `query:"dry_run" doc:"Report the server versions compaction would remove without removing them" required:"false"`
+}
+
+// AdminCompactBody represents the response body of the compact endpoint
+type AdminCompactBody struct {
+	DryRun  bool                        `json:"dryRun" doc:"Whether nothing was removed because the request was a dry run"`
+	Removed []database.CompactedVersion `json:"removed" doc:"Server versions removed by compaction, or that a dry run would remove"`
+}
+
+// AdminRevalidateLine is one line of the revalidate endpoint's newline-delimited JSON response. Each server version
+// that fails validation gets a line, followed by a final line with either the summary or the error that stopped
+// the scan.
+type AdminRevalidateLine struct {
+	*service.ValidationIssue
+	Summary *service.RevalidationSummary `json:"summary,omitempty" doc:"Totals, on the final line of a completed scan"`
+	Error   string                       `json:"error,omitempty" doc:"Why the scan stopped early, on the final line"`
+}
+
+// RegisterAdminEndpoints registers the admin endpoints with a custom path prefix.
+// Admin endpoints are only registered when an admin API key is configured, so they 404 otherwise.
+func RegisterAdminEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	if cfg.AdminAPIKey == "" {
+		return
+	}
+
+	// Admin operations live in their own group, which checks the admin API key before any
+	// handler input is parsed
+	admin := huma.NewGroup(api, pathPrefix+"/admin")
+	admin.UseMiddleware(adminAuthMiddleware(api, cfg.AdminAPIKey))
+
+	// Set server status endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-set-server-status" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPut,
+		Path:        "/servers/{serverName}/versions/{version}/status",
+		Summary:     "Set MCP server status",
+		Description: "Change the status of a specific version of an MCP server, optionally with a reason that is returned as statusReason (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminSetStatusInput) (*Response[apiv0.ServerResponse], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		// URL-decode the version
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		updatedServer, err := registry.SetServerStatus(ctx, serverName, version, model.Status(input.Body.Status), input.Body.Reason)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, databaseError(ctx, "Failed to set server status", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *updatedServer,
+		}, nil
+	})
+	// Deprecate server endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-deprecate-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/servers/{serverName}/versions/{version}/deprecate",
+		Summary:     "Deprecate MCP server",
+		Description: "Mark a specific version of an MCP server as deprecated, optionally pointing clients at the server that replaces it (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminDeprecateInput) (*Response[apiv0.ServerResponse], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		// URL-decode the version
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		deprecatedServer, err := registry.DeprecateServer(ctx, serverName, version, input.Body.ReplacedBy)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, databaseError(ctx, "Failed to deprecate server", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *deprecatedServer,
+		}, nil
+	})
+	// Set latest version endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-set-latest-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPut,
+		Path:        "/servers/{serverName}/versions/{version}/latest",
+		Summary:     "Set latest MCP server version",
+		Description: "Designate a specific version of an MCP server as the latest, unmarking every other version of the server, e.g. to roll back a bad publish. The version is pinned: reconciling, imports and reloads keep it the latest until a newer version is published. Deleted versions can't be made the latest (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminSetLatestInput) (*Response[apiv0.ServerResponse], error) {
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		server, err := registry.SetLatestVersion(ctx, serverName, version)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, databaseError(ctx, "Failed to set latest version", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *server,
+		}, nil
+	})
+	// Transfer server endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-transfer-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/servers/{serverName}/transfer",
+		Summary:     "Transfer MCP server",
+		Description: "Move every version of an MCP server to a new name, e.g. when a project changes hands, optionally leaving a deprecated tombstone at the old name that points to the new one (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminTransferInput) (*Response[apiv0.ServerListResponse], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		transferred, err := registry.TransferServer(ctx, serverName, input.Body.NewName, input.Body.Tombstone)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			if errors.Is(err, database.ErrAlreadyExists) {
+				return nil, huma.Error409Conflict(err.Error())
+			}
+			return nil, databaseError(ctx, "Failed to transfer server", err)
+		}
+
+		servers := make([]apiv0.ServerResponse, len(transferred))
+		for i, server := range transferred {
+			servers[i] = *server
+		}
+
+		return &Response[apiv0.ServerListResponse]{
+			Body: apiv0.ServerListResponse{
+				Servers: servers,
+				Metadata: apiv0.Metadata{
+					Count: len(servers),
+				},
+			},
+		}, nil
+	})
+	// Publish catalog endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-publish-catalog" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/catalog",
+		Summary:     "Publish catalog to S3",
+		Description: "Upload the server versions matching a filter to S3 as a registry data file. The configured catalog URL and filter are used for any field the request body leaves unset (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminCatalogInput) (*Response[AdminCatalogBody], error) {
+		// Fields the body leaves unset keep their configured defaults
+		s3URL, status, latestOnly := cfg.CatalogS3URL, cfg.CatalogStatus, cfg.CatalogLatestOnly
+		if input.Body != nil {
+			if input.Body.URL != "" {
+				s3URL = input.Body.URL
+			}
+			if input.Body.Status != nil {
+				status = *input.Body.Status
+			}
+			if input.Body.LatestOnly != nil {
+				latestOnly = *input.Body.LatestOnly
+			}
+		}
+		filter := service.NewCatalogFilter(status, latestOnly)
+		if s3URL == "" {
+			return nil, huma.Error400BadRequest("No catalog URL given and MCP_REGISTRY_CATALOG_S3_URL is not configured")
+		}
+
+		bucket, key, err := aws.ParseS3URL(s3URL)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid catalog URL", err)
+		}
+
+		published, err := registry.PublishCatalog(ctx, filter, bucket, key)
+		if err != nil {
+			if errors.Is(err, aws.ErrUploadFailed) {
+				return nil, huma.Error502BadGateway("Failed to publish catalog", err)
+			}
+			return nil, databaseError(ctx, "Failed to publish catalog", err)
+		}
+
+		return &Response[AdminCatalogBody]{
+			Body: AdminCatalogBody{URL: s3URL, Servers: published},
+		}, nil
+	})
+	// Create snapshot endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID:   "admin-create-snapshot" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:        http.MethodPost,
+		Path:          "/snapshots",
+		Summary:       "Create registry snapshot",
+		Description:   "Capture every server version as an immutable snapshot, served unchanged at /snapshots/{id} from then on. An existing snapshot ID cannot be reused (admin only).",
+		Tags:          []string{"admin"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminSnapshotInput) (*Response[AdminSnapshotBody], error) {
+		id := ""
+		if input.Body != nil {
+			id = input.Body.ID
+		}
+
+		snapshot, servers, err := registry.CreateSnapshot(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, database.ErrInvalidInput):
+				return nil, huma.Error400BadRequest("Failed to create snapshot", err)
+			case errors.Is(err, database.ErrAlreadyExists):
+				return nil, huma.Error409Conflict("A snapshot with this ID already exists")
+			}
+			return nil, databaseError(ctx, "Failed to create snapshot", err)
+		}
+
+		return &Response[AdminSnapshotBody]{
+			Body: AdminSnapshotBody{
+				ID:        snapshot.ID,
+				SHA256:    snapshot.SHA256,
+				CreatedAt: snapshot.CreatedAt,
+				Servers:   servers,
+			},
+		}, nil
+	})
+	// Reconcile latest endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-reconcile-latest" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/reconcile-latest",
+		Summary:     "Reconcile latest versions",
+		Description: "Recompute the latest version of every server and fix any servers with no latest version or more than one, leaving servers whose latest version an administrator pinned alone, and report how many versions were corrected (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminAuthInput) (*Response[AdminReconcileLatestBody], error) {
+		corrected, err := registry.ReconcileLatest(ctx)
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to reconcile latest versions", err)
+		}
+
+		return &Response[AdminReconcileLatestBody]{
+			Body: AdminReconcileLatestBody{Corrected: corrected},
+		}, nil
+	})
+	// Compact endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-compact" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/compact",
+		Summary:     "Compact registry data",
+		Description: "Remove server versions deleted longer ago than the configured retention and versions beyond the configured per-server limit, reporting what was removed. " +
+			"With dry_run=true, report what would be removed without removing anything (admin only).",
+		Tags: []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminCompactInput) (*Response[AdminCompactBody], error) {
+		result, err := registry.Compact(ctx, input.DryRun)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Failed to compact registry data", err)
+			}
+			return nil, databaseError(ctx, "Failed to compact registry data", err)
+		}
+
+		removed := result.Removed
+		if removed == nil {
+			removed = []database.CompactedVersion{}
+		}
+		return &Response[AdminCompactBody]{
+			Body: AdminCompactBody{DryRun: input.DryRun, Removed: removed},
+		}, nil
+	})
+	// Revalidate endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-revalidate" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        "/revalidate",
+		Summary:     "Revalidate stored servers",
+		Description: "Run the current publish validation against every stored server version that isn't deleted, without modifying anything. " +
+			"Failures are streamed as newline-delimited JSON, one server version per line, followed by a summary line (admin only).",
+		Tags: []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(_ context.Context, input *AdminAuthInput) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{Body: func(hctx huma.Context) {
+			streamRevalidation(hctx, registry)
+		}}, nil
+	})
+	// Flush endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-flush" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/flush",
+		Summary:     "Flush registry data",
+		Description: "Immediately persist any in-memory registry changes to storage, e.g. before taking a snapshot (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminAuthInput) (*Response[AdminFlushBody], error) {
+		if err := registry.Flush(ctx); err != nil {
+			return nil, databaseError(ctx, "Failed to flush registry data", err)
+		}
+
+		return &Response[AdminFlushBody]{
+			Body: AdminFlushBody{Status: "flushed"},
+		}, nil
+	})
+	// Reload endpoint
+	huma.Register(admin, huma.Operation{
+		OperationID: "admin-reload" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        "/reload",
+		Summary:     "Reload registry data from S3",
+		Description: "Immediately download registry data from S3 and reload it, without waiting for an SQS notification. The download is validated before it replaces the current data (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *AdminReloadInput) (*Response[AdminReloadBody], error) {
+		var s3URL string
+		if input.Body != nil {
+			s3URL = input.Body.URL
+		}
+
+		records, err := registry.ReloadFromS3(ctx, s3URL)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Failed to reload registry data", err)
+			}
+			if errors.Is(err, aws.ErrInvalidDownload) {
+				return nil, huma.Error422UnprocessableEntity("Failed to reload registry data", err)
+			}
+			return nil, huma.Error502BadGateway("Failed to reload registry data", err)
+		}
+
+		return &Response[AdminReloadBody]{
+			Body: AdminReloadBody{Records: records},
+		}, nil
+	})
+
+	// The effective configuration is served next to the public metadata endpoints, behind the same key
+	configGroup := huma.NewGroup(api, pathPrefix)
+	configGroup.UseMiddleware(adminAuthMiddleware(api, cfg.AdminAPIKey))
+	huma.Register(configGroup, huma.Operation{
+		OperationID: "admin-get-config" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        "/config",
+		Summary:     "Get effective configuration",
+		Description: "Return the configuration this instance resolved from its environment, keyed by environment variable, for debugging deployments. Secrets such as the admin API key, JWT private key, GitHub client secret and database password are redacted (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(_ context.Context, input *AdminAuthInput) (*Response[*config.EffectiveConfig], error) {
+		return &Response[*config.EffectiveConfig]{Body: cfg.Effective()}, nil
+	})
+}
+
+// streamRevalidation writes each validation failure as a line of JSON as soon as it is found, so reports on large
+// datasets arrive incrementally
+func streamRevalidation(hctx huma.Context, registry service.RegistryService) {
+	hctx.SetHeader("Content-Type", "application/x-ndjson")
+	w := hctx.BodyWriter()
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	summary, err := registry.RevalidateServers(hctx.Context(), func(issue service.ValidationIssue) error {
+		if err := encoder.Encode(AdminRevalidateLine{ValidationIssue: &issue}); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	last := AdminRevalidateLine{Summary: &summary}
+	if err != nil {
+		log.Printf("Revalidation stopped after %d server versions: %v", summary.Checked, err)
+		last = AdminRevalidateLine{Error: "Failed to revalidate servers: " + err.Error()}
+	}
+	_ = encoder.Encode(last)
+}
+
+// adminAuthMiddleware rejects requests that don't carry the admin API key with a 401
+func adminAuthMiddleware(api huma.API, apiKey string) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if err := validateAdminAPIKey(ctx.Header("Authorization"), apiKey); err != nil {
+			log.Printf("Rejected admin request %s %s from %s: %v", ctx.Method(), ctx.URL().Path, ClientIP(ctx.Context()), err)
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, err.Error())
+			return
+		}
+		// Changes made through the admin API are audited as the admin
+		next(huma.WithContext(ctx, withRequestActor(ctx.Context(), adminActor)))
+	}
+}
+
+// adminActor identifies holders of the admin API key in the audit log
+var adminActor = service.Actor{AuthMethod: "admin_api_key", Subject: "admin"}
+
+// validateAdminAPIKey checks a Bearer Authorization header against the configured admin API key
+func validateAdminAPIKey(authHeader, apiKey string) error {
+	const bearerPrefix = "Bearer "
+	if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+		return errors.New("invalid Authorization header format. Expected 'Bearer <token>'")
+	}
+	token := authHeader[len(bearerPrefix):]
+
+	// Constant-time comparison so the key can't be recovered through response timing
+	if subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
+		return errors.New("invalid admin API key")
+	}
+	return nil
+}
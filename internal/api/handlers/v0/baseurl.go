@@ -0,0 +1,30 @@
+package v0
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// baseURLKey is the context key for the external base URL of the API
+type baseURLKey struct{}
+
+// WithBaseURL returns a context carrying the base URL clients use to reach the API, as seen from
+// outside any TLS-terminating proxy. Handlers resolve the self-referential URLs they return against it.
+func WithBaseURL(ctx context.Context, baseURL *url.URL) context.Context {
+	return context.WithValue(ctx, baseURLKey{}, baseURL)
+}
+
+// absoluteURL resolves an already-escaped API path such as "/v0/servers" against the external base URL
+// in ctx. Without a base URL the path is returned relative, as before.
+func absoluteURL(ctx context.Context, escapedPath string, query url.Values) string {
+	target := escapedPath
+	if baseURL, ok := ctx.Value(baseURLKey{}).(*url.URL); ok && baseURL != nil {
+		target = baseURL.Scheme + "://" + baseURL.Host + strings.TrimSuffix(baseURL.EscapedPath(), "/") + escapedPath
+	}
+
+	if encoded := query.Encode(); encoded != "" {
+		target += "?" + encoded
+	}
+	return target
+}
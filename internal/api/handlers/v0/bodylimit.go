@@ -0,0 +1,54 @@
+package v0
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// defaultMaxBodyBytes is the request body limit used when none is configured
+const defaultMaxBodyBytes = 1024 * 1024
+
+// withBodyLimit caps the request body size of a publish or update operation at the configured limit,
+// replacing Huma's built-in 1MB default so the limit can be raised as well as lowered
+func withBodyLimit(api huma.API, cfg *config.Config, op huma.Operation) huma.Operation {
+	maxBytes := cfg.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+
+	// Disable Huma's own limit; the middleware enforces ours
+	op.MaxBodyBytes = -1
+	op.Middlewares = append(op.Middlewares, bodyLimitMiddleware(api, maxBytes))
+	return op
+}
+
+// bodyLimitMiddleware rejects request bodies larger than maxBytes with 413 Request Entity Too Large.
+// The body is read through http.MaxBytesReader so an oversized upload is never fully buffered.
+func bodyLimitMiddleware(api huma.API, maxBytes int64) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		r, w := humago.Unwrap(ctx)
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				_ = huma.WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds the %d byte limit", maxBytes))
+				return
+			}
+			_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+
+		// Hand the already-read body on to the operation
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(ctx)
+	}
+}
@@ -0,0 +1,84 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// CapabilitiesBody describes how clients can page through and narrow server lists
+type CapabilitiesBody struct {
+	Pagination PaginationCapabilities `json:"pagination" doc:"Page sizes and pagination modes of server lists"`
+	Filters    []QueryParameter       `json:"filters" doc:"Query parameters that narrow the server list"`
+	SortFields []string               `json:"sortFields" doc:"Fields server lists are ordered by, in order of precedence. Fuzzy searches rank by similarity first." example:"[\"name\",\"version\"]"`
+}
+
+// PaginationCapabilities describes the page sizes and pagination modes of server lists
+type PaginationCapabilities struct {
+	DefaultLimit int      `json:"defaultLimit" example:"30" doc:"Servers per page when limit is unset"`
+	MaxLimit     int      `json:"maxLimit" example:"100" doc:"Most servers per page; larger limits are lowered to it"`
+	Modes        []string `json:"modes" doc:"Supported pagination modes, recommended first" example:"[\"cursor\",\"offset\"]"`
+}
+
+// QueryParameter describes a query parameter of the server list
+type QueryParameter struct {
+	Name        string   `json:"name" example:"has_remotes" doc:"Query parameter name"`
+	Description string   `json:"description" doc:"What the parameter does"`
+	Enum        []string `json:"enum,omitempty" doc:"Accepted values, when restricted"`
+}
+
+// listSortFields are the fields server lists are ordered by, matching the database backends
+var listSortFields = []string{"name", "version"}
+
+// listFilters are the query parameters of the server list that select servers, as opposed to paginating
+// or shaping the response
+var listFilters = queryParameters(reflect.TypeOf(ListServersInput{}), "cursor", "offset", "limit", "fields", "schema_version", "resolve_packages")
+
+// queryParameters describes the query parameters of an input struct type, leaving out the excluded ones
+func queryParameters(t reflect.Type, exclude ...string) []QueryParameter {
+	var params []QueryParameter
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || slices.Contains(exclude, name) {
+			continue
+		}
+		param := QueryParameter{Name: name, Description: field.Tag.Get("doc")}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			param.Enum = strings.Split(enum, ",")
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// RegisterCapabilitiesEndpoint registers the capabilities endpoint with a custom path prefix
+func RegisterCapabilitiesEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+	defaultLimit, maxLimit := cfg.PageSizes()
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-capabilities" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/capabilities",
+		Summary:     "Get list capabilities",
+		Description: "Describe the page sizes, pagination modes, filters and ordering of server lists, as configured on this registry",
+		Tags:        []string{"servers"},
+	}, func(_ context.Context, _ *struct{}) (*Response[CapabilitiesBody], error) {
+		return &Response[CapabilitiesBody]{
+			Body: CapabilitiesBody{
+				Pagination: PaginationCapabilities{
+					DefaultLimit: defaultLimit,
+					MaxLimit:     maxLimit,
+					Modes:        []string{"cursor", "offset"},
+				},
+				Filters:    listFilters,
+				SortFields: listSortFields,
+			},
+		}, nil
+	})
+}
@@ -0,0 +1,95 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesEndpoint(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{DefaultPageSize: 3, MaxPageSize: 5}
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+
+	for i := range 8 {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        fmt.Sprintf("com.example/server-%d", i),
+			Description: "Capabilities test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+	v0.RegisterCapabilitiesEndpoint(api, "/v0", cfg)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/capabilities", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var capabilities v0.CapabilitiesBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &capabilities))
+	assert.Equal(t, 3, capabilities.Pagination.DefaultLimit)
+	assert.Equal(t, 5, capabilities.Pagination.MaxLimit)
+	assert.Equal(t, []string{"cursor", "offset"}, capabilities.Pagination.Modes)
+	assert.Equal(t, []string{"name", "version"}, capabilities.SortFields)
+
+	filters := make(map[string]v0.QueryParameter)
+	for _, filter := range capabilities.Filters {
+		filters[filter.Name] = filter
+	}
+	for _, name := range []string{"search", "updated_since", "version", "tag"} {
+		assert.Contains(t, filters, name)
+	}
+	for _, name := range []string{"cursor", "offset", "limit", "fields"} {
+		assert.NotContains(t, filters, name)
+	}
+
+	count := func(t *testing.T, path string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return len(body.Servers)
+	}
+
+	t.Run("advertised maximum matches the enforced clamp", func(t *testing.T) {
+		assert.Equal(t, capabilities.Pagination.MaxLimit, count(t, "/v0/servers?limit=100"))
+	})
+
+	t.Run("advertised default applies without a limit", func(t *testing.T) {
+		assert.Equal(t, capabilities.Pagination.DefaultLimit, count(t, "/v0/servers"))
+	})
+
+	t.Run("server names are paged the same way", func(t *testing.T) {
+		names := func(path string) []string {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var body v0.ServerNamesBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			return body.Names
+		}
+		assert.Len(t, names("/v0/names?limit=100"), capabilities.Pagination.MaxLimit)
+		assert.Len(t, names("/v0/names"), capabilities.Pagination.DefaultLimit)
+	})
+}
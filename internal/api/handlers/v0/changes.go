@@ -0,0 +1,76 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ChangesInput represents the input for reading the changes feed
+type ChangesInput struct {
+	Since string `query:"since" doc:"Change token from the previous call's metadata; omit to start from the first change" required:"false"`
+	Limit int    `query:"limit" doc:"Number of changes per page" default:"30" minimum:"1" maximum:"100" example:"50"`
+}
+
+// ChangesMetadata describes a page of the changes feed
+type ChangesMetadata struct {
+	NextToken string `json:"nextToken,omitempty" doc:"Change token to pass as since in the next call to resume after the last change returned. Unchanged when there are no new changes."`
+	Count     int    `json:"count" doc:"Number of changes in this page, counting removals; fewer than the limit means the consumer has caught up"`
+}
+
+// RemovedServer identifies a server version removed from the registry
+type RemovedServer struct {
+	Name    string `json:"name" doc:"Server name"`
+	Version string `json:"version" doc:"Removed version"`
+}
+
+// ChangesBody is a page of the changes feed
+type ChangesBody struct {
+	Servers  []apiv0.ServerResponse `json:"servers" doc:"Server versions changed since the token, oldest change first"`
+	Removed  []RemovedServer        `json:"removed,omitempty" doc:"Server versions removed from storage since the token, e.g. by compaction or pruning"`
+	Metadata ChangesMetadata        `json:"metadata" doc:"Change feed metadata"`
+}
+
+// RegisterChangesEndpoint registers the changes feed endpoint with a custom path prefix
+func RegisterChangesEndpoint(api huma.API, pathPrefix string, registry service.RegistryService) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-changes" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/changes",
+		Summary:     "List changed MCP servers",
+		Description: "Get the server versions published, updated or removed since a change token, in the order they changed, for incremental sync. Resuming from the returned token neither repeats nor skips a change. Each version appears at most once per page, in its most recent state.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ChangesInput) (*Response[ChangesBody], error) {
+		changes, next, err := registry.ListChanges(ctx, input.Since, input.Limit)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid change token", err)
+			}
+			return nil, databaseError(ctx, "Failed to get changes", err)
+		}
+
+		servers := make([]apiv0.ServerResponse, 0, len(changes))
+		var removed []RemovedServer
+		for _, change := range changes {
+			if change.Server == nil {
+				removed = append(removed, RemovedServer{Name: change.Removed.Name, Version: change.Removed.Version})
+				continue
+			}
+			servers = append(servers, upgradeResponse(*change.Server))
+		}
+
+		return &Response[ChangesBody]{
+			Body: ChangesBody{
+				Servers:  servers,
+				Removed:  removed,
+				Metadata: ChangesMetadata{NextToken: next, Count: len(changes)},
+			},
+		}, nil
+	})
+}
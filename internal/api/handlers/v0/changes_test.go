@@ -0,0 +1,102 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesEndpoint(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	registryService := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterChangesEndpoint(api, "/v0", registryService)
+
+	publish := func(name, version string) {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Change feed test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	// changes reads one page of the feed, returning the name@version of each change, with removals prefixed by "-"
+	changes := func(t *testing.T, since string, limit string) ([]string, string) {
+		t.Helper()
+		query := url.Values{"limit": {limit}}
+		if since != "" {
+			query.Set("since", since)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/changes?"+query.Encode(), nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var body v0.ChangesBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, len(body.Servers)+len(body.Removed), body.Metadata.Count)
+		var changed []string
+		for _, server := range body.Servers {
+			changed = append(changed, server.Server.Name+"@"+server.Server.Version)
+		}
+		for _, removed := range body.Removed {
+			changed = append(changed, "-"+removed.Name+"@"+removed.Version)
+		}
+		return changed, body.Metadata.NextToken
+	}
+
+	publish("com.example/alpha", "1.0.0")
+	publish("com.example/beta", "1.0.0")
+	publish("com.example/gamma", "1.0.0")
+
+	// Initial sync, a page at a time
+	first, token := changes(t, "", "2")
+	assert.Equal(t, []string{"com.example/alpha@1.0.0", "com.example/beta@1.0.0"}, first)
+	second, token := changes(t, token, "2")
+	assert.Equal(t, []string{"com.example/gamma@1.0.0"}, second)
+
+	// Caught up: nothing new, and the token stays put
+	none, unchanged := changes(t, token, "2")
+	assert.Empty(t, none)
+	assert.Equal(t, token, unchanged)
+
+	// New versions and edits to earlier ones show up once each, in the order they happened
+	publish("com.example/alpha", "2.0.0")
+	_, err := registryService.SetServerStatus(ctx, "com.example/beta", "1.0.0", model.StatusDeprecated, "")
+	require.NoError(t, err)
+
+	// Publishing 2.0.0 also changed alpha 1.0.0, which is no longer the latest
+	resumed, token := changes(t, token, "10")
+	assert.Equal(t, []string{"com.example/alpha@1.0.0", "com.example/alpha@2.0.0", "com.example/beta@1.0.0"}, resumed)
+	none, _ = changes(t, token, "10")
+	assert.Empty(t, none)
+
+	// Versions removed from storage are reported too
+	require.NoError(t, db.DeleteServerVersion(ctx, nil, "com.example/gamma", "1.0.0"))
+	removed, _ := changes(t, token, "10")
+	assert.Equal(t, []string{"-com.example/gamma@1.0.0"}, removed)
+
+	t.Run("malformed token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/changes?since=not-a-token", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
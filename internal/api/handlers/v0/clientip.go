@@ -0,0 +1,29 @@
+package v0
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// clientIPKey is the context key for the address of the client that made a request
+type clientIPKey struct{}
+
+// WithClientIP returns a context carrying the address of the client that made the request, resolved
+// through any trusted proxies
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// ClientIP returns the client address stored by WithClientIP, or an empty string if there is none
+func ClientIP(ctx context.Context) string {
+	clientIP, _ := ctx.Value(clientIPKey{}).(string)
+	return clientIP
+}
+
+// withRequestActor returns a context that attributes the changes made with it to actor, at the client
+// address of the request in ctx
+func withRequestActor(ctx context.Context, actor service.Actor) context.Context {
+	actor.IP = ClientIP(ctx)
+	return service.WithActor(ctx, actor)
+}
@@ -25,12 +25,20 @@ type EditServerInput struct {
 	Body          apiv0.ServerJSON `body:""`
 }
 
+// PatchServerInput represents the input for partially updating a server
+type PatchServerInput struct {
+	Authorization string         `header:"Authorization" doc:"Registry JWT token with edit permissions" required:"true"`
+	ServerName    string         `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version       string         `path:"version" doc:"URL-encoded version to edit" example:"1.0.0"`
+	Body          map[string]any `body:"" doc:"JSON merge patch (RFC 7396) to apply to the server"`
+}
+
 // RegisterEditEndpoints registers the edit endpoint with a custom path prefix
 func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
 	jwtManager := auth.NewJWTManager(cfg)
 
 	// Edit server endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, withBodyLimit(api, cfg, huma.Operation{
 		OperationID: "edit-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
 		Method:      http.MethodPut,
 		Path:        pathPrefix + "/servers/{serverName}/versions/{version}",
@@ -40,19 +48,10 @@ func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.Reg
 		Security: []map[string][]string{
 			{"bearer": {}},
 		},
-	}, func(ctx context.Context, input *EditServerInput) (*Response[apiv0.ServerResponse], error) {
-		// Extract bearer token
-		const bearerPrefix = "Bearer "
-		authHeader := input.Authorization
-		if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
-			return nil, huma.Error401Unauthorized("Invalid Authorization header format. Expected 'Bearer <token>'")
-		}
-		token := authHeader[len(bearerPrefix):]
-
-		// Validate Registry JWT token
-		claims, err := jwtManager.ValidateToken(ctx, token)
+	}), func(ctx context.Context, input *EditServerInput) (*Response[apiv0.ServerResponse], error) {
+		claims, err := validateRegistryToken(ctx, jwtManager, input.Authorization)
 		if err != nil {
-			return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
+			return nil, err
 		}
 
 		// URL-decode the server name
@@ -70,10 +69,7 @@ func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.Reg
 		// Get current server to check permissions against existing name
 		currentServer, err := registry.GetServerByNameAndVersion(ctx, serverName, version)
 		if err != nil {
-			if errors.Is(err, database.ErrNotFound) {
-				return nil, huma.Error404NotFound("Server not found")
-			}
-			return nil, huma.Error500InternalServerError("Failed to get current server", err)
+			return nil, databaseError(ctx, "Failed to get current server", err)
 		}
 
 		// Verify edit permissions for this server using the existing server name
@@ -112,16 +108,85 @@ func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.Reg
 		if input.Status != "" {
 			statusPtr = &input.Status
 		}
-		updatedServer, err := registry.UpdateServer(ctx, serverName, version, &input.Body, statusPtr)
+		updatedServer, err := registry.UpdateServer(withRequestActor(ctx, claimsActor(claims)), serverName, version, &input.Body, statusPtr)
 		if err != nil {
-			if errors.Is(err, database.ErrNotFound) {
-				return nil, huma.Error404NotFound("Server not found")
+			if errors.Is(err, database.ErrNotFound) || errors.Is(err, database.ErrDatabase) {
+				return nil, databaseError(ctx, "Failed to edit server", err)
 			}
-			return nil, huma.Error400BadRequest("Failed to edit server", err)
+			return nil, badRequest("Failed to edit server", err)
 		}
 
 		return &Response[apiv0.ServerResponse]{
 			Body: *updatedServer,
 		}, nil
 	})
+
+	// Patch server endpoint
+	huma.Register(api, withBodyLimit(api, cfg, huma.Operation{
+		OperationID: "patch-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPatch,
+		Path:        pathPrefix + "/servers/{serverName}/versions/{version}",
+		Summary:     "Patch MCP server",
+		Description: "Partially update a specific version of an existing MCP server using JSON merge patch semantics. Fields not present in the patch are left unchanged (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}), func(ctx context.Context, input *PatchServerInput) (*Response[apiv0.ServerResponse], error) {
+		claims, err := validateRegistryToken(ctx, jwtManager, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		// URL-decode the version
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		// Verify edit permissions for this server
+		if !jwtManager.HasPermission(serverName, auth.PermissionActionEdit, claims.Permissions) {
+			return nil, huma.Error403Forbidden("You do not have edit permissions for this server")
+		}
+
+		updatedServer, err := registry.PatchServer(withRequestActor(ctx, claimsActor(claims)), serverName, version, input.Body)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) || errors.Is(err, database.ErrDatabase) {
+				return nil, databaseError(ctx, "Failed to edit server", err)
+			}
+			return nil, badRequest("Failed to edit server", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *updatedServer,
+		}, nil
+	})
+}
+
+// validateRegistryToken extracts the bearer token from an Authorization header and validates it as a Registry JWT
+func validateRegistryToken(ctx context.Context, jwtManager *auth.JWTManager, authHeader string) (*auth.JWTClaims, error) {
+	// Extract bearer token
+	const bearerPrefix = "Bearer "
+	if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+		return nil, huma.Error401Unauthorized("Invalid Authorization header format. Expected 'Bearer <token>'")
+	}
+	token := authHeader[len(bearerPrefix):]
+
+	// Validate Registry JWT token
+	claims, err := jwtManager.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
+	}
+	return claims, nil
+}
+
+// claimsActor identifies the holder of a Registry JWT in the audit log
+func claimsActor(claims *auth.JWTClaims) service.Actor {
+	return service.Actor{AuthMethod: string(claims.AuthMethod), Subject: claims.AuthMethodSubject}
 }
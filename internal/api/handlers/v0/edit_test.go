@@ -661,3 +661,100 @@ func TestEditServerEndpointEdgeCases(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestPatchServerEndpoint(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+
+	serverName := "io.github.testuser/patchable-server"
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Original description",
+		Version:     "1.0.0",
+		Packages: []model.Package{
+			{RegistryType: "npm", Identifier: "@testuser/patchable-server", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}},
+		},
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://testuser.github.io/patchable/mcp"},
+		},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterEditEndpoints(api, "/v0", registryService, cfg)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.testuser/*"},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		patch          string
+		expectedStatus int
+		checkResult    func(*testing.T, *apiv0.ServerResponse)
+	}{
+		{
+			name:           "patch only description",
+			patch:          `{"description":"Patched description"}`,
+			expectedStatus: http.StatusOK,
+			checkResult: func(t *testing.T, resp *apiv0.ServerResponse) {
+				t.Helper()
+				assert.Equal(t, "Patched description", resp.Server.Description)
+				require.Len(t, resp.Server.Packages, 1)
+				assert.Equal(t, "@testuser/patchable-server", resp.Server.Packages[0].Identifier)
+				require.Len(t, resp.Server.Remotes, 1)
+				assert.Equal(t, "https://testuser.github.io/patchable/mcp", resp.Server.Remotes[0].URL)
+			},
+		},
+		{
+			name:           "rename is rejected",
+			patch:          `{"name":"io.github.testuser/renamed-server"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid merged result is rejected",
+			patch:          `{"$schema":null}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := "/v0/servers/" + url.PathEscape(serverName) + "/versions/1.0.0"
+			req := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader([]byte(tc.patch)))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			req.Header.Set("Authorization", "Bearer "+tokenResponse.RegistryToken)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+			if tc.checkResult != nil {
+				var resp apiv0.ServerResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				tc.checkResult(t, &resp)
+			}
+		})
+	}
+
+	// The stored record keeps the patched description and its untouched fields
+	stored, err := registryService.GetServerByNameAndVersion(context.Background(), serverName, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "Patched description", stored.Server.Description)
+	assert.Len(t, stored.Server.Packages, 1)
+	assert.Len(t, stored.Server.Remotes, 1)
+}
@@ -0,0 +1,58 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// retryAfterKey is the context key for how long clients should wait before retrying a 503
+type retryAfterKey struct{}
+
+// WithRetryAfter returns a context carrying how long clients should wait before retrying a request
+// that failed with 503 Service Unavailable
+func WithRetryAfter(ctx context.Context, retryAfter time.Duration) context.Context {
+	return context.WithValue(ctx, retryAfterKey{}, retryAfter)
+}
+
+// RetryAfterMiddleware makes 503 responses from the API's handlers carry a Retry-After header of
+// retryAfter, rounded up to whole seconds. A zero duration sends no header.
+func RetryAfterMiddleware(retryAfter time.Duration) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		next(huma.WithContext(ctx, WithRetryAfter(ctx.Context(), retryAfter)))
+	}
+}
+
+// serviceUnavailable builds a 503 error, telling the client when to retry if ctx carries a retry hint
+func serviceUnavailable(ctx context.Context, msg string, err error) error {
+	apiErr := huma.Error503ServiceUnavailable(msg, err)
+
+	retryAfter, _ := ctx.Value(retryAfterKey{}).(time.Duration)
+	if retryAfter <= 0 {
+		return apiErr
+	}
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	return huma.ErrorWithHeaders(apiErr, http.Header{"Retry-After": {seconds}})
+}
+
+// databaseError maps a failed registry lookup or write to an HTTP error: 404 when the server does not
+// exist, 503 when the database is temporarily unavailable or the request timed out, and 500 for anything else
+func databaseError(ctx context.Context, msg string, err error) error {
+	switch {
+	case err.Error() == errRecordNotFound || errors.Is(err, database.ErrNotFound):
+		return huma.Error404NotFound("Server not found")
+	case errors.Is(err, database.ErrDatabase):
+		return serviceUnavailable(ctx, msg, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return serviceUnavailable(ctx, "Request timed out", err)
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
@@ -0,0 +1,127 @@
+package v0_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// failingDB is a Database whose reads fail with a fixed error
+type failingDB struct {
+	database.Database
+	err error
+}
+
+func (db *failingDB) ListServers(_ context.Context, _ pgx.Tx, _ *database.ServerFilter, _ string, _ int) ([]*apiv0.ServerResponse, string, error) {
+	return nil, "", db.err
+}
+
+func (db *failingDB) GetServerByName(_ context.Context, _ pgx.Tx, _ string) (*apiv0.ServerResponse, error) {
+	return nil, db.err
+}
+
+func (db *failingDB) GetServerByNameAndVersion(_ context.Context, _ pgx.Tx, _, _ string) (*apiv0.ServerResponse, error) {
+	return nil, db.err
+}
+
+func (db *failingDB) GetAllVersionsByServerName(_ context.Context, _ pgx.Tx, _ string) ([]*apiv0.ServerResponse, error) {
+	return nil, db.err
+}
+
+func TestDatabaseErrorStatus(t *testing.T) {
+	serverPath := "/v0/servers/" + url.PathEscape("com.example/server")
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "not found", err: database.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "database unavailable", err: fmt.Errorf("%w: failed to get server: connection refused", database.ErrDatabase), expectedStatus: http.StatusServiceUnavailable},
+		{name: "unexpected failure", err: errors.New("failed to unmarshal server JSON"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	paths := []string{
+		serverPath + "/versions/1.0.0",
+		serverPath + "/versions/latest",
+		serverPath + "/versions",
+		serverPath + "/latest",
+	}
+
+	for _, tc := range testCases {
+		db := &failingDB{Database: database.NewTestJSONFileDB(t), err: tc.err}
+		registryService := service.NewRegistryService(db, config.NewConfig())
+
+		mux := http.NewServeMux()
+		api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+		v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+		for _, path := range paths {
+			t.Run(tc.name+" "+path, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+			})
+		}
+	}
+
+	t.Run("list reports an unavailable database as 503", func(t *testing.T) {
+		db := &failingDB{Database: database.NewTestJSONFileDB(t), err: fmt.Errorf("%w: connection refused", database.ErrDatabase)}
+		mux := http.NewServeMux()
+		api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+		v0.RegisterServersEndpoints(api, "/v0", service.NewRegistryService(db, config.NewConfig()))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	})
+}
+
+func TestServiceUnavailableRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name               string
+		err                error
+		retryAfter         time.Duration
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{name: "database unavailable", err: fmt.Errorf("%w: connection refused", database.ErrDatabase), retryAfter: 30 * time.Second, expectedStatus: http.StatusServiceUnavailable, expectedRetryAfter: "30"},
+		{name: "request timeout", err: fmt.Errorf("failed to query servers: %w", context.DeadlineExceeded), retryAfter: 30 * time.Second, expectedStatus: http.StatusServiceUnavailable, expectedRetryAfter: "30"},
+		{name: "partial seconds round up", err: database.ErrDatabase, retryAfter: 1500 * time.Millisecond, expectedStatus: http.StatusServiceUnavailable, expectedRetryAfter: "2"},
+		{name: "disabled", err: database.ErrDatabase, expectedStatus: http.StatusServiceUnavailable},
+		{name: "not sent on other errors", err: errors.New("failed to unmarshal server JSON"), retryAfter: 30 * time.Second, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &failingDB{Database: database.NewTestJSONFileDB(t), err: tc.err}
+			mux := http.NewServeMux()
+			api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+			api.UseMiddleware(v0.RetryAfterMiddleware(tc.retryAfter))
+			v0.RegisterServersEndpoints(api, "/v0", service.NewRegistryService(db, config.NewConfig()))
+
+			for _, path := range []string{"/v0/servers", "/v0/servers/" + url.PathEscape("com.example/server") + "/versions/latest"} {
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+				assert.Equal(t, tc.expectedRetryAfter, w.Header().Get("Retry-After"), path)
+			}
+		})
+	}
+}
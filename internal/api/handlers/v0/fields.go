@@ -0,0 +1,182 @@
+package v0
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/schemaversion"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// serverFields are the top-level server.json fields that can be requested with the fields query
+// parameter, in the order they are serialized
+var serverFields = jsonFieldNames(reflect.TypeOf(apiv0.ServerJSON{}))
+
+// jsonFieldNames returns the JSON names of a struct type's serialized fields
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseFields parses the comma-separated fields query parameter, returning nil when it is unset
+func parseFields(fields string) ([]string, error) {
+	if fields == "" {
+		return nil, nil
+	}
+
+	var requested []string
+	for field := range strings.SplitSeq(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(requested, field) {
+			continue
+		}
+		if !slices.Contains(serverFields, field) {
+			return nil, huma.Error400BadRequest("Unknown field '" + field + "': expected one of " + strings.Join(serverFields, ", "))
+		}
+		requested = append(requested, field)
+	}
+	if len(requested) == 0 {
+		return nil, huma.Error400BadRequest("fields must name at least one field")
+	}
+	return requested, nil
+}
+
+// serverView controls how servers are serialized in responses
+type serverView struct {
+	fields        []string // top-level server fields to keep; nil keeps them all
+	schemaVersion string   // server.json schema version to convert to; empty serves servers as stored, upgraded if outdated
+	packages      bool     // whether to add the resolved download URL to each package
+}
+
+// isDefault reports whether servers are serialized without conversion or trimming
+func (v serverView) isDefault() bool {
+	return v.fields == nil && v.schemaVersion == "" && !v.packages
+}
+
+// ServerBody is a server response that is converted and trimmed as requested when serialized
+type ServerBody struct {
+	apiv0.ServerResponse
+	view serverView
+}
+
+// MarshalJSON serializes the server response, applying the requested schema version, fields and package resolution if any
+func (b ServerBody) MarshalJSON() ([]byte, error) {
+	response := b.ServerResponse
+	if b.view.schemaVersion == "" {
+		response = upgradeResponse(response)
+	}
+	if b.view.isDefault() {
+		return json.Marshal(response)
+	}
+	rendered, err := renderServer(response, b.view)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+// Schema documents ServerBody as the server response it serializes
+func (ServerBody) Schema(r huma.Registry) *huma.Schema {
+	return r.Schema(reflect.TypeOf(apiv0.ServerResponse{}), true, "")
+}
+
+// ServerListBody is a server list response whose servers are converted and trimmed as requested when serialized
+type ServerListBody struct {
+	apiv0.ServerListResponse
+	view serverView
+}
+
+// MarshalJSON serializes the server list, applying the requested schema version, fields and package resolution if any
+func (b ServerListBody) MarshalJSON() ([]byte, error) {
+	list := b.ServerListResponse
+	if b.view.schemaVersion == "" {
+		list.Servers = slices.Clone(list.Servers)
+		upgradeResponses(list.Servers)
+	}
+	if b.view.isDefault() {
+		return json.Marshal(list)
+	}
+
+	rendered := renderedServerList{
+		Servers:  make([]renderedServerResponse, len(list.Servers)),
+		Metadata: list.Metadata,
+	}
+	for i, server := range list.Servers {
+		r, err := renderServer(server, b.view)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Servers[i] = r
+	}
+	return json.Marshal(rendered)
+}
+
+// Schema documents ServerListBody as the server list response it serializes
+func (ServerListBody) Schema(r huma.Registry) *huma.Schema {
+	return r.Schema(reflect.TypeOf(apiv0.ServerListResponse{}), true, "")
+}
+
+// renderedServerResponse mirrors apiv0.ServerResponse with the server already serialized
+type renderedServerResponse struct {
+	Server json.RawMessage    `json:"server"`
+	Meta   apiv0.ResponseMeta `json:"_meta"`
+}
+
+// renderedServerList mirrors apiv0.ServerListResponse with every server already serialized
+type renderedServerList struct {
+	Servers  []renderedServerResponse `json:"servers"`
+	Metadata apiv0.Metadata           `json:"metadata"`
+}
+
+// renderServer serializes a server response's server converted to the requested schema version and
+// trimmed to the requested fields, keeping the usual field order, with its packages resolved if requested
+func renderServer(response apiv0.ServerResponse, view serverView) (renderedServerResponse, error) {
+	data, err := json.Marshal(response.Server)
+	if err != nil {
+		return renderedServerResponse{}, err
+	}
+	if view.schemaVersion != "" {
+		if data, err = schemaversion.Convert(data, view.schemaVersion); err != nil {
+			return renderedServerResponse{}, err
+		}
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return renderedServerResponse{}, err
+	}
+	if packages, ok := all["packages"]; ok && view.packages {
+		if all["packages"], err = resolvePackages(packages); err != nil {
+			return renderedServerResponse{}, err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, field := range serverFields {
+		value, ok := all[field]
+		if !ok || (view.fields != nil && !slices.Contains(view.fields, field)) {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return renderedServerResponse{Server: buf.Bytes(), Meta: response.Meta}, nil
+}
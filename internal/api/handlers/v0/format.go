@@ -0,0 +1,64 @@
+package v0
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+
+	"github.com/danielgtaylor/huma/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLFormat serializes responses as YAML for clients sending `Accept: application/yaml`.
+// Values go through their JSON encoding first so the existing `json` struct tags
+// (field names, omitempty, custom marshalers) apply unchanged to the YAML output.
+var YAMLFormat = huma.Format{
+	Marshal: func(w io.Writer, v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		// YAML is a superset of JSON, so decoding into a node keeps the key order
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		resetStyle(&node)
+
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return err
+		}
+		return enc.Close()
+	},
+	Unmarshal: func(data []byte, v any) error {
+		var decoded any
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		jsonData, err := json.Marshal(decoded)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(jsonData, v)
+	},
+}
+
+// ResponseFormats returns the formats available for content negotiation: JSON (the default) and YAML
+func ResponseFormats() map[string]huma.Format {
+	// Copy so the shared huma.DefaultFormats map is never modified
+	formats := maps.Clone(huma.DefaultFormats)
+	formats["application/yaml"] = YAMLFormat
+	formats["yaml"] = YAMLFormat
+	return formats
+}
+
+// resetStyle switches a node decoded from JSON to block style, letting the encoder pick quoting as needed
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,94 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestYAMLContentNegotiation(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/yaml-server",
+		Description: "YAML test server",
+		Version:     "1.0.0",
+		Repository: &model.Repository{
+			URL:    "https://github.com/example/yaml-server",
+			Source: "github",
+		},
+		Remotes: []model.Transport{
+			{Type: model.TransportTypeStreamableHTTP, URL: "https://example.com/mcp"},
+		},
+	})
+	require.NoError(t, err)
+
+	humaConfig := huma.DefaultConfig("Test API", "1.0.0")
+	humaConfig.Formats = v0.ResponseFormats()
+	mux := http.NewServeMux()
+	api := humago.New(mux, humaConfig)
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	paths := map[string]string{
+		"server detail": "/v0/servers/" + url.PathEscape("com.example/yaml-server") + "/versions/1.0.0",
+		"server list":   "/v0/servers",
+	}
+
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			jsonResp := get(path, "application/json")
+			require.Equal(t, http.StatusOK, jsonResp.Code, jsonResp.Body.String())
+			assert.Contains(t, jsonResp.Header().Get("Content-Type"), "application/json")
+
+			yamlResp := get(path, "application/yaml")
+			require.Equal(t, http.StatusOK, yamlResp.Code, yamlResp.Body.String())
+			assert.Contains(t, yamlResp.Header().Get("Content-Type"), "application/yaml")
+			assert.NotEqual(t, '{', yamlResp.Body.String()[0], "YAML response should use block style")
+
+			defaultResp := get(path, "")
+			require.Equal(t, http.StatusOK, defaultResp.Code)
+			assert.Contains(t, defaultResp.Header().Get("Content-Type"), "application/json")
+
+			var fromJSON map[string]any
+			require.NoError(t, json.Unmarshal(jsonResp.Body.Bytes(), &fromJSON))
+
+			// Normalize the YAML document through JSON so both sides use the same Go types
+			var fromYAMLRaw any
+			require.NoError(t, yaml.Unmarshal(yamlResp.Body.Bytes(), &fromYAMLRaw))
+			normalized, err := json.Marshal(fromYAMLRaw)
+			require.NoError(t, err)
+			var fromYAML map[string]any
+			require.NoError(t, json.Unmarshal(normalized, &fromYAML))
+
+			assert.Equal(t, fromJSON, fromYAML)
+		})
+	}
+}
@@ -4,51 +4,115 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/metric"
 
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
 
 // HealthBody represents the health check response body
 type HealthBody struct {
-	Status         string `json:"status" example:"ok" doc:"Health status"`
-	GitHubClientID string `json:"github_client_id,omitempty" doc:"GitHub OAuth App Client ID"`
+	Status         string        `json:"status" example:"ok" doc:"Health status" enum:"ok,degraded"`
+	GitHubClientID string        `json:"github_client_id,omitempty" doc:"GitHub OAuth App Client ID"`
+	LastSync       *LastSyncBody `json:"last_sync,omitempty" doc:"Last successful refresh of the registry data, if the database is synced from an external source"`
+	S3             *CheckBody    `json:"s3,omitempty" doc:"Reachability of the S3 data file, if the S3 health check is enabled"`
+}
+
+// CheckBody reports the outcome of a dependency check
+type CheckBody struct {
+	Status string `json:"status" example:"ok" doc:"Check status" enum:"ok,error"`
+	Error  string `json:"error,omitempty" doc:"Why the check failed"`
+}
+
+// LastSyncBody describes the last successful refresh of the registry data
+type LastSyncBody struct {
+	At     time.Time `json:"at" doc:"When the data was last refreshed"`
+	Source string    `json:"source" doc:"Where the data was loaded from" example:"s3://registry-bucket/registry.json"`
+	Stale  bool      `json:"stale" doc:"Whether the data is older than the configured staleness threshold"`
+}
+
+// HealthOutput is the health check response; degraded health is reported with 503 so readiness probes fail
+type HealthOutput struct {
+	Status int
+	Body   HealthBody
 }
 
 // RegisterHealthEndpoint registers the health check endpoint with a custom path prefix
-func RegisterHealthEndpoint(api huma.API, pathPrefix string, cfg *config.Config, metrics *telemetry.Metrics) {
+func RegisterHealthEndpoint(api huma.API, pathPrefix string, cfg *config.Config, registry service.RegistryService, metrics telemetry.Recorder) {
 	huma.Register(api, huma.Operation{
 		OperationID: "get-health" + strings.ReplaceAll(pathPrefix, "/", "-"),
 		Method:      http.MethodGet,
 		Path:        pathPrefix + "/health",
 		Summary:     "Health check",
-		Description: "Check the health status of the API",
-		Tags:        []string{"health"},
-	}, func(ctx context.Context, _ *struct{}) (*Response[HealthBody], error) {
-		// Record the health check metrics
-		recordHealthMetrics(ctx, metrics, pathPrefix+"/health", cfg.Version)
-
-		return &Response[HealthBody]{
+		Description: "Check the health status of the API. Reports `degraded` with 503 Service Unavailable when the registry data " +
+			"has not been synced within the configured staleness threshold, or when the optional S3 reachability check fails.",
+		Tags: []string{"health"},
+	}, func(ctx context.Context, _ *struct{}) (*HealthOutput, error) {
+		output := &HealthOutput{
+			Status: http.StatusOK,
 			Body: HealthBody{
 				Status:         "ok",
 				GitHubClientID: cfg.GithubClientID,
 			},
-		}, nil
+		}
+
+		if registry != nil {
+			if lastSync, ok := registry.LastSync(); ok {
+				stale := cfg.SyncStalenessThreshold > 0 && time.Since(lastSync.At) > cfg.SyncStalenessThreshold
+				output.Body.LastSync = &LastSyncBody{
+					At:     lastSync.At,
+					Source: lastSync.Source,
+					Stale:  stale,
+				}
+				if stale {
+					output.degrade()
+				}
+			}
+
+			if cfg.S3HealthCheck {
+				output.Body.S3 = checkS3(ctx, registry, cfg.S3HealthCheckTimeout)
+				if output.Body.S3.Status != "ok" {
+					output.degrade()
+				}
+			}
+		}
+
+		// Record the health check metrics
+		recordHealthMetrics(ctx, metrics, pathPrefix+"/health", cfg.Version, output.Body)
+
+		return output, nil
 	})
 }
 
-// recordHealthMetrics records the health check metrics
-func recordHealthMetrics(ctx context.Context, metrics *telemetry.Metrics, path string, version string) {
-	attrs := []attribute.KeyValue{
-		attribute.String("path", path),
-		attribute.String("version", version),
-		attribute.String("service", telemetry.Namespace),
+// degrade marks the service as degraded, failing readiness probes
+func (o *HealthOutput) degrade() {
+	o.Status = http.StatusServiceUnavailable
+	o.Body.Status = "degraded"
+}
+
+// checkS3 confirms the S3 data file is reachable, bounding the request by timeout
+func checkS3(ctx context.Context, registry service.RegistryService, timeout time.Duration) *CheckBody {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	// metric : Up status (1 = healthy, 0 = unhealthy)
-	metrics.Up.Record(ctx, 1, metric.WithAttributes(attrs...))
+	if err := registry.CheckS3(ctx); err != nil {
+		return &CheckBody{Status: "error", Error: err.Error()}
+	}
+	return &CheckBody{Status: "ok"}
+}
+
+// recordHealthMetrics records the health check metrics
+func recordHealthMetrics(ctx context.Context, metrics telemetry.Recorder, path string, version string, body HealthBody) {
+	metrics.RecordHealth(ctx, path, version, body.Status == "ok")
+
+	// metric : Unix time of the last successful data sync
+	if body.LastSync != nil {
+		metrics.RecordLastSync(ctx, body.LastSync.At, body.LastSync.Source)
+	}
 }
@@ -2,16 +2,24 @@ package v0_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
 
@@ -56,7 +64,7 @@ func TestHealthEndpoint(t *testing.T) {
 			shutdownTelemetry, metrics, _ := telemetry.InitMetrics("test")
 
 			// Register the health endpoint
-			v0.RegisterHealthEndpoint(api, "/v0", tc.config, metrics)
+			v0.RegisterHealthEndpoint(api, "/v0", tc.config, nil, metrics)
 
 			// Create a test request
 			req := httptest.NewRequest(http.MethodGet, "/v0/health", nil)
@@ -84,3 +92,153 @@ func TestHealthEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthEndpoint_SyncStaleness(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"servers":[]}`), 0o600))
+	db, err := database.NewJSONFileDB(context.Background(), filePath)
+	require.NoError(t, err)
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+
+	cfg := &config.Config{SyncStalenessThreshold: 50 * time.Millisecond}
+	registryService := service.NewRegistryService(db, cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	shutdownTelemetry, metrics, _ := telemetry.InitMetrics("test")
+	defer func() { _ = shutdownTelemetry(context.Background()) }()
+	v0.RegisterHealthEndpoint(api, "/v0", cfg, registryService, metrics)
+
+	getHealth := func() (int, v0.HealthBody) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/health", nil))
+		var body v0.HealthBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	// Fresh data is healthy and reports where it came from
+	code, body := getHealth()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+	require.NotNil(t, body.LastSync)
+	assert.Equal(t, "s3://registry-bucket/registry.json", body.LastSync.Source)
+	assert.False(t, body.LastSync.Stale)
+
+	// Once the threshold passes without a sync, readiness degrades
+	time.Sleep(100 * time.Millisecond)
+	code, body = getHealth()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "degraded", body.Status)
+	require.NotNil(t, body.LastSync)
+	assert.True(t, body.LastSync.Stale)
+
+	// A new sync restores it
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+	code, body = getHealth()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+}
+
+// s3CheckRegistry stubs the S3 reachability check of a registry service
+type s3CheckRegistry struct {
+	service.RegistryService
+	err error
+}
+
+func (r *s3CheckRegistry) CheckS3(_ context.Context) error {
+	return r.err
+}
+
+func TestHealthEndpoint_S3Check(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkErr       error
+		expectedStatus int
+		expectedHealth string
+		expectedS3     v0.CheckBody
+	}{
+		{
+			name:           "reachable bucket",
+			expectedStatus: http.StatusOK,
+			expectedHealth: "ok",
+			expectedS3:     v0.CheckBody{Status: "ok"},
+		},
+		{
+			name:           "access denied",
+			checkErr:       errors.New("failed to head object s3://registry-bucket/registry.json: api error AccessDenied: Access Denied"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHealth: "degraded",
+			expectedS3: v0.CheckBody{
+				Status: "error",
+				Error:  "failed to head object s3://registry-bucket/registry.json: api error AccessDenied: Access Denied",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{S3HealthCheck: true, S3HealthCheckTimeout: time.Second}
+			registry := &s3CheckRegistry{
+				RegistryService: service.NewRegistryService(database.NewTestJSONFileDB(t), cfg),
+				err:             tc.checkErr,
+			}
+
+			mux := http.NewServeMux()
+			api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+			shutdownTelemetry, metrics, _ := telemetry.InitMetrics("test")
+			defer func() { _ = shutdownTelemetry(context.Background()) }()
+			v0.RegisterHealthEndpoint(api, "/v0", cfg, registry, metrics)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/health", nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			var body v0.HealthBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tc.expectedHealth, body.Status)
+			require.NotNil(t, body.S3)
+			assert.Equal(t, tc.expectedS3, *body.S3)
+		})
+	}
+}
+
+// recordingMetrics is a metrics fake recording the health checks and syncs reported to it
+type recordingMetrics struct {
+	telemetry.NoopRecorder
+	health    []string
+	lastSyncs []string
+}
+
+func (m *recordingMetrics) RecordHealth(_ context.Context, path, _ string, up bool) {
+	status := "down"
+	if up {
+		status = "up"
+	}
+	m.health = append(m.health, path+" "+status)
+}
+
+func (m *recordingMetrics) RecordLastSync(_ context.Context, _ time.Time, source string) {
+	m.lastSyncs = append(m.lastSyncs, source)
+}
+
+func TestHealthEndpoint_RecordsMetrics(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"servers":[]}`), 0o600))
+	db, err := database.NewJSONFileDB(context.Background(), filePath)
+	require.NoError(t, err)
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+
+	cfg := &config.Config{Version: "test"}
+	metrics := &recordingMetrics{}
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterHealthEndpoint(api, "/v0", cfg, service.NewRegistryService(db, cfg), metrics)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/health", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, []string{"/v0/health up"}, metrics.health)
+	assert.Equal(t, []string{"s3://registry-bucket/registry.json"}, metrics.lastSyncs)
+}
@@ -0,0 +1,151 @@
+package v0
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// resolvedURLField is the field added to each package when packages are resolved
+const resolvedURLField = "resolvedUrl"
+
+// ociDefaultRegistry is the registry images without a registry host in their reference are pulled from
+const ociDefaultRegistry = "registry-1.docker.io"
+
+// resolvePackageURL returns the fully-qualified URL a package is downloaded from, based on its registry type,
+// and "" for registry types it doesn't know how to resolve
+func resolvePackageURL(pkg model.Package) string {
+	switch pkg.RegistryType {
+	case model.RegistryTypeNPM:
+		return resolveNPMURL(pkg)
+	case model.RegistryTypePyPI:
+		return resolvePyPIURL(pkg)
+	case model.RegistryTypeNuGet:
+		return resolveNuGetURL(pkg)
+	case model.RegistryTypeOCI:
+		return resolveOCIURL(pkg)
+	default:
+		return ""
+	}
+}
+
+// registryBase returns a package's registry base URL, or fallback when it doesn't name one
+func registryBase(pkg model.Package, fallback string) string {
+	if pkg.RegistryBaseURL != "" {
+		return strings.TrimSuffix(pkg.RegistryBaseURL, "/")
+	}
+	return fallback
+}
+
+// resolveNPMURL resolves an npm package to its tarball, e.g. https://registry.npmjs.org/@scope/name/-/name-1.0.0.tgz
+func resolveNPMURL(pkg model.Package) string {
+	if pkg.Identifier == "" || pkg.Version == "" {
+		return ""
+	}
+	return registryBase(pkg, model.RegistryURLNPM) + "/" + pkg.Identifier + "/-/" +
+		path.Base(pkg.Identifier) + "-" + url.PathEscape(pkg.Version) + ".tgz"
+}
+
+// resolvePyPIURL resolves a PyPI package to its release page, e.g. https://pypi.org/project/name/1.0.0/
+func resolvePyPIURL(pkg model.Package) string {
+	if pkg.Identifier == "" || pkg.Version == "" {
+		return ""
+	}
+	return registryBase(pkg, model.RegistryURLPyPI) + "/project/" + url.PathEscape(pkg.Identifier) + "/" +
+		url.PathEscape(pkg.Version) + "/"
+}
+
+// resolveNuGetURL resolves a NuGet package to its .nupkg in the flat container, which uses lowercase IDs and versions
+func resolveNuGetURL(pkg model.Package) string {
+	if pkg.Identifier == "" || pkg.Version == "" {
+		return ""
+	}
+	id := url.PathEscape(strings.ToLower(pkg.Identifier))
+	version := url.PathEscape(strings.ToLower(pkg.Version))
+	return registryBase(pkg, model.RegistryURLNuGet) + "/v3-flatcontainer/" + id + "/" + version + "/" + id + "." + version + ".nupkg"
+}
+
+// resolveOCIURL resolves an image reference to its manifest on the image's registry host, e.g.
+// https://ghcr.io/v2/owner/repo/manifests/1.0.0. Images without a registry host come from Docker Hub.
+func resolveOCIURL(pkg model.Package) string {
+	ref := pkg.Identifier
+	if ref == "" {
+		return ""
+	}
+
+	// The reference is a digest or a tag; without either, fall back to the package version, then to latest
+	var reference string
+	if image, digest, ok := strings.Cut(ref, "@"); ok {
+		ref, reference = image, digest
+	} else if i := strings.LastIndex(ref, ":"); i >= 0 && !strings.Contains(ref[i:], "/") {
+		ref, reference = ref[:i], ref[i+1:]
+	}
+	if reference == "" {
+		reference = pkg.Version
+	}
+	if reference == "" {
+		reference = "latest"
+	}
+
+	// As with docker, the first component is a registry host only if it looks like one
+	host, repository := ociDefaultRegistry, ref
+	if first, rest, ok := strings.Cut(ref, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, repository = first, rest
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = ociDefaultRegistry
+	}
+	if host == ociDefaultRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	if repository == "" {
+		return ""
+	}
+
+	return "https://" + host + "/v2/" + repository + "/manifests/" + reference
+}
+
+// resolvePackages adds the resolved URL to each serialized package of a server that has one, leaving packages
+// of unknown registry types as they are
+func resolvePackages(packages json.RawMessage) (json.RawMessage, error) {
+	var decoded []json.RawMessage
+	if err := json.Unmarshal(packages, &decoded); err != nil {
+		return nil, err
+	}
+
+	for i, raw := range decoded {
+		var pkg model.Package
+		if err := json.Unmarshal(raw, &pkg); err != nil {
+			return nil, err
+		}
+		resolved := resolvePackageURL(pkg)
+		if resolved == "" {
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(raw)
+		if len(trimmed) < 2 || trimmed[len(trimmed)-1] != '}' {
+			continue
+		}
+		value, err := json.Marshal(resolved)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		buf.Write(trimmed[:len(trimmed)-1])
+		if len(bytes.TrimSpace(trimmed[1:len(trimmed)-1])) > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"` + resolvedURLField + `":`)
+		buf.Write(value)
+		buf.WriteByte('}')
+		decoded[i] = buf.Bytes()
+	}
+
+	return json.Marshal(decoded)
+}
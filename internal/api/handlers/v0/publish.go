@@ -2,38 +2,61 @@ package v0
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
 
+// ValidationErrorModel is the 400 response for server.json payloads that fail validation.
+// It extends the standard error model with every failure, so publishers can fix them all in one pass.
+type ValidationErrorModel struct {
+	huma.ErrorModel
+	ValidationErrors []validators.FieldError `json:"validationErrors" doc:"Every validation failure found in the server.json"`
+}
+
 // PublishServerInput represents the input for publishing a server
 type PublishServerInput struct {
 	Authorization string           `header:"Authorization" doc:"Registry JWT token (obtained from /v0/auth/token/github)" required:"true"`
+	DryRun        bool             `query:"dry_run" doc:"Run every publish check and return the version that would be stored, without storing it" required:"false"`
 	Body          apiv0.ServerJSON `body:""`
 }
 
+// PublishServerOutput is the published server, or with a dry run the server that would have been published
+type PublishServerOutput struct {
+	DryRun string `header:"X-Dry-Run" doc:"'true' when nothing was stored because the request was a dry run"`
+	Body   apiv0.ServerResponse
+}
+
 // RegisterPublishEndpoint registers the publish endpoint with a custom path prefix
 func RegisterPublishEndpoint(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
 	// Create JWT manager for token validation
 	jwtManager := auth.NewJWTManager(cfg)
+	limiter := newPublishRateLimiter(func(serverName string) float64 {
+		return cfg.PublishLimits(serverName).PublishRPS
+	})
 
-	huma.Register(api, huma.Operation{
+	huma.Register(api, withBodyLimit(api, cfg, huma.Operation{
 		OperationID: "publish-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
 		Method:      http.MethodPost,
 		Path:        pathPrefix + "/publish",
 		Summary:     "Publish MCP server",
-		Description: "Publish a new MCP server to the registry or update an existing one",
+		Description: "Publish a new MCP server to the registry or update an existing one. With dry_run=true, every publish check runs and the version that would be stored is returned, but nothing is stored.",
 		Tags:        []string{"publish"},
 		Security: []map[string][]string{
 			{"bearer": {}},
 		},
-	}, func(ctx context.Context, input *PublishServerInput) (*Response[apiv0.ServerResponse], error) {
+	}), func(ctx context.Context, input *PublishServerInput) (*PublishServerOutput, error) {
 		// Extract bearer token
 		const bearerPrefix = "Bearer "
 		authHeader := input.Authorization
@@ -53,19 +76,110 @@ func RegisterPublishEndpoint(api huma.API, pathPrefix string, registry service.R
 			return nil, huma.Error403Forbidden(buildPermissionErrorMessage(input.Body.Name, claims.Permissions))
 		}
 
-		// Publish the server with extensions
-		publishedServer, err := registry.CreateServer(ctx, &input.Body)
-		if err != nil {
-			return nil, huma.Error400BadRequest("Failed to publish server", err)
+		// Throttle rapid republishing of one server before it contends for the publish lock; dry runs don't
+		// store anything, so they don't use up the budget
+		if !input.DryRun {
+			if ok, retryAfter := limiter.allow(input.Body.Name, time.Now()); !ok {
+				return nil, huma.ErrorWithHeaders(
+					huma.Error429TooManyRequests("Too many publishes of "+input.Body.Name+", please retry later"),
+					http.Header{"Retry-After": {strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))}},
+				)
+			}
+		}
+
+		// Publish the server with extensions, or with a dry run only work out what would be published
+		// An identical republish returns the existing version; conflicting content is rejected
+		publish := registry.CreateServer
+		if input.DryRun {
+			publish = registry.PreviewServer
+		}
+		publishedServer, err := publish(withRequestActor(ctx, claimsActor(claims)), &input.Body)
+		if err != nil && !errors.Is(err, database.ErrAlreadyPublished) {
+			if errors.Is(err, database.ErrInvalidVersion) || errors.Is(err, database.ErrVersionNotNewer) ||
+				errors.Is(err, database.ErrMaxServersReached) {
+				return nil, huma.Error409Conflict("Failed to publish server", err)
+			}
+			if errors.Is(err, database.ErrRegistryFull) {
+				return nil, huma.NewError(http.StatusInsufficientStorage, "Failed to publish server", err)
+			}
+			if errors.Is(err, validators.ErrDisallowedPackageRegistry) || errors.Is(err, validators.ErrUnpinnedOCIPackage) {
+				return nil, huma.Error422UnprocessableEntity("Failed to publish server", err)
+			}
+			if errors.Is(err, database.ErrDatabase) {
+				return nil, databaseError(ctx, "Failed to publish server", err)
+			}
+			return nil, badRequest("Failed to publish server", err)
 		}
 
 		// Return the published server response with metadata
-		return &Response[apiv0.ServerResponse]{
-			Body: *publishedServer,
+		output := &PublishServerOutput{Body: *publishedServer}
+		if input.DryRun {
+			output.DryRun = "true"
+		}
+		return output, nil
+	})
+}
+
+// ValidateServerInput represents the input for validating a server without publishing it
+type ValidateServerInput struct {
+	Body apiv0.ServerJSON `body:""`
+}
+
+// RegisterValidateEndpoint registers the endpoint that checks a server.json against the publish rules
+// without storing it
+func RegisterValidateEndpoint(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	huma.Register(api, withBodyLimit(api, cfg, huma.Operation{
+		OperationID: "validate-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        pathPrefix + "/servers/validate",
+		Summary:     "Validate MCP server",
+		Description: "Check a server.json against every rule applied when publishing, without publishing it. Returns the server as it would be stored, or 422 listing every problem.",
+		Tags:        []string{"publish"},
+	}), func(ctx context.Context, input *ValidateServerInput) (*Response[apiv0.ServerJSON], error) {
+		normalized, err := registry.ValidateServer(ctx, &input.Body)
+		if err != nil {
+			return nil, validationFailure(http.StatusUnprocessableEntity, "Server failed validation", err)
+		}
+
+		return &Response[apiv0.ServerJSON]{
+			Body: *normalized,
 		}, nil
 	})
 }
 
+// badRequest returns a 400 error, listing every validation failure when err carries them
+func badRequest(msg string, err error) error {
+	return validationFailure(http.StatusBadRequest, msg, err)
+}
+
+// validationFailure returns an error with the given status, listing every validation failure
+// when err carries them
+func validationFailure(status int, msg string, err error) error {
+	var validationErrs validators.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return huma.NewError(status, msg, err)
+	}
+
+	details := make([]*huma.ErrorDetail, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		location := "body"
+		if fieldErr.Field != "" {
+			location += "." + fieldErr.Field
+		}
+		details[i] = &huma.ErrorDetail{Message: fieldErr.Message, Location: location}
+	}
+
+	return &ValidationErrorModel{
+		ErrorModel: huma.ErrorModel{
+			Status: status,
+			Title:  http.StatusText(status),
+			Detail: msg,
+			Errors: details,
+		},
+		ValidationErrors: validationErrs,
+	}
+}
+
 // buildPermissionErrorMessage creates a detailed error message showing what permissions
 // the user has and what they're trying to publish
 func buildPermissionErrorMessage(attemptedResource string, permissions []auth.Permission) string {
@@ -7,9 +7,12 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
@@ -201,7 +204,7 @@ func TestPublishEndpoint(t *testing.T) {
 				}
 				_, _ = registry.CreateServer(context.Background(), &existingServer)
 			},
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusConflict,
 			expectedError:  "invalid version: cannot publish duplicate version",
 		},
 		{
@@ -524,3 +527,922 @@ func TestPublishEndpoint_MultipleSlashesEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// TestPublishEndpoint_Republish tests that republishing an identical version succeeds while conflicting content is rejected
+func TestPublishEndpoint_Republish(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(server apiv0.ServerJSON) *httptest.ResponseRecorder {
+		body, err := json.Marshal(server)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	server := apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/republish-server",
+		Description: "A server published twice",
+		Version:     "1.0.0",
+	}
+
+	first := publish(server)
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	var firstResp apiv0.ServerResponse
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &firstResp))
+
+	t.Run("identical republish returns existing version", func(t *testing.T) {
+		rr := publish(server)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var resp apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, server.Description, resp.Server.Description)
+		require.NotNil(t, resp.Meta.Official)
+		assert.True(t, firstResp.Meta.Official.PublishedAt.Equal(resp.Meta.Official.PublishedAt), "published_at should be unchanged")
+	})
+
+	t.Run("conflicting republish is rejected", func(t *testing.T) {
+		conflicting := server
+		conflicting.Description = "Different content under the same version"
+
+		rr := publish(conflicting)
+		assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "cannot publish duplicate version")
+	})
+
+	versions, err := registryService.GetAllVersionsByServerName(context.Background(), server.Name)
+	require.NoError(t, err)
+	assert.Len(t, versions, 1)
+}
+
+func TestPublishEndpoint_PublishedBy(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	tests := []struct {
+		name            string
+		claims          auth.JWTClaims
+		serverName      string
+		wantPublishedBy string
+	}{
+		{
+			name: "github user",
+			claims: auth.JWTClaims{
+				AuthMethod:        auth.MethodGitHubAT,
+				AuthMethodSubject: "octocat",
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.octocat/*"},
+				},
+			},
+			serverName:      "io.github.octocat/weather",
+			wantPublishedBy: "octocat",
+		},
+		{
+			name: "anonymous",
+			claims: auth.JWTClaims{
+				AuthMethod:        auth.MethodNone,
+				AuthMethodSubject: "anonymous",
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublish, ResourcePattern: "io.modelcontextprotocol.anonymous/*"},
+				},
+			},
+			serverName:      "io.modelcontextprotocol.anonymous/weather",
+			wantPublishedBy: "anonymous",
+		},
+		{
+			name: "token without a subject",
+			claims: auth.JWTClaims{
+				AuthMethod: auth.MethodNone,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+				},
+			},
+			serverName:      "com.example/no-subject",
+			wantPublishedBy: "anonymous",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := generateTestJWTToken(testConfig, tt.claims)
+			require.NoError(t, err)
+
+			body, err := json.Marshal(apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        tt.serverName,
+				Description: "A server with a known publisher",
+				Version:     "1.0.0",
+			})
+			require.NoError(t, err)
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+			var resp apiv0.ServerResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Meta.Official)
+			assert.Equal(t, tt.wantPublishedBy, resp.Meta.Official.PublishedBy)
+
+			stored, err := registryService.GetServerByNameAndVersion(context.Background(), tt.serverName, "1.0.0")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPublishedBy, stored.Meta.Official.PublishedBy)
+		})
+	}
+
+	t.Run("author filter matches the publisher", func(t *testing.T) {
+		author := "OctoCat"
+		servers, _, err := registryService.ListServers(context.Background(), &database.ServerFilter{Author: &author}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		assert.Equal(t, "io.github.octocat/weather", servers[0].Server.Name)
+	})
+}
+
+func TestPublishEndpoint_DryRun(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(server apiv0.ServerJSON, query string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(server)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish"+query, bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	versions := func() []string {
+		servers, err := registryService.GetAllVersionsByServerName(context.Background(), "com.example/dry-run-server")
+		if errors.Is(err, database.ErrNotFound) {
+			return nil
+		}
+		require.NoError(t, err)
+		var result []string
+		for _, server := range servers {
+			result = append(result, server.Server.Version)
+		}
+		return result
+	}
+
+	server := apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/dry-run-server",
+		Description: "A server previewed before publishing",
+		Version:     "2.0.0",
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+		},
+	}
+
+	t.Run("first version", func(t *testing.T) {
+		before := time.Now()
+		rr := publish(server, "?dry_run=true")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "true", rr.Header().Get("X-Dry-Run"))
+
+		var resp apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		// The response shows the normalized server and the computed registry metadata
+		assert.Len(t, resp.Server.Remotes, 1)
+		require.NotNil(t, resp.Meta.Official)
+		assert.True(t, resp.Meta.Official.IsLatest)
+		assert.Equal(t, model.StatusActive, resp.Meta.Official.Status)
+		assert.False(t, resp.Meta.Official.PublishedAt.Before(before.Truncate(time.Second)))
+
+		assert.Empty(t, versions(), "a dry run must not store anything")
+	})
+
+	rr := publish(server, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Empty(t, rr.Header().Get("X-Dry-Run"))
+
+	t.Run("older version is not the latest", func(t *testing.T) {
+		older := server
+		older.Version = "1.0.0"
+		older.Remotes = []model.Transport{{Type: "streamable-http", URL: "https://example.com/mcp"}}
+
+		rr := publish(older, "?dry_run=true")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.False(t, resp.Meta.Official.IsLatest)
+
+		latest, err := registryService.GetServerByName(context.Background(), server.Name)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", latest.Server.Version, "a dry run must not change the latest version")
+	})
+
+	t.Run("duplicate version is rejected", func(t *testing.T) {
+		conflicting := server
+		conflicting.Description = "Different content under the same version"
+
+		rr := publish(conflicting, "?dry_run=true")
+		assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	})
+
+	assert.Equal(t, []string{"2.0.0"}, versions())
+}
+
+func TestPublishEndpoint_ValidationErrors(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	// Violates several rules at once: version range, invalid repository URL, and an unsupported remote transport
+	body, err := json.Marshal(apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/invalid-server",
+		Description: "A server with several problems",
+		Version:     "^1.0.0",
+		Repository: &model.Repository{
+			URL:    "not-a-url",
+			Source: "github",
+		},
+		Remotes: []model.Transport{
+			{Type: model.TransportTypeStdio, URL: "https://example.com/mcp"},
+		},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+
+	var resp v0.ValidationErrorModel
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "Failed to publish server", resp.Detail)
+
+	fields := make([]string, len(resp.ValidationErrors))
+	for i, fieldErr := range resp.ValidationErrors {
+		fields[i] = fieldErr.Field
+		assert.NotEmpty(t, fieldErr.Message)
+	}
+	assert.Equal(t, []string{"version", "repository", "remotes[0]"}, fields)
+	assert.Len(t, resp.Errors, len(resp.ValidationErrors))
+}
+
+func TestPublishEndpoint_BodySizeLimit(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		MaxBodyBytes:             2048,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/size-limit-server",
+		Description: "A server for testing the body size limit",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	publish := func(body []byte) *httptest.ResponseRecorder {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("body over the limit is rejected", func(t *testing.T) {
+		// Trailing whitespace keeps the JSON valid while pushing it past the limit
+		oversized := append(bytes.Clone(body), bytes.Repeat([]byte(" "), 4096)...)
+		rr := publish(oversized)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "2048 byte limit")
+	})
+
+	t.Run("body under the limit is accepted", func(t *testing.T) {
+		require.Less(t, len(body), 2048)
+		rr := publish(body)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+}
+
+func TestPublishEndpoint_AllowedPackageRegistries(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		AllowedPackageRegistries: []string{model.RegistryTypeNPM, model.RegistryTypeOCI},
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(server apiv0.ServerJSON) *httptest.ResponseRecorder {
+		body, err := json.Marshal(server)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	npmPackage := model.Package{
+		RegistryType: model.RegistryTypeNPM,
+		Identifier:   "@example/allowed-server",
+		Version:      "1.0.0",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+
+	t.Run("only allowed registries", func(t *testing.T) {
+		rr := publish(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/allowed-server",
+			Description: "A server shipping from an allowed registry",
+			Version:     "1.0.0",
+			Packages:    []model.Package{npmPackage},
+		})
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("disallowed registry is rejected", func(t *testing.T) {
+		rr := publish(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/disallowed-server",
+			Description: "A server shipping from a disallowed registry",
+			Version:     "1.0.0",
+			Packages: []model.Package{
+				npmPackage,
+				{
+					RegistryType: model.RegistryTypePyPI,
+					Identifier:   "disallowed-server",
+					Version:      "1.0.0",
+					Transport:    model.Transport{Type: model.TransportTypeStdio},
+				},
+			},
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "packages[1] (disallowed-server) uses registry type 'pypi'")
+
+		_, err := registryService.GetServerByName(context.Background(), "com.example/disallowed-server")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestPublishEndpoint_RequireOCIDigest(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		RequireOCIDigest:         true,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(name string, identifiers ...string) *httptest.ResponseRecorder {
+		server := apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A server shipping an OCI image",
+			Version:     "1.0.0",
+		}
+		for _, identifier := range identifiers {
+			server.Packages = append(server.Packages, model.Package{
+				RegistryType: model.RegistryTypeOCI,
+				Identifier:   identifier,
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			})
+		}
+		body, err := json.Marshal(server)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	const digest = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	t.Run("digest-pinned image is accepted", func(t *testing.T) {
+		rr := publish("com.example/pinned-server", "ghcr.io/example/pinned-server@"+digest, "docker.io/example/tagged-and-pinned:1.0.0@"+digest)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("tag-only image is rejected", func(t *testing.T) {
+		rr := publish("com.example/tagged-server", "ghcr.io/example/pinned-server@"+digest, "ghcr.io/example/tagged-server:1.0.0")
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "packages[1] (ghcr.io/example/tagged-server:1.0.0) uses a tag without a digest")
+
+		_, err := registryService.GetServerByName(context.Background(), "com.example/tagged-server")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestPublishEndpoint_MonotonicVersions(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		EnforceMonotonicVersions: true,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/monotonic-server",
+			Description: "A server that only moves forward",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := publish("1.2.0")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = publish("1.1.0")
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), "not newer than 1.2.0")
+
+	rr = publish("1.3.0")
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestPublishEndpoint_MaxVersionsPerServer(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		MaxVersionsPerServer:     1,
+		MaxVersionsPolicy:        service.MaxVersionsPolicyReject,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/capped-server",
+			Description: "A server with a version limit",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := publish("1.0.0")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = publish("2.0.0")
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), "1 versions allowed")
+}
+
+func TestPublishEndpoint_MaxServers(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		MaxServers:               2,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(name, version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A server in a registry with a server limit",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for _, name := range []string{"com.example/first", "com.example/second"} {
+		rr := publish(name, "1.0.0")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	t.Run("new server is rejected at the limit", func(t *testing.T) {
+		rr := publish("com.example/third", "1.0.0")
+		assert.Equal(t, http.StatusInsufficientStorage, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "2 servers allowed")
+
+		_, err := registryService.GetServerByName(context.Background(), "com.example/third")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("new version of an existing server is accepted at the limit", func(t *testing.T) {
+		rr := publish("com.example/first", "2.0.0")
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+}
+
+func TestPublishEndpoint_RateLimit(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		PublishRPS:               0.2, // one publish per server every 5 seconds
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(name, version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A frequently published server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := publish("com.example/busy-server", "1.0.0")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Rapid republishes of the same server are throttled
+	for _, version := range []string{"1.0.1", "1.0.2", "1.0.3"} {
+		rr = publish("com.example/busy-server", version)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code, rr.Body.String())
+		retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+		require.NoError(t, err, "Retry-After should be a number of seconds")
+		assert.InDelta(t, 5, retryAfter, 1)
+	}
+
+	_, err = registryService.GetServerByNameAndVersion(context.Background(), "com.example/busy-server", "1.0.1")
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	// Other servers have their own budget
+	rr = publish("com.example/quiet-server", "1.0.0")
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestPublishEndpoint_NamespaceRateLimit(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		PublishRPS:               0.2,
+		NamespaceQuotas: config.NamespaceQuotas{
+			"com.example/":         {PublishRPS: 100},
+			"com.example/trusted-": {PublishRPS: 1000},
+			"com.example/slow-":    {PublishRPS: 0.01},
+		},
+	}
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	token, err := generateTestJWTToken(testConfig, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	publish := func(name, version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A frequently published server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/publish", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Servers under a namespace quota get its rate instead of the global one
+	for _, version := range []string{"1.0.0", "1.0.1", "1.0.2"} {
+		rr := publish("com.example/trusted-server", version)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	// The most specific prefix wins, even when it is stricter than the namespace's
+	rr := publish("com.example/slow-server", "1.0.0")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = publish("com.example/slow-server", "1.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, rr.Body.String())
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 100, retryAfter, 1)
+
+	// Servers outside every quota keep the global rate
+	rr = publish("org.example/server", "1.0.0")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = publish("org.example/server", "1.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, rr.Body.String())
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	testConfig := &config.Config{
+		EnableRegistryValidation: false,
+		AllowedPackageRegistries: []string{model.RegistryTypeNPM},
+	}
+	testDB := database.NewTestJSONFileDB(t)
+	registryService := service.NewRegistryService(testDB, testConfig)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterValidateEndpoint(api, "/v0", registryService, testConfig)
+
+	validate := func(server apiv0.ServerJSON) *httptest.ResponseRecorder {
+		body, err := json.Marshal(server)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/v0/servers/validate", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("valid server is normalized and not stored", func(t *testing.T) {
+		remote := model.Transport{Type: "streamable-http", URL: "https://example.com/mcp"}
+		rr := validate(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/valid-server",
+			Description: "A server that passes every check",
+			Version:     "1.0.0",
+			Remotes:     []model.Transport{remote, remote},
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var normalized apiv0.ServerJSON
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &normalized))
+		assert.Equal(t, "com.example/valid-server", normalized.Name)
+		assert.Equal(t, []model.Transport{remote}, normalized.Remotes, "duplicate remotes should be collapsed")
+
+		_, err := registryService.GetServerByName(context.Background(), "com.example/valid-server")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("every problem is reported", func(t *testing.T) {
+		rr := validate(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/invalid-server",
+			Description: "A server with several problems",
+			Version:     "^1.0.0",
+			Repository: &model.Repository{
+				URL:    "not-a-url",
+				Source: "github",
+			},
+			Remotes: []model.Transport{
+				{Type: model.TransportTypeStdio, URL: "https://example.com/mcp"},
+			},
+		})
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+
+		var resp v0.ValidationErrorModel
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		fields := make([]string, len(resp.ValidationErrors))
+		for i, fieldErr := range resp.ValidationErrors {
+			fields[i] = fieldErr.Field
+			assert.NotEmpty(t, fieldErr.Message)
+		}
+		assert.Equal(t, []string{"version", "repository", "remotes[0]"}, fields)
+	})
+
+	t.Run("package registry allowlist", func(t *testing.T) {
+		rr := validate(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/pypi-server",
+			Description: "A server shipping from a disallowed registry",
+			Version:     "1.0.0",
+			Packages: []model.Package{{
+				RegistryType: model.RegistryTypePyPI,
+				Identifier:   "pypi-server",
+				Version:      "1.0.0",
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			}},
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "uses registry type 'pypi'")
+	})
+}
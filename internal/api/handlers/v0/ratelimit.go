@@ -0,0 +1,69 @@
+package v0
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxIdlePublishLimiters is how many per-server limiters are kept before idle ones are pruned
+const maxIdlePublishLimiters = 10000
+
+// publishRateLimiter limits how often each server can be published, with a token bucket per
+// normalized server name, so one publisher can't thrash the publish lock and storage
+type publishRateLimiter struct {
+	mu       sync.Mutex
+	rps      func(serverName string) float64 // publishes per second allowed for a server; not positive is unlimited
+	limiters map[string]*rate.Limiter
+}
+
+// newPublishRateLimiter allows rps(serverName) publishes per second per server, with bursts of up to
+// ceil of that rate. Servers whose rate is not positive are unlimited.
+func newPublishRateLimiter(rps func(serverName string) float64) *publishRateLimiter {
+	return &publishRateLimiter{
+		rps:      rps,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow takes a token for serverName, returning how long to wait before retrying if none is left
+func (l *publishRateLimiter) allow(serverName string, now time.Time) (bool, time.Duration) {
+	rps := l.rps(serverName)
+	if rps <= 0 {
+		return true, 0
+	}
+
+	key := strings.ToLower(strings.TrimSpace(serverName))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		if len(l.limiters) >= maxIdlePublishLimiters {
+			l.pruneIdle(now)
+		}
+		limiter = rate.NewLimiter(rate.Limit(rps), max(1, int(math.Ceil(rps))))
+		l.limiters[key] = limiter
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// pruneIdle drops limiters whose bucket has refilled, since they hold no state worth keeping.
+// Callers must hold l.mu.
+func (l *publishRateLimiter) pruneIdle(now time.Time) {
+	for key, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, key)
+		}
+	}
+}
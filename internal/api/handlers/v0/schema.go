@@ -0,0 +1,64 @@
+package v0
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/schemaversion"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// SchemaInput represents the input for getting a specific version of the server.json schema
+type SchemaInput struct {
+	Version string `path:"version" doc:"Schema version" example:"2025-10-17"`
+}
+
+// SchemaOutput is the server.json JSON Schema, served as application/schema+json
+type SchemaOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        json.RawMessage
+}
+
+// RegisterSchemaEndpoints registers the endpoints serving the server.json JSON Schema with a custom path prefix
+func RegisterSchemaEndpoints(api huma.API, pathPrefix string) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-schema" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/schema",
+		Summary:     "Get server.json schema",
+		Description: "Returns the JSON Schema that published server.json files are validated against, at the schema version the registry requires.",
+		Tags:        []string{"schema"},
+	}, func(_ context.Context, _ *struct{}) (*SchemaOutput, error) {
+		return schemaResponse(model.CurrentSchemaVersion)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-schema-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/schema/{version}",
+		Summary:     "Get a specific server.json schema version",
+		Description: "Returns the JSON Schema for server.json at a specific schema version. Only the current schema version is served; older versions that servers can still be converted to return 404.",
+		Tags:        []string{"schema"},
+	}, func(_ context.Context, input *SchemaInput) (*SchemaOutput, error) {
+		return schemaResponse(input.Version)
+	})
+}
+
+// schemaResponse serves the embedded schema of a schema version
+func schemaResponse(version string) (*SchemaOutput, error) {
+	schema, ok := schemaversion.Schema(version)
+	if !ok {
+		if schemaversion.IsSupported(version) {
+			return nil, huma.Error404NotFound("Schema version '" + version + "' is not served; servers can be converted to it, but only the schema of " + model.CurrentSchemaVersion + " is available")
+		}
+		return nil, huma.Error404NotFound("Schema version '" + version + "' not found")
+	}
+	return &SchemaOutput{
+		ContentType: "application/schema+json",
+		Body:        schema,
+	}, nil
+}
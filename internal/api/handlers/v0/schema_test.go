@@ -0,0 +1,81 @@
+package v0_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestSchemaEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterSchemaEndpoints(api, "/v0")
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("current schema", func(t *testing.T) {
+		w := get("/v0/schema")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+
+		// The schema compiles and is the version publishes are validated against
+		compiler := jsonschema.NewCompiler()
+		require.NoError(t, compiler.AddResource(model.CurrentSchemaURL, bytes.NewReader(w.Body.Bytes())))
+		schema, err := compiler.Compile(model.CurrentSchemaURL)
+		require.NoError(t, err)
+
+		var doc struct {
+			ID string `json:"$id"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, model.CurrentSchemaURL, doc.ID)
+
+		// A server.json accepted by the registry validates against it
+		server := map[string]any{
+			"$schema":     model.CurrentSchemaURL,
+			"name":        "com.example/schema-test",
+			"description": "Validated against the served schema",
+			"version":     "1.0.0",
+		}
+		require.NoError(t, schema.Validate(server))
+		delete(server, "name")
+		require.Error(t, schema.Validate(server))
+	})
+
+	t.Run("specific version", func(t *testing.T) {
+		w := get("/v0/schema/" + model.CurrentSchemaVersion)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, get("/v0/schema").Body.String(), w.Body.String())
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		for _, version := range []string{"2024-01-01", "latest"} {
+			w := get("/v0/schema/" + version)
+			assert.Equal(t, http.StatusNotFound, w.Code, version)
+			assert.Contains(t, w.Body.String(), "not found", version)
+		}
+	})
+
+	t.Run("older supported version", func(t *testing.T) {
+		for _, version := range []string{"2025-09-29", "2025-10-11"} {
+			w := get("/v0/schema/" + version)
+			assert.Equal(t, http.StatusNotFound, w.Code, version)
+			assert.Contains(t, w.Body.String(), "only the schema of "+model.CurrentSchemaVersion+" is available", version)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package v0
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/schemaversion"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// schemaVersionParam is the Accept header media type parameter that requests a server.json schema version,
+// e.g. `Accept: application/json; schema-version=2025-10-11`
+const schemaVersionParam = "schema-version"
+
+// parseSchemaVersion returns the server.json schema version requested by the schema_version query parameter,
+// or failing that the Accept header, and "" to serve servers in their stored version, upgraded if outdated
+func parseSchemaVersion(query, accept string) (string, error) {
+	if query != "" {
+		if !schemaversion.IsSupported(query) {
+			return "", huma.Error400BadRequest(unsupportedSchemaVersion(query))
+		}
+		return query, nil
+	}
+
+	for mediaRange := range strings.SplitSeq(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		version, ok := params[schemaVersionParam]
+		if !ok {
+			continue
+		}
+		if !schemaversion.IsSupported(version) {
+			return "", huma.Error406NotAcceptable(unsupportedSchemaVersion(version))
+		}
+		return version, nil
+	}
+
+	return "", nil
+}
+
+// unsupportedSchemaVersion describes a request for a schema version servers can't be converted to
+func unsupportedSchemaVersion(version string) string {
+	return "Unsupported schema version '" + version + "': expected one of " + strings.Join(schemaversion.Versions(), ", ")
+}
+
+// parseView builds the serialization of servers requested by the fields, schema_version and resolve_packages
+// query parameters and the Accept header
+func parseView(fields, schemaVersion, accept string, resolvePackages bool) (serverView, error) {
+	requested, err := parseFields(fields)
+	if err != nil {
+		return serverView{}, err
+	}
+	version, err := parseSchemaVersion(schemaVersion, accept)
+	if err != nil {
+		return serverView{}, err
+	}
+	return serverView{fields: requested, schemaVersion: version, packages: resolvePackages}, nil
+}
+
+// upgradeResponse presents a server stored under an older server.json schema version in the current one, so
+// old records read like new ones without a data migration. The stored record is left untouched. Servers in the
+// current or an unknown schema version are returned as stored, as are servers that fail to convert.
+func upgradeResponse(response apiv0.ServerResponse) apiv0.ServerResponse {
+	if !schemaversion.IsOutdated(response.Server.Schema) {
+		return response
+	}
+
+	data, err := json.Marshal(response.Server)
+	if err == nil {
+		data, err = schemaversion.Upgrade(data)
+	}
+	var upgraded apiv0.ServerJSON
+	if err == nil {
+		err = json.Unmarshal(data, &upgraded)
+	}
+	if err != nil {
+		log.Printf("Serving %s@%s in its stored schema version: %v", response.Server.Name, response.Server.Version, err)
+		return response
+	}
+
+	response.Server = upgraded
+	return response
+}
+
+// upgradeResponses applies upgradeResponse to each server in place
+func upgradeResponses(responses []apiv0.ServerResponse) {
+	for i := range responses {
+		responses[i] = upgradeResponse(responses[i])
+	}
+}
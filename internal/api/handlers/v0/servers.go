@@ -2,9 +2,12 @@ package v0
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,27 +21,152 @@ const errRecordNotFound = "record not found"
 
 // ListServersInput represents the input for listing servers
 type ListServersInput struct {
-	Cursor       string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
-	Limit        int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
-	UpdatedSince string `query:"updated_since" doc:"Filter servers updated since timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
-	Search       string `query:"search" doc:"Search servers by name (substring match)" required:"false" example:"filesystem"`
-	Version      string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	Cursor          string  `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
+	Offset          string  `query:"offset" doc:"Number of matching servers to skip, for clients that can't keep a cursor. Cursors are recommended: offset pages may skip or repeat servers published or removed between requests. Can't be combined with cursor." required:"false" example:"60"`
+	Limit           int     `query:"limit" doc:"Number of items per page; defaults to the registry's page size and is capped at its maximum, both listed at /capabilities" required:"false" minimum:"1" example:"50"`
+	UpdatedSince    string  `query:"updated_since" doc:"Filter servers updated since timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	Search          string  `query:"search" doc:"Search servers by name (substring match, ignoring case and accents)" required:"false" example:"filesystem"`
+	SearchMode      string  `query:"search_mode" enum:"substring,fuzzy" doc:"How search matches names: 'substring' (default), or 'fuzzy' to tolerate typos, ranking matches by similarity without pagination" required:"false" example:"fuzzy"`
+	MinSimilarity   float64 `query:"min_similarity" minimum:"0" maximum:"1" doc:"Trigram similarity from 0 to 1 a name needs to match a fuzzy search; defaults to the registry's configured threshold" required:"false" example:"0.3"`
+	Prefix          string  `query:"prefix" doc:"Filter servers whose name starts with this prefix" required:"false" example:"io.github.acme/"`
+	Version         string  `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	HasRemotes      string  `query:"has_remotes" enum:"true,false" doc:"Only return servers that have at least one remote ('true') or none ('false')" required:"false" example:"true"`
+	HasPackages     string  `query:"has_packages" enum:"true,false" doc:"Only return servers that have at least one package ('true') or none ('false')" required:"false" example:"false"`
+	HasProvenance   string  `query:"has_provenance" enum:"true,false" doc:"Only return servers that have at least one provenance attestation ('true') or none ('false')" required:"false" example:"true"`
+	Tag             string  `query:"tag" doc:"Only return servers carrying this tag" required:"false" example:"database"`
+	TransportType   string  `query:"transport_type" doc:"Only return servers with a remote of this transport type, e.g. 'streamable-http' or 'sse'" required:"false" example:"sse"`
+	Author          string  `query:"author" doc:"Only return servers whose repository is owned by this user or organization, or that were published by this identity, ignoring case, e.g. 'acme' for https://github.com/acme/weather" required:"false" example:"acme"`
+	Fields          string  `query:"fields" doc:"Comma-separated top-level server fields to return (e.g. 'name,version,description'); all fields are returned when unset" required:"false" example:"name,version,description"`
+	SchemaVersion   string  `query:"schema_version" doc:"server.json schema version to serve servers in (e.g. '2025-10-11'); servers stored under an older version are upgraded to the current one when unset" required:"false" example:"2025-10-11"`
+	ResolvePackages bool    `query:"resolve_packages" doc:"Add a resolvedUrl to each package with its fully-qualified download URL, based on its registry type (npm, pypi, nuget, oci); packages of other types are left as they are" required:"false" example:"true"`
+	Accept          string  `header:"Accept" doc:"Media type to respond with; a schema-version parameter requests a server.json schema version, e.g. 'application/json; schema-version=2025-10-11'" required:"false"`
+}
+
+// NamespaceServersInput represents the input for listing the servers in a namespace
+type NamespaceServersInput struct {
+	Prefix          string `path:"prefix" doc:"URL-encoded server name prefix" example:"io.github.acme%2F"`
+	Cursor          string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
+	Limit           int    `query:"limit" doc:"Number of items per page; defaults to the registry's page size and is capped at its maximum, both listed at /capabilities" required:"false" minimum:"1" example:"50"`
+	Version         string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	Fields          string `query:"fields" doc:"Comma-separated top-level server fields to return (e.g. 'name,version,description'); all fields are returned when unset" required:"false" example:"name,version,description"`
+	SchemaVersion   string `query:"schema_version" doc:"server.json schema version to serve servers in (e.g. '2025-10-11'); servers stored under an older version are upgraded to the current one when unset" required:"false" example:"2025-10-11"`
+	ResolvePackages bool   `query:"resolve_packages" doc:"Add a resolvedUrl to each package with its fully-qualified download URL, based on its registry type (npm, pypi, nuget, oci); packages of other types are left as they are" required:"false" example:"true"`
+	Accept          string `header:"Accept" doc:"Media type to respond with; a schema-version parameter requests a server.json schema version, e.g. 'application/json; schema-version=2025-10-11'" required:"false"`
+}
+
+// AuthorServersInput represents the input for listing the servers maintained by an author
+type AuthorServersInput struct {
+	ID              string `path:"id" doc:"User or organization that owns the servers' repositories or published them, e.g. 'acme' for https://github.com/acme/weather" example:"acme"`
+	Cursor          string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
+	Limit           int    `query:"limit" doc:"Number of items per page; defaults to the registry's page size and is capped at its maximum, both listed at /capabilities" required:"false" minimum:"1" example:"50"`
+	Version         string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	Fields          string `query:"fields" doc:"Comma-separated top-level server fields to return (e.g. 'name,version,description'); all fields are returned when unset" required:"false" example:"name,version,description"`
+	SchemaVersion   string `query:"schema_version" doc:"server.json schema version to serve servers in (e.g. '2025-10-11'); servers stored under an older version are upgraded to the current one when unset" required:"false" example:"2025-10-11"`
+	ResolvePackages bool   `query:"resolve_packages" doc:"Add a resolvedUrl to each package with its fully-qualified download URL, based on its registry type (npm, pypi, nuget, oci); packages of other types are left as they are" required:"false" example:"true"`
+	Accept          string `header:"Accept" doc:"Media type to respond with; a schema-version parameter requests a server.json schema version, e.g. 'application/json; schema-version=2025-10-11'" required:"false"`
 }
 
 // ServerDetailInput represents the input for getting server details
 type ServerDetailInput struct {
 	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	AsOf       string `query:"as_of" doc:"Resolve the latest version as of this timestamp (RFC3339 datetime)" required:"false" example:"2025-01-01T00:00:00Z"`
 }
 
 // ServerVersionDetailInput represents the input for getting a specific version
 type ServerVersionDetailInput struct {
+	ServerName      string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version         string `path:"version" doc:"URL-encoded server version" example:"1.0.0"`
+	AsOf            string `query:"as_of" doc:"Resolve the 'latest' version as of this timestamp (RFC3339 datetime). Only valid with the 'latest' version." required:"false" example:"2025-01-01T00:00:00Z"`
+	Fields          string `query:"fields" doc:"Comma-separated top-level server fields to return (e.g. 'name,version,description'); all fields are returned when unset" required:"false" example:"name,version,description"`
+	SchemaVersion   string `query:"schema_version" doc:"server.json schema version to serve servers in (e.g. '2025-10-11'); servers stored under an older version are upgraded to the current one when unset" required:"false" example:"2025-10-11"`
+	ResolvePackages bool   `query:"resolve_packages" doc:"Add a resolvedUrl to each package with its fully-qualified download URL, based on its registry type (npm, pypi, nuget, oci); packages of other types are left as they are" required:"false" example:"true"`
+	Accept          string `header:"Accept" doc:"Media type to respond with; a schema-version parameter requests a server.json schema version, e.g. 'application/json; schema-version=2025-10-11'" required:"false"`
+}
+
+// ServerVersionInput identifies a specific server version
+type ServerVersionInput struct {
+	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version    string `path:"version" doc:"URL-encoded server version" example:"1.0.0"`
+}
+
+// ServerVersionHeaders carries the cache validators of a server version
+type ServerVersionHeaders struct {
+	ETag         string `header:"ETag" doc:"Weak entity tag of the server version, changing whenever the version is updated"`
+	LastModified string `header:"Last-Modified" doc:"When the server version was last updated"`
+}
+
+// ServerVersionOutput is a server version along with its cache validators
+type ServerVersionOutput struct {
+	ServerVersionHeaders
+	Body ServerBody
+}
+
+// ServerExportOutput is a server version as an entry of a seed file, offered as a download
+type ServerExportOutput struct {
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               apiv0.ServerJSON
+}
+
+// ServerVersionMetaInput represents the input for getting the stored registry metadata of a version
+type ServerVersionMetaInput struct {
 	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
 	Version    string `path:"version" doc:"URL-encoded server version" example:"1.0.0"`
 }
 
+// LatestServerRedirect redirects to the version-specific URL of a server's latest version
+type LatestServerRedirect struct {
+	Location        string `header:"Location" doc:"URL of the latest version's details"`
+	ResolvedVersion string `header:"X-Resolved-Version" doc:"The version the latest version resolved to"`
+}
+
+// ServerListOutput is a page of servers, with a Link header pointing at the next page if there is one
+type ServerListOutput struct {
+	Link string `header:"Link" doc:"RFC 8288 link to the next page of results, with rel=\"next\""`
+	Body ServerListBody
+}
+
+// ListServerNamesInput represents the input for listing distinct server names
+type ListServerNamesInput struct {
+	Cursor string `query:"cursor" doc:"Pagination cursor" required:"false" example:"com.example/my-server"`
+	Limit  int    `query:"limit" doc:"Number of names per page; defaults to the registry's page size and is capped at its maximum, both listed at /capabilities" required:"false" minimum:"1" example:"50"`
+	Prefix string `query:"prefix" doc:"Only list names starting with this prefix" required:"false" example:"io.github.acme/"`
+}
+
+// ServerNamesBody is a page of distinct server names
+type ServerNamesBody struct {
+	Names    []string       `json:"names" doc:"Distinct server names in name order"`
+	Metadata apiv0.Metadata `json:"metadata" doc:"Pagination metadata"`
+}
+
 // ServerVersionsInput represents the input for listing all versions of a server
 type ServerVersionsInput struct {
-	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	ServerName      string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Fields          string `query:"fields" doc:"Comma-separated top-level server fields to return (e.g. 'name,version,description'); all fields are returned when unset" required:"false" example:"name,version,description"`
+	SchemaVersion   string `query:"schema_version" doc:"server.json schema version to serve servers in (e.g. '2025-10-11'); servers stored under an older version are upgraded to the current one when unset" required:"false" example:"2025-10-11"`
+	ResolvePackages bool   `query:"resolve_packages" doc:"Add a resolvedUrl to each package with its fully-qualified download URL, based on its registry type (npm, pypi, nuget, oci); packages of other types are left as they are" required:"false" example:"true"`
+	Accept          string `header:"Accept" doc:"Media type to respond with; a schema-version parameter requests a server.json schema version, e.g. 'application/json; schema-version=2025-10-11'" required:"false"`
+}
+
+// ServerRefBody identifies a specific version of a server
+type ServerRefBody struct {
+	Name    string `json:"name" minLength:"1" doc:"Server name" example:"io.github.user/weather"`
+	Version string `json:"version" minLength:"1" doc:"Exact server version" example:"1.0.2"`
+}
+
+// ResolveServersRequest lists the server versions to fetch
+type ResolveServersRequest struct {
+	Servers []ServerRefBody `json:"servers" minItems:"1" maxItems:"100" doc:"Server versions to fetch"`
+}
+
+// ResolveServersInput represents the input for fetching several server versions at once
+type ResolveServersInput struct {
+	Body ResolveServersRequest
+}
+
+// ResolveServersBody holds the requested server versions that were found, and flags those that weren't
+type ResolveServersBody struct {
+	Servers []apiv0.ServerResponse `json:"servers" doc:"Server versions found, in request order"`
+	Missing []ServerRefBody        `json:"missing" doc:"Requested server versions that don't exist"`
 }
 
 // RegisterServersEndpoints registers all server-related endpoints with a custom path prefix
@@ -51,7 +179,12 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		Summary:     "List MCP servers",
 		Description: "Get a paginated list of MCP servers from the registry",
 		Tags:        []string{"servers"},
-	}, func(ctx context.Context, input *ListServersInput) (*Response[apiv0.ServerListResponse], error) {
+	}, func(ctx context.Context, input *ListServersInput) (*ServerListOutput, error) {
+		view, err := parseView(input.Fields, input.SchemaVersion, input.Accept, input.ResolvePackages)
+		if err != nil {
+			return nil, err
+		}
+
 		// Build filter from input parameters
 		filter := &database.ServerFilter{}
 
@@ -67,39 +200,143 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 
 		// Handle search parameter
 		if input.Search != "" {
-			filter.SubstringName = &input.Search
+			if input.SearchMode == "fuzzy" {
+				filter.FuzzyName = &input.Search
+				filter.MinSimilarity = input.MinSimilarity
+			} else {
+				filter.SubstringName = &input.Search
+			}
 		}
 
-		// Handle version parameter
-		if input.Version != "" {
-			if input.Version == "latest" {
-				// Special case: filter for latest versions
-				isLatest := true
-				filter.IsLatest = &isLatest
-			} else {
-				// Future: exact version matching
-				filter.Version = &input.Version
+		// Handle prefix parameter
+		if input.Prefix != "" {
+			filter.NamePrefix = &input.Prefix
+		}
+
+		// Handle offset parameter, an alternative to cursors for simple clients
+		if input.Offset != "" {
+			if input.Cursor != "" {
+				return nil, huma.Error400BadRequest("cursor and offset can't be combined")
+			}
+			offset, err := strconv.Atoi(input.Offset)
+			if err != nil || offset < 0 {
+				return nil, huma.Error400BadRequest("Invalid offset: expected a non-negative integer")
 			}
+			filter.Offset = offset
 		}
 
-		// Get paginated results with filtering
-		servers, nextCursor, err := registry.ListServers(ctx, filter, input.Cursor, input.Limit)
+		setVersionFilter(filter, input.Version)
+		filter.HasRemotes = parseBoolFilter(input.HasRemotes)
+		filter.HasPackages = parseBoolFilter(input.HasPackages)
+		filter.HasProvenance = parseBoolFilter(input.HasProvenance)
+		if input.Tag != "" {
+			filter.Tag = &input.Tag
+		}
+		if input.TransportType != "" {
+			filter.TransportType = &input.TransportType
+		}
+		if input.Author != "" {
+			filter.Author = &input.Author
+		}
+
+		return listServers(ctx, registry, filter, input.Cursor, input.Limit, view, pathPrefix+"/servers", url.Values{
+			"updated_since":    nonEmpty(input.UpdatedSince),
+			"search":           nonEmpty(input.Search),
+			"search_mode":      nonEmpty(input.SearchMode),
+			"prefix":           nonEmpty(input.Prefix),
+			"version":          nonEmpty(input.Version),
+			"has_remotes":      nonEmpty(input.HasRemotes),
+			"has_packages":     nonEmpty(input.HasPackages),
+			"has_provenance":   nonEmpty(input.HasProvenance),
+			"tag":              nonEmpty(input.Tag),
+			"transport_type":   nonEmpty(input.TransportType),
+			"author":           nonEmpty(input.Author),
+			"fields":           nonEmpty(input.Fields),
+			"schema_version":   nonEmpty(input.SchemaVersion),
+			"resolve_packages": nonFalse(input.ResolvePackages),
+			"offset":           nonEmpty(input.Offset),
+		})
+	})
+
+	// List servers in a namespace endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-namespace-servers" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/namespaces/{prefix}/servers",
+		Summary:     "List MCP servers in a namespace",
+		Description: "Get a paginated list of MCP servers whose name starts with the given prefix, e.g. all servers under 'io.github.acme/'",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *NamespaceServersInput) (*ServerListOutput, error) {
+		// URL-decode the prefix
+		prefix, err := url.PathUnescape(input.Prefix)
 		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to get registry list", err)
+			return nil, huma.Error400BadRequest("Invalid prefix encoding", err)
 		}
 
-		// Convert []*ServerResponse to []ServerResponse
-		serverValues := make([]apiv0.ServerResponse, len(servers))
-		for i, server := range servers {
-			serverValues[i] = *server
+		view, err := parseView(input.Fields, input.SchemaVersion, input.Accept, input.ResolvePackages)
+		if err != nil {
+			return nil, err
 		}
 
-		return &Response[apiv0.ServerListResponse]{
-			Body: apiv0.ServerListResponse{
-				Servers: serverValues,
+		filter := &database.ServerFilter{NamePrefix: &prefix}
+		setVersionFilter(filter, input.Version)
+
+		return listServers(ctx, registry, filter, input.Cursor, input.Limit, view, pathPrefix+"/namespaces/"+url.PathEscape(prefix)+"/servers", url.Values{
+			"version":          nonEmpty(input.Version),
+			"fields":           nonEmpty(input.Fields),
+			"schema_version":   nonEmpty(input.SchemaVersion),
+			"resolve_packages": nonFalse(input.ResolvePackages),
+		})
+	})
+
+	// List servers by author endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-author-servers" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/authors/{id}/servers",
+		Summary:     "List MCP servers by author",
+		Description: "Get a paginated list of MCP servers whose repository is owned by the given user or organization, or that it published, e.g. all servers in repositories under https://github.com/acme",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *AuthorServersInput) (*ServerListOutput, error) {
+		view, err := parseView(input.Fields, input.SchemaVersion, input.Accept, input.ResolvePackages)
+		if err != nil {
+			return nil, err
+		}
+
+		filter := &database.ServerFilter{Author: &input.ID}
+		setVersionFilter(filter, input.Version)
+
+		return listServers(ctx, registry, filter, input.Cursor, input.Limit, view, pathPrefix+"/authors/"+url.PathEscape(input.ID)+"/servers", url.Values{
+			"version":          nonEmpty(input.Version),
+			"fields":           nonEmpty(input.Fields),
+			"schema_version":   nonEmpty(input.SchemaVersion),
+			"resolve_packages": nonFalse(input.ResolvePackages),
+		})
+	})
+
+	// List server names endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-server-names" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/names",
+		Summary:     "List MCP server names",
+		Description: "Get a paginated list of distinct server names, without versions or server details. Useful for autocomplete and sitemaps.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ListServerNamesInput) (*Response[ServerNamesBody], error) {
+		names, nextCursor, err := registry.ListServerNames(ctx, input.Prefix, input.Cursor, input.Limit)
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to get server names", err)
+		}
+		if names == nil {
+			names = []string{}
+		}
+
+		return &Response[ServerNamesBody]{
+			Body: ServerNamesBody{
+				Names: names,
 				Metadata: apiv0.Metadata{
 					NextCursor: nextCursor,
-					Count:      len(servers),
+					Count:      len(names),
 				},
 			},
 		}, nil
@@ -113,7 +350,7 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		Summary:     "Get specific MCP server version",
 		Description: "Get detailed information about a specific version of an MCP server. Use the special version 'latest' to get the latest version.",
 		Tags:        []string{"servers"},
-	}, func(ctx context.Context, input *ServerVersionDetailInput) (*Response[apiv0.ServerResponse], error) {
+	}, func(ctx context.Context, input *ServerVersionDetailInput) (*ServerVersionOutput, error) {
 		// URL-decode the server name
 		serverName, err := url.PathUnescape(input.ServerName)
 		if err != nil {
@@ -126,23 +363,185 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 			return nil, huma.Error400BadRequest("Invalid version encoding", err)
 		}
 
+		asOf, err := parseAsOf(input.AsOf)
+		if err != nil {
+			return nil, err
+		}
+
+		view, err := parseView(input.Fields, input.SchemaVersion, input.Accept, input.ResolvePackages)
+		if err != nil {
+			return nil, err
+		}
+
 		var serverResponse *apiv0.ServerResponse
 		// Handle "latest" as a special version
 		if version == "latest" {
-			serverResponse, err = registry.GetServerByName(ctx, serverName)
+			serverResponse, err = getLatestServer(ctx, registry, serverName, asOf)
 		} else {
+			if !asOf.IsZero() {
+				return nil, huma.Error400BadRequest("as_of can only be used with the 'latest' version")
+			}
 			serverResponse, err = registry.GetServerByNameAndVersion(ctx, serverName, version)
 		}
 
 		if err != nil {
-			if err.Error() == errRecordNotFound || errors.Is(err, database.ErrNotFound) {
+			return nil, databaseError(ctx, "Failed to get server details", err)
+		}
+
+		return &ServerVersionOutput{
+			ServerVersionHeaders: versionHeaders(serverResponse),
+			Body:                 ServerBody{ServerResponse: *serverResponse, view: view},
+		}, nil
+	})
+
+	// Check whether a specific server version exists
+	huma.Register(api, huma.Operation{
+		OperationID:   "head-server-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:        http.MethodHead,
+		Path:          pathPrefix + "/servers/{serverName}/versions/{version}",
+		Summary:       "Check whether an MCP server version exists",
+		Description:   "Respond with 200 and the ETag and Last-Modified headers of the version-specific GET when the version exists, or 404 when it doesn't. Use the special version 'latest' to check the latest version.",
+		Tags:          []string{"servers"},
+		DefaultStatus: http.StatusOK,
+	}, func(ctx context.Context, input *ServerVersionInput) (*ServerVersionHeaders, error) {
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		// Answer misses without loading the version
+		if version != "latest" {
+			exists, err := registry.CheckVersionExists(ctx, serverName, version)
+			if err != nil {
+				return nil, databaseError(ctx, "Failed to check server version", err)
+			}
+			if !exists {
 				return nil, huma.Error404NotFound("Server not found")
 			}
-			return nil, huma.Error500InternalServerError("Failed to get server details", err)
 		}
 
-		return &Response[apiv0.ServerResponse]{
-			Body: *serverResponse,
+		var serverResponse *apiv0.ServerResponse
+		if version == "latest" {
+			serverResponse, err = registry.GetServerByName(ctx, serverName)
+		} else {
+			serverResponse, err = registry.GetServerByNameAndVersion(ctx, serverName, version)
+		}
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to check server version", err)
+		}
+
+		headers := versionHeaders(serverResponse)
+		return &headers, nil
+	})
+
+	// Get the stored registry metadata of a server version
+	huma.Register(api, huma.Operation{
+		OperationID: "get-server-version-meta" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/versions/{version}/meta",
+		Summary:     "Get registry metadata of an MCP server version",
+		Description: "Get only the official registry metadata (status, timestamps, latest flag) of a specific server version, exactly as stored. Use the special version 'latest' for the latest version.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ServerVersionMetaInput) (*Response[apiv0.RegistryExtensions], error) {
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		var serverResponse *apiv0.ServerResponse
+		if version == "latest" {
+			serverResponse, err = registry.GetServerByName(ctx, serverName)
+		} else {
+			serverResponse, err = registry.GetServerByNameAndVersion(ctx, serverName, version)
+		}
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to get server metadata", err)
+		}
+
+		if serverResponse.Meta.Official == nil {
+			return nil, huma.Error404NotFound("Server version has no registry metadata")
+		}
+
+		return &Response[apiv0.RegistryExtensions]{
+			Body: *serverResponse.Meta.Official,
+		}, nil
+	})
+
+	// Export a server version as a seed entry
+	huma.Register(api, huma.Operation{
+		OperationID: "export-server-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/versions/{version}/export",
+		Summary:     "Export an MCP server version as a seed entry",
+		Description: "Download a server version as the server.json object a seed file holds, without the registry metadata, so it can be imported into another registry as-is. Use the special version 'latest' to export the latest version.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ServerVersionInput) (*ServerExportOutput, error) {
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		var serverResponse *apiv0.ServerResponse
+		if version == "latest" {
+			serverResponse, err = registry.GetServerByName(ctx, serverName)
+		} else {
+			serverResponse, err = registry.GetServerByNameAndVersion(ctx, serverName, version)
+		}
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to export server", err)
+		}
+
+		return &ServerExportOutput{
+			ContentDisposition: exportDisposition(serverResponse.Server),
+			Body:               serverResponse.Server,
+		}, nil
+	})
+
+	// Latest server version redirect endpoint
+	huma.Register(api, huma.Operation{
+		OperationID:   "get-server-latest" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:        http.MethodGet,
+		Path:          pathPrefix + "/servers/{serverName}/latest",
+		Summary:       "Redirect to the latest MCP server version",
+		Description:   "Redirect to the version-specific URL of the latest version of an MCP server. The resolved version is also returned in the X-Resolved-Version header.",
+		Tags:          []string{"servers"},
+		DefaultStatus: http.StatusFound,
+	}, func(ctx context.Context, input *ServerDetailInput) (*LatestServerRedirect, error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		asOf, err := parseAsOf(input.AsOf)
+		if err != nil {
+			return nil, err
+		}
+
+		serverResponse, err := getLatestServer(ctx, registry, serverName, asOf)
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to get server details", err)
+		}
+
+		version := serverResponse.Server.Version
+		return &LatestServerRedirect{
+			Location:        absoluteURL(ctx, pathPrefix+"/servers/"+url.PathEscape(serverName)+"/versions/"+url.PathEscape(version), nil),
+			ResolvedVersion: version,
 		}, nil
 	})
 
@@ -154,20 +553,22 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		Summary:     "Get all versions of an MCP server",
 		Description: "Get all available versions for a specific MCP server",
 		Tags:        []string{"servers"},
-	}, func(ctx context.Context, input *ServerVersionsInput) (*Response[apiv0.ServerListResponse], error) {
+	}, func(ctx context.Context, input *ServerVersionsInput) (*Response[ServerListBody], error) {
 		// URL-decode the server name
 		serverName, err := url.PathUnescape(input.ServerName)
 		if err != nil {
 			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
 		}
 
+		view, err := parseView(input.Fields, input.SchemaVersion, input.Accept, input.ResolvePackages)
+		if err != nil {
+			return nil, err
+		}
+
 		// Get all versions for this server
 		servers, err := registry.GetAllVersionsByServerName(ctx, serverName)
 		if err != nil {
-			if err.Error() == errRecordNotFound || errors.Is(err, database.ErrNotFound) {
-				return nil, huma.Error404NotFound("Server not found")
-			}
-			return nil, huma.Error500InternalServerError("Failed to get server versions", err)
+			return nil, databaseError(ctx, "Failed to get server versions", err)
 		}
 
 		// Convert []*ServerResponse to []ServerResponse
@@ -176,13 +577,186 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 			serverValues[i] = *server
 		}
 
-		return &Response[apiv0.ServerListResponse]{
-			Body: apiv0.ServerListResponse{
-				Servers: serverValues,
-				Metadata: apiv0.Metadata{
-					Count: len(servers),
+		return &Response[ServerListBody]{
+			Body: ServerListBody{
+				ServerListResponse: apiv0.ServerListResponse{
+					Servers: serverValues,
+					Metadata: apiv0.Metadata{
+						Count: len(servers),
+					},
 				},
+				view: view,
 			},
 		}, nil
 	})
+
+	// Resolve server versions endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "resolve-servers" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        pathPrefix + "/servers/resolve",
+		Summary:     "Resolve MCP server versions",
+		Description: "Fetch up to 100 specific server versions by name and version in one request, e.g. to resolve a dependency set. Versions that don't exist are listed under missing.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ResolveServersInput) (*Response[ResolveServersBody], error) {
+		refs := make([]database.ServerRef, len(input.Body.Servers))
+		for i, ref := range input.Body.Servers {
+			refs[i] = database.ServerRef{Name: ref.Name, Version: ref.Version}
+		}
+
+		servers, err := registry.GetServerVersions(ctx, refs)
+		if err != nil {
+			return nil, databaseError(ctx, "Failed to resolve server versions", err)
+		}
+
+		found := make(map[database.ServerRef]bool, len(servers))
+		body := ResolveServersBody{
+			Servers: make([]apiv0.ServerResponse, len(servers)),
+			Missing: []ServerRefBody{},
+		}
+		for i, server := range servers {
+			body.Servers[i] = upgradeResponse(*server)
+			found[database.ServerRef{Name: server.Server.Name, Version: server.Server.Version}] = true
+		}
+		for i, ref := range refs {
+			if !found[ref] {
+				body.Missing = append(body.Missing, input.Body.Servers[i])
+			}
+		}
+
+		return &Response[ResolveServersBody]{Body: body}, nil
+	})
+}
+
+// setVersionFilter applies the version query parameter to a list filter
+func setVersionFilter(filter *database.ServerFilter, version string) {
+	if version == "" {
+		return
+	}
+	if version == "latest" {
+		// Special case: filter for latest versions
+		isLatest := true
+		filter.IsLatest = &isLatest
+		return
+	}
+	filter.Version = &version
+}
+
+// parseBoolFilter converts an optional "true"/"false" query parameter into a filter value, nil when unset
+func parseBoolFilter(value string) *bool {
+	if value == "" {
+		return nil
+	}
+	b := value == "true"
+	return &b
+}
+
+// listServers fetches a page of servers matching filter and builds the list response, serialized as view requests.
+// path and query describe the request so the next page can be linked, by offset if query has one and by cursor otherwise.
+func listServers(ctx context.Context, registry service.RegistryService, filter *database.ServerFilter, cursor string, limit int, view serverView, path string, query url.Values) (*ServerListOutput, error) {
+	// Get paginated results with filtering
+	servers, nextCursor, err := registry.ListServers(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, databaseError(ctx, "Failed to get registry list", err)
+	}
+
+	// Convert []*ServerResponse to []ServerResponse
+	serverValues := make([]apiv0.ServerResponse, len(servers))
+	for i, server := range servers {
+		serverValues[i] = *server
+	}
+
+	output := &ServerListOutput{
+		Body: ServerListBody{
+			ServerListResponse: apiv0.ServerListResponse{
+				Servers: serverValues,
+				Metadata: apiv0.Metadata{
+					NextCursor: nextCursor,
+					Count:      len(servers),
+				},
+			},
+			view: view,
+		},
+	}
+
+	if nextCursor != "" {
+		if query.Get("offset") != "" {
+			nextOffset := filter.Offset + len(servers)
+			output.Body.Metadata.NextOffset = nextOffset
+			query.Set("offset", strconv.Itoa(nextOffset))
+		} else {
+			query.Set("cursor", nextCursor)
+		}
+		if limit > 0 {
+			query.Set("limit", strconv.Itoa(limit))
+		}
+		output.Link = fmt.Sprintf(`<%s>; rel="next"`, absoluteURL(ctx, path, query))
+	}
+
+	return output, nil
+}
+
+// nonEmpty wraps a query parameter value for url.Values, dropping it when unset
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// nonFalse wraps a boolean query parameter for url.Values, dropping it when false
+func nonFalse(value bool) []string {
+	if !value {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// parseAsOf parses the optional as_of query parameter, returning the zero time when it isn't set
+func parseAsOf(asOf string) (time.Time, error) {
+	if asOf == "" {
+		return time.Time{}, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return time.Time{}, huma.Error400BadRequest("Invalid as_of format: expected RFC3339 timestamp (e.g., 2025-01-01T00:00:00Z)")
+	}
+	return at, nil
+}
+
+// getLatestServer returns the latest version of a server, or the latest version as of a point in time when asOf is set
+func getLatestServer(ctx context.Context, registry service.RegistryService, serverName string, asOf time.Time) (*apiv0.ServerResponse, error) {
+	if asOf.IsZero() {
+		return registry.GetServerByName(ctx, serverName)
+	}
+	return registry.GetServerAsOf(ctx, serverName, asOf)
+}
+
+// versionHeaders returns the cache validators of a server version, derived from its identity and last update time.
+// The entity tag is weak because the same version can be served in several views and schema versions.
+func versionHeaders(serverResponse *apiv0.ServerResponse) ServerVersionHeaders {
+	official := serverResponse.Meta.Official
+	if official == nil {
+		return ServerVersionHeaders{}
+	}
+
+	updatedAt := official.UpdatedAt.UTC()
+	sum := sha256.Sum256([]byte(serverResponse.Server.Name + "\x00" + serverResponse.Server.Version + "\x00" + updatedAt.Format(time.RFC3339Nano)))
+	return ServerVersionHeaders{
+		ETag:         `W/"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: updatedAt.Format(http.TimeFormat),
+	}
+}
+
+// exportDisposition names the download of an exported server version after the server and version,
+// e.g. "io.github.user_weather-1.0.2.json"
+func exportDisposition(server apiv0.ServerJSON) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '"' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, server.Name+"-"+server.Version)
+	return `attachment; filename="` + name + `.json"`
 }
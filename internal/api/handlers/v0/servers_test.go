@@ -6,13 +6,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
@@ -114,65 +119,1082 @@ func TestListServersEndpoint(t *testing.T) {
 	}
 }
 
+func TestListServersEndpoint_HasRemotesAndPackages(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, server := range []apiv0.ServerJSON{
+		{Name: "com.example/remote-only", Remotes: []model.Transport{{Type: "streamable-http", URL: "https://remote.example.com/mcp"}}},
+		{Name: "com.example/packages-only", Packages: []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@example/packages-only", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}}}},
+		{
+			Name:     "com.example/both",
+			Remotes:  []model.Transport{{Type: "streamable-http", URL: "https://both.example.com/mcp"}},
+			Packages: []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@example/both", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}}},
+		},
+	} {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Transport filter test server"
+		server.Version = "1.0.0"
+		_, err := registryService.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+		expectedNames  []string
+	}{
+		{"?has_remotes=true", http.StatusOK, []string{"com.example/both", "com.example/remote-only"}},
+		{"?has_remotes=false", http.StatusOK, []string{"com.example/packages-only"}},
+		{"?has_packages=false", http.StatusOK, []string{"com.example/remote-only"}},
+		{"?has_packages=true&has_remotes=true", http.StatusOK, []string{"com.example/both"}},
+		{"?has_remotes=yes", http.StatusUnprocessableEntity, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestListServersEndpoint_HasProvenance(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, server := range []apiv0.ServerJSON{
+		{Name: "com.example/unattested"},
+		{Name: "com.example/attested", Meta: &apiv0.ServerMeta{Provenance: &apiv0.Provenance{Attestations: []apiv0.Attestation{
+			{Type: apiv0.AttestationTypeSigstoreBundle, URL: "https://example.com/attested.sigstore.json"},
+		}}}},
+	} {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Provenance filter test server"
+		server.Version = "1.0.0"
+		_, err := registryService.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+		expectedNames  []string
+	}{
+		{"?has_provenance=true", http.StatusOK, []string{"com.example/attested"}},
+		{"?has_provenance=false", http.StatusOK, []string{"com.example/unattested"}},
+		{"", http.StatusOK, []string{"com.example/attested", "com.example/unattested"}},
+		{"?has_provenance=1", http.StatusUnprocessableEntity, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+				if server.Server.Name == "com.example/attested" {
+					require.NotNil(t, server.Server.Meta)
+					require.NotNil(t, server.Server.Meta.Provenance)
+					assert.Equal(t, "https://example.com/attested.sigstore.json", server.Server.Meta.Provenance.Attestations[0].URL)
+				}
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestListServersEndpoint_Tag(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, server := range []apiv0.ServerJSON{
+		{Name: "com.example/untagged"},
+		{Name: "com.example/empty-tags", Meta: &apiv0.ServerMeta{Tags: []string{}}},
+		{Name: "com.example/postgres", Meta: &apiv0.ServerMeta{Tags: []string{"database"}}},
+		{Name: "com.example/sqlite-inspector", Meta: &apiv0.ServerMeta{Tags: []string{"devtools", "database"}}},
+	} {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Tag filter test server"
+		server.Version = "1.0.0"
+		_, err := registryService.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"?tag=database", []string{"com.example/postgres", "com.example/sqlite-inspector"}},
+		{"?tag=devtools", []string{"com.example/sqlite-inspector"}},
+		{"?tag=devtools&search=postgres", nil},
+		{"?tag=data", nil},
+		{"", []string{"com.example/empty-tags", "com.example/postgres", "com.example/sqlite-inspector", "com.example/untagged"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+				if tt.query != "" {
+					require.NotNil(t, server.Server.Meta)
+					assert.NotEmpty(t, server.Server.Meta.Tags)
+				}
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+
+	t.Run("tag carries over to the next page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers?tag=database&limit=1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Link"), "tag=database")
+	})
+}
+
+func TestListServersEndpoint_TransportType(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, server := range []apiv0.ServerJSON{
+		{Name: "com.example/sse", Remotes: []model.Transport{{Type: "sse", URL: "https://sse.example.com/sse"}}},
+		{Name: "com.example/both", Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://both.example.com/mcp"},
+			{Type: "sse", URL: "https://both.example.com/sse"},
+		}},
+		{Name: "com.example/http", Remotes: []model.Transport{{Type: "streamable-http", URL: "https://http.example.com/mcp"}}},
+		{Name: "com.example/stdio-only", Packages: []model.Package{
+			{RegistryType: "npm", Identifier: "@example/stdio-only", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}},
+		}},
+	} {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Transport type filter test server"
+		server.Version = "1.0.0"
+		_, err := registryService.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"?transport_type=sse", []string{"com.example/both", "com.example/sse"}},
+		{"?transport_type=streamable-http", []string{"com.example/both", "com.example/http"}},
+		// Only remotes are matched, so servers that run locally over stdio are excluded
+		{"?transport_type=stdio", nil},
+		{"", []string{"com.example/both", "com.example/http", "com.example/sse", "com.example/stdio-only"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+
+	t.Run("transport type carries over to the next page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers?transport_type=sse&limit=1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Link"), "transport_type=sse")
+	})
+}
+
+func TestListServersEndpoint_Author(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, server := range []apiv0.ServerJSON{
+		{Name: "com.acme/weather", Repository: &model.Repository{URL: "https://github.com/acme/weather", Source: "github"}},
+		{Name: "com.acme/maps", Repository: &model.Repository{URL: "https://github.com/ACME/tools", Source: "github", Subfolder: "maps"}},
+		{Name: "com.example/acme-fork", Repository: &model.Repository{URL: "https://github.com/someone-else/acme", Source: "github"}},
+		{Name: "com.example/no-repository"},
+	} {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Author filter test server"
+		server.Version = "1.0.0"
+		_, err := registryService.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		path          string
+		expectedNames []string
+	}{
+		// Owners are matched ignoring case, and only the owner is matched rather than the repository name
+		{"/v0/servers?author=acme", []string{"com.acme/maps", "com.acme/weather"}},
+		{"/v0/authors/acme/servers", []string{"com.acme/maps", "com.acme/weather"}},
+		{"/v0/authors/Someone-Else/servers", []string{"com.example/acme-fork"}},
+		{"/v0/authors/nobody/servers", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+
+	t.Run("author carries over to the next page", func(t *testing.T) {
+		for _, path := range []string{"/v0/servers?author=acme&limit=1", "/v0/authors/acme/servers?limit=1"} {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Link"), "acme", path)
+		}
+	})
+}
+
+func TestListServersEndpoint_FuzzySearch(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, name := range []string{"io.github.user/weather", "io.github.user/weather-alerts", "io.github.user/filesystem"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Fuzzy search test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+		expectedNames  []string
+	}{
+		{"?search=wether", http.StatusOK, nil},
+		{"?search=wether&search_mode=fuzzy", http.StatusOK, []string{"io.github.user/weather"}},
+		{"?search=wether&search_mode=fuzzy&min_similarity=0.2", http.StatusOK, []string{"io.github.user/weather", "io.github.user/weather-alerts"}},
+		{"?search=filesytem&search_mode=fuzzy", http.StatusOK, []string{"io.github.user/filesystem"}},
+		{"?search=wether&search_mode=regex", http.StatusUnprocessableEntity, nil},
+		{"?search=wether&search_mode=fuzzy&min_similarity=2", http.StatusUnprocessableEntity, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			var names []string
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Empty(t, resp.Metadata.NextCursor)
+		})
+	}
+}
+
+func TestListServersEndpoint_OffsetPagination(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, name := range []string{"alpha", "bravo", "charlie", "delta", "echo"} {
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/" + name,
+				Description: "Offset pagination test server",
+				Version:     version,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(t *testing.T, path string) (*httptest.ResponseRecorder, apiv0.ServerListResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var body apiv0.ServerListResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		}
+		return w, body
+	}
+
+	// walk follows the next links from path, returning every server as name@version
+	walk := func(t *testing.T, path string) []string {
+		t.Helper()
+		var servers []string
+		for path != "" {
+			w, body := get(t, path)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			for _, server := range body.Servers {
+				servers = append(servers, server.Server.Name+"@"+server.Server.Version)
+			}
+
+			path = ""
+			if link := w.Header().Get("Link"); link != "" {
+				next, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+				require.NoError(t, err)
+				path = next.RequestURI()
+			}
+		}
+		return servers
+	}
+
+	for _, query := range []string{"limit=3", "limit=5", "limit=3&version=latest", "limit=2&search=a"} {
+		t.Run(query, func(t *testing.T) {
+			byCursor := walk(t, "/v0/servers?"+query)
+			byOffset := walk(t, "/v0/servers?offset=0&"+query)
+			require.NotEmpty(t, byCursor)
+			assert.Equal(t, byCursor, byOffset)
+		})
+	}
+
+	t.Run("next offset", func(t *testing.T) {
+		w, body := get(t, "/v0/servers?offset=3&limit=4")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, body.Servers, 4)
+		assert.Equal(t, "com.example/bravo", body.Servers[0].Server.Name)
+		assert.Equal(t, "2.0.0", body.Servers[0].Server.Version)
+		assert.Equal(t, 7, body.Metadata.NextOffset)
+		assert.NotEmpty(t, body.Metadata.NextCursor)
+		assert.Contains(t, w.Header().Get("Link"), "offset=7")
+		assert.NotContains(t, w.Header().Get("Link"), "cursor=")
+
+		// Cursor pagination doesn't report offsets
+		_, body = get(t, "/v0/servers?limit=4")
+		assert.Zero(t, body.Metadata.NextOffset)
+	})
+
+	t.Run("offset past the end", func(t *testing.T) {
+		w, body := get(t, "/v0/servers?offset=100")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, body.Servers)
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+
+	t.Run("invalid offsets", func(t *testing.T) {
+		for _, query := range []string{"offset=-1", "offset=abc", "offset=2&cursor=com.example/alpha:1.0.0"} {
+			w, _ := get(t, "/v0/servers?"+query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
+
+func TestResolveServersEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/resolvable",
+			Description: "Resolve test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	resolve := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v0/servers/resolve", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("mix of existing and missing versions", func(t *testing.T) {
+		w := resolve(t, `{"servers":[
+			{"name":"com.example/resolvable","version":"2.0.0"},
+			{"name":"com.example/resolvable","version":"9.9.9"},
+			{"name":"com.example/unknown","version":"1.0.0"},
+			{"name":"com.example/resolvable","version":"1.0.0"}
+		]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp v0.ResolveServersBody
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Servers, 2)
+		assert.Equal(t, "2.0.0", resp.Servers[0].Server.Version)
+		assert.Equal(t, "1.0.0", resp.Servers[1].Server.Version)
+		assert.NotNil(t, resp.Servers[0].Meta.Official)
+		assert.Equal(t, []v0.ServerRefBody{
+			{Name: "com.example/resolvable", Version: "9.9.9"},
+			{Name: "com.example/unknown", Version: "1.0.0"},
+		}, resp.Missing)
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		w := resolve(t, `{"servers":[{"name":"com.example/unknown","version":"1.0.0"}]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp v0.ResolveServersBody
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Empty(t, resp.Servers)
+		assert.Len(t, resp.Missing, 1)
+	})
+
+	t.Run("empty request is rejected", func(t *testing.T) {
+		w := resolve(t, `{"servers":[]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestListNamespaceServersEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	for _, name := range []string{"com.acme/alpha", "com.acme/beta", "com.acme/gamma", "com.acmecorp/other", "com.example/other"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Namespace test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	list := func(prefix, query string) apiv0.ServerListResponse {
+		req := httptest.NewRequest(http.MethodGet, "/v0/namespaces/"+url.PathEscape(prefix)+"/servers"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	t.Run("prefix matching several servers", func(t *testing.T) {
+		resp := list("com.acme/", "")
+		names := make([]string, 0, len(resp.Servers))
+		for _, server := range resp.Servers {
+			names = append(names, server.Server.Name)
+		}
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta", "com.acme/gamma"}, names)
+		assert.Empty(t, resp.Metadata.NextCursor)
+	})
+
+	t.Run("prefix composes with pagination", func(t *testing.T) {
+		var names []string
+		cursor := ""
+		for range 5 {
+			query := "?limit=2"
+			if cursor != "" {
+				query += "&cursor=" + url.QueryEscape(cursor)
+			}
+			resp := list("com.acme/", query)
+			for _, server := range resp.Servers {
+				names = append(names, server.Server.Name)
+			}
+			cursor = resp.Metadata.NextCursor
+			if cursor == "" {
+				break
+			}
+		}
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta", "com.acme/gamma"}, names)
+	})
+
+	t.Run("prefix matching no servers", func(t *testing.T) {
+		resp := list("org.nobody/", "")
+		assert.Empty(t, resp.Servers)
+		assert.Equal(t, 0, resp.Metadata.Count)
+		assert.Empty(t, resp.Metadata.NextCursor)
+	})
+
+	t.Run("prefix query parameter on list endpoint", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers?prefix="+url.QueryEscape("com.acme"), nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Servers, 4)
+	})
+}
+
+func TestListServerNamesEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	for _, name := range []string{"com.acme/alpha", "com.acme/beta", "com.example/other"} {
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "Names test server",
+				Version:     version,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	list := func(query string) v0.ServerNamesBody {
+		req := httptest.NewRequest(http.MethodGet, "/v0/names"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp v0.ServerNamesBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	t.Run("one entry per server across versions", func(t *testing.T) {
+		resp := list("")
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta", "com.example/other"}, resp.Names)
+		assert.Equal(t, 3, resp.Metadata.Count)
+		assert.Empty(t, resp.Metadata.NextCursor)
+	})
+
+	t.Run("prefix filter", func(t *testing.T) {
+		resp := list("?prefix=" + url.QueryEscape("com.acme/"))
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta"}, resp.Names)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		resp := list("?limit=2")
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta"}, resp.Names)
+		require.Equal(t, "com.acme/beta", resp.Metadata.NextCursor)
+
+		resp = list("?limit=2&cursor=" + url.QueryEscape(resp.Metadata.NextCursor))
+		assert.Equal(t, []string{"com.example/other"}, resp.Names)
+		assert.Empty(t, resp.Metadata.NextCursor)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		resp := list("?prefix=org.nobody/")
+		assert.NotNil(t, resp.Names)
+		assert.Empty(t, resp.Names)
+	})
+}
+
 func TestGetLatestServerVersionEndpoint(t *testing.T) {
 	ctx := context.Background()
 	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
 
-	// Setup test data
-	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
-		Schema:      model.CurrentSchemaURL,
-		Name:        "com.example/detail-server",
-		Description: "Server for detail testing",
-		Version:     "1.0.0",
-	})
+	// Setup test data
+	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/detail-server",
+		Description: "Server for detail testing",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	// Create API
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		name           string
+		serverName     string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "get existing server latest version",
+			serverName:     "com.example/detail-server",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get non-existent server",
+			serverName:     "com.example/non-existent",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Server not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// URL encode the server name
+			encodedName := url.PathEscape(tt.serverName)
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/versions/latest", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp apiv0.ServerResponse
+				err := json.NewDecoder(w.Body).Decode(&resp)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.serverName, resp.Server.Name)
+				assert.NotNil(t, resp.Meta.Official)
+			} else if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestLatestServerRedirectEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	// Setup test data with an older and a newer version
+	for _, version := range []string{"1.0.0", "1.2.0+build.5"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/redirect-server",
+			Description: "Server for latest redirect testing",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	// Create API
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		name             string
+		serverName       string
+		expectedStatus   int
+		expectedLocation string
+		expectedVersion  string
+	}{
+		{
+			name:             "redirects to latest version",
+			serverName:       "com.example/redirect-server",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "/v0/servers/com.example%2Fredirect-server/versions/1.2.0+build.5",
+			expectedVersion:  "1.2.0+build.5",
+		},
+		{
+			name:           "unknown server",
+			serverName:     "com.example/non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encodedName := url.PathEscape(tt.serverName)
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/latest", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusFound {
+				return
+			}
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			assert.Equal(t, tt.expectedVersion, w.Header().Get("X-Resolved-Version"))
+
+			// Following the redirect serves the resolved version
+			followReq := httptest.NewRequest(http.MethodGet, w.Header().Get("Location"), nil)
+			followW := httptest.NewRecorder()
+			mux.ServeHTTP(followW, followReq)
+			require.Equal(t, http.StatusOK, followW.Code, followW.Body.String())
+
+			var resp apiv0.ServerResponse
+			require.NoError(t, json.NewDecoder(followW.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedVersion, resp.Server.Version)
+		})
+	}
+}
+
+func TestGetServerAsOfEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	// Seed a registry file with versions published on different dates, including a backport of 1.5 after 2.0.0
+	seed := `{"servers": [
+		{"server_name": "com.example/asof-server", "version": "1.0.0", "status": "active", "published_at": "2023-06-01T00:00:00Z", "updated_at": "2023-06-01T00:00:00Z", "is_latest": false,
+		 "value": {"$schema": "` + model.CurrentSchemaURL + `", "name": "com.example/asof-server", "description": "As-of server", "version": "1.0.0"}},
+		{"server_name": "com.example/asof-server", "version": "1.5.0", "status": "active", "published_at": "2023-12-01T00:00:00Z", "updated_at": "2023-12-01T00:00:00Z", "is_latest": false,
+		 "value": {"$schema": "` + model.CurrentSchemaURL + `", "name": "com.example/asof-server", "description": "As-of server", "version": "1.5.0"}},
+		{"server_name": "com.example/asof-server", "version": "2.0.0", "status": "active", "published_at": "2024-06-01T00:00:00Z", "updated_at": "2024-06-01T00:00:00Z", "is_latest": true,
+		 "value": {"$schema": "` + model.CurrentSchemaURL + `", "name": "com.example/asof-server", "description": "As-of server", "version": "2.0.0"}},
+		{"server_name": "com.example/asof-server", "version": "1.5.1", "status": "active", "published_at": "2024-09-01T00:00:00Z", "updated_at": "2024-09-01T00:00:00Z", "is_latest": false,
+		 "value": {"$schema": "` + model.CurrentSchemaURL + `", "name": "com.example/asof-server", "description": "As-of server", "version": "1.5.1"}}
+	]}`
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(seed), 0600))
+	db, err := database.NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	registryService := service.NewRegistryService(db, &config.Config{})
+
+	// Create API
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	encodedName := url.PathEscape("com.example/asof-server")
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedVersion string
+	}{
+		{
+			name:            "latest without as_of",
+			path:            "/v0/servers/" + encodedName + "/versions/latest",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "2.0.0",
+		},
+		{
+			name:            "latest as of 2024-01-01",
+			path:            "/v0/servers/" + encodedName + "/versions/latest?as_of=2024-01-01T00:00:00Z",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "1.5.0",
+		},
+		{
+			name:            "latest as of a time after a backport",
+			path:            "/v0/servers/" + encodedName + "/versions/latest?as_of=2025-01-01T00:00:00Z",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "2.0.0",
+		},
+		{
+			name:            "latest as of 2023-07-01",
+			path:            "/v0/servers/" + encodedName + "/versions/latest?as_of=2023-07-01T00:00:00Z",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "1.0.0",
+		},
+		{
+			name:           "as of before first publish",
+			path:           "/v0/servers/" + encodedName + "/versions/latest?as_of=2020-01-01T00:00:00Z",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid as_of",
+			path:           "/v0/servers/" + encodedName + "/versions/latest?as_of=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "as_of with specific version",
+			path:           "/v0/servers/" + encodedName + "/versions/1.0.0?as_of=2024-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				var resp apiv0.ServerResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedVersion, resp.Server.Version)
+			}
+		})
+	}
+
+	// The latest redirect honours as_of too
+	req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/latest?as_of=2024-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "1.5.0", w.Header().Get("X-Resolved-Version"))
+}
+
+func TestGetServerVersionMetaEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	const serverName = "com.example/meta-server"
+	for _, server := range []struct{ name, version string }{
+		{serverName, "1.0.0"},
+		{serverName, "2.0.0"},
+		{"com.example/successor", "1.0.0"},
+	} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        server.name,
+			Description: "Meta test server",
+			Version:     server.version,
+		})
+		require.NoError(t, err)
+	}
+	_, err := registryService.DeprecateServer(ctx, serverName, "1.0.0", "com.example/successor")
 	require.NoError(t, err)
 
-	// Create API
 	mux := http.NewServeMux()
 	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
 	v0.RegisterServersEndpoints(api, "/v0", registryService)
 
+	encodedName := url.PathEscape(serverName)
+
 	tests := []struct {
 		name           string
-		serverName     string
+		version        string
+		storedVersion  string
 		expectedStatus int
-		expectedError  string
 	}{
-		{
-			name:           "get existing server latest version",
-			serverName:     "com.example/detail-server",
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name:           "get non-existent server",
-			serverName:     "com.example/non-existent",
-			expectedStatus: http.StatusNotFound,
-			expectedError:  "Server not found",
-		},
+		{name: "older deprecated version", version: "1.0.0", storedVersion: "1.0.0", expectedStatus: http.StatusOK},
+		{name: "latest version", version: "2.0.0", storedVersion: "2.0.0", expectedStatus: http.StatusOK},
+		{name: "latest alias", version: "latest", storedVersion: "2.0.0", expectedStatus: http.StatusOK},
+		{name: "unknown version", version: "9.9.9", expectedStatus: http.StatusNotFound},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// URL encode the server name
-			encodedName := url.PathEscape(tt.serverName)
-			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/versions/latest", nil)
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/versions/"+tt.version+"/meta", nil)
 			w := httptest.NewRecorder()
-
 			mux.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
 
-			assert.Equal(t, tt.expectedStatus, w.Code)
+			stored, err := registryService.GetServerByNameAndVersion(ctx, serverName, tt.storedVersion)
+			require.NoError(t, err)
+			require.NotNil(t, stored.Meta.Official)
 
-			if tt.expectedStatus == http.StatusOK {
-				var resp apiv0.ServerResponse
-				err := json.NewDecoder(w.Body).Decode(&resp)
-				assert.NoError(t, err)
-				assert.Equal(t, tt.serverName, resp.Server.Name)
-				assert.NotNil(t, resp.Meta.Official)
-			} else if tt.expectedError != "" {
-				assert.Contains(t, w.Body.String(), tt.expectedError)
+			var meta apiv0.RegistryExtensions
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+			assert.Equal(t, stored.Meta.Official.Status, meta.Status)
+			assert.Equal(t, stored.Meta.Official.IsLatest, meta.IsLatest)
+			assert.Equal(t, stored.Meta.Official.ReplacedBy, meta.ReplacedBy)
+			assert.True(t, stored.Meta.Official.PublishedAt.Equal(meta.PublishedAt))
+			assert.True(t, stored.Meta.Official.UpdatedAt.Equal(meta.UpdatedAt))
+
+			// Only the metadata block is returned, not the server payload
+			assert.NotContains(t, w.Body.String(), `"server"`)
+		})
+	}
+
+	t.Run("fields reflect the stored state", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+encodedName+"/versions/1.0.0/meta", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var meta apiv0.RegistryExtensions
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+		assert.Equal(t, model.StatusDeprecated, meta.Status)
+		assert.False(t, meta.IsLatest)
+		assert.Equal(t, "com.example/successor", meta.ReplacedBy)
+	})
+}
+
+func TestHeadServerVersionEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	const serverName = "com.example/head-server"
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Head test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	encodedName := url.PathEscape(serverName)
+
+	tests := []struct {
+		name           string
+		serverName     string
+		version        string
+		getVersion     string
+		expectedStatus int
+	}{
+		{name: "existing version", serverName: encodedName, version: "1.0.0", getVersion: "1.0.0", expectedStatus: http.StatusOK},
+		{name: "latest alias", serverName: encodedName, version: "latest", getVersion: "2.0.0", expectedStatus: http.StatusOK},
+		{name: "unknown version", serverName: encodedName, version: "9.9.9", expectedStatus: http.StatusNotFound},
+		{name: "unknown server", serverName: url.PathEscape("com.example/missing"), version: "1.0.0", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/v0/servers/"+tt.serverName+"/versions/"+tt.version, nil))
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
 			}
+			assert.Empty(t, w.Body.String())
+
+			// The validators match those of the corresponding GET
+			getW := httptest.NewRecorder()
+			mux.ServeHTTP(getW, httptest.NewRequest(http.MethodGet, "/v0/servers/"+tt.serverName+"/versions/"+tt.getVersion, nil))
+			require.Equal(t, http.StatusOK, getW.Code)
+			assert.NotEmpty(t, w.Header().Get("ETag"))
+			assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+			assert.Equal(t, getW.Header().Get("ETag"), w.Header().Get("ETag"))
+			assert.Equal(t, getW.Header().Get("Last-Modified"), w.Header().Get("Last-Modified"))
 		})
 	}
+
+	// Different versions have different entity tags
+	first, second := httptest.NewRecorder(), httptest.NewRecorder()
+	mux.ServeHTTP(first, httptest.NewRequest(http.MethodHead, "/v0/servers/"+encodedName+"/versions/1.0.0", nil))
+	mux.ServeHTTP(second, httptest.NewRequest(http.MethodHead, "/v0/servers/"+encodedName+"/versions/2.0.0", nil))
+	assert.NotEqual(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+}
+
+func TestExportServerVersionEndpoint(t *testing.T) {
+	ctx := context.Background()
+	source := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	const serverName = "io.github.example/exported"
+	original := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Export test server",
+		Title:       "Exported",
+		Version:     "1.2.0",
+		Repository: &model.Repository{
+			URL:    "https://github.com/example/exported",
+			Source: "github",
+		},
+		Packages: []model.Package{
+			{
+				RegistryType: model.RegistryTypeNPM,
+				Identifier:   "@example/exported",
+				Version:      "1.2.0",
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			},
+		},
+		Remotes: []model.Transport{
+			{Type: model.TransportTypeStreamableHTTP, URL: "https://example.github.io/exported/mcp"},
+		},
+	}
+	_, err := source.CreateServer(ctx, original)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", source)
+
+	export := func(version string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		path := "/v0/servers/" + url.PathEscape(serverName) + "/versions/" + version + "/export"
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := export("1.2.0")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `attachment; filename="io.github.example_exported-1.2.0.json"`, w.Header().Get("Content-Disposition"))
+
+	// The entry carries no registry metadata
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.Equal(t, serverName, raw["name"])
+	assert.NotContains(t, raw, "server")
+	assert.NotContains(t, raw, "_meta")
+
+	t.Run("round trip through another registry's seed", func(t *testing.T) {
+		seedFile := filepath.Join(t.TempDir(), "seed.json")
+		require.NoError(t, os.WriteFile(seedFile, w.Body.Bytes(), 0600))
+
+		target := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+		result, err := importer.NewService(target, nil).ImportFromPath(ctx, seedFile)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+
+		exported, err := source.GetServerByNameAndVersion(ctx, serverName, "1.2.0")
+		require.NoError(t, err)
+		imported, err := target.GetServerByNameAndVersion(ctx, serverName, "1.2.0")
+		require.NoError(t, err)
+		assert.Equal(t, exported.Server, imported.Server)
+	})
+
+	t.Run("latest alias and unknown versions", func(t *testing.T) {
+		latest := export("latest")
+		require.Equal(t, http.StatusOK, latest.Code)
+		assert.Equal(t, w.Body.String(), latest.Body.String())
+
+		assert.Equal(t, http.StatusNotFound, export("9.9.9").Code)
+	})
 }
 
 func TestGetServerVersionEndpoint(t *testing.T) {
@@ -458,7 +1480,7 @@ func TestServersEndpointEdgeCases(t *testing.T) {
 			expectedStatus int
 			expectedError  string
 		}{
-			{"limit too high", "?limit=1000", http.StatusUnprocessableEntity, "validation failed"},
+			{"limit above maximum is clamped", "?limit=1000", http.StatusOK, ""},
 			{"negative limit", "?limit=-1", http.StatusUnprocessableEntity, "validation failed"},
 			{"invalid updated_since format", "?updated_since=invalid", http.StatusBadRequest, "Invalid updated_since format"},
 			{"future updated_since", "?updated_since=2030-01-01T00:00:00Z", http.StatusOK, ""},
@@ -518,3 +1540,362 @@ func TestServersEndpointEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestSparseFieldsets(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+
+	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/sparse-server",
+		Description: "Server with packages and remotes",
+		Version:     "1.0.0",
+		WebsiteURL:  "https://example.com",
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+		},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// serverObjects extracts the raw server objects from a detail or list response
+	serverObjects := func(t *testing.T, w *httptest.ResponseRecorder) []map[string]json.RawMessage {
+		t.Helper()
+		var body struct {
+			Server  map[string]json.RawMessage `json:"server"`
+			Servers []struct {
+				Server map[string]json.RawMessage `json:"server"`
+				Meta   json.RawMessage            `json:"_meta"`
+			} `json:"servers"`
+			Meta json.RawMessage `json:"_meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		if body.Server != nil {
+			assert.NotEmpty(t, body.Meta, "registry metadata should always be returned")
+			return []map[string]json.RawMessage{body.Server}
+		}
+		servers := make([]map[string]json.RawMessage, len(body.Servers))
+		for i, server := range body.Servers {
+			assert.NotEmpty(t, server.Meta, "registry metadata should always be returned")
+			servers[i] = server.Server
+		}
+		return servers
+	}
+
+	paths := []string{
+		"/v0/servers",
+		"/v0/namespaces/com.example%2F/servers",
+		"/v0/servers/com.example%2Fsparse-server/versions",
+		"/v0/servers/com.example%2Fsparse-server/versions/latest",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			w := get(path + "?fields=name,version,%20description")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			servers := serverObjects(t, w)
+			require.Len(t, servers, 1)
+			assert.Len(t, servers[0], 3)
+			assert.JSONEq(t, `"com.example/sparse-server"`, string(servers[0]["name"]))
+			assert.JSONEq(t, `"1.0.0"`, string(servers[0]["version"]))
+			assert.JSONEq(t, `"Server with packages and remotes"`, string(servers[0]["description"]))
+			for _, omitted := range []string{"$schema", "remotes", "websiteUrl"} {
+				assert.NotContains(t, servers[0], omitted)
+			}
+
+			// Without fields the full server is returned
+			w = get(path)
+			require.Equal(t, http.StatusOK, w.Code)
+			servers = serverObjects(t, w)
+			require.Len(t, servers, 1)
+			assert.Contains(t, servers[0], "remotes")
+			assert.Contains(t, servers[0], "websiteUrl")
+
+			w = get(path + "?fields=name,packagez")
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Unknown field 'packagez'")
+		})
+	}
+
+	t.Run("fields carry over to the next page", func(t *testing.T) {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/sparse-server-2",
+			Description: "Second server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+
+		w := get("/v0/servers?limit=1&fields=name")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Link"), "fields=name")
+	})
+}
+
+func TestResolvePackages(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	registryService := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	stdio := model.Transport{Type: "stdio"}
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/resolved",
+		Description: "Server with a package of every registry type",
+		Version:     "1.0.0",
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeNPM, Identifier: "@example/server", Version: "1.2.3", Transport: stdio},
+			{RegistryType: model.RegistryTypeNPM, RegistryBaseURL: "https://npm.example.com/", Identifier: "plain", Version: "2.0.0", Transport: stdio},
+			{RegistryType: model.RegistryTypePyPI, Identifier: "example-server", Version: "0.4.0", Transport: stdio},
+			{RegistryType: model.RegistryTypeNuGet, Identifier: "Example.Server", Version: "1.0.0-Beta", Transport: stdio},
+			{RegistryType: model.RegistryTypeOCI, Identifier: "ghcr.io/example/server:1.0.0", Transport: stdio},
+			{RegistryType: model.RegistryTypeOCI, Identifier: "localhost:5000/server", Transport: stdio},
+			{RegistryType: model.RegistryTypeOCI, Identifier: "alpine:3.20", Transport: stdio},
+			{RegistryType: model.RegistryTypeOCI, Identifier: "docker.io/example/server@sha256:abc", Transport: stdio},
+			{RegistryType: model.RegistryTypeMCPB, Identifier: "https://example.com/server.mcpb", FileSHA256: strings.Repeat("a", 64), Transport: stdio},
+			{RegistryType: "cargo", Identifier: "example-server", Version: "1.0.0", Transport: stdio},
+		},
+	}, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: time.Now(),
+		UpdatedAt:   time.Now(),
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	// resolvedURLs extracts the resolved URL of each package of the first server in a detail or list response
+	resolvedURLs := func(t *testing.T, path string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		type server struct {
+			Packages []struct {
+				Identifier  string `json:"identifier"`
+				ResolvedURL string `json:"resolvedUrl"`
+			} `json:"packages"`
+		}
+		var body struct {
+			Server  *server `json:"server"`
+			Servers []struct {
+				Server server `json:"server"`
+			} `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		if body.Server == nil {
+			require.NotEmpty(t, body.Servers)
+			body.Server = &body.Servers[0].Server
+		}
+
+		urls := make([]string, len(body.Server.Packages))
+		for i, pkg := range body.Server.Packages {
+			assert.NotEmpty(t, pkg.Identifier, "packages should keep their fields")
+			urls[i] = pkg.ResolvedURL
+		}
+		return urls
+	}
+
+	expected := []string{
+		"https://registry.npmjs.org/@example/server/-/server-1.2.3.tgz",
+		"https://npm.example.com/plain/-/plain-2.0.0.tgz",
+		"https://pypi.org/project/example-server/0.4.0/",
+		"https://api.nuget.org/v3-flatcontainer/example.server/1.0.0-beta/example.server.1.0.0-beta.nupkg",
+		"https://ghcr.io/v2/example/server/manifests/1.0.0",
+		"https://localhost:5000/v2/server/manifests/latest",
+		"https://registry-1.docker.io/v2/library/alpine/manifests/3.20",
+		"https://registry-1.docker.io/v2/example/server/manifests/sha256:abc",
+		"", // mcpb identifiers are already URLs
+		"", // unknown registry types are left unexpanded
+	}
+
+	paths := []string{
+		"/v0/servers",
+		"/v0/namespaces/com.example%2F/servers",
+		"/v0/servers/com.example%2Fresolved/versions",
+		"/v0/servers/com.example%2Fresolved/versions/1.0.0",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, expected, resolvedURLs(t, path+"?resolve_packages=true"))
+		})
+	}
+
+	t.Run("not resolved by default", func(t *testing.T) {
+		for _, url := range resolvedURLs(t, "/v0/servers/com.example%2Fresolved/versions/1.0.0") {
+			assert.Empty(t, url)
+		}
+	})
+
+	t.Run("combined with fields", func(t *testing.T) {
+		assert.Equal(t, expected, resolvedURLs(t, "/v0/servers?resolve_packages=true&fields=name,packages"))
+	})
+}
+
+func TestOutdatedSchemaUpgradedOnRead(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	registryService := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	// A record stored before OCI versions moved into the image tag
+	const serverName = "com.example/outdated"
+	oldSchemaURL := "https://static.modelcontextprotocol.io/schemas/2025-09-29/server.schema.json"
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      oldSchemaURL,
+		Name:        serverName,
+		Description: "Server stored under an old schema",
+		Version:     "1.0.0",
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeOCI, Identifier: "ghcr.io/example/outdated", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}},
+		},
+	}, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: time.Now(),
+		UpdatedAt:   time.Now(),
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(t *testing.T, path string) []byte {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+	assertUpgraded := func(t *testing.T, server apiv0.ServerJSON) {
+		t.Helper()
+		assert.Equal(t, model.CurrentSchemaURL, server.Schema)
+		require.Len(t, server.Packages, 1)
+		assert.Equal(t, "ghcr.io/example/outdated:1.0.0", server.Packages[0].Identifier)
+		assert.Empty(t, server.Packages[0].Version)
+	}
+
+	const path = "/v0/servers/com.example%2Foutdated/versions/1.0.0"
+
+	t.Run("detail", func(t *testing.T) {
+		var body apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(get(t, path), &body))
+		assertUpgraded(t, body.Server)
+		assert.Equal(t, model.StatusActive, body.Meta.Official.Status)
+	})
+
+	t.Run("list with fields", func(t *testing.T) {
+		var body apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(get(t, "/v0/servers?fields=$schema,packages"), &body))
+		require.Len(t, body.Servers, 1)
+		assertUpgraded(t, body.Servers[0].Server)
+	})
+
+	t.Run("explicitly requested stored version", func(t *testing.T) {
+		var body apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(get(t, path+"?schema_version=2025-09-29"), &body))
+		assert.Equal(t, oldSchemaURL, body.Server.Schema)
+		assert.Equal(t, "ghcr.io/example/outdated", body.Server.Packages[0].Identifier)
+		assert.Equal(t, "1.0.0", body.Server.Packages[0].Version)
+	})
+
+	t.Run("storage is untouched", func(t *testing.T) {
+		stored, err := db.GetServerByNameAndVersion(ctx, nil, serverName, "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, oldSchemaURL, stored.Server.Schema)
+		assert.Equal(t, "ghcr.io/example/outdated", stored.Server.Packages[0].Identifier)
+		assert.Equal(t, "1.0.0", stored.Server.Packages[0].Version)
+	})
+}
+
+func TestSchemaVersionNegotiation(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/negotiated",
+		Description: "Server served in several schema versions",
+		Version:     "1.0.0",
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeOCI, Identifier: "ghcr.io/example/negotiated:1.0.0", Transport: model.Transport{Type: "stdio"}},
+		},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	detail := func(t *testing.T, w *httptest.ResponseRecorder) apiv0.ServerJSON {
+		t.Helper()
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body apiv0.ServerResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Server
+	}
+
+	const path = "/v0/servers/com.example%2Fnegotiated/versions/1.0.0"
+
+	t.Run("stored version by default", func(t *testing.T) {
+		server := detail(t, get(path, ""))
+		assert.Equal(t, model.CurrentSchemaURL, server.Schema)
+		assert.Equal(t, "ghcr.io/example/negotiated:1.0.0", server.Packages[0].Identifier)
+	})
+
+	t.Run("query parameter", func(t *testing.T) {
+		server := detail(t, get(path+"?schema_version=2025-09-29", ""))
+		assert.Equal(t, "https://static.modelcontextprotocol.io/schemas/2025-09-29/server.schema.json", server.Schema)
+		assert.Equal(t, "ghcr.io/example/negotiated", server.Packages[0].Identifier)
+		assert.Equal(t, "1.0.0", server.Packages[0].Version)
+	})
+
+	t.Run("accept header parameter", func(t *testing.T) {
+		server := detail(t, get(path, "application/json; schema-version=2025-09-29"))
+		assert.Equal(t, "ghcr.io/example/negotiated", server.Packages[0].Identifier)
+	})
+
+	t.Run("lists and fields", func(t *testing.T) {
+		w := get("/v0/servers?schema_version=2025-09-29&fields=$schema,packages", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Servers, 1)
+		assert.Equal(t, "ghcr.io/example/negotiated", body.Servers[0].Server.Packages[0].Identifier)
+		assert.Empty(t, body.Servers[0].Server.Name)
+	})
+
+	t.Run("unsupported versions", func(t *testing.T) {
+		w := get(path+"?schema_version=2024-01-01", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Unsupported schema version '2024-01-01'")
+
+		w = get(path, "application/json; schema-version=2024-01-01")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+}
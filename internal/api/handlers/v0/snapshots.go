@@ -0,0 +1,55 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// SnapshotInput represents the input for getting a registry snapshot
+type SnapshotInput struct {
+	ID string `path:"id" doc:"Snapshot ID" example:"2026-10-release"`
+}
+
+// SnapshotOutput is a registry snapshot, served byte for byte as it was captured
+type SnapshotOutput struct {
+	ContentType  string `header:"Content-Type"`
+	ETag         string `header:"ETag" doc:"SHA-256 digest of the snapshot"`
+	LastModified string `header:"Last-Modified" doc:"When the snapshot was taken"`
+	CacheControl string `header:"Cache-Control"`
+	Body         []byte
+}
+
+// RegisterSnapshotEndpoints registers the endpoint serving registry snapshots with a custom path prefix
+func RegisterSnapshotEndpoints(api huma.API, pathPrefix string, registry service.RegistryService) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-snapshot" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/snapshots/{id}",
+		Summary:     "Get registry snapshot",
+		Description: "Get an immutable snapshot of the registry in the registry data file format, exactly as it was captured. Snapshots never change, so they can be pinned for reproducible installs.",
+		Tags:        []string{"snapshots"},
+	}, func(ctx context.Context, input *SnapshotInput) (*SnapshotOutput, error) {
+		snapshot, err := registry.GetSnapshot(ctx, input.ID)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Snapshot not found")
+			}
+			return nil, databaseError(ctx, "Failed to get snapshot", err)
+		}
+
+		return &SnapshotOutput{
+			ContentType:  "application/json",
+			ETag:         `"` + snapshot.SHA256 + `"`,
+			LastModified: snapshot.CreatedAt.UTC().Format(http.TimeFormat),
+			CacheControl: "public, max-age=31536000, immutable",
+			Body:         snapshot.Content,
+		}, nil
+	})
+}
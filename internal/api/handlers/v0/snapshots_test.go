@@ -0,0 +1,122 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestSnapshotEndpoints(t *testing.T) {
+	const adminKey = "test-admin-key"
+	ctx := context.Background()
+	cfg := &config.Config{AdminAPIKey: adminKey}
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+
+	publish := func(version string) {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/pinned",
+			Description: "Snapshot test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+	publish("1.0.0")
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterAdminEndpoints(api, "/v0", registryService, cfg)
+	v0.RegisterSnapshotEndpoints(api, "/v0", registryService)
+
+	createSnapshot := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v0/admin/snapshots", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+adminKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	getSnapshot := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v0/snapshots/"+id, nil))
+		return w
+	}
+
+	w := createSnapshot(`{"id":"release-1"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created v0.AdminSnapshotBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "release-1", created.ID)
+	assert.Equal(t, 1, created.Servers)
+	assert.Len(t, created.SHA256, 64)
+
+	before := getSnapshot("release-1")
+	require.Equal(t, http.StatusOK, before.Code, before.Body.String())
+	assert.Equal(t, `"`+created.SHA256+`"`, before.Header().Get("ETag"))
+	assert.Contains(t, before.Header().Get("Cache-Control"), "immutable")
+	snapshotBody, err := io.ReadAll(before.Body)
+	require.NoError(t, err)
+
+	// The snapshot is a registry data file holding the one version
+	var data struct {
+		Servers []struct {
+			ServerName string `json:"server_name"`
+			Version    string `json:"version"`
+			Status     string `json:"status"`
+		} `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal(snapshotBody, &data))
+	require.Len(t, data.Servers, 1)
+	assert.Equal(t, "1.0.0", data.Servers[0].Version)
+
+	// Mutate the live data
+	publish("2.0.0")
+	_, err = registryService.SetServerStatus(ctx, "com.example/pinned", "1.0.0", model.StatusDeprecated, "")
+	require.NoError(t, err)
+
+	t.Run("snapshot is unchanged by later mutations", func(t *testing.T) {
+		after := getSnapshot("release-1")
+		require.Equal(t, http.StatusOK, after.Code)
+		assert.Equal(t, snapshotBody, after.Body.Bytes())
+		assert.Equal(t, before.Header().Get("ETag"), after.Header().Get("ETag"))
+		assert.Equal(t, before.Header().Get("Last-Modified"), after.Header().Get("Last-Modified"))
+	})
+
+	t.Run("snapshot IDs cannot be reused", func(t *testing.T) {
+		w := createSnapshot(`{"id":"release-1"}`)
+		assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		assert.Equal(t, snapshotBody, getSnapshot("release-1").Body.Bytes())
+	})
+
+	t.Run("default ID is a digest of the content", func(t *testing.T) {
+		w := createSnapshot(``)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var latest v0.AdminSnapshotBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+		assert.Equal(t, latest.SHA256[:16], latest.ID)
+		assert.Equal(t, 2, latest.Servers)
+		assert.NotEqual(t, created.SHA256, latest.SHA256)
+		assert.Equal(t, http.StatusOK, getSnapshot(latest.ID).Code)
+	})
+
+	t.Run("invalid and unknown IDs", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, createSnapshot(`{"id":"../escape"}`).Code)
+		assert.Equal(t, http.StatusNotFound, getSnapshot("missing").Code)
+	})
+}
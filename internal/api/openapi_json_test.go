@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+func TestOpenAPIJSONEndpoint(t *testing.T) {
+	shutdownTelemetry, metrics, err := telemetry.InitMetrics("test")
+	require.NoError(t, err)
+	defer func() { _ = shutdownTelemetry(context.Background()) }()
+
+	cfg := &config.Config{
+		JWTPrivateKey: "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20", // 32-byte hex key
+	}
+	versionInfo := &v0.VersionBody{Version: "test", GitCommit: "test", BuildTime: "test"}
+
+	mux := http.NewServeMux()
+	router.NewHumaAPI(cfg, nil, mux, metrics, versionInfo)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "json")
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			Responses map[string]any `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+
+	assert.Regexp(t, `^3\.`, spec.OpenAPI)
+
+	// The servers list endpoint and all of its filter parameters must be described
+	listServers, ok := spec.Paths["/v0/servers"]["get"]
+	require.True(t, ok, "GET /v0/servers should be in the spec")
+	assert.Equal(t, "list-servers-v0", listServers.OperationID)
+
+	params := map[string]string{}
+	for _, p := range listServers.Parameters {
+		params[p.Name] = p.In
+	}
+	for _, name := range []string{"cursor", "limit", "updated_since", "search", "version"} {
+		assert.Equal(t, "query", params[name], "query parameter %q should be described", name)
+	}
+	assert.Contains(t, listServers.Responses, "200")
+	assert.Contains(t, listServers.Responses, "default", "error responses should be described")
+
+	// Response and error schemas are generated from the Go types
+	for _, schema := range []string{"ServerListResponse", "ServerResponse", "ErrorModel"} {
+		assert.Contains(t, spec.Components.Schemas, schema)
+	}
+}
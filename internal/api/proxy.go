@@ -0,0 +1,140 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+)
+
+// ProxyTrust decides whose X-Forwarded-* headers to believe. Without trusted proxies it follows a single
+// trust-everyone switch; with them, only requests whose direct peer is a trusted proxy have their
+// forwarded headers honored, so clients connecting directly can't spoof their address or scheme.
+type ProxyTrust struct {
+	trustAll bool
+	proxies  []netip.Prefix
+}
+
+// NewProxyTrust builds a ProxyTrust from CIDRs or single IPs. Invalid entries are logged and ignored.
+// trustAll applies only when no proxies are given.
+func NewProxyTrust(trustAll bool, proxies []string) *ProxyTrust {
+	trust := &ProxyTrust{trustAll: trustAll}
+	for _, entry := range proxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := parseProxy(entry)
+		if err != nil {
+			log.Printf("Ignoring invalid trusted proxy %q: expected a CIDR such as 10.0.0.0/8 or an IP address", entry)
+			continue
+		}
+		trust.proxies = append(trust.proxies, prefix)
+	}
+	if len(trust.proxies) > 0 {
+		trust.trustAll = false
+	}
+	return trust
+}
+
+// parseProxy parses a CIDR, or a single IP as a prefix covering only that address
+func parseProxy(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// isTrusted reports whether an address is a trusted proxy
+func (p *ProxyTrust) isTrusted(addr netip.Addr) bool {
+	if p.trustAll {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustsForwarded reports whether the forwarded headers of a request should be honored, which is
+// when its direct peer is a trusted proxy
+func (p *ProxyTrust) TrustsForwarded(r *http.Request) bool {
+	peer, ok := peerAddr(r)
+	if !ok {
+		return p.trustAll
+	}
+	return p.isTrusted(peer)
+}
+
+// ClientIP resolves the address of the client that made a request. Requests from trusted proxies are
+// traced back through X-Forwarded-For, from the nearest hop outwards, to the first address that isn't
+// itself a trusted proxy; anything else is attributed to the socket's remote address.
+func (p *ProxyTrust) ClientIP(r *http.Request) string {
+	peer, ok := peerAddr(r)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !p.isTrusted(peer) {
+		return peer.String()
+	}
+
+	client := peer
+	hops := forwardedFor(r)
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// The chain can't be followed past a malformed entry
+			break
+		}
+		client = hop.Unmap()
+		if !p.isTrusted(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// peerAddr returns the address of the direct peer of a request
+func peerAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// forwardedFor returns the entries of every X-Forwarded-For header of a request, client first
+func forwardedFor(r *http.Request) []string {
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// ClientIPMiddleware stores the client address of each request, as resolved by trust, in the request context
+func ClientIPMiddleware(trust *ProxyTrust, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(v0.WithClientIP(r.Context(), trust.ClientIP(r))))
+	})
+}
@@ -0,0 +1,110 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/modelcontextprotocol/registry/internal/api"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	var resolved string
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		resolved = v0.ClientIP(r.Context())
+	})
+
+	tests := []struct {
+		name          string
+		trustAll      bool
+		proxies       []string
+		remoteAddr    string
+		forwardedFor  []string
+		expectedIP    string
+		trustsForward bool
+	}{
+		{
+			name:          "trusted peer is traced back to the client",
+			proxies:       []string{"10.0.0.0/8"},
+			remoteAddr:    "10.0.0.5:51234",
+			forwardedFor:  []string{"203.0.113.7"},
+			expectedIP:    "203.0.113.7",
+			trustsForward: true,
+		},
+		{
+			name:          "untrusted peer cannot spoof its address",
+			proxies:       []string{"10.0.0.0/8"},
+			remoteAddr:    "198.51.100.20:51234",
+			forwardedFor:  []string{"203.0.113.7"},
+			expectedIP:    "198.51.100.20",
+			trustsForward: false,
+		},
+		{
+			name:          "entries added by the client before the trusted chain are ignored",
+			proxies:       []string{"10.0.0.0/8", "192.168.1.1"},
+			remoteAddr:    "10.0.0.5:51234",
+			forwardedFor:  []string{"1.2.3.4, 203.0.113.7", "192.168.1.1"},
+			expectedIP:    "203.0.113.7",
+			trustsForward: true,
+		},
+		{
+			name:          "trusted peer without forwarded headers is the client",
+			proxies:       []string{"10.0.0.0/8"},
+			remoteAddr:    "10.0.0.5:51234",
+			expectedIP:    "10.0.0.5",
+			trustsForward: true,
+		},
+		{
+			name:          "IPv6 proxy ranges",
+			proxies:       []string{"fd00::/8"},
+			remoteAddr:    "[fd00::1]:51234",
+			forwardedFor:  []string{"2001:db8::7"},
+			expectedIP:    "2001:db8::7",
+			trustsForward: true,
+		},
+		{
+			name:          "nothing trusted by default",
+			remoteAddr:    "10.0.0.5:51234",
+			forwardedFor:  []string{"203.0.113.7"},
+			expectedIP:    "10.0.0.5",
+			trustsForward: false,
+		},
+		{
+			name:          "trusting every peer without a proxy list",
+			trustAll:      true,
+			remoteAddr:    "10.0.0.5:51234",
+			forwardedFor:  []string{"203.0.113.7, 10.0.0.9"},
+			expectedIP:    "203.0.113.7",
+			trustsForward: true,
+		},
+		{
+			name:          "a proxy list overrides trusting every peer",
+			trustAll:      true,
+			proxies:       []string{"10.0.0.0/8", "not-a-cidr"},
+			remoteAddr:    "198.51.100.20:51234",
+			forwardedFor:  []string{"203.0.113.7"},
+			expectedIP:    "198.51.100.20",
+			trustsForward: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trust := api.NewProxyTrust(tt.trustAll, tt.proxies)
+
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			resolved = ""
+			api.ClientIPMiddleware(trust, handler).ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.expectedIP, resolved)
+			assert.Equal(t, tt.trustsForward, trust.TrustsForwarded(req))
+		})
+	}
+}
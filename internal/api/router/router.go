@@ -10,8 +10,6 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/metric"
 
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/config"
@@ -37,7 +35,7 @@ func getRoutePath(ctx huma.Context) string {
 	return ctx.URL().Path
 }
 
-func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...MiddlewareOption) func(huma.Context, func(huma.Context)) {
+func MetricTelemetryMiddleware(metrics telemetry.Recorder, options ...MiddlewareOption) func(huma.Context, func(huma.Context)) {
 	config := &middlewareConfig{
 		skipPaths: make(map[string]bool),
 	}
@@ -64,24 +62,7 @@ func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...Middleware
 
 		next(ctx)
 
-		duration := time.Since(start).Seconds()
-		statusCode := ctx.Status()
-
-		// Combine common and custom attributes
-		attrs := []attribute.KeyValue{
-			attribute.String("method", method),
-			attribute.String("path", routePath),
-			attribute.Int("status_code", statusCode),
-		}
-
-		// Record metrics
-		metrics.Requests.Add(ctx.Context(), 1, metric.WithAttributes(attrs...))
-
-		if statusCode >= 400 {
-			metrics.ErrorCount.Add(ctx.Context(), 1, metric.WithAttributes(attrs...))
-		}
-
-		metrics.RequestDuration.Record(ctx.Context(), duration, metric.WithAttributes(attrs...))
+		metrics.RecordRequest(ctx.Context(), method, routePath, ctx.Status(), time.Since(start))
 	}
 }
 
@@ -130,12 +111,16 @@ func handle404(w http.ResponseWriter, r *http.Request) {
 }
 
 // NewHumaAPI creates a new Huma API with all routes registered
-func NewHumaAPI(cfg *config.Config, registry service.RegistryService, mux *http.ServeMux, metrics *telemetry.Metrics, versionInfo *v0.VersionBody) huma.API {
+func NewHumaAPI(cfg *config.Config, registry service.RegistryService, mux *http.ServeMux, metrics telemetry.Recorder, versionInfo *v0.VersionBody) huma.API {
 	// Create Huma API configuration
 	humaConfig := huma.DefaultConfig("Official MCP Registry", "1.0.0")
 	humaConfig.Info.Description = "A community driven registry service for Model Context Protocol (MCP) servers.\n\n[GitHub repository](https://github.com/modelcontextprotocol/registry) | [Documentation](https://github.com/modelcontextprotocol/registry/tree/main/docs)"
 	// Disable $schema property in responses: https://github.com/danielgtaylor/huma/issues/230
 	humaConfig.CreateHooks = []func(huma.Config) huma.Config{}
+	// Huma's default config serves the OpenAPI spec at /openapi.json and /openapi.yaml. The spec is generated
+	// from the registered operations and their input/output struct tags, so it can't drift from the handlers.
+	// Respond with YAML when requested via `Accept: application/yaml`, JSON otherwise
+	humaConfig.Formats = v0.ResponseFormats()
 
 	// Create a new API using humago adapter for standard library
 	api := humago.New(mux, humaConfig)
@@ -177,12 +162,17 @@ func NewHumaAPI(cfg *config.Config, registry service.RegistryService, mux *http.
 		WithSkipPaths("/health", "/metrics", "/ping", "/docs"),
 	))
 
+	// Tell clients when to retry on 503s
+	api.UseMiddleware(v0.RetryAfterMiddleware(cfg.RetryAfter))
+
 	// Register routes for all API versions
 	RegisterV0Routes(api, cfg, registry, metrics, versionInfo)
 	RegisterV0_1Routes(api, cfg, registry, metrics, versionInfo)
 
 	// Add /metrics for Prometheus metrics using promhttp
-	mux.Handle("/metrics", metrics.PrometheusHandler())
+	if scrapable, ok := metrics.(telemetry.Scrapable); ok {
+		mux.Handle("/metrics", scrapable.PrometheusHandler())
+	}
 
 	// Add UI and 404 handler for all other routes
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
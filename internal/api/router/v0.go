@@ -12,26 +12,38 @@ import (
 )
 
 func RegisterV0Routes(
-	api huma.API, cfg *config.Config, registry service.RegistryService, metrics *telemetry.Metrics, versionInfo *v0.VersionBody,
+	api huma.API, cfg *config.Config, registry service.RegistryService, metrics telemetry.Recorder, versionInfo *v0.VersionBody,
 ) {
-	v0.RegisterHealthEndpoint(api, "/v0", cfg, metrics)
+	v0.RegisterHealthEndpoint(api, "/v0", cfg, registry, metrics)
 	v0.RegisterPingEndpoint(api, "/v0")
 	v0.RegisterVersionEndpoint(api, "/v0", versionInfo)
+	v0.RegisterSchemaEndpoints(api, "/v0")
 	v0.RegisterServersEndpoints(api, "/v0", registry)
+	v0.RegisterCapabilitiesEndpoint(api, "/v0", cfg)
+	v0.RegisterChangesEndpoint(api, "/v0", registry)
+	v0.RegisterSnapshotEndpoints(api, "/v0", registry)
+	v0.RegisterValidateEndpoint(api, "/v0", registry, cfg)
 	v0auth.RegisterAuthEndpoints(api, "/v0", cfg)
+	v0.RegisterAdminEndpoints(api, "/v0", registry, cfg)
 	// Disable edit and publish endpoints in v0
 	//v0.RegisterEditEndpoints(api, "/v0", registry, cfg)
 	//v0.RegisterPublishEndpoint(api, "/v0", registry, cfg)
 }
 
 func RegisterV0_1Routes(
-	api huma.API, cfg *config.Config, registry service.RegistryService, metrics *telemetry.Metrics, versionInfo *v0.VersionBody,
+	api huma.API, cfg *config.Config, registry service.RegistryService, metrics telemetry.Recorder, versionInfo *v0.VersionBody,
 ) {
-	v0.RegisterHealthEndpoint(api, "/v0.1", cfg, metrics)
+	v0.RegisterHealthEndpoint(api, "/v0.1", cfg, registry, metrics)
 	v0.RegisterPingEndpoint(api, "/v0.1")
 	v0.RegisterVersionEndpoint(api, "/v0.1", versionInfo)
+	v0.RegisterSchemaEndpoints(api, "/v0.1")
 	v0.RegisterServersEndpoints(api, "/v0.1", registry)
+	v0.RegisterCapabilitiesEndpoint(api, "/v0.1", cfg)
+	v0.RegisterChangesEndpoint(api, "/v0.1", registry)
+	v0.RegisterSnapshotEndpoints(api, "/v0.1", registry)
+	v0.RegisterValidateEndpoint(api, "/v0.1", registry, cfg)
 	v0auth.RegisterAuthEndpoints(api, "/v0.1", cfg)
+	v0.RegisterAdminEndpoints(api, "/v0.1", registry, cfg)
 	// Disable edit and publish endpoints in v0.1
 	//v0.RegisterEditEndpoints(api, "/v0.1", registry, cfg)
 	//v0.RegisterPublishEndpoint(api, "/v0.1", registry, cfg)
@@ -2,9 +2,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -13,6 +20,7 @@ import (
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/api/router"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
@@ -35,16 +43,161 @@ func TrailingSlashMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// BaseURLMiddleware determines the external base URL of each request and stores it in the request
+// context, so handlers generate absolute URLs with the scheme and host clients actually used.
+// A configured base URL wins; otherwise X-Forwarded-Proto and X-Forwarded-Host are honored when
+// trust trusts the request's peer, falling back to the connection's own scheme and Host header.
+func BaseURLMiddleware(baseURL string, trust *ProxyTrust, next http.Handler) http.Handler {
+	var configured *url.URL
+	if baseURL != "" {
+		parsed, err := url.Parse(baseURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			log.Printf("Ignoring invalid base URL %q: expected an absolute URL such as https://registry.example.com", baseURL)
+		} else {
+			configured = parsed
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		external := configured
+		if external == nil {
+			external = requestBaseURL(r, trust.TrustsForwarded(r))
+		}
+
+		next.ServeHTTP(w, r.WithContext(v0.WithBaseURL(r.Context(), external)))
+	})
+}
+
+// requestBaseURL derives the base URL from the request, optionally trusting proxy headers
+func requestBaseURL(r *http.Request, trustForwarded bool) *url.URL {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+
+	if trustForwarded {
+		if proto := firstHeaderValue(r.Header.Get("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwardedHost := firstHeaderValue(r.Header.Get("X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	return &url.URL{Scheme: scheme, Host: host}
+}
+
+// firstHeaderValue returns the first entry of a comma-separated header set by a chain of proxies,
+// which is the one closest to the client
+func firstHeaderValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.ToLower(strings.TrimSpace(first))
+}
+
+// WriteDrain tracks in-flight mutating requests so shutdown can let them finish before the database is closed.
+// Once draining starts, new mutating requests are rejected with 503 while reads continue to be served.
+type WriteDrain struct {
+	// RetryAfter is sent in a Retry-After header with rejected requests, rounded up to whole seconds; 0 omits it
+	RetryAfter time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// Middleware counts mutating requests while they run and rejects them once draining has started
+func (d *WriteDrain) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			writeShuttingDown(w, d.RetryAfter)
+			return
+		}
+		d.inFlight.Add(1)
+		d.mu.Unlock()
+		defer d.inFlight.Done()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Drain stops accepting mutating requests and waits for those in flight to complete, returning the context's
+// error if it is done first
+func (d *WriteDrain) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReadOnlyMiddleware marks the context of GET and HEAD requests with database.ReadOnly, so the reads that serve
+// them may come from a read replica. Other requests, including the reads they check preconditions with, use the
+// primary.
+func ReadOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			r = r.WithContext(database.ReadOnly(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isMutatingMethod reports whether requests with an HTTP method may change registry data
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// writeShuttingDown responds to a mutating request received while the server is shutting down, telling the
+// client to retry after retryAfter when it is set
+func writeShuttingDown(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Connection", "close")
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(huma.ErrorModel{
+		Title:  http.StatusText(http.StatusServiceUnavailable),
+		Status: http.StatusServiceUnavailable,
+		Detail: "The registry is shutting down and not accepting changes; retry shortly",
+	})
+}
+
 // Server represents the HTTP server
 type Server struct {
 	config   *config.Config
 	registry service.RegistryService
 	humaAPI  huma.API
 	server   *http.Server
+	writes   *WriteDrain
 }
 
 // NewServer creates a new HTTP server
-func NewServer(cfg *config.Config, registryService service.RegistryService, metrics *telemetry.Metrics, versionInfo *v0.VersionBody) *Server {
+func NewServer(cfg *config.Config, registryService service.RegistryService, metrics telemetry.Recorder, versionInfo *v0.VersionBody) *Server {
 	// Create HTTP mux and Huma API
 	mux := http.NewServeMux()
 
@@ -66,14 +219,22 @@ func NewServer(cfg *config.Config, registryService service.RegistryService, metr
 		MaxAge:           86400, // 24 hours
 	})
 
+	// The draining server will have stopped within the shutdown timeout, by when a replacement should be serving
+	writes := &WriteDrain{RetryAfter: cfg.ShutdownTimeout}
+
 	// Wrap the mux with middleware stack
-	// Order: TrailingSlash -> CORS -> Mux
-	handler := TrailingSlashMiddleware(corsHandler.Handler(mux))
+	// Order: TrailingSlash -> CORS -> WriteDrain -> ReadOnly -> ClientIP -> BaseURL -> Mux
+	// None of it authenticates; auth is applied per route, so health checks, metrics scrapes and the OpenAPI
+	// document never require it, and admin routes are authenticated by their own route group.
+	proxies := NewProxyTrust(cfg.TrustForwardedHeaders, cfg.TrustedProxies)
+	handler := BaseURLMiddleware(cfg.BaseURL, proxies, mux)
+	handler = TrailingSlashMiddleware(corsHandler.Handler(writes.Middleware(ReadOnlyMiddleware(ClientIPMiddleware(proxies, handler)))))
 
 	server := &Server{
 		config:   cfg,
 		registry: registryService,
 		humaAPI:  api,
+		writes:   writes,
 		server: &http.Server{
 			Addr:              cfg.ServerAddress,
 			Handler:           handler,
@@ -84,13 +245,43 @@ func NewServer(cfg *config.Config, registryService service.RegistryService, metr
 	return server
 }
 
-// Start begins listening for incoming HTTP requests
+// Handler returns the server's HTTP handler, including the full middleware stack
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start begins listening for incoming HTTP requests on the configured address
 func (s *Server) Start() error {
-	log.Printf("HTTP server starting on %s", s.config.ServerAddress)
-	return s.server.ListenAndServe()
+	listener, err := net.Listen("tcp", s.config.ServerAddress)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
 }
 
-// Shutdown gracefully shuts down the server
+// Serve accepts incoming HTTP requests on listener. When a TLS certificate and key are configured, it serves
+// HTTPS, negotiating HTTP/2 with clients that support it; otherwise it serves plaintext HTTP/1.1.
+func (s *Server) Serve(listener net.Listener) error {
+	certFile, keyFile := s.config.TLSCert, s.config.TLSKey
+	if (certFile == "") != (keyFile == "") {
+		listener.Close()
+		return errors.New("TLS requires both MCP_REGISTRY_TLS_CERT and MCP_REGISTRY_TLS_KEY to be set")
+	}
+
+	if certFile == "" {
+		log.Printf("HTTP server starting on %s (plaintext HTTP/1.1; TLS is expected to be terminated by a proxy)", listener.Addr())
+		return s.server.Serve(listener)
+	}
+	log.Printf("HTTP server starting on %s (HTTPS with HTTP/2, certificate %s)", listener.Addr(), certFile)
+	return s.server.ServeTLS(listener, certFile, keyFile)
+}
+
+// Shutdown gracefully shuts down the server. Mutating requests are drained first, so publishes and edits
+// in flight complete before the caller closes the database, then the listener is closed and remaining
+// requests are given until ctx is done to finish.
 func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.writes.Drain(ctx); err != nil {
+		log.Printf("Timed out waiting for in-flight writes to complete: %v", err)
+	}
 	return s.server.Shutdown(ctx)
 }
@@ -1,11 +1,44 @@
 package api_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
 
 	"github.com/modelcontextprotocol/registry/internal/api"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
 func TestTrailingSlashMiddleware(t *testing.T) {
@@ -93,3 +126,450 @@ func TestTrailingSlashMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseURLMiddleware(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), config.NewConfig())
+	for _, name := range []string{"com.example/link-a", "com.example/link-b"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Link test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	humaAPI := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(humaAPI, "/v0", registryService)
+
+	serve := func(handler http.Handler, path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = "internal:8080"
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	forwarded := map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "registry.example.com, proxy.internal",
+	}
+	latestPath := "/v0/servers/" + url.PathEscape("com.example/link-a") + "/latest"
+
+	t.Run("trusted forwarded headers set the scheme and host", func(t *testing.T) {
+		handler := api.BaseURLMiddleware("", api.NewProxyTrust(true, nil), mux)
+
+		w := serve(handler, latestPath, forwarded)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://registry.example.com/v0/servers/com.example%2Flink-a/versions/1.0.0", w.Header().Get("Location"))
+
+		w = serve(handler, "/v0/servers?limit=1&search=link", forwarded)
+		require.Equal(t, http.StatusOK, w.Code)
+		link := w.Header().Get("Link")
+		assert.True(t, strings.HasPrefix(link, "<https://registry.example.com/v0/servers?cursor="), link)
+		assert.Contains(t, link, "search=link")
+		assert.True(t, strings.HasSuffix(link, `>; rel="next"`), link)
+	})
+
+	t.Run("untrusted forwarded headers are ignored", func(t *testing.T) {
+		w := serve(api.BaseURLMiddleware("", api.NewProxyTrust(false, nil), mux), latestPath, forwarded)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "http://internal:8080/v0/servers/com.example%2Flink-a/versions/1.0.0", w.Header().Get("Location"))
+	})
+
+	t.Run("forwarded headers are only honored from trusted proxies", func(t *testing.T) {
+		handler := api.BaseURLMiddleware("", api.NewProxyTrust(false, []string{"10.0.0.0/8"}), mux)
+
+		req := httptest.NewRequest(http.MethodGet, latestPath, nil)
+		req.Host = "internal:8080"
+		req.RemoteAddr = "10.1.2.3:40000"
+		for key, value := range forwarded {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://registry.example.com/v0/servers/com.example%2Flink-a/versions/1.0.0", w.Header().Get("Location"))
+
+		// httptest requests come from 192.0.2.1, outside the trusted range
+		w = serve(handler, latestPath, forwarded)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "http://internal:8080/v0/servers/com.example%2Flink-a/versions/1.0.0", w.Header().Get("Location"))
+	})
+
+	t.Run("configured base URL wins", func(t *testing.T) {
+		w := serve(api.BaseURLMiddleware("https://mcp.example.org/registry/", api.NewProxyTrust(true, nil), mux), latestPath, forwarded)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://mcp.example.org/registry/v0/servers/com.example%2Flink-a/versions/1.0.0", w.Header().Get("Location"))
+	})
+
+	t.Run("last page has no next link", func(t *testing.T) {
+		w := serve(api.BaseURLMiddleware("", api.NewProxyTrust(true, nil), mux), "/v0/servers", forwarded)
+		require.Equal(t, http.StatusOK, w.Code)
+		for _, link := range w.Header().Values("Link") {
+			assert.NotContains(t, link, `rel="next"`)
+		}
+	})
+}
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	var readOnly bool
+	handler := api.ReadOnlyMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		readOnly = database.IsReadOnly(r.Context())
+	}))
+
+	for method, want := range map[string]bool{
+		http.MethodGet:    true,
+		http.MethodHead:   true,
+		http.MethodPost:   false,
+		http.MethodPut:    false,
+		http.MethodDelete: false,
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/v0/servers", nil))
+		assert.Equal(t, want, readOnly, method)
+	}
+}
+
+func TestNewServer_HealthAndAdminAuth(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AdminAPIKey = "test-admin-key"
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	handler := api.NewServer(cfg, registryService, metrics, &v0.VersionBody{}).Handler()
+
+	serve := func(method, path, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("health needs no auth", func(t *testing.T) {
+		for _, path := range []string{"/v0/health", "/v0.1/health"} {
+			w := serve(http.MethodGet, path, "")
+			assert.Equal(t, http.StatusOK, w.Code, path)
+		}
+	})
+
+	t.Run("admin requires the admin key", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/flush", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+
+		w = serve(http.MethodPost, "/v0/admin/flush", "Bearer wrong-key")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+
+		w = serve(http.MethodPost, "/v0/admin/flush", "Bearer test-admin-key")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+}
+
+// auditEntries is an audit sink collecting entries in memory
+type auditEntries []service.AuditEntry
+
+func (a *auditEntries) Record(_ context.Context, entry service.AuditEntry) error {
+	*a = append(*a, entry)
+	return nil
+}
+
+func TestNewServer_AuditsClientIP(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AdminAPIKey = "test-admin-key"
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	cfg.EnableRegistryValidation = false
+	cfg.TrustedProxies = []string{"192.0.2.0/24"}
+	audited := &auditEntries{}
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg, service.WithAuditSink(audited))
+	_, err := registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/audited",
+		Description: "A server changed through the admin API",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	*audited = nil
+
+	handler := api.NewServer(cfg, registryService, telemetry.NoopRecorder{}, &v0.VersionBody{}).Handler()
+
+	// httptest requests come from 192.0.2.1, a trusted proxy, so the forwarded client address is used
+	path := "/v0/admin/servers/" + url.PathEscape("com.example/audited") + "/versions/1.0.0/status"
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"status":"deprecated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-admin-key")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.Len(t, *audited, 1)
+	assert.Equal(t, service.Actor{AuthMethod: "admin_api_key", Subject: "admin", IP: "203.0.113.7"}, (*audited)[0].Actor)
+}
+
+func TestNewServer_FailedMetricsInit(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+
+	failingInit := func(string) (telemetry.ShutdownFunc, *telemetry.Metrics, error) {
+		return nil, nil, errors.New("otlp endpoint unreachable")
+	}
+	shutdown, metrics := telemetry.InitMetricsOrNoop("test", failingInit)
+	require.NotNil(t, metrics)
+	require.NoError(t, shutdown(context.Background()))
+
+	handler := api.NewServer(cfg, registryService, metrics, &v0.VersionBody{}).Handler()
+	for _, path := range []string{"/v0/health", "/v0/servers", "/v0/does-not-exist"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.NotEqual(t, http.StatusInternalServerError, w.Code, path)
+	}
+}
+
+// requestMetrics is a metrics fake recording the HTTP requests reported to it
+type requestMetrics struct {
+	telemetry.NoopRecorder
+	requests []string
+}
+
+func (m *requestMetrics) RecordRequest(_ context.Context, method, path string, statusCode int, _ time.Duration) {
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", method, path, statusCode))
+}
+
+func TestNewServer_RecordsRequestMetrics(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+	metrics := &requestMetrics{}
+	handler := api.NewServer(cfg, registryService, metrics, &v0.VersionBody{}).Handler()
+
+	for _, path := range []string{"/v0/servers", "/v0/health", "/v0/servers/com.example%2Fmissing/versions/1.0.0"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Health checks aren't instrumented, and requests are recorded by route rather than by path
+	assert.Equal(t, []string{
+		"GET /v0/servers 200",
+		"GET /v0/servers/{serverName}/versions/{version} 404",
+	}, metrics.requests)
+
+	t.Run("metrics aren't served without a scrapable recorder", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// slowPublishService blocks publishes of one version until released, simulating a slow publish transaction
+type slowPublishService struct {
+	service.RegistryService
+	slowVersion string
+	started     chan struct{}
+	release     chan struct{}
+}
+
+func (s *slowPublishService) CreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+	if req.Version == s.slowVersion {
+		close(s.started)
+		<-s.release
+	}
+	return s.RegistryService.CreateServer(ctx, req)
+}
+
+func TestWriteDrain_SlowPublishCompletes(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	cfg.EnableRegistryValidation = false
+	registryService := &slowPublishService{
+		RegistryService: service.NewRegistryService(database.NewTestJSONFileDB(t), cfg),
+		slowVersion:     "1.0.0",
+		started:         make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	humaAPI := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPingEndpoint(humaAPI, "/v0")
+	v0.RegisterPublishEndpoint(humaAPI, "/v0", registryService, cfg)
+	drain := &api.WriteDrain{RetryAfter: 10 * time.Second}
+	handler := drain.Middleware(mux)
+
+	token, err := auth.NewJWTManager(cfg).GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	publish := func(version string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/slow-server",
+			Description: "A server published during shutdown",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token.RegistryToken)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	published := make(chan *httptest.ResponseRecorder, 1)
+	go func() { published <- publish("1.0.0") }()
+	select {
+	case <-registryService.started:
+	case w := <-published:
+		t.Fatalf("publish returned before reaching the service: %d %s", w.Code, w.Body.String())
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		drained <- drain.Drain(ctx)
+	}()
+
+	// New writes are rejected once draining starts, while reads are still served
+	attempt := 1
+	var rejected *httptest.ResponseRecorder
+	require.Eventually(t, func() bool {
+		attempt++
+		rejected = publish(fmt.Sprintf("%d.0.0", attempt))
+		return rejected.Code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "10", rejected.Header().Get("Retry-After"))
+	ping := httptest.NewRecorder()
+	handler.ServeHTTP(ping, httptest.NewRequest(http.MethodGet, "/v0/ping", nil))
+	assert.Equal(t, http.StatusOK, ping.Code)
+
+	select {
+	case <-drained:
+		t.Fatal("drain completed while a publish was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(registryService.release)
+	w := <-published
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, <-drained)
+
+	stored, err := registryService.GetServerByNameAndVersion(context.Background(), "com.example/slow-server", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", stored.Server.Version)
+}
+
+func TestWriteDrain_DeadlineExceeded(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	drain := &api.WriteDrain{}
+	handler := drain.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v0/admin/flush", nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, drain.Drain(ctx), context.DeadlineExceeded)
+}
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and its key to dir, returning the certificate
+// pool that trusts it and the file paths
+func writeSelfSignedCert(t *testing.T, dir string) (*x509.CertPool, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "registry test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return pool, certFile, keyFile
+}
+
+func TestServer_TLS(t *testing.T) {
+	pool, certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.TLSCert = certFile
+	cfg.TLSKey = keyFile
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+	server := api.NewServer(cfg, registryService, metrics, &v0.VersionBody{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, server.Shutdown(ctx))
+		require.ErrorIs(t, <-served, http.ErrServerClosed)
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/v0/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+	require.NotNil(t, resp.TLS)
+}
+
+func TestServer_TLSRequiresCertAndKey(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.TLSCert = "cert.pem"
+	cfg.JWTPrivateKey = strings.Repeat("ab", 32)
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+	server := api.NewServer(cfg, registryService, metrics, &v0.VersionBody{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	err = server.Serve(listener)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MCP_REGISTRY_TLS_KEY")
+}
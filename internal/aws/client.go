@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// userAgentKey identifies the registry in the user agent of its AWS requests, followed by its version. It also
+// names the sessions of assumed roles.
+const userAgentKey = "mcp-registry"
+
+// ClientSettings configures the AWS clients the registry creates
+type ClientSettings struct {
+	Version string // registry version, reported in the user agent of every AWS request
+	Profile string // named profile in the shared AWS config and credentials files; empty uses the default
+	RoleARN string // IAM role to assume with the loaded credentials; empty uses them directly
+}
+
+var (
+	clientSettingsMu sync.RWMutex
+	clientSettings   ClientSettings
+)
+
+// Configure sets up the AWS clients created from now on. Call it at startup, before creating any client.
+func Configure(settings ClientSettings) {
+	clientSettingsMu.Lock()
+	defer clientSettingsMu.Unlock()
+	clientSettings = settings
+}
+
+// currentSettings returns the settings set by Configure
+func currentSettings() ClientSettings {
+	clientSettingsMu.RLock()
+	defer clientSettingsMu.RUnlock()
+	return clientSettings
+}
+
+// loadConfig loads the default AWS configuration with the configured profile and role, adding the registry's user
+// agent and the logging of failed requests to every client created from it
+func loadConfig(ctx context.Context) (aws.Config, error) {
+	settings := currentSettings()
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions(settings)...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	assumeRole(&cfg, settings.RoleARN)
+	return cfg, nil
+}
+
+// loadOptions returns the options loadConfig builds the AWS configuration with
+func loadOptions(settings ClientSettings) []func(*config.LoadOptions) error {
+	opts := []func(*config.LoadOptions) error{config.WithAPIOptions(apiOptions(settings))}
+	if settings.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(settings.Profile))
+	}
+	return opts
+}
+
+// assumeRole replaces the credentials of cfg with temporary ones for roleARN, obtained from STS with the original
+// credentials and refreshed before they expire. An empty roleARN leaves cfg unchanged.
+func assumeRole(cfg *aws.Config, roleARN string) {
+	if roleARN == "" {
+		return
+	}
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*cfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = userAgentKey
+	})
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+}
+
+// apiOptions returns the middleware the registry adds to the stack of every AWS request
+func apiOptions(settings ClientSettings) []func(*middleware.Stack) error {
+	version := settings.Version
+	if version == "" {
+		version = "dev"
+	}
+	return []func(*middleware.Stack) error{
+		awsmiddleware.AddUserAgentKeyValue(userAgentKey, version),
+		addFailureLogging,
+	}
+}
+
+// addFailureLogging logs each request that fails, after retries, with the request ID AWS assigned it, so the
+// failure can be traced in AWS support cases and CloudTrail
+func addFailureLogging(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RegistryFailureLogging",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			if err != nil {
+				requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata)
+				var responseErr *awshttp.ResponseError
+				if !ok && errors.As(err, &responseErr) {
+					requestID = responseErr.ServiceRequestID()
+				}
+				if requestID == "" {
+					requestID = "none"
+				}
+				log.Printf("AWS %s %s failed (request ID %s): %v",
+					awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), requestID, err)
+			}
+			return out, metadata, err
+		}), middleware.After)
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// fakeHTTPClient records the requests sent to it and fails each with an AWS error response
+type fakeHTTPClient struct {
+	requests []*http.Request
+}
+
+func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header: http.Header{
+			"Content-Type":     []string{"application/x-amz-json-1.0"},
+			"X-Amzn-Requestid": []string{"req-1234"},
+		},
+		Body: io.NopCloser(strings.NewReader(`{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"The specified queue does not exist."}`)),
+	}, nil
+}
+
+func TestAPIOptions_RegistersMiddleware(t *testing.T) {
+	stack := middleware.NewStack("test", nil)
+	for _, option := range apiOptions(ClientSettings{Version: "1.2.3"}) {
+		if err := option(stack); err != nil {
+			t.Fatalf("applying API option: %v", err)
+		}
+	}
+
+	if _, ok := stack.Build.Get("UserAgent"); !ok {
+		t.Errorf("user agent middleware is not registered")
+	}
+	if _, ok := stack.Initialize.Get("RegistryFailureLogging"); !ok {
+		t.Errorf("failure logging middleware is not registered")
+	}
+}
+
+func TestAPIOptions_Requests(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	httpClient := &fakeHTTPClient{}
+	client := sqs.New(sqs.Options{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		HTTPClient:       httpClient,
+		RetryMaxAttempts: 1,
+		APIOptions:       apiOptions(ClientSettings{Version: "1.2.3"}),
+	})
+
+	_, err := client.GetQueueUrl(context.Background(), &sqs.GetQueueUrlInput{QueueName: aws.String("missing")})
+	if err == nil {
+		t.Fatalf("GetQueueUrl() should fail")
+	}
+
+	if len(httpClient.requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(httpClient.requests))
+	}
+	if ua := httpClient.requests[0].Header.Get("User-Agent"); !strings.Contains(ua, "mcp-registry/1.2.3") {
+		t.Errorf("User-Agent = %q, want it to contain mcp-registry/1.2.3", ua)
+	}
+	if got := logs.String(); !strings.Contains(got, "SQS GetQueueUrl failed (request ID req-1234)") {
+		t.Errorf("log = %q, want the failed operation and its request ID", got)
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    ClientSettings
+		wantProfile string
+	}{
+		{name: "default credentials", settings: ClientSettings{Version: "1.2.3"}},
+		{name: "named profile", settings: ClientSettings{Version: "1.2.3", Profile: "registry"}, wantProfile: "registry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts config.LoadOptions
+			for _, option := range loadOptions(tt.settings) {
+				if err := option(&opts); err != nil {
+					t.Fatalf("applying load option: %v", err)
+				}
+			}
+
+			if opts.SharedConfigProfile != tt.wantProfile {
+				t.Errorf("SharedConfigProfile = %q, want %q", opts.SharedConfigProfile, tt.wantProfile)
+			}
+			if len(opts.APIOptions) != len(apiOptions(tt.settings)) {
+				t.Errorf("got %d API options, want the registry's %d", len(opts.APIOptions), len(apiOptions(tt.settings)))
+			}
+		})
+	}
+}
+
+func TestAssumeRole(t *testing.T) {
+	t.Run("no role keeps the credentials", func(t *testing.T) {
+		cfg := aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}}
+		assumeRole(&cfg, "")
+		if _, ok := cfg.Credentials.(aws.AnonymousCredentials); !ok {
+			t.Errorf("Credentials = %T, want them unchanged", cfg.Credentials)
+		}
+	})
+
+	t.Run("role credentials come from STS", func(t *testing.T) {
+		cfg := aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}}
+		assumeRole(&cfg, "arn:aws:iam::123456789012:role/mcp-registry")
+		if !aws.IsCredentialsProvider(cfg.Credentials, &stscreds.AssumeRoleProvider{}) {
+			t.Errorf("Credentials = %T, want a cached assume role provider", cfg.Credentials)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// sqsSendAPI is the subset of the SQS client used by SQSPublisher
+type sqsSendAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// Change describes a change to a server version announced by SQSPublisher
+type Change struct {
+	Op         string    `json:"op"` // the kind of change, e.g. create or update
+	ServerName string    `json:"serverName"`
+	Version    string    `json:"version"`
+	Status     string    `json:"status,omitempty"` // the version's status after the change
+	Time       time.Time `json:"time"`
+}
+
+// ChangeMessage is the body of the messages SQSPublisher sends. When an export of the registry is configured and
+// reflects the change, Records announces it in the same format SQSListener consumes, so a downstream registry
+// listening on the queue reloads the export.
+type ChangeMessage struct {
+	Records []S3EventRecord `json:"Records,omitempty"`
+	Change  Change          `json:"change"`
+}
+
+// SQSPublisherConfig holds configuration for the SQS publisher
+type SQSPublisherConfig struct {
+	QueueURL  string // SQS queue URL to send change messages to
+	ExportURL string // Optional S3 URL of a registry data file that reflects the changes, announced with messages that ask for it
+}
+
+// SQSPublisher sends a message to an SQS queue for each change to a server version
+type SQSPublisher struct {
+	client       sqsSendAPI
+	queueURL     string
+	exportBucket string // empty unless an export is announced
+	exportKey    string
+}
+
+// NewSQSPublisher creates a new SQS publisher
+func NewSQSPublisher(ctx context.Context, cfg SQSPublisherConfig) (*SQSPublisher, error) {
+	awsCfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return newSQSPublisher(sqs.NewFromConfig(awsCfg), cfg)
+}
+
+// newSQSPublisher creates an SQS publisher that sends with client
+func newSQSPublisher(client sqsSendAPI, cfg SQSPublisherConfig) (*SQSPublisher, error) {
+	if cfg.QueueURL == "" {
+		return nil, fmt.Errorf("SQS publisher requires a queue URL")
+	}
+
+	p := &SQSPublisher{client: client, queueURL: cfg.QueueURL}
+	if cfg.ExportURL != "" {
+		bucket, key, err := ParseS3URL(cfg.ExportURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid export URL: %w", err)
+		}
+		p.exportBucket, p.exportKey = bucket, key
+	}
+	return p, nil
+}
+
+// Publish sends a message announcing change, and the export if one is configured and announceExport is set
+// because the export has been refreshed since the change. On FIFO queues, changes to the same server share a
+// message group, so they are delivered in the order they were published.
+func (p *SQSPublisher) Publish(ctx context.Context, change Change, announceExport bool) error {
+	msg := ChangeMessage{Change: change}
+	if announceExport && p.exportBucket != "" {
+		var record S3EventRecord
+		record.S3.Bucket.Name = p.exportBucket
+		record.S3.Object.Key = p.exportKey
+		msg.Records = []S3EventRecord{record}
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode change message: %w", err)
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(p.queueURL),
+		MessageBody: aws.String(string(body)),
+	}
+	if strings.HasSuffix(p.queueURL, ".fifo") {
+		// Group and deduplication IDs are limited to 128 characters, which long server names would exceed
+		input.MessageGroupId = aws.String(fifoID(change.ServerName))
+		input.MessageDeduplicationId = aws.String(fifoID(fmt.Sprintf("%s:%s@%s:%d", change.Op, change.ServerName, change.Version, change.Time.UnixNano())))
+	}
+
+	if _, err := p.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("failed to send change message: %w", err)
+	}
+	return nil
+}
+
+// fifoID derives a FIFO queue message group or deduplication ID from value
+func fifoID(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// fakeSQSSender records the messages sent to it
+type fakeSQSSender struct {
+	sent    []*sqs.SendMessageInput
+	sendErr error
+}
+
+func (f *fakeSQSSender) SendMessage(_ context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+	f.sent = append(f.sent, params)
+	return &sqs.SendMessageOutput{MessageId: aws.String("sent")}, nil
+}
+
+func TestSQSPublisher_Publish(t *testing.T) {
+	change := Change{
+		Op:         "create",
+		ServerName: "com.example/weather",
+		Version:    "1.0.0",
+		Status:     "active",
+		Time:       time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("sends the change", func(t *testing.T) {
+		client := &fakeSQSSender{}
+		publisher, err := newSQSPublisher(client, SQSPublisherConfig{QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/changes"})
+		if err != nil {
+			t.Fatalf("newSQSPublisher() error = %v", err)
+		}
+		if err := publisher.Publish(context.Background(), change, true); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+
+		if len(client.sent) != 1 {
+			t.Fatalf("sent %d messages, want 1", len(client.sent))
+		}
+		sent := client.sent[0]
+		if got := aws.ToString(sent.QueueUrl); got != "https://sqs.us-east-1.amazonaws.com/123456789012/changes" {
+			t.Errorf("QueueUrl = %q", got)
+		}
+		if sent.MessageGroupId != nil || sent.MessageDeduplicationId != nil {
+			t.Errorf("standard queue messages should not carry FIFO IDs")
+		}
+
+		var msg ChangeMessage
+		if err := json.Unmarshal([]byte(aws.ToString(sent.MessageBody)), &msg); err != nil {
+			t.Fatalf("message body is not a ChangeMessage: %v", err)
+		}
+		if msg.Change != change {
+			t.Errorf("Change = %+v, want %+v", msg.Change, change)
+		}
+		if len(msg.Records) != 0 {
+			t.Errorf("Records = %+v, want none without an export URL", msg.Records)
+		}
+	})
+
+	t.Run("announces the export in the listener's format", func(t *testing.T) {
+		client := &fakeSQSSender{}
+		publisher, err := newSQSPublisher(client, SQSPublisherConfig{
+			QueueURL:  "https://sqs.us-east-1.amazonaws.com/123456789012/changes",
+			ExportURL: "s3://registry-bucket/exports/registry.json",
+		})
+		if err != nil {
+			t.Fatalf("newSQSPublisher() error = %v", err)
+		}
+		if err := publisher.Publish(context.Background(), change, true); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+
+		var msg SQSMessage
+		if err := json.Unmarshal([]byte(aws.ToString(client.sent[0].MessageBody)), &msg); err != nil {
+			t.Fatalf("message body is not an SQSMessage: %v", err)
+		}
+		if len(msg.Records) != 1 || msg.Records[0].S3.Bucket.Name != "registry-bucket" || msg.Records[0].S3.Object.Key != "exports/registry.json" {
+			t.Errorf("Records = %+v, want s3://registry-bucket/exports/registry.json", msg.Records)
+		}
+
+		// An export that doesn't reflect the change isn't announced
+		if err := publisher.Publish(context.Background(), change, false); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		var unannounced SQSMessage
+		if err := json.Unmarshal([]byte(aws.ToString(client.sent[1].MessageBody)), &unannounced); err != nil {
+			t.Fatalf("message body is not an SQSMessage: %v", err)
+		}
+		if len(unannounced.Records) != 0 {
+			t.Errorf("Records = %+v, want none when the export isn't announced", unannounced.Records)
+		}
+	})
+
+	t.Run("FIFO queues group by server", func(t *testing.T) {
+		client := &fakeSQSSender{}
+		publisher, err := newSQSPublisher(client, SQSPublisherConfig{QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/changes.fifo"})
+		if err != nil {
+			t.Fatalf("newSQSPublisher() error = %v", err)
+		}
+		long := change
+		long.ServerName = "com.example/" + strings.Repeat("a", 190)
+		for _, c := range []Change{change, long} {
+			if err := publisher.Publish(context.Background(), c, true); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+		}
+
+		for _, sent := range client.sent {
+			group, dedup := aws.ToString(sent.MessageGroupId), aws.ToString(sent.MessageDeduplicationId)
+			if group == "" || len(group) > 128 || dedup == "" || len(dedup) > 128 {
+				t.Errorf("FIFO IDs must be 1 to 128 characters: group %q, deduplication %q", group, dedup)
+			}
+		}
+		if aws.ToString(client.sent[0].MessageGroupId) == aws.ToString(client.sent[1].MessageGroupId) {
+			t.Errorf("different servers should be in different message groups")
+		}
+	})
+
+	t.Run("send failures are returned", func(t *testing.T) {
+		errSend := errors.New("access denied")
+		publisher, err := newSQSPublisher(&fakeSQSSender{sendErr: errSend}, SQSPublisherConfig{QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/changes"})
+		if err != nil {
+			t.Fatalf("newSQSPublisher() error = %v", err)
+		}
+		if err := publisher.Publish(context.Background(), change, true); !errors.Is(err, errSend) {
+			t.Errorf("Publish() error = %v, want %v", err, errSend)
+		}
+	})
+
+	t.Run("invalid export URL is rejected", func(t *testing.T) {
+		_, err := newSQSPublisher(&fakeSQSSender{}, SQSPublisherConfig{QueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/changes", ExportURL: "ftp://registry"})
+		if err == nil {
+			t.Errorf("newSQSPublisher() should reject a non-S3 export URL")
+		}
+	})
+}
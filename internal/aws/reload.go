@@ -0,0 +1,196 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for retrying a failed reload of an already-downloaded file
+const (
+	defaultReloadAttempts = 3
+	defaultReloadBackoff  = time.Second
+)
+
+// ErrInvalidDownload is returned when a file downloaded from S3 fails validation
+var ErrInvalidDownload = errors.New("downloaded file failed validation")
+
+// ErrUploadFailed is returned when an object can't be written to S3
+var ErrUploadFailed = errors.New("failed to upload to S3")
+
+// ObjectVersion identifies the revision of an S3 object that was downloaded. Fields the store didn't report
+// are left zero.
+type ObjectVersion struct {
+	LastModified time.Time
+	ETag         string
+}
+
+// supersededBy reports whether an object at version v is already reflected by the one applied: applied was
+// modified later, or it is the same revision. Without modification times there is no order.
+func (v ObjectVersion) supersededBy(applied ObjectVersion) bool {
+	if v.LastModified.IsZero() || applied.LastModified.IsZero() {
+		return false
+	}
+	if applied.LastModified.After(v.LastModified) {
+		return true
+	}
+	return applied.LastModified.Equal(v.LastModified) && v.ETag != "" && applied.ETag == v.ETag
+}
+
+// FileDownloader downloads an S3 object to a local file, returning the revision it downloaded
+type FileDownloader interface {
+	DownloadFile(ctx context.Context, bucket, key, localPath string) (ObjectVersion, error)
+}
+
+// ObjectChecker checks that an S3 object is reachable without downloading it
+type ObjectChecker interface {
+	CheckObject(ctx context.Context, bucket, key string) error
+}
+
+// ObjectUploader writes an S3 object
+type ObjectUploader interface {
+	UploadObject(ctx context.Context, bucket, key string, body []byte, contentType string) error
+}
+
+// LatestObjectFinder finds the most recently modified S3 object under a key prefix
+type LatestObjectFinder interface {
+	LatestObjectKey(ctx context.Context, bucket, prefix string) (string, error)
+}
+
+// S3Client combines the S3 operations used by the registry service
+type S3Client interface {
+	FileDownloader
+	ObjectChecker
+	ObjectUploader
+}
+
+// S3Reloader downloads a registry data file from S3 and reloads the database from it.
+// The download goes to a separate file and is validated before it replaces the live file,
+// so a bad upload never takes effect. It is safe for concurrent use: downloads run in parallel,
+// while replacing the live file and reloading from it happen one at a time, and a download of an
+// object older than the one last reloaded is discarded so data never rolls back.
+type S3Reloader struct {
+	downloader     FileDownloader
+	targetFilePath string
+	validate       func(path string) error
+	reload         func(source string) error
+	reloadAttempts int
+	reloadBackoff  time.Duration
+	downloads      atomic.Int64  // numbers the download files, so concurrent downloads don't share one
+	reloadMu       sync.Mutex    // serializes replacing the live file and reloading from it
+	applied        ObjectVersion // object last reloaded successfully, guarded by reloadMu
+}
+
+// S3ReloaderOption configures an S3Reloader
+type S3ReloaderOption func(*S3Reloader)
+
+// WithReloadRetry sets how many times a failed reload is attempted against the already-downloaded
+// file before giving up, and the base delay of the jittered exponential backoff between attempts.
+// Non-positive values keep the defaults.
+func WithReloadRetry(attempts int, backoff time.Duration) S3ReloaderOption {
+	return func(r *S3Reloader) {
+		if attempts > 0 {
+			r.reloadAttempts = attempts
+		}
+		if backoff > 0 {
+			r.reloadBackoff = backoff
+		}
+	}
+}
+
+// NewS3Reloader creates a reloader that swaps downloads into targetFilePath.
+// validate and reload are optional; reload is passed the s3:// URI the data came from.
+func NewS3Reloader(downloader FileDownloader, targetFilePath string, validate func(path string) error, reload func(source string) error, opts ...S3ReloaderOption) *S3Reloader {
+	r := &S3Reloader{
+		downloader:     downloader,
+		targetFilePath: targetFilePath,
+		validate:       validate,
+		reload:         reload,
+		reloadAttempts: defaultReloadAttempts,
+		reloadBackoff:  defaultReloadBackoff,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Reload downloads s3://bucket/key, validates it, moves it over the target file and reloads the database
+func (r *S3Reloader) Reload(ctx context.Context, bucket, key string) error {
+	downloadPath := fmt.Sprintf("%s.download-%d", r.targetFilePath, r.downloads.Add(1))
+	version, err := r.downloader.DownloadFile(ctx, bucket, key, downloadPath)
+	if err != nil {
+		return fmt.Errorf("failed to download file from S3: %w", err)
+	}
+
+	if r.validate != nil {
+		if err := r.validate(downloadPath); err != nil {
+			os.Remove(downloadPath)
+			return fmt.Errorf("%w: s3://%s/%s: %v", ErrInvalidDownload, bucket, key, err)
+		}
+	}
+
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	// A download that finished after a newer one, or a redelivery of the object already loaded, must not
+	// replace it
+	if version.supersededBy(r.applied) {
+		os.Remove(downloadPath)
+		log.Printf("Skipping s3://%s/%s modified %s: a copy modified %s is already loaded",
+			bucket, key, version.LastModified.Format(time.RFC3339), r.applied.LastModified.Format(time.RFC3339))
+		return nil
+	}
+
+	// Atomically replace the live file with the validated download
+	if err := os.Rename(downloadPath, r.targetFilePath); err != nil {
+		os.Remove(downloadPath)
+		return fmt.Errorf("failed to replace %s: %w", r.targetFilePath, err)
+	}
+
+	if r.reload != nil {
+		if err := r.reloadWithRetry(ctx, fmt.Sprintf("s3://%s/%s", bucket, key)); err != nil {
+			return err
+		}
+	}
+
+	r.applied = version
+	return nil
+}
+
+// reloadWithRetry calls the reload callback until it succeeds or the attempts run out, backing off
+// between attempts. The downloaded file is already in place, so retries never re-download it.
+func (r *S3Reloader) reloadWithRetry(ctx context.Context, source string) error {
+	var err error
+	for attempt := 1; attempt <= r.reloadAttempts; attempt++ {
+		if err = r.reload(source); err == nil {
+			return nil
+		}
+		if attempt == r.reloadAttempts {
+			break
+		}
+
+		delay := backoffDelay(r.reloadBackoff, attempt)
+		log.Printf("Reload from %s failed (attempt %d/%d), retrying in %s: %v", source, attempt, r.reloadAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to reload database: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	return fmt.Errorf("failed to reload database after %d attempts: %w", r.reloadAttempts, err)
+}
+
+// backoffDelay doubles base for each failed attempt and adds up to 50% random jitter, so replicas
+// reloading the same file don't retry in lockstep
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	return delay + rand.N(delay/2+1)
+}
@@ -0,0 +1,158 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingDownloader writes fixed content for any object and counts downloads
+type countingDownloader struct {
+	content   []byte
+	downloads int
+}
+
+func (d *countingDownloader) DownloadFile(_ context.Context, _, _, localPath string) (ObjectVersion, error) {
+	d.downloads++
+	return ObjectVersion{}, os.WriteFile(localPath, d.content, 0600)
+}
+
+func TestS3Reloader_RetriesReload(t *testing.T) {
+	errTransient := errors.New("file is busy")
+
+	tests := []struct {
+		name        string
+		failures    int // reload calls that fail before one succeeds
+		attempts    int
+		wantErr     bool
+		wantReloads int
+	}{
+		{name: "succeeds first time", failures: 0, attempts: 3, wantReloads: 1},
+		{name: "transient failures then success", failures: 2, attempts: 3, wantReloads: 3},
+		{name: "gives up after max attempts", failures: 5, attempts: 3, wantErr: true, wantReloads: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := filepath.Join(t.TempDir(), "registry.json")
+			downloader := &countingDownloader{content: []byte(`{"servers":[]}`)}
+
+			var reloads int
+			var sources []string
+			reload := func(source string) error {
+				reloads++
+				sources = append(sources, source)
+				if reloads <= tt.failures {
+					return errTransient
+				}
+				return nil
+			}
+
+			reloader := NewS3Reloader(downloader, targetPath, nil, reload, WithReloadRetry(tt.attempts, time.Millisecond))
+			err := reloader.Reload(context.Background(), "registry-bucket", "registry.json")
+
+			if tt.wantErr {
+				if !errors.Is(err, errTransient) {
+					t.Errorf("Reload() error = %v, want %v", err, errTransient)
+				}
+			} else if err != nil {
+				t.Errorf("Reload() unexpected error = %v", err)
+			}
+			if reloads != tt.wantReloads {
+				t.Errorf("reload called %d times, want %d", reloads, tt.wantReloads)
+			}
+			if downloader.downloads != 1 {
+				t.Errorf("file downloaded %d times, want 1: retries must reuse the downloaded file", downloader.downloads)
+			}
+			for _, source := range sources {
+				if source != "s3://registry-bucket/registry.json" {
+					t.Errorf("reload source = %q, want s3://registry-bucket/registry.json", source)
+				}
+			}
+		})
+	}
+}
+
+func TestS3Reloader_StopsRetryingWhenCancelled(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+	downloader := &countingDownloader{content: []byte(`{"servers":[]}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := 0
+	reload := func(string) error {
+		reloads++
+		cancel()
+		return errors.New("reload failed")
+	}
+
+	reloader := NewS3Reloader(downloader, targetPath, nil, reload, WithReloadRetry(5, time.Hour))
+	err := reloader.Reload(ctx, "registry-bucket", "registry.json")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Reload() error = %v, want context.Canceled", err)
+	}
+	if reloads != 1 {
+		t.Errorf("reload called %d times, want 1", reloads)
+	}
+}
+
+// versionedDownloader serves each key's content at a fixed modification time, holding back downloads of keys
+// in hold until their channel is closed
+type versionedDownloader struct {
+	modified map[string]time.Time
+	hold     map[string]chan struct{}
+}
+
+func (d *versionedDownloader) DownloadFile(_ context.Context, _, key, localPath string) (ObjectVersion, error) {
+	if wait, ok := d.hold[key]; ok {
+		<-wait
+	}
+	modified := d.modified[key]
+	return ObjectVersion{LastModified: modified, ETag: `"` + key + `"`}, os.WriteFile(localPath, []byte(key), 0600)
+}
+
+func TestS3Reloader_SkipsOlderDownloads(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+	now := time.Now()
+	release := make(chan struct{})
+	downloader := &versionedDownloader{
+		modified: map[string]time.Time{"old.json": now.Add(-time.Minute), "new.json": now},
+		hold:     map[string]chan struct{}{"old.json": release},
+	}
+
+	var sources []string
+	reload := func(source string) error {
+		sources = append(sources, source)
+		return nil
+	}
+	reloader := NewS3Reloader(downloader, targetPath, nil, reload)
+	ctx := context.Background()
+
+	// The older object's download finishes only after the newer one is loaded
+	oldDone := make(chan error, 1)
+	go func() { oldDone <- reloader.Reload(ctx, "registry-bucket", "old.json") }()
+	if err := reloader.Reload(ctx, "registry-bucket", "new.json"); err != nil {
+		t.Fatalf("Reload(new.json) error = %v", err)
+	}
+	close(release)
+	if err := <-oldDone; err != nil {
+		t.Fatalf("Reload(old.json) error = %v", err)
+	}
+
+	// A redelivered notification for the loaded object is skipped too
+	if err := reloader.Reload(ctx, "registry-bucket", "new.json"); err != nil {
+		t.Fatalf("Reload(new.json) again error = %v", err)
+	}
+
+	if got, _ := os.ReadFile(targetPath); string(got) != "new.json" {
+		t.Errorf("live file holds %q, want the newer object", got)
+	}
+	if len(sources) != 1 || sources[0] != "s3://registry-bucket/new.json" {
+		t.Errorf("reloaded from %v, want only s3://registry-bucket/new.json", sources)
+	}
+	if leftovers, _ := filepath.Glob(targetPath + ".download-*"); len(leftovers) != 0 {
+		t.Errorf("skipped downloads left behind: %v", leftovers)
+	}
+}
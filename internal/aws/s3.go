@@ -1,26 +1,37 @@
 package aws
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+// s3API is the subset of the S3 client used by S3Downloader, so tests can substitute it
+type s3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
 // S3Downloader handles downloading files from S3
 type S3Downloader struct {
-	client *s3.Client
+	client s3API
 }
 
-// NewS3Downloader creates a new S3 downloader with default AWS config
+// NewS3Downloader creates a new S3 downloader with the default AWS config and the settings passed to Configure
 func NewS3Downloader(ctx context.Context) (*S3Downloader, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -30,11 +41,11 @@ func NewS3Downloader(ctx context.Context) (*S3Downloader, error) {
 	}, nil
 }
 
-// DownloadFile downloads a file from S3 to a local path
+// DownloadFile downloads a file from S3 to a local path, returning the revision of the object it downloaded
 // bucket: S3 bucket name
 // key: S3 object key (path within bucket)
 // localPath: local file path to write to
-func (d *S3Downloader) DownloadFile(ctx context.Context, bucket, key, localPath string) error {
+func (d *S3Downloader) DownloadFile(ctx context.Context, bucket, key, localPath string) (ObjectVersion, error) {
 	log.Printf("Downloading s3://%s/%s to %s", bucket, key, localPath)
 
 	// Get the object from S3
@@ -43,21 +54,21 @@ func (d *S3Downloader) DownloadFile(ctx context.Context, bucket, key, localPath
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get object from S3: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to get object from S3: %w", err)
 	}
 	defer result.Body.Close()
 
 	// Ensure the directory exists
 	dir := filepath.Dir(localPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Create temporary file
 	tempFile := localPath + ".tmp"
 	outFile, err := os.Create(tempFile)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary file: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to create temporary file: %w", err)
 	}
 	defer outFile.Close()
 
@@ -65,25 +76,93 @@ func (d *S3Downloader) DownloadFile(ctx context.Context, bucket, key, localPath
 	written, err := io.Copy(outFile, result.Body)
 	if err != nil {
 		os.Remove(tempFile) // Clean up temp file on error
-		return fmt.Errorf("failed to write file: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to write file: %w", err)
 	}
 
 	// Close the file before renaming
 	if err := outFile.Close(); err != nil {
 		os.Remove(tempFile)
-		return fmt.Errorf("failed to close temporary file: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
 	// Atomically replace the target file
 	if err := os.Rename(tempFile, localPath); err != nil {
 		os.Remove(tempFile)
-		return fmt.Errorf("failed to rename temporary file: %w", err)
+		return ObjectVersion{}, fmt.Errorf("failed to rename temporary file: %w", err)
 	}
 
 	log.Printf("Successfully downloaded %d bytes from S3", written)
+	return ObjectVersion{LastModified: aws.ToTime(result.LastModified), ETag: aws.ToString(result.ETag)}, nil
+}
+
+// CheckObject confirms the object exists and is readable with the current credentials, using a
+// HeadObject request so nothing is downloaded
+func (d *S3Downloader) CheckObject(ctx context.Context, bucket, key string) error {
+	if _, err := d.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("failed to head object s3://%s/%s: %w", bucket, key, err)
+	}
 	return nil
 }
 
+// UploadObject writes body to s3://bucket/key, replacing any existing object
+func (d *S3Downloader) UploadObject(ctx context.Context, bucket, key string, body []byte, contentType string) error {
+	if _, err := d.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	}); err != nil {
+		return fmt.Errorf("%w: s3://%s/%s: %w", ErrUploadFailed, bucket, key, err)
+	}
+
+	log.Printf("Uploaded %d bytes to s3://%s/%s", len(body), bucket, key)
+	return nil
+}
+
+// LatestObjectKey returns the key of the most recently modified object under prefix, for uploads
+// published under timestamped keys. Keys ending in '/' are folder markers and are skipped.
+func (d *S3Downloader) LatestObjectKey(ctx context.Context, bucket, prefix string) (string, error) {
+	var latestKey string
+	var latestModified time.Time
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		page, err := d.client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return "", fmt.Errorf("failed to list objects under s3://%s/%s: %w", bucket, prefix, err)
+		}
+
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			modified := aws.ToTime(object.LastModified)
+			// Break ties by key so the choice doesn't depend on listing order
+			if latestKey == "" || modified.After(latestModified) || (modified.Equal(latestModified) && key > latestKey) {
+				latestKey = key
+				latestModified = modified
+			}
+		}
+
+		if !aws.ToBool(page.IsTruncated) {
+			break
+		}
+		input.ContinuationToken = page.NextContinuationToken
+	}
+
+	if latestKey == "" {
+		return "", fmt.Errorf("no objects found under s3://%s/%s", bucket, prefix)
+	}
+	return latestKey, nil
+}
+
 // ParseS3URL parses an S3 Object URL or S3 URI into bucket and key components
 // Supports multiple URL formats:
 // - S3 URI: s3://bucket/key (for backward compatibility)
@@ -91,6 +170,13 @@ func (d *S3Downloader) DownloadFile(ctx context.Context, bucket, key, localPath
 // - Virtual-hosted-style (us-east-1): https://bucket.s3.amazonaws.com/key
 // - Path-style: https://s3.region.amazonaws.com/bucket/key
 // - Path-style (us-east-1): https://s3.amazonaws.com/bucket/key
+// - Access point: https://name-account.s3-accesspoint.region.amazonaws.com/key
+// - VPC interface endpoint: https://bucket-name.bucket.vpce-id.s3.region.vpce.amazonaws.com/key,
+// https://bucket.vpce-id.s3.region.vpce.amazonaws.com/bucket-name/key or, for access points,
+// https://name-account.accesspoint.vpce-id.s3.region.vpce.amazonaws.com/key
+//
+// Access points are returned as their ARN, which the S3 client accepts in place of a bucket name, unless the
+// host names an access point alias (ending in -s3alias), which is returned as is.
 func ParseS3URL(url string) (bucket, key string, err error) {
 	// Support legacy S3 URI format (s3://bucket/key) for backward compatibility
 	if len(url) >= 5 && url[:5] == "s3://" {
@@ -137,6 +223,17 @@ func parseHTTPSS3URL(remaining string) (bucket, key string, err error) {
 		return "", "", fmt.Errorf("invalid S3 URL: missing object key")
 	}
 
+	// VPC endpoint hosts also contain ".s3.", so they must not fall through to the generic styles
+	if strings.HasSuffix(host, ".vpce.amazonaws.com") {
+		if bucket, key, ok := parseVPCEndpoint(host, path); ok {
+			return bucket, key, nil
+		}
+		return "", "", fmt.Errorf("invalid S3 URL: unrecognized S3 VPC endpoint URL format")
+	}
+	if bucket, key, ok := parseAccessPoint(host, path); ok {
+		return bucket, key, nil
+	}
+
 	// Try virtual-hosted-style first
 	if bucket, key, ok := parseVirtualHostedStyle(host, path); ok {
 		return bucket, key, nil
@@ -167,6 +264,79 @@ func parseVirtualHostedStyle(host, path string) (bucket, key string, ok bool) {
 	return "", "", false
 }
 
+// parseAccessPoint attempts to parse access point URLs: name-account.s3-accesspoint[.dualstack].region.amazonaws.com
+func parseAccessPoint(host, path string) (bucket, key string, ok bool) {
+	label, rest, found := strings.Cut(host, ".s3-accesspoint.")
+	if !found || label == "" {
+		return "", "", false
+	}
+	region, found := strings.CutSuffix(rest, ".amazonaws.com")
+	if !found {
+		return "", "", false
+	}
+	region = strings.TrimPrefix(region, "dualstack.")
+	if region == "" || strings.Contains(region, ".") {
+		return "", "", false
+	}
+	return accessPointBucket(label, region), path, true
+}
+
+// parseVPCEndpoint attempts to parse URLs of S3 interface endpoints: bucket-name.bucket.vpce-id.s3.region.vpce.amazonaws.com
+// (virtual-hosted-style), bucket.vpce-id.s3.region.vpce.amazonaws.com/bucket-name (path-style) and
+// name-account.accesspoint.vpce-id.s3.region.vpce.amazonaws.com (access points)
+func parseVPCEndpoint(host, path string) (bucket, key string, ok bool) {
+	prefix, found := strings.CutSuffix(host, ".vpce.amazonaws.com")
+	if !found {
+		return "", "", false
+	}
+
+	labels := strings.Split(prefix, ".")
+	endpoint := slices.IndexFunc(labels, func(label string) bool { return strings.HasPrefix(label, "vpce-") })
+	// The endpoint ID is followed by "s3" and the region, and preceded by the endpoint type
+	if endpoint < 1 || len(labels) != endpoint+3 || labels[endpoint+1] != "s3" {
+		return "", "", false
+	}
+	region := labels[endpoint+2]
+	kind, names := labels[endpoint-1], labels[:endpoint-1]
+
+	switch {
+	case kind == "bucket" && len(names) == 0:
+		return extractBucketAndKey(path)
+	case kind == "bucket":
+		return strings.Join(names, "."), path, true
+	case kind == "accesspoint" && len(names) == 1:
+		return accessPointBucket(names[0], region), path, true
+	default:
+		return "", "", false
+	}
+}
+
+// accessPointBucket returns the value to address an access point by in place of a bucket name, given the first
+// label of its host: an alias as is, or the ARN of a name-account label
+func accessPointBucket(label, region string) string {
+	if strings.HasSuffix(label, "-s3alias") {
+		return label
+	}
+	i := strings.LastIndex(label, "-")
+	if i <= 0 || !isAccountID(label[i+1:]) {
+		return label
+	}
+	return "arn:aws:s3:" + region + ":" + label[i+1:] + ":accesspoint/" + label[:i]
+}
+
+// isAccountID reports whether s is a 12-digit AWS account ID
+func isAccountID(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // parsePathStyle attempts to parse path-style URLs
 func parsePathStyle(host, path string) (bucket, key string, ok bool) {
 	// Check for us-east-1 format: s3.amazonaws.com/bucket/key
@@ -1,7 +1,16 @@
 package aws
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func TestParseS3URL(t *testing.T) {
@@ -77,6 +86,53 @@ func TestParseS3URL(t *testing.T) {
 			wantKey:    "deep/nested/file.json",
 			wantErr:    false,
 		},
+		// Access points and VPC interface endpoints
+		{
+			name:       "access point virtual-hosted-style",
+			url:        "https://registry-ap-123456789012.s3-accesspoint.us-east-1.amazonaws.com/exports/registry.json",
+			wantBucket: "arn:aws:s3:us-east-1:123456789012:accesspoint/registry-ap",
+			wantKey:    "exports/registry.json",
+		},
+		{
+			name:       "access point dual-stack",
+			url:        "https://registry-123456789012.s3-accesspoint.dualstack.eu-west-1.amazonaws.com/registry.json",
+			wantBucket: "arn:aws:s3:eu-west-1:123456789012:accesspoint/registry",
+			wantKey:    "registry.json",
+		},
+		{
+			name:       "access point alias",
+			url:        "https://registry-ap-hrzrlukc5m36ft7okagglf3gmwluquse1b-s3alias.s3-accesspoint.us-east-1.amazonaws.com/registry.json",
+			wantBucket: "registry-ap-hrzrlukc5m36ft7okagglf3gmwluquse1b-s3alias",
+			wantKey:    "registry.json",
+		},
+		{
+			name:       "VPC endpoint virtual-hosted-style",
+			url:        "https://my.bucket.bucket.vpce-1a2b3c4d-5e6f.s3.us-east-1.vpce.amazonaws.com/path/to/file.json",
+			wantBucket: "my.bucket",
+			wantKey:    "path/to/file.json",
+		},
+		{
+			name:       "VPC endpoint path-style",
+			url:        "https://bucket.vpce-1a2b3c4d-5e6f.s3.us-east-1.vpce.amazonaws.com/my-bucket/file.json",
+			wantBucket: "my-bucket",
+			wantKey:    "file.json",
+		},
+		{
+			name:       "VPC endpoint access point",
+			url:        "https://registry-ap-123456789012.accesspoint.vpce-1a2b3c4d-5e6f.s3.us-west-2.vpce.amazonaws.com/registry.json",
+			wantBucket: "arn:aws:s3:us-west-2:123456789012:accesspoint/registry-ap",
+			wantKey:    "registry.json",
+		},
+		{
+			name:    "VPC endpoint path-style missing key",
+			url:     "https://bucket.vpce-1a2b3c4d-5e6f.s3.us-east-1.vpce.amazonaws.com/my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "VPC endpoint of another service",
+			url:     "https://vpce-1a2b3c4d-5e6f.sqs.us-east-1.vpce.amazonaws.com/file.json",
+			wantErr: true,
+		},
 		{
 			name:    "missing https:// prefix",
 			url:     "http://bucket.s3.amazonaws.com/key",
@@ -150,3 +206,102 @@ func TestParseS3URL(t *testing.T) {
 		})
 	}
 }
+
+// fakeS3API answers HeadObject with a fixed error and ListObjectsV2 with fixed pages
+type fakeS3API struct {
+	headErr error
+	pages   [][]types.Object
+}
+
+func (f *fakeS3API) GetObject(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeS3API) HeadObject(_ context.Context, _ *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func (f *fakeS3API) PutObject(_ context.Context, _ *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeS3API) ListObjectsV2(_ context.Context, params *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	page := 0
+	if params.ContinuationToken != nil {
+		page, _ = strconv.Atoi(*params.ContinuationToken)
+	}
+	output := &s3.ListObjectsV2Output{Contents: f.pages[page]}
+	if page+1 < len(f.pages) {
+		output.IsTruncated = aws.Bool(true)
+		output.NextContinuationToken = aws.String(strconv.Itoa(page + 1))
+	}
+	return output, nil
+}
+
+func TestLatestObjectKey(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	object := func(key string, age time.Duration) types.Object {
+		return types.Object{Key: aws.String(key), LastModified: aws.Time(base.Add(-age))}
+	}
+
+	downloader := &S3Downloader{client: &fakeS3API{pages: [][]types.Object{
+		{
+			object("registry/", 0),
+			object("registry/2024-05-31T12:00:00Z.json", 24*time.Hour),
+			object("registry/2024-06-01T11:00:00Z.json", time.Hour),
+		},
+		{
+			object("registry/2024-06-01T12:00:00Z.json", 0),
+			object("registry/2024-05-01T12:00:00Z.json", 31*24*time.Hour),
+		},
+	}}}
+
+	key, err := downloader.LatestObjectKey(context.Background(), "registry-bucket", "registry/")
+	if err != nil {
+		t.Fatalf("LatestObjectKey() error = %v", err)
+	}
+	if key != "registry/2024-06-01T12:00:00Z.json" {
+		t.Errorf("LatestObjectKey() = %q, want the newest object", key)
+	}
+
+	t.Run("empty prefix", func(t *testing.T) {
+		downloader := &S3Downloader{client: &fakeS3API{pages: [][]types.Object{{object("registry/", 0)}}}}
+		if _, err := downloader.LatestObjectKey(context.Background(), "registry-bucket", "registry/"); err == nil {
+			t.Error("LatestObjectKey() expected an error when no objects match")
+		}
+	})
+}
+
+func TestCheckObject(t *testing.T) {
+	accessDenied := errors.New("api error AccessDenied: Access Denied")
+
+	tests := []struct {
+		name    string
+		headErr error
+	}{
+		{name: "reachable object", headErr: nil},
+		{name: "access denied", headErr: accessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloader := &S3Downloader{client: &fakeS3API{headErr: tt.headErr}}
+			err := downloader.CheckObject(context.Background(), "registry-bucket", "registry.json")
+			if tt.headErr == nil {
+				if err != nil {
+					t.Errorf("CheckObject() unexpected error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.headErr) {
+				t.Errorf("CheckObject() error = %v, want %v", err, tt.headErr)
+			}
+			if !strings.Contains(err.Error(), "s3://registry-bucket/registry.json") || !strings.Contains(err.Error(), "AccessDenied") {
+				t.Errorf("CheckObject() error = %q, want the object and the failure reason", err)
+			}
+		})
+	}
+}
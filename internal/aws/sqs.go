@@ -5,53 +5,83 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// SQS message events counted by the SQSMessages metric
+const (
+	sqsEventReceived  = "received"
+	sqsEventProcessed = "processed"
+	sqsEventFailed    = "failed"
+	sqsEventDeleted   = "deleted"
 )
 
+// sqsAPI is the subset of the SQS client used by SQSListener
+type sqsAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+}
+
 // SQSListener handles receiving and processing messages from SQS
 type SQSListener struct {
-	client          *sqs.Client
+	client          sqsAPI
 	queueURL        string
-	s3Downloader    *S3Downloader
+	reloader        *S3Reloader
+	latest          LatestObjectFinder // nil unless message keys are resolved as prefixes
 	targetFilePath  string
-	reloadCallback  func() error
 	stopChan        chan struct{}
+	done            chan struct{}      // closed when every polling loop has exited
+	cancel          context.CancelFunc // cancels message processing that outlasts Stop's context
 	maxMessages     int32
 	waitTimeSeconds int32
+	concurrency     int                // polling loops run by Start
+	metrics         telemetry.Recorder // nil disables instrumentation
 }
 
 // SQSMessage represents the expected structure of messages from SQS
 type SQSMessage struct {
-	Records []struct {
-		S3 struct {
-			Bucket struct {
-				Name string `json:"name"`
-			} `json:"bucket"`
-			Object struct {
-				Key string `json:"key"`
-				URL string `json:"url,omitempty"` // Optional if custom
-			} `json:"object"`
-		} `json:"s3"`
-	} `json:"Records"`
+	Records []S3EventRecord `json:"Records"`
+}
+
+// S3EventRecord announces an object uploaded to S3, in the format of S3 event notifications
+type S3EventRecord struct {
+	S3 struct {
+		Bucket struct {
+			Name string `json:"name"`
+		} `json:"bucket"`
+		Object struct {
+			Key string `json:"key"`
+			URL string `json:"url,omitempty"` // Optional if custom
+		} `json:"object"`
+	} `json:"s3"`
 }
 
 // SQSListenerConfig holds configuration for the SQS listener
 type SQSListenerConfig struct {
-	QueueURL        string       // SQS queue URL
-	TargetFilePath  string       // Local file path to write downloaded S3 file
-	ReloadCallback  func() error // Function to call after file is updated
-	MaxMessages     int32        // Maximum number of messages to retrieve per request (1-10)
-	WaitTimeSeconds int32        // Long polling wait time in seconds (0-20)
+	QueueURL        string                    // SQS queue URL
+	TargetFilePath  string                    // Local file path to write downloaded S3 file
+	ReloadCallback  func(source string) error // Function to call after file is updated, with the S3 URI it came from
+	ValidateFile    func(path string) error   // Optional check of a downloaded file before it replaces TargetFilePath
+	MaxMessages     int32                     // Maximum number of messages to retrieve per request (1-10)
+	WaitTimeSeconds int32                     // Long polling wait time in seconds (0-20)
+	ReloadAttempts  int                       // Attempts at reloading a downloaded file before leaving the message for redelivery (default 3)
+	ReloadBackoff   time.Duration             // Base delay between reload attempts, doubled per attempt with jitter (default 1s)
+	ResolvePrefix   bool                      // Treat message keys as prefixes and reload the most recently modified object under them
+	Concurrency     int                       // Workers that poll and process messages in parallel; downloads overlap but reloads take turns (default 1)
+	Metrics         telemetry.Recorder        // Optional metrics for received, processed, failed and deleted messages
 }
 
 // NewSQSListener creates a new SQS listener
 func NewSQSListener(ctx context.Context, cfg SQSListenerConfig) (*SQSListener, error) {
-	awsCfg, err := config.LoadDefaultConfig(ctx)
+	awsCfg, err := loadConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -78,29 +108,78 @@ func NewSQSListener(ctx context.Context, cfg SQSListenerConfig) (*SQSListener, e
 		waitTimeSeconds = 20
 	}
 
+	concurrency := max(cfg.Concurrency, 1)
+
+	reloader := NewS3Reloader(s3Downloader, cfg.TargetFilePath, cfg.ValidateFile, cfg.ReloadCallback,
+		WithReloadRetry(cfg.ReloadAttempts, cfg.ReloadBackoff))
+
+	var latest LatestObjectFinder
+	if cfg.ResolvePrefix {
+		latest = s3Downloader
+	}
+
 	return &SQSListener{
 		client:          sqs.NewFromConfig(awsCfg),
 		queueURL:        cfg.QueueURL,
-		s3Downloader:    s3Downloader,
+		reloader:        reloader,
+		latest:          latest,
 		targetFilePath:  cfg.TargetFilePath,
-		reloadCallback:  cfg.ReloadCallback,
 		stopChan:        make(chan struct{}),
 		maxMessages:     maxMessages,
 		waitTimeSeconds: waitTimeSeconds,
+		concurrency:     concurrency,
+		metrics:         cfg.Metrics,
 	}, nil
 }
 
-// Start begins listening for messages from SQS in a goroutine
+// Start begins listening for messages from SQS, in one goroutine per configured worker
 func (l *SQSListener) Start(ctx context.Context) {
-	log.Printf("Starting SQS listener for queue: %s", l.queueURL)
+	workers := max(l.concurrency, 1)
+	log.Printf("Starting SQS listener for queue %s with %d workers", l.queueURL, workers)
 
-	go l.pollMessages(ctx)
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.pollMessages(ctx)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(l.done)
+	}()
 }
 
-// Stop stops the SQS listener
-func (l *SQSListener) Stop() {
+// Stop stops polling for messages and waits for the messages already received to finish processing.
+// If ctx is done first, their processing is cancelled and ctx's error returned.
+func (l *SQSListener) Stop(ctx context.Context) error {
 	log.Println("Stopping SQS listener...")
 	close(l.stopChan)
+	if l.done == nil {
+		return nil
+	}
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		<-l.done
+		return fmt.Errorf("SQS listener stopped before finishing in-flight messages: %w", ctx.Err())
+	}
+}
+
+// stopping reports whether Stop has been called
+func (l *SQSListener) stopping() bool {
+	select {
+	case <-l.stopChan:
+		return true
+	default:
+		return false
+	}
 }
 
 // pollMessages continuously polls for messages from SQS
@@ -117,8 +196,12 @@ func (l *SQSListener) pollMessages(ctx context.Context) {
 			// Poll for messages
 			if err := l.receiveAndProcessMessages(ctx); err != nil {
 				log.Printf("Error processing SQS messages: %v", err)
-				// Wait before retrying
-				time.Sleep(5 * time.Second)
+				// Wait before retrying, unless the listener is stopped meanwhile
+				select {
+				case <-time.After(5 * time.Second):
+				case <-l.stopChan:
+				case <-ctx.Done():
+				}
 			}
 		}
 	}
@@ -126,71 +209,161 @@ func (l *SQSListener) pollMessages(ctx context.Context) {
 
 // receiveAndProcessMessages receives and processes messages from SQS
 func (l *SQSListener) receiveAndProcessMessages(ctx context.Context) error {
-	result, err := l.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+	// Stopping ends the long poll right away; messages already received are still processed
+	receiveCtx, stopReceiving := context.WithCancel(ctx)
+	defer stopReceiving()
+	go func() {
+		select {
+		case <-l.stopChan:
+			stopReceiving()
+		case <-receiveCtx.Done():
+		}
+	}()
+
+	result, err := l.client.ReceiveMessage(receiveCtx, &sqs.ReceiveMessageInput{
 		QueueUrl:            aws.String(l.queueURL),
 		MaxNumberOfMessages: l.maxMessages,
 		WaitTimeSeconds:     l.waitTimeSeconds,
 		MessageAttributeNames: []string{
 			string(types.QueueAttributeNameAll),
 		},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameSentTimestamp,
+		},
 	})
 	if err != nil {
+		if l.stopping() {
+			return nil
+		}
 		return fmt.Errorf("failed to receive messages: %w", err)
 	}
 
-	// Process each message
+	// Messages announcing the same object are coalesced, so a burst of upload notifications reloads it once
+	var batches []*sqsBatch
+	byTarget := map[string]*sqsBatch{}
 	for _, msg := range result.Messages {
-		if err := l.processMessage(ctx, msg); err != nil {
+		l.recordEvent(ctx, sqsEventReceived)
+		l.recordAge(ctx, msg)
+
+		bucket, key, err := parseMessage(msg)
+		if err != nil {
+			l.recordEvent(ctx, sqsEventFailed)
 			log.Printf("Error processing message: %v", err)
-			// Continue processing other messages even if one fails
 			continue
 		}
 
-		// Delete the message after successful processing
-		if err := l.deleteMessage(ctx, msg.ReceiptHandle); err != nil {
-			log.Printf("Error deleting message: %v", err)
+		target := "s3://" + bucket + "/" + key
+		batch, ok := byTarget[target]
+		if !ok {
+			batch = &sqsBatch{bucket: bucket, key: key}
+			byTarget[target] = batch
+			batches = append(batches, batch)
+		} else {
+			log.Printf("Coalescing SQS message %s with earlier message for %s", aws.ToString(msg.MessageId), target)
+		}
+		batch.messages = append(batch.messages, msg)
+	}
+
+	for _, batch := range batches {
+		start := time.Now()
+		err := l.processTarget(ctx, batch.bucket, batch.key)
+		if l.metrics != nil {
+			l.metrics.RecordSQSProcessingDuration(ctx, time.Since(start))
+		}
+		if err != nil {
+			log.Printf("Error processing message: %v", err)
+		}
+
+		for _, msg := range batch.messages {
+			if err != nil {
+				// Continue processing other messages even if one fails
+				l.recordEvent(ctx, sqsEventFailed)
+				continue
+			}
+			l.recordEvent(ctx, sqsEventProcessed)
+
+			// Delete the message after successful processing
+			if err := l.deleteMessage(ctx, msg.ReceiptHandle); err != nil {
+				log.Printf("Error deleting message: %v", err)
+				continue
+			}
+			l.recordEvent(ctx, sqsEventDeleted)
 		}
 	}
 
 	return nil
 }
 
-// processMessage processes a single SQS message
-func (l *SQSListener) processMessage(ctx context.Context, msg types.Message) error {
+// sqsBatch is the messages of one receive that announce the same S3 object
+type sqsBatch struct {
+	bucket, key string
+	messages    []types.Message
+}
+
+// parseMessage returns the S3 object an SQS message announces
+func parseMessage(msg types.Message) (bucket, key string, err error) {
 	log.Printf("Received SQS message: %s", aws.ToString(msg.MessageId))
 
 	// Parse the message body
 	var sqsMsg SQSMessage
 	if err := json.Unmarshal([]byte(aws.ToString(msg.Body)), &sqsMsg); err != nil {
-		return fmt.Errorf("failed to parse message body: %w", err)
+		return "", "", fmt.Errorf("failed to parse message body: %w", err)
 	}
 
 	// Extract the S3 URL
-	var bucket, key string
 	for _, record := range sqsMsg.Records {
 		bucket = record.S3.Bucket.Name
 		key = record.S3.Object.Key
 	}
+	return bucket, key, nil
+}
 
-	// Download the file from S3
-	if err := l.s3Downloader.DownloadFile(ctx, bucket, key, l.targetFilePath); err != nil {
-		return fmt.Errorf("failed to download file from S3: %w", err)
+// processTarget reloads the database from the S3 object announced by one or more messages
+func (l *SQSListener) processTarget(ctx context.Context, bucket, key string) error {
+	// Some publishers upload under timestamped keys and only announce the prefix
+	if l.latest != nil {
+		latestKey, err := l.latest.LatestObjectKey(ctx, bucket, key)
+		if err != nil {
+			return err
+		}
+		log.Printf("Resolved s3://%s/%s to latest object %s", bucket, key, latestKey)
+		key = latestKey
 	}
 
-	log.Printf("Successfully downloaded file from %s/%s to %s", bucket, key, l.targetFilePath)
-
-	// Call the reload callback to reload the database
-	if l.reloadCallback != nil {
-		log.Println("Reloading database from updated file...")
-		if err := l.reloadCallback(); err != nil {
-			return fmt.Errorf("failed to reload database: %w", err)
-		}
-		log.Println("Database reloaded successfully")
+	// Download and validate the file from S3, then reload the database from it
+	if err := l.reloader.Reload(ctx, bucket, key); err != nil {
+		return err
 	}
 
+	log.Printf("Successfully reloaded database from %s/%s via %s", bucket, key, l.targetFilePath)
+
 	return nil
 }
 
+// recordEvent counts an SQS message event when metrics are configured
+func (l *SQSListener) recordEvent(ctx context.Context, event string) {
+	if l.metrics == nil {
+		return
+	}
+	l.metrics.RecordSQSMessage(ctx, event)
+}
+
+// recordAge records how long a message waited in the queue, from its SentTimestamp attribute
+func (l *SQSListener) recordAge(ctx context.Context, msg types.Message) {
+	if l.metrics == nil {
+		return
+	}
+	sent, ok := msg.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)]
+	if !ok {
+		return
+	}
+	sentMillis, err := strconv.ParseInt(sent, 10, 64)
+	if err != nil {
+		return
+	}
+	l.metrics.RecordSQSMessageAge(ctx, time.Since(time.UnixMilli(sentMillis)))
+}
+
 // deleteMessage deletes a message from the queue
 func (l *SQSListener) deleteMessage(ctx context.Context, receiptHandle *string) error {
 	_, err := l.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
@@ -0,0 +1,441 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// fakeSQS hands out a fixed batch of messages once and records deletions
+type fakeSQS struct {
+	messages  []types.Message
+	deleted   []string
+	deleteErr error
+}
+
+func (f *fakeSQS) ReceiveMessage(_ context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	messages := f.messages
+	f.messages = nil
+	return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+}
+
+func (f *fakeSQS) DeleteMessage(_ context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	if f.deleteErr != nil {
+		return nil, f.deleteErr
+	}
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+// s3Notification builds an SQS message announcing an upload to bucket/key, sent age ago
+func s3Notification(id, key string, age time.Duration) types.Message {
+	return types.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String("receipt-" + id),
+		Body:          aws.String(`{"Records":[{"s3":{"bucket":{"name":"registry-bucket"},"object":{"key":"` + key + `"}}}]}`),
+		Attributes: map[string]string{
+			string(types.MessageSystemAttributeNameSentTimestamp): strconv.FormatInt(time.Now().Add(-age).UnixMilli(), 10),
+		},
+	}
+}
+
+func TestSQSListener_Metrics(t *testing.T) {
+	errReload := errors.New("reload failed")
+
+	tests := []struct {
+		name        string
+		reloadErr   error
+		deleteErr   error
+		wantEvents  map[string]int64
+		wantDeleted int
+	}{
+		{
+			name:        "processed and deleted",
+			wantEvents:  map[string]int64{"received": 2, "processed": 2, "deleted": 2},
+			wantDeleted: 2,
+		},
+		{
+			name:       "processing fails",
+			reloadErr:  errReload,
+			wantEvents: map[string]int64{"received": 2, "failed": 2},
+		},
+		{
+			name:       "delete fails",
+			deleteErr:  errors.New("access denied"),
+			wantEvents: map[string]int64{"received": 2, "processed": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			reader := sdkmetric.NewManualReader()
+			metrics, err := telemetry.NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+			if err != nil {
+				t.Fatalf("NewMetrics() error = %v", err)
+			}
+
+			client := &fakeSQS{
+				messages: []types.Message{
+					s3Notification("1", "registry.json", 30*time.Second),
+					s3Notification("2", "registry-2.json", 90*time.Second),
+				},
+				deleteErr: tt.deleteErr,
+			}
+			targetPath := filepath.Join(t.TempDir(), "registry.json")
+			reload := func(string) error { return tt.reloadErr }
+			listener := &SQSListener{
+				client:         client,
+				queueURL:       "https://sqs.us-east-1.amazonaws.com/123456789012/registry",
+				reloader:       NewS3Reloader(&countingDownloader{content: []byte(`{"servers":[]}`)}, targetPath, nil, reload, WithReloadRetry(1, time.Millisecond)),
+				targetFilePath: targetPath,
+				metrics:        metrics,
+			}
+
+			if err := listener.receiveAndProcessMessages(ctx); err != nil {
+				t.Fatalf("receiveAndProcessMessages() error = %v", err)
+			}
+			if len(client.deleted) != tt.wantDeleted {
+				t.Errorf("deleted %d messages, want %d", len(client.deleted), tt.wantDeleted)
+			}
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(ctx, &rm); err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+
+			events := map[string]int64{}
+			var durations uint64
+			var age float64
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					switch data := m.Data.(type) {
+					case metricdata.Sum[int64]:
+						if m.Name != telemetry.Namespace+".sqs.messages" {
+							continue
+						}
+						for _, dp := range data.DataPoints {
+							event, _ := dp.Attributes.Value("event")
+							events[event.AsString()] += dp.Value
+						}
+					case metricdata.Histogram[float64]:
+						if m.Name != telemetry.Namespace+".sqs.processing.duration" {
+							continue
+						}
+						for _, dp := range data.DataPoints {
+							durations += dp.Count
+						}
+					case metricdata.Gauge[float64]:
+						if m.Name != telemetry.Namespace+".sqs.message.age" {
+							continue
+						}
+						for _, dp := range data.DataPoints {
+							age = dp.Value
+						}
+					}
+				}
+			}
+
+			if len(events) != len(tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+			for event, want := range tt.wantEvents {
+				if events[event] != want {
+					t.Errorf("%s events = %d, want %d", event, events[event], want)
+				}
+			}
+			if durations != 2 {
+				t.Errorf("recorded %d processing durations, want 2", durations)
+			}
+			// The gauge holds the age of the last message received
+			if age < 89 || age > 120 {
+				t.Errorf("message age = %vs, want about 90s", age)
+			}
+		})
+	}
+}
+
+func TestSQSListener_NoMetrics(t *testing.T) {
+	client := &fakeSQS{messages: []types.Message{s3Notification("1", "registry.json", time.Second)}}
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+	listener := &SQSListener{
+		client:         client,
+		reloader:       NewS3Reloader(&countingDownloader{content: []byte(`{"servers":[]}`)}, targetPath, nil, func(string) error { return nil }),
+		targetFilePath: targetPath,
+	}
+
+	if err := listener.receiveAndProcessMessages(context.Background()); err != nil {
+		t.Fatalf("receiveAndProcessMessages() error = %v", err)
+	}
+	if len(client.deleted) != 1 {
+		t.Errorf("deleted %d messages, want 1", len(client.deleted))
+	}
+}
+
+func TestSQSListener_CoalescesDuplicates(t *testing.T) {
+	client := &fakeSQS{messages: []types.Message{
+		s3Notification("1", "registry.json", 3*time.Second),
+		s3Notification("2", "registry.json", 2*time.Second),
+		s3Notification("3", "registry.json", time.Second),
+	}}
+	downloader := &countingDownloader{content: []byte(`{"servers":[]}`)}
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+
+	var reloads int
+	reload := func(string) error {
+		reloads++
+		return nil
+	}
+	listener := &SQSListener{
+		client:         client,
+		reloader:       NewS3Reloader(downloader, targetPath, nil, reload),
+		targetFilePath: targetPath,
+	}
+
+	if err := listener.receiveAndProcessMessages(context.Background()); err != nil {
+		t.Fatalf("receiveAndProcessMessages() error = %v", err)
+	}
+	if downloader.downloads != 1 || reloads != 1 {
+		t.Errorf("downloaded %d and reloaded %d times, want once each", downloader.downloads, reloads)
+	}
+	if len(client.deleted) != 3 {
+		t.Errorf("deleted %v, want all three messages", client.deleted)
+	}
+}
+
+// fixedLatestFinder resolves every prefix to a fixed key
+type fixedLatestFinder struct {
+	key      string
+	prefixes []string
+}
+
+func (f *fixedLatestFinder) LatestObjectKey(_ context.Context, _, prefix string) (string, error) {
+	f.prefixes = append(f.prefixes, prefix)
+	return f.key, nil
+}
+
+func TestSQSListener_ResolvePrefix(t *testing.T) {
+	client := &fakeSQS{messages: []types.Message{s3Notification("1", "registry/", time.Second)}}
+	finder := &fixedLatestFinder{key: "registry/2024-06-01T12:00:00Z.json"}
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+
+	var sources []string
+	reload := func(source string) error {
+		sources = append(sources, source)
+		return nil
+	}
+	listener := &SQSListener{
+		client:         client,
+		reloader:       NewS3Reloader(&countingDownloader{content: []byte(`{"servers":[]}`)}, targetPath, nil, reload),
+		latest:         finder,
+		targetFilePath: targetPath,
+	}
+
+	if err := listener.receiveAndProcessMessages(context.Background()); err != nil {
+		t.Fatalf("receiveAndProcessMessages() error = %v", err)
+	}
+	if len(finder.prefixes) != 1 || finder.prefixes[0] != "registry/" {
+		t.Errorf("resolved prefixes = %v, want [registry/]", finder.prefixes)
+	}
+	if len(sources) != 1 || sources[0] != "s3://registry-bucket/registry/2024-06-01T12:00:00Z.json" {
+		t.Errorf("reloaded from %v, want the latest object", sources)
+	}
+}
+
+// pollingSQS hands out a fixed batch of messages once, then long polls until the request is cancelled
+type pollingSQS struct {
+	fakeSQS
+}
+
+func (f *pollingSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	if len(f.messages) > 0 {
+		return f.fakeSQS.ReceiveMessage(ctx, params, optFns...)
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// blockingDownloader signals when a download starts and blocks until it is cancelled
+type blockingDownloader struct {
+	started chan struct{}
+}
+
+func (d *blockingDownloader) DownloadFile(ctx context.Context, _, _, _ string) (ObjectVersion, error) {
+	close(d.started)
+	<-ctx.Done()
+	return ObjectVersion{}, ctx.Err()
+}
+
+func TestSQSListener_Stop(t *testing.T) {
+	newListener := func(client sqsAPI, downloader FileDownloader) *SQSListener {
+		targetPath := filepath.Join(t.TempDir(), "registry.json")
+		return &SQSListener{
+			client:         client,
+			reloader:       NewS3Reloader(downloader, targetPath, nil, func(string) error { return nil }),
+			targetFilePath: targetPath,
+			stopChan:       make(chan struct{}),
+		}
+	}
+
+	t.Run("ends the long poll", func(t *testing.T) {
+		listener := newListener(&pollingSQS{}, &countingDownloader{})
+		listener.Start(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		start := time.Now()
+		if err := listener.Stop(ctx); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Stop() took %v, want the long poll to end right away", elapsed)
+		}
+	})
+
+	t.Run("cancels processing at the deadline", func(t *testing.T) {
+		downloader := &blockingDownloader{started: make(chan struct{})}
+		client := &pollingSQS{fakeSQS{messages: []types.Message{s3Notification("1", "registry.json", time.Second)}}}
+		listener := newListener(client, downloader)
+		listener.Start(context.Background())
+		<-downloader.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := listener.Stop(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if len(client.deleted) != 0 {
+			t.Errorf("deleted %d messages, want the unfinished message left for redelivery", len(client.deleted))
+		}
+	})
+}
+
+// queueSQS hands out one queued message per receive, then long polls until the request is cancelled.
+// It is safe for concurrent use.
+type queueSQS struct {
+	mu       sync.Mutex
+	messages []types.Message
+	deleted  []string
+}
+
+func (q *queueSQS) ReceiveMessage(ctx context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	q.mu.Lock()
+	if len(q.messages) > 0 {
+		msg := q.messages[0]
+		q.messages = q.messages[1:]
+		q.mu.Unlock()
+		return &sqs.ReceiveMessageOutput{Messages: []types.Message{msg}}, nil
+	}
+	q.mu.Unlock()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (q *queueSQS) DeleteMessage(_ context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.deleted = append(q.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (q *queueSQS) deletedCount() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.deleted)
+}
+
+// barrierDownloader holds each download until a given number are in progress at once
+type barrierDownloader struct {
+	barrier sync.WaitGroup
+}
+
+func (d *barrierDownloader) DownloadFile(ctx context.Context, _, key, localPath string) (ObjectVersion, error) {
+	d.barrier.Done()
+	wait := make(chan struct{})
+	go func() {
+		d.barrier.Wait()
+		close(wait)
+	}()
+	select {
+	case <-wait:
+	case <-ctx.Done():
+		return ObjectVersion{}, ctx.Err()
+	}
+	return ObjectVersion{}, os.WriteFile(localPath, []byte(key), 0600)
+}
+
+func TestSQSListener_Concurrency(t *testing.T) {
+	const workers = 3
+
+	client := &queueSQS{}
+	for i := range workers {
+		client.messages = append(client.messages, s3Notification(strconv.Itoa(i), "registry-"+strconv.Itoa(i)+".json", time.Second))
+	}
+	downloader := &barrierDownloader{}
+	downloader.barrier.Add(workers)
+	targetPath := filepath.Join(t.TempDir(), "registry.json")
+
+	var inReload, overlaps, reloads atomic.Int32
+	reload := func(source string) error {
+		if inReload.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer inReload.Add(-1)
+
+		// The live file must hold the download being reloaded until the reload is done
+		want, err := os.ReadFile(targetPath)
+		if err != nil {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+		if got, _ := os.ReadFile(targetPath); string(got) != string(want) {
+			overlaps.Add(1)
+		}
+		reloads.Add(1)
+		return nil
+	}
+	listener := &SQSListener{
+		client:         client,
+		reloader:       NewS3Reloader(downloader, targetPath, nil, reload),
+		targetFilePath: targetPath,
+		stopChan:       make(chan struct{}),
+		concurrency:    workers,
+	}
+	listener.Start(context.Background())
+
+	// Each download waits for all the others to start, so the messages are only processed if the workers run in parallel
+	deadline := time.Now().Add(5 * time.Second)
+	for client.deletedCount() < workers && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := listener.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if deleted := client.deletedCount(); deleted != workers {
+		t.Fatalf("processed %d messages, want %d processed in parallel", deleted, workers)
+	}
+	if reloads.Load() != workers {
+		t.Errorf("reloaded %d times, want %d", reloads.Load(), workers)
+	}
+	if overlaps.Load() != 0 {
+		t.Errorf("reloads overlapped %d times, want them serialized", overlaps.Load())
+	}
+}
@@ -1,23 +1,77 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
 // Config holds the application configuration
-// See .env.example for more documentation
+// See .env.example for more documentation. Redacted hides fields tagged secret:"true" and the database URL passwords.
 type Config struct {
 	ServerAddress            string `env:"SERVER_ADDRESS" envDefault:":8080"`
+	BaseURL                  string `env:"BASE_URL" envDefault:""`                     // external base URL used for absolute URLs in responses
+	TrustForwardedHeaders    bool   `env:"TRUST_FORWARDED_HEADERS" envDefault:"false"` // honor X-Forwarded-* headers from any peer
+	TLSCert                  string `env:"TLS_CERT" envDefault:""`                     // PEM certificate file; with TLSKey, serves HTTPS and HTTP/2
+	TLSKey                   string `env:"TLS_KEY" envDefault:""`                      // PEM private key file of TLSCert
 	DatabaseURL              string `env:"DATABASE_URL" envDefault:"postgres://localhost:5432/mcp-registry?sslmode=disable"`
-	DatabaseType             string `env:"DATABASE_TYPE" envDefault:"jsonfile"` // "postgres" or "jsonfile"
+	DatabaseReplicaURL       string `env:"DATABASE_REPLICA_URL" envDefault:""`  // optional PostgreSQL read replica for the reads that serve GET requests
+	DatabaseType             string `env:"DATABASE_TYPE" envDefault:"jsonfile"` // "postgres", "jsonfile" or a registered backend
 	JSONFilePath             string `env:"JSON_FILE_PATH" envDefault:"data/registry.json"`
-	SeedFrom                 string `env:"SEED_FROM" envDefault:"data/seed.json"`
+	JSONTolerantLoad         bool   `env:"JSON_TOLERANT_LOAD" envDefault:"false"` // skip malformed records instead of failing to load
+	JSONCompact              bool   `env:"JSON_COMPACT" envDefault:"false"`       // write the JSON file without indentation
+	JSONWriteAheadLog        bool   `env:"JSON_WAL" envDefault:"false"`           // log changes to <file>.wal until flushed, replayed on startup
+	SeedFrom                 string `env:"SEED_FROM" envDefault:"data/seed.json"` // comma-separated; later sources override earlier ones
+	SeedRequired             bool   `env:"SEED_REQUIRED" envDefault:"false"`      // exit on startup if the seed import fails
 	Version                  string `env:"VERSION" envDefault:"dev"`
 	GithubClientID           string `env:"GITHUB_CLIENT_ID" envDefault:""`
-	GithubClientSecret       string `env:"GITHUB_CLIENT_SECRET" envDefault:""`
-	JWTPrivateKey            string `env:"JWT_PRIVATE_KEY" envDefault:""`
+	GithubClientSecret       string `env:"GITHUB_CLIENT_SECRET" envDefault:"" secret:"true"`
+	JWTPrivateKey            string `env:"JWT_PRIVATE_KEY" envDefault:"" secret:"true"`
 	EnableAnonymousAuth      bool   `env:"ENABLE_ANONYMOUS_AUTH" envDefault:"true"`
 	EnableRegistryValidation bool   `env:"ENABLE_REGISTRY_VALIDATION" envDefault:"true"`
+	AdminAPIKey              string `env:"ADMIN_API_KEY" envDefault:"" secret:"true"`
+	MaxBodyBytes             int64  `env:"MAX_BODY_BYTES" envDefault:"1048576"`
+
+	// MetricsEnabled records metrics and serves them at /metrics; when disabled, nothing is recorded
+	MetricsEnabled bool `env:"METRICS_ENABLED" envDefault:"true"`
+
+	// ReconcileLatestOnStartup fixes servers with no latest version or more than one after seeding
+	ReconcileLatestOnStartup bool `env:"RECONCILE_LATEST_ON_STARTUP" envDefault:"false"`
+
+	// RetryAfter is sent in a Retry-After header with 503 responses, telling clients when to retry; 0 omits the header
+	RetryAfter time.Duration `env:"RETRY_AFTER" envDefault:"30s"`
+
+	// ShutdownTimeout bounds graceful shutdown, from draining SQS messages and HTTP requests to closing the database
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+
+	// FuzzySearchMinSimilarity is the trigram similarity from 0 to 1 a server name needs to match a fuzzy search
+	// that doesn't set its own threshold; lower values tolerate more typos but return looser matches. 0 uses 0.3.
+	FuzzySearchMinSimilarity float64 `env:"FUZZY_SEARCH_MIN_SIMILARITY" envDefault:"0.3"`
+
+	// TrustedProxies are the CIDRs (or single IPs) of the proxies in front of the registry. When set, X-Forwarded-*
+	// headers are only honored on requests whose direct peer is in the list, instead of following TrustForwardedHeaders.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+
+	// Publish policy
+	AllowedPackageRegistries []string `env:"ALLOWED_PACKAGE_REGISTRIES" envSeparator:","`
+	AllowedTags              []string `env:"ALLOWED_TAGS" envSeparator:","`                 // tags servers may carry; any well-formed tag is accepted when empty
+	EnforceMonotonicVersions bool     `env:"ENFORCE_MONOTONIC_VERSIONS" envDefault:"false"` // reject publishing a version that isn't newer than the latest
+	RequireOCIDigest         bool     `env:"REQUIRE_OCI_DIGEST" envDefault:"false"`         // reject OCI packages referenced by tag instead of digest
+	PublishRPS               float64  `env:"PUBLISH_RPS" envDefault:"0"`                    // publishes per second allowed per server name; 0 is unlimited
+	MaxVersionsPerServer     int      `env:"MAX_VERSIONS_PER_SERVER" envDefault:"10000"`    // versions a server may have; 0 is unlimited
+	MaxVersionsPolicy        string   `env:"MAX_VERSIONS_POLICY" envDefault:"reject"`       // at the limit, "reject" the publish or "prune" the oldest version
+	KeepVersions             int      `env:"KEEP_VERSIONS" envDefault:"0"`                  // on publishing a new latest, delete all but the newest N versions; 0 keeps all
+	MaxServers               int      `env:"MAX_SERVERS" envDefault:"0"`                    // distinct servers the registry may hold; 0 is unlimited
+
+	// NamespaceQuotasFile is a JSON file of per-namespace publish limits overriding the ones above for servers
+	// under a name prefix; the most specific matching prefix applies. NamespaceQuotas holds its contents.
+	NamespaceQuotasFile string          `env:"NAMESPACE_QUOTAS_FILE" envDefault:""`
+	NamespaceQuotas     NamespaceQuotas `env:"-"`
 
 	// OIDC Configuration
 	OIDCEnabled      bool   `env:"OIDC_ENABLED" envDefault:"false"`
@@ -31,6 +85,144 @@ type Config struct {
 	Region      string `env:"AWS_REGION" envDefault:"us-east-1"`
 	SQSEnabled  bool   `env:"SQS_ENABLED" envDefault:"false"`
 	SQSQueueURL string `env:"SQS_QUEUE_URL" envDefault:""`
+	S3URL       string `env:"S3_URL" envDefault:""`
+
+	// AWSProfile selects a named profile from the shared AWS config and credentials files, and AWSRoleARN an IAM
+	// role to assume with those credentials, for the S3 and SQS clients; empty uses the default credential chain
+	AWSProfile string `env:"AWS_PROFILE" envDefault:""`
+	AWSRoleARN string `env:"AWS_ROLE_ARN" envDefault:""`
+
+	// SQSResolvePrefix treats the S3 key in SQS messages as a prefix and reloads the most recently
+	// modified object under it, for uploads published under timestamped keys
+	SQSResolvePrefix bool `env:"SQS_RESOLVE_PREFIX" envDefault:"false"`
+
+	// SQSConcurrency is the number of workers polling the SQS queue and processing messages in parallel.
+	// Their downloads overlap, but reloads of the database still happen one at a time.
+	SQSConcurrency int `env:"SQS_CONCURRENCY" envDefault:"1"`
+
+	// ImportLatestPolicy decides the latest versions when SQS updates are imported into a database not served from
+	// the data file: "file" applies the file's latest flags over the stored ones, and "recompute" ignores them and
+	// recomputes the latest version of each imported server
+	ImportLatestPolicy string `env:"IMPORT_LATEST_POLICY" envDefault:"file"`
+
+	// NotifySQSQueueURL sends a message to this SQS queue for every change to a server version, for deployments
+	// that feed downstream registries; empty disables notifications. NotifyS3URL is an optional S3 URL the catalog is
+	// uploaded to before changes are announced, announced with them in the S3 event format the SQS listener consumes.
+	NotifySQSQueueURL string `env:"NOTIFY_SQS_QUEUE_URL" envDefault:""`
+	NotifyS3URL       string `env:"NOTIFY_S3_URL" envDefault:""`
+
+	// AuditLog records every change to server versions as JSON lines: "stdout", or a file path to append to;
+	// empty disables the audit log
+	AuditLog string `env:"AUDIT_LOG" envDefault:""`
+
+	// Catalog publishing: upload the servers matching the catalog filter to CatalogS3URL every CatalogInterval
+	// (0 disables the schedule), and on demand via the admin catalog endpoint
+	CatalogS3URL      string        `env:"CATALOG_S3_URL" envDefault:""`
+	CatalogInterval   time.Duration `env:"CATALOG_INTERVAL" envDefault:"0"`
+	CatalogStatus     string        `env:"CATALOG_STATUS" envDefault:"active"` // only publish servers with this status; empty publishes all
+	CatalogLatestOnly bool          `env:"CATALOG_LATEST_ONLY" envDefault:"false"`
+
+	// Compaction: remove versions deleted longer than CompactDeletedRetention ago and each server's versions
+	// beyond CompactMaxVersions (0 disables either) on CompactSchedule, an interval such as "24h" or a daily
+	// UTC time such as "03:30" (empty disables the schedule), and on demand via the admin compact endpoint
+	CompactSchedule         string        `env:"COMPACT_SCHEDULE" envDefault:""`
+	CompactDeletedRetention time.Duration `env:"COMPACT_DELETED_RETENTION" envDefault:"0"`
+	CompactMaxVersions      int           `env:"COMPACT_MAX_VERSIONS" envDefault:"0"`
+
+	// Publish locks of the JSON file database: waiting for a lock fails after JSONLockTimeout (0 waits for as
+	// long as the request allows), and locks held longer than JSONStaleLockThreshold are logged and counted
+	// in metrics, checked at that interval (0 disables the check)
+	JSONLockTimeout        time.Duration `env:"JSON_LOCK_TIMEOUT" envDefault:"30s"`
+	JSONStaleLockThreshold time.Duration `env:"JSON_STALE_LOCK_THRESHOLD" envDefault:"1m"`
+
+	// Page sizes of server lists: DefaultPageSize servers are returned when a request doesn't set a limit, and
+	// larger limits than MaxPageSize are lowered to it
+	DefaultPageSize int `env:"DEFAULT_PAGE_SIZE" envDefault:"30"`
+	MaxPageSize     int `env:"MAX_PAGE_SIZE" envDefault:"100"`
+
+	// SyncStalenessThreshold marks the service degraded when the last successful data sync is older; 0 disables the check
+	SyncStalenessThreshold time.Duration `env:"SYNC_STALENESS_THRESHOLD" envDefault:"0"`
+
+	// S3HealthCheck makes the health endpoint confirm the S3 data file at S3URL is reachable
+	S3HealthCheck        bool          `env:"S3_HEALTH_CHECK" envDefault:"false"`
+	S3HealthCheckTimeout time.Duration `env:"S3_HEALTH_CHECK_TIMEOUT" envDefault:"5s"`
+}
+
+// Page sizes used when the configured ones are unset
+const (
+	fallbackDefaultPageSize = 30
+	fallbackMaxPageSize     = 100
+)
+
+// PageSizes returns the default and maximum page sizes of server lists. The default never exceeds the maximum.
+func (c *Config) PageSizes() (defaultSize, maxSize int) {
+	defaultSize, maxSize = c.DefaultPageSize, c.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = fallbackMaxPageSize
+	}
+	if defaultSize <= 0 {
+		defaultSize = fallbackDefaultPageSize
+	}
+	return min(defaultSize, maxSize), maxSize
+}
+
+// redactedValue replaces secrets in Redacted configurations
+const redactedValue = "REDACTED"
+
+// Redacted returns a copy of the configuration that is safe to show, such as to operators debugging a deployment:
+// fields tagged secret:"true" are replaced with REDACTED when set, and the passwords of the database URLs are masked
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	v := reflect.ValueOf(&redacted).Elem()
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if v.Type().Field(i).Tag.Get("secret") == "true" && field.Kind() == reflect.String && field.String() != "" {
+			field.SetString(redactedValue)
+		}
+	}
+
+	redacted.DatabaseURL = redactURL(c.DatabaseURL)
+	redacted.DatabaseReplicaURL = redactURL(c.DatabaseReplicaURL)
+	return &redacted
+}
+
+// redactURL masks the passwords of a database connection string: those in the userinfo and query parameters
+// of a URL, or the whole value of any other form, such as a keyword/value DSN, which may embed them anywhere
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		return redactedValue
+	}
+
+	query := u.Query()
+	masked := false
+	for key := range query {
+		// Covers password and sslpassword, which pgx also reads from the query
+		if strings.Contains(strings.ToLower(key), "password") {
+			query.Set(key, redactedValue)
+			masked = true
+		}
+	}
+	if masked {
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+// Validate checks the settings that only take one of a few values, so a typo fails at startup rather than
+// on the first request or message that needs the setting
+func (c *Config) Validate() error {
+	if !slices.Contains([]string{"reject", "prune"}, c.MaxVersionsPolicy) {
+		return fmt.Errorf("MCP_REGISTRY_MAX_VERSIONS_POLICY must be reject or prune, got %q", c.MaxVersionsPolicy)
+	}
+	if !slices.Contains([]string{"file", "recompute"}, c.ImportLatestPolicy) {
+		return fmt.Errorf("MCP_REGISTRY_IMPORT_LATEST_POLICY must be file or recompute, got %q", c.ImportLatestPolicy)
+	}
+	return nil
 }
 
 // NewConfig creates a new configuration with default values
@@ -42,5 +234,14 @@ func NewConfig() *Config {
 	if err != nil {
 		panic(err)
 	}
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	if cfg.NamespaceQuotasFile != "" {
+		cfg.NamespaceQuotas, err = LoadNamespaceQuotas(cfg.NamespaceQuotasFile)
+		if err != nil {
+			panic(err)
+		}
+	}
 	return &cfg
 }
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{MaxVersionsPolicy: "reject", ImportLatestPolicy: "file"}
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("default policies rejected: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"prune policy", func(c *Config) { c.MaxVersionsPolicy = "prune" }, ""},
+		{"recompute policy", func(c *Config) { c.ImportLatestPolicy = "recompute" }, ""},
+		{"unknown max versions policy", func(c *Config) { c.MaxVersionsPolicy = "drop" }, "MCP_REGISTRY_MAX_VERSIONS_POLICY"},
+		{"unknown import latest policy", func(c *Config) { c.ImportLatestPolicy = "newest" }, "MCP_REGISTRY_IMPORT_LATEST_POLICY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error mentioning %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRedacted_DatabaseURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"unset", "", ""},
+		{"no password", "postgres://registry@db:5432/registry", "postgres://registry@db:5432/registry"},
+		{"userinfo password", "postgres://registry:hunter2@db:5432/registry", "postgres://registry:xxxxx@db:5432/registry"},
+		{"query password", "postgres://registry@db/registry?password=hunter2&sslmode=require", "postgres://registry@db/registry?password=REDACTED&sslmode=require"},
+		{"query ssl password", "postgres://registry@db/registry?sslpassword=hunter2", "postgres://registry@db/registry?sslpassword=REDACTED"},
+		{"keyword DSN", "host=db user=registry password=hunter2 dbname=registry", "REDACTED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted := (&Config{DatabaseURL: tt.url, DatabaseReplicaURL: tt.url}).Redacted()
+			if redacted.DatabaseURL != tt.want || redacted.DatabaseReplicaURL != tt.want {
+				t.Fatalf("redacted %q to %q and %q, want %q", tt.url, redacted.DatabaseURL, redacted.DatabaseReplicaURL, tt.want)
+			}
+			if strings.Contains(redacted.DatabaseURL, "hunter2") {
+				t.Fatalf("password left in %q", redacted.DatabaseURL)
+			}
+		})
+	}
+}
+
+func TestEffective_KeyedByEnvironmentVariable(t *testing.T) {
+	keys := map[string]bool{}
+	effectiveType := reflect.TypeOf(EffectiveConfig{})
+	for i := range effectiveType.NumField() {
+		keys[effectiveType.Field(i).Tag.Get("json")] = true
+	}
+
+	// Every setting read from the environment is shown under its variable name
+	configType := reflect.TypeOf(Config{})
+	variables := 0
+	for i := range configType.NumField() {
+		name := configType.Field(i).Tag.Get("env")
+		if name == "" || name == "-" {
+			continue
+		}
+		variables++
+		if !keys["MCP_REGISTRY_"+name] {
+			t.Errorf("%s isn't shown as MCP_REGISTRY_%s", configType.Field(i).Name, name)
+		}
+	}
+	if len(keys) != variables {
+		t.Errorf("effective configuration has %d keys, want one per environment variable (%d)", len(keys), variables)
+	}
+}
+
+func TestEffective_DurationsAsStrings(t *testing.T) {
+	effective := (&Config{RetryAfter: 30 * time.Second, JSONStaleLockThreshold: time.Minute}).Effective()
+	if effective.RetryAfter != "30s" || effective.JSONStaleLockThreshold != "1m0s" {
+		t.Fatalf("durations rendered as %q and %q", effective.RetryAfter, effective.JSONStaleLockThreshold)
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+// EffectiveConfig is the configuration shown to operators debugging a deployment, keyed by the environment
+// variables that set each field. Durations are rendered as strings such as "30s", the format they're configured in.
+type EffectiveConfig struct {
+	ServerAddress            string   `json:"MCP_REGISTRY_SERVER_ADDRESS"`
+	BaseURL                  string   `json:"MCP_REGISTRY_BASE_URL"`
+	TrustForwardedHeaders    bool     `json:"MCP_REGISTRY_TRUST_FORWARDED_HEADERS"`
+	TLSCert                  string   `json:"MCP_REGISTRY_TLS_CERT"`
+	TLSKey                   string   `json:"MCP_REGISTRY_TLS_KEY"`
+	DatabaseURL              string   `json:"MCP_REGISTRY_DATABASE_URL"`
+	DatabaseReplicaURL       string   `json:"MCP_REGISTRY_DATABASE_REPLICA_URL"`
+	DatabaseType             string   `json:"MCP_REGISTRY_DATABASE_TYPE"`
+	JSONFilePath             string   `json:"MCP_REGISTRY_JSON_FILE_PATH"`
+	JSONTolerantLoad         bool     `json:"MCP_REGISTRY_JSON_TOLERANT_LOAD"`
+	JSONCompact              bool     `json:"MCP_REGISTRY_JSON_COMPACT"`
+	JSONWriteAheadLog        bool     `json:"MCP_REGISTRY_JSON_WAL"`
+	SeedFrom                 string   `json:"MCP_REGISTRY_SEED_FROM"`
+	SeedRequired             bool     `json:"MCP_REGISTRY_SEED_REQUIRED"`
+	Version                  string   `json:"MCP_REGISTRY_VERSION"`
+	GithubClientID           string   `json:"MCP_REGISTRY_GITHUB_CLIENT_ID"`
+	GithubClientSecret       string   `json:"MCP_REGISTRY_GITHUB_CLIENT_SECRET"`
+	JWTPrivateKey            string   `json:"MCP_REGISTRY_JWT_PRIVATE_KEY"`
+	EnableAnonymousAuth      bool     `json:"MCP_REGISTRY_ENABLE_ANONYMOUS_AUTH"`
+	EnableRegistryValidation bool     `json:"MCP_REGISTRY_ENABLE_REGISTRY_VALIDATION"`
+	AdminAPIKey              string   `json:"MCP_REGISTRY_ADMIN_API_KEY"`
+	MaxBodyBytes             int64    `json:"MCP_REGISTRY_MAX_BODY_BYTES"`
+	MetricsEnabled           bool     `json:"MCP_REGISTRY_METRICS_ENABLED"`
+	ReconcileLatestOnStartup bool     `json:"MCP_REGISTRY_RECONCILE_LATEST_ON_STARTUP"`
+	RetryAfter               string   `json:"MCP_REGISTRY_RETRY_AFTER"`
+	ShutdownTimeout          string   `json:"MCP_REGISTRY_SHUTDOWN_TIMEOUT"`
+	FuzzySearchMinSimilarity float64  `json:"MCP_REGISTRY_FUZZY_SEARCH_MIN_SIMILARITY"`
+	TrustedProxies           []string `json:"MCP_REGISTRY_TRUSTED_PROXIES"`
+	AllowedPackageRegistries []string `json:"MCP_REGISTRY_ALLOWED_PACKAGE_REGISTRIES"`
+	AllowedTags              []string `json:"MCP_REGISTRY_ALLOWED_TAGS"`
+	EnforceMonotonicVersions bool     `json:"MCP_REGISTRY_ENFORCE_MONOTONIC_VERSIONS"`
+	RequireOCIDigest         bool     `json:"MCP_REGISTRY_REQUIRE_OCI_DIGEST"`
+	PublishRPS               float64  `json:"MCP_REGISTRY_PUBLISH_RPS"`
+	MaxVersionsPerServer     int      `json:"MCP_REGISTRY_MAX_VERSIONS_PER_SERVER"`
+	MaxVersionsPolicy        string   `json:"MCP_REGISTRY_MAX_VERSIONS_POLICY"`
+	KeepVersions             int      `json:"MCP_REGISTRY_KEEP_VERSIONS"`
+	MaxServers               int      `json:"MCP_REGISTRY_MAX_SERVERS"`
+	NamespaceQuotasFile      string   `json:"MCP_REGISTRY_NAMESPACE_QUOTAS_FILE"`
+	OIDCEnabled              bool     `json:"MCP_REGISTRY_OIDC_ENABLED"`
+	OIDCIssuer               string   `json:"MCP_REGISTRY_OIDC_ISSUER"`
+	OIDCClientID             string   `json:"MCP_REGISTRY_OIDC_CLIENT_ID"`
+	OIDCExtraClaims          string   `json:"MCP_REGISTRY_OIDC_EXTRA_CLAIMS"`
+	OIDCEditPerms            string   `json:"MCP_REGISTRY_OIDC_EDIT_PERMISSIONS"`
+	OIDCPublishPerms         string   `json:"MCP_REGISTRY_OIDC_PUBLISH_PERMISSIONS"`
+	Region                   string   `json:"MCP_REGISTRY_AWS_REGION"`
+	SQSEnabled               bool     `json:"MCP_REGISTRY_SQS_ENABLED"`
+	SQSQueueURL              string   `json:"MCP_REGISTRY_SQS_QUEUE_URL"`
+	S3URL                    string   `json:"MCP_REGISTRY_S3_URL"`
+	AWSProfile               string   `json:"MCP_REGISTRY_AWS_PROFILE"`
+	AWSRoleARN               string   `json:"MCP_REGISTRY_AWS_ROLE_ARN"`
+	SQSResolvePrefix         bool     `json:"MCP_REGISTRY_SQS_RESOLVE_PREFIX"`
+	SQSConcurrency           int      `json:"MCP_REGISTRY_SQS_CONCURRENCY"`
+	ImportLatestPolicy       string   `json:"MCP_REGISTRY_IMPORT_LATEST_POLICY"`
+	NotifySQSQueueURL        string   `json:"MCP_REGISTRY_NOTIFY_SQS_QUEUE_URL"`
+	NotifyS3URL              string   `json:"MCP_REGISTRY_NOTIFY_S3_URL"`
+	AuditLog                 string   `json:"MCP_REGISTRY_AUDIT_LOG"`
+	CatalogS3URL             string   `json:"MCP_REGISTRY_CATALOG_S3_URL"`
+	CatalogInterval          string   `json:"MCP_REGISTRY_CATALOG_INTERVAL"`
+	CatalogStatus            string   `json:"MCP_REGISTRY_CATALOG_STATUS"`
+	CatalogLatestOnly        bool     `json:"MCP_REGISTRY_CATALOG_LATEST_ONLY"`
+	CompactSchedule          string   `json:"MCP_REGISTRY_COMPACT_SCHEDULE"`
+	CompactDeletedRetention  string   `json:"MCP_REGISTRY_COMPACT_DELETED_RETENTION"`
+	CompactMaxVersions       int      `json:"MCP_REGISTRY_COMPACT_MAX_VERSIONS"`
+	JSONLockTimeout          string   `json:"MCP_REGISTRY_JSON_LOCK_TIMEOUT"`
+	JSONStaleLockThreshold   string   `json:"MCP_REGISTRY_JSON_STALE_LOCK_THRESHOLD"`
+	DefaultPageSize          int      `json:"MCP_REGISTRY_DEFAULT_PAGE_SIZE"`
+	MaxPageSize              int      `json:"MCP_REGISTRY_MAX_PAGE_SIZE"`
+	SyncStalenessThreshold   string   `json:"MCP_REGISTRY_SYNC_STALENESS_THRESHOLD"`
+	S3HealthCheck            bool     `json:"MCP_REGISTRY_S3_HEALTH_CHECK"`
+	S3HealthCheckTimeout     string   `json:"MCP_REGISTRY_S3_HEALTH_CHECK_TIMEOUT"`
+}
+
+// Effective returns the redacted configuration keyed by environment variable, as served by the admin config endpoint
+func (c *Config) Effective() *EffectiveConfig {
+	c = c.Redacted()
+	return &EffectiveConfig{
+		ServerAddress:            c.ServerAddress,
+		BaseURL:                  c.BaseURL,
+		TrustForwardedHeaders:    c.TrustForwardedHeaders,
+		TLSCert:                  c.TLSCert,
+		TLSKey:                   c.TLSKey,
+		DatabaseURL:              c.DatabaseURL,
+		DatabaseReplicaURL:       c.DatabaseReplicaURL,
+		DatabaseType:             c.DatabaseType,
+		JSONFilePath:             c.JSONFilePath,
+		JSONTolerantLoad:         c.JSONTolerantLoad,
+		JSONCompact:              c.JSONCompact,
+		JSONWriteAheadLog:        c.JSONWriteAheadLog,
+		SeedFrom:                 c.SeedFrom,
+		SeedRequired:             c.SeedRequired,
+		Version:                  c.Version,
+		GithubClientID:           c.GithubClientID,
+		GithubClientSecret:       c.GithubClientSecret,
+		JWTPrivateKey:            c.JWTPrivateKey,
+		EnableAnonymousAuth:      c.EnableAnonymousAuth,
+		EnableRegistryValidation: c.EnableRegistryValidation,
+		AdminAPIKey:              c.AdminAPIKey,
+		MaxBodyBytes:             c.MaxBodyBytes,
+		MetricsEnabled:           c.MetricsEnabled,
+		ReconcileLatestOnStartup: c.ReconcileLatestOnStartup,
+		RetryAfter:               c.RetryAfter.String(),
+		ShutdownTimeout:          c.ShutdownTimeout.String(),
+		FuzzySearchMinSimilarity: c.FuzzySearchMinSimilarity,
+		TrustedProxies:           c.TrustedProxies,
+		AllowedPackageRegistries: c.AllowedPackageRegistries,
+		AllowedTags:              c.AllowedTags,
+		EnforceMonotonicVersions: c.EnforceMonotonicVersions,
+		RequireOCIDigest:         c.RequireOCIDigest,
+		PublishRPS:               c.PublishRPS,
+		MaxVersionsPerServer:     c.MaxVersionsPerServer,
+		MaxVersionsPolicy:        c.MaxVersionsPolicy,
+		KeepVersions:             c.KeepVersions,
+		MaxServers:               c.MaxServers,
+		NamespaceQuotasFile:      c.NamespaceQuotasFile,
+		OIDCEnabled:              c.OIDCEnabled,
+		OIDCIssuer:               c.OIDCIssuer,
+		OIDCClientID:             c.OIDCClientID,
+		OIDCExtraClaims:          c.OIDCExtraClaims,
+		OIDCEditPerms:            c.OIDCEditPerms,
+		OIDCPublishPerms:         c.OIDCPublishPerms,
+		Region:                   c.Region,
+		SQSEnabled:               c.SQSEnabled,
+		SQSQueueURL:              c.SQSQueueURL,
+		S3URL:                    c.S3URL,
+		AWSProfile:               c.AWSProfile,
+		AWSRoleARN:               c.AWSRoleARN,
+		SQSResolvePrefix:         c.SQSResolvePrefix,
+		SQSConcurrency:           c.SQSConcurrency,
+		ImportLatestPolicy:       c.ImportLatestPolicy,
+		NotifySQSQueueURL:        c.NotifySQSQueueURL,
+		NotifyS3URL:              c.NotifyS3URL,
+		AuditLog:                 c.AuditLog,
+		CatalogS3URL:             c.CatalogS3URL,
+		CatalogInterval:          c.CatalogInterval.String(),
+		CatalogStatus:            c.CatalogStatus,
+		CatalogLatestOnly:        c.CatalogLatestOnly,
+		CompactSchedule:          c.CompactSchedule,
+		CompactDeletedRetention:  c.CompactDeletedRetention.String(),
+		CompactMaxVersions:       c.CompactMaxVersions,
+		JSONLockTimeout:          c.JSONLockTimeout.String(),
+		JSONStaleLockThreshold:   c.JSONStaleLockThreshold.String(),
+		DefaultPageSize:          c.DefaultPageSize,
+		MaxPageSize:              c.MaxPageSize,
+		SyncStalenessThreshold:   c.SyncStalenessThreshold.String(),
+		S3HealthCheck:            c.S3HealthCheck,
+		S3HealthCheckTimeout:     c.S3HealthCheckTimeout.String(),
+	}
+}
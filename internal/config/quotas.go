@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NamespaceQuota overrides the global publish limits for servers whose names start with a prefix.
+// Unset (zero) fields fall back to the global limit.
+type NamespaceQuota struct {
+	PublishRPS           float64 `json:"publishRps,omitempty"`           // publishes per second allowed per server name
+	MaxVersionsPerServer int     `json:"maxVersionsPerServer,omitempty"` // versions each server may have
+	MaxServers           int     `json:"maxServers,omitempty"`           // distinct servers the namespace may hold
+}
+
+// NamespaceQuotas maps server name prefixes, such as "io.github.acme/", to their quotas
+type NamespaceQuotas map[string]NamespaceQuota
+
+// PublishLimits are the limits that apply to publishing one server, 0 meaning unlimited
+type PublishLimits struct {
+	PublishRPS           float64
+	MaxVersionsPerServer int
+	MaxServers           int    // distinct servers allowed under Namespace
+	Namespace            string // prefix of the quota that applied, empty for the global limits
+}
+
+// LoadNamespaceQuotas reads namespace quotas from a JSON file mapping name prefixes to quotas
+func LoadNamespaceQuotas(path string) (NamespaceQuotas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace quotas: %w", err)
+	}
+	var quotas NamespaceQuotas
+	if err := json.Unmarshal(data, &quotas); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace quotas %s: %w", path, err)
+	}
+	for prefix, quota := range quotas {
+		if prefix == "" {
+			return nil, fmt.Errorf("namespace quotas %s: empty prefix", path)
+		}
+		if quota.PublishRPS < 0 || quota.MaxVersionsPerServer < 0 || quota.MaxServers < 0 {
+			return nil, fmt.Errorf("namespace quotas %s: negative limit for %q", path, prefix)
+		}
+	}
+	return quotas, nil
+}
+
+// lookup returns the quota with the longest prefix of serverName, and false if none matches
+func (q NamespaceQuotas) lookup(serverName string) (string, NamespaceQuota, bool) {
+	var (
+		best  string
+		quota NamespaceQuota
+		found bool
+	)
+	for prefix, candidate := range q {
+		if strings.HasPrefix(serverName, prefix) && (!found || len(prefix) > len(best)) {
+			best, quota, found = prefix, candidate, true
+		}
+	}
+	return best, quota, found
+}
+
+// PublishLimits returns the limits for publishing serverName: those of the most specific matching namespace
+// quota, with the global limits filling in anything the quota leaves unset
+func (c *Config) PublishLimits(serverName string) PublishLimits {
+	limits := PublishLimits{
+		PublishRPS:           c.PublishRPS,
+		MaxVersionsPerServer: c.MaxVersionsPerServer,
+	}
+	prefix, quota, ok := c.NamespaceQuotas.lookup(serverName)
+	if !ok {
+		return limits
+	}
+
+	limits.Namespace = prefix
+	if quota.PublishRPS > 0 {
+		limits.PublishRPS = quota.PublishRPS
+	}
+	if quota.MaxVersionsPerServer > 0 {
+		limits.MaxVersionsPerServer = quota.MaxVersionsPerServer
+	}
+	limits.MaxServers = quota.MaxServers
+	return limits
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadNamespaceQuotas(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "quotas.json")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("valid file", func(t *testing.T) {
+		quotas, err := LoadNamespaceQuotas(write(t, `{
+			"io.github.acme/": {"publishRps": 5, "maxVersionsPerServer": 500, "maxServers": 100},
+			"io.github.acme/partner-": {"maxServers": 10}
+		}`))
+		if err != nil {
+			t.Fatalf("LoadNamespaceQuotas() error = %v", err)
+		}
+		want := NamespaceQuota{PublishRPS: 5, MaxVersionsPerServer: 500, MaxServers: 100}
+		if got := quotas["io.github.acme/"]; got != want {
+			t.Errorf("quota = %+v, want %+v", got, want)
+		}
+	})
+
+	for name, content := range map[string]string{
+		"malformed":      `{"io.github.acme/": {"maxServers": "many"}}`,
+		"empty prefix":   `{"": {"maxServers": 1}}`,
+		"negative limit": `{"io.github.acme/": {"publishRps": -1}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadNamespaceQuotas(write(t, content)); err == nil {
+				t.Error("LoadNamespaceQuotas() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestPublishLimits(t *testing.T) {
+	cfg := &Config{
+		PublishRPS:           1,
+		MaxVersionsPerServer: 100,
+		NamespaceQuotas: NamespaceQuotas{
+			"io.github.acme/":         {PublishRPS: 5, MaxServers: 20},
+			"io.github.acme/partner-": {MaxVersionsPerServer: 1000, MaxServers: 2},
+		},
+	}
+
+	tests := []struct {
+		serverName string
+		want       PublishLimits
+	}{
+		{"io.github.acme/partner-tool", PublishLimits{PublishRPS: 1, MaxVersionsPerServer: 1000, MaxServers: 2, Namespace: "io.github.acme/partner-"}},
+		{"io.github.acme/tool", PublishLimits{PublishRPS: 5, MaxVersionsPerServer: 100, MaxServers: 20, Namespace: "io.github.acme/"}},
+		{"io.github.other/tool", PublishLimits{PublishRPS: 1, MaxVersionsPerServer: 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serverName, func(t *testing.T) {
+			if got := cfg.PublishLimits(tt.serverName); got != tt.want {
+				t.Errorf("PublishLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
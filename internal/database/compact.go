@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// CompactOptions selects the server versions Compact removes
+type CompactOptions struct {
+	// DeletedRetention removes versions that were deleted longer ago than this; 0 keeps deleted versions
+	DeletedRetention time.Duration
+	// MaxVersions keeps only this many of each server's versions, the latest and then the most recently
+	// published; 0 keeps every version
+	MaxVersions int
+	// DryRun reports the versions that would be removed without removing them
+	DryRun bool
+}
+
+// CompactReason is why Compact removed a server version
+type CompactReason string
+
+const (
+	// CompactReasonDeleted marks a version deleted for longer than the retention period
+	CompactReasonDeleted CompactReason = "deleted"
+	// CompactReasonMaxVersions marks a version beyond the per-server version limit
+	CompactReasonMaxVersions CompactReason = "max_versions"
+)
+
+// CompactedVersion identifies a server version removed by Compact
+type CompactedVersion struct {
+	ServerName string        `json:"serverName"`
+	Version    string        `json:"version"`
+	Reason     CompactReason `json:"reason"`
+}
+
+// CompactResult reports the server versions removed by Compact, or that a dry run would remove
+type CompactResult struct {
+	Removed []CompactedVersion
+}
+
+// Compact implements Compactor.Compact. It holds the write lock for the whole run and writes the
+// remaining records straight to the JSON file, so concurrent writes wait rather than interleave with it.
+func (db *JSONFileDB) Compact(ctx context.Context, opts CompactOptions) (CompactResult, error) {
+	if ctx.Err() != nil {
+		return CompactResult{}, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	reasons := compactReasons(db.data.Servers, opts, time.Now())
+	if len(reasons) == 0 {
+		return CompactResult{}, nil
+	}
+
+	var result CompactResult
+	kept := make([]serverRecord, 0, len(db.data.Servers)-len(reasons))
+	for i, record := range db.data.Servers {
+		if reason, ok := reasons[i]; ok {
+			result.Removed = append(result.Removed, CompactedVersion{ServerName: record.ServerName, Version: record.Version, Reason: reason})
+			continue
+		}
+		kept = append(kept, record)
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// Removals aren't write-ahead logged, so the file is rewritten now; this also persists any
+	// pending changes, after which the log can be truncated. Each removal leaves a tombstone for the changes feed.
+	previous := *db.data
+	removed := slices.Clip(db.data.Removed)
+	for _, version := range result.Removed {
+		removed = append(dropRemoved(removed, version.ServerName, version.Version),
+			removedRecord{ServerName: version.ServerName, Version: version.Version, ChangeSeq: db.nextChangeSeq(), unsynced: true})
+	}
+	db.data.Servers = kept
+	db.data.Removed = removed
+	if err := db.writeFile(); err != nil {
+		*db.data = previous
+		db.writeFailing = true
+		return CompactResult{}, fmt.Errorf("%w: failed to write compacted %s: %v", ErrDatabase, db.filePath, err)
+	}
+	db.dirty = false
+	db.writeFailing = false
+
+	if err := db.truncateWAL(); err != nil {
+		return result, fmt.Errorf("%w: failed to truncate write-ahead log: %v", ErrDatabase, err)
+	}
+	return result, nil
+}
+
+// compactReasons returns the indexes of the records Compact removes, with why each is removed
+func compactReasons(records []serverRecord, opts CompactOptions, now time.Time) map[int]CompactReason {
+	byServer := make(map[string][]int)
+	for i, record := range records {
+		byServer[record.ServerName] = append(byServer[record.ServerName], i)
+	}
+
+	reasons := make(map[int]CompactReason)
+	for _, indexes := range byServer {
+		remaining := indexes
+		if opts.DeletedRetention > 0 {
+			cutoff := now.Add(-opts.DeletedRetention)
+			remaining = nil
+			for _, i := range indexes {
+				if records[i].Status == string(model.StatusDeleted) && records[i].UpdatedAt.Before(cutoff) {
+					reasons[i] = CompactReasonDeleted
+				} else {
+					remaining = append(remaining, i)
+				}
+			}
+		}
+
+		if opts.MaxVersions > 0 && len(remaining) > opts.MaxVersions {
+			// Keep the latest version first, then the most recently published
+			remaining = slices.Clone(remaining)
+			slices.SortStableFunc(remaining, func(a, b int) int {
+				if records[a].IsLatest != records[b].IsLatest {
+					if records[a].IsLatest {
+						return -1
+					}
+					return 1
+				}
+				return records[b].PublishedAt.Compare(records[a].PublishedAt)
+			})
+			for _, i := range remaining[opts.MaxVersions:] {
+				reasons[i] = CompactReasonMaxVersions
+			}
+			remaining = remaining[:opts.MaxVersions]
+		}
+
+		// A server keeps its latest version unless nothing of it remains
+		if len(remaining) > 0 {
+			for _, i := range indexes {
+				if records[i].IsLatest {
+					delete(reasons, i)
+				}
+			}
+		}
+	}
+	return reasons
+}
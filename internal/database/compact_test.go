@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompact tests that compaction removes expired deleted versions and versions beyond the limit,
+// keeps each server's latest version and persists the result, including changes still in the WAL
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	now := time.Now()
+	seed := func(name, version string, status model.Status, age time.Duration, isLatest bool) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Compaction test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{
+			Status:      status,
+			PublishedAt: now.Add(-age),
+			UpdatedAt:   now.Add(-age),
+			IsLatest:    isLatest,
+		})
+		require.NoError(t, err)
+	}
+	day := 24 * time.Hour
+	seed("com.example/many", "1.0.0", model.StatusActive, 5*day, false)
+	seed("com.example/many", "2.0.0", model.StatusDeleted, 40*day, false)
+	seed("com.example/many", "3.0.0", model.StatusActive, 3*day, false)
+	seed("com.example/many", "4.0.0", model.StatusActive, 2*day, false)
+	seed("com.example/many", "5.0.0", model.StatusActive, 10*day, true) // latest despite being published earlier
+	seed("com.example/recently-deleted", "1.0.0", model.StatusDeleted, day, true)
+	seed("com.example/latest-deleted", "1.0.0", model.StatusActive, 60*day, false)
+	seed("com.example/latest-deleted", "2.0.0", model.StatusDeleted, 40*day, true)
+	seed("com.example/gone", "1.0.0", model.StatusDeleted, 40*day, true)
+
+	// A dry run reports what would be removed without touching the data, the file or the WAL
+	before := db.snapshot()
+	walBefore, err := os.ReadFile(filePath + ".wal")
+	require.NoError(t, err)
+	dryRun, err := db.Compact(ctx, CompactOptions{DeletedRetention: 30 * day, MaxVersions: 2, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, before, db.snapshot())
+	assert.NoFileExists(t, filePath)
+	walAfter, err := os.ReadFile(filePath + ".wal")
+	require.NoError(t, err)
+	assert.Equal(t, walBefore, walAfter)
+
+	result, err := db.Compact(ctx, CompactOptions{DeletedRetention: 30 * day, MaxVersions: 2})
+	require.NoError(t, err)
+	assert.Equal(t, dryRun.Removed, result.Removed)
+	assert.ElementsMatch(t, []CompactedVersion{
+		{ServerName: "com.example/many", Version: "1.0.0", Reason: CompactReasonMaxVersions},
+		{ServerName: "com.example/many", Version: "2.0.0", Reason: CompactReasonDeleted},
+		{ServerName: "com.example/many", Version: "3.0.0", Reason: CompactReasonMaxVersions},
+		{ServerName: "com.example/gone", Version: "1.0.0", Reason: CompactReasonDeleted},
+	}, result.Removed)
+
+	// The changes were only in the WAL before compaction, so reopening without it shows the file was rewritten
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reopened.Close() })
+
+	remaining := map[string][]string{}
+	for _, record := range reopened.snapshot() {
+		remaining[record.ServerName] = append(remaining[record.ServerName], record.Version)
+	}
+	assert.Equal(t, map[string][]string{
+		"com.example/many":             {"4.0.0", "5.0.0"},
+		"com.example/recently-deleted": {"1.0.0"},
+		"com.example/latest-deleted":   {"1.0.0", "2.0.0"},
+	}, remaining)
+
+	t.Run("nothing left to remove", func(t *testing.T) {
+		result, err := db.Compact(ctx, CompactOptions{DeletedRetention: 30 * day, MaxVersions: 2})
+		require.NoError(t, err)
+		assert.Empty(t, result.Removed)
+	})
+}
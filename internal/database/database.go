@@ -16,7 +16,13 @@ var (
 	ErrInvalidInput      = errors.New("invalid input")
 	ErrDatabase          = errors.New("database error")
 	ErrInvalidVersion    = errors.New("invalid version: cannot publish duplicate version")
-	ErrMaxServersReached = errors.New("maximum number of versions for this server reached (10000): please reach out at https://github.com/modelcontextprotocol/registry to explain your use case")
+	ErrAlreadyPublished  = errors.New("identical version already published")
+	ErrVersionNotNewer   = errors.New("invalid version: must be newer than the current latest version")
+	ErrMaxServersReached = errors.New("maximum number of versions for this server reached")
+	ErrRegistryFull      = errors.New("registry has reached its maximum number of servers")
+	ErrLockTimeout       = errors.New("timed out waiting for the publish lock")
+	ErrReloading         = errors.New("registry data is being reloaded")
+	ErrFileLocked        = errors.New("data file is locked by another process")
 )
 
 // ServerFilter defines filtering options for server queries
@@ -25,8 +31,39 @@ type ServerFilter struct {
 	RemoteURL     *string    // for duplicate URL detection
 	UpdatedSince  *time.Time // for incremental sync filtering
 	SubstringName *string    // for substring search on name
+	FuzzyName     *string    // for typo-tolerant search on name; matches are ranked by similarity and not paginated
+	MinSimilarity float64    // trigram similarity from 0 to 1 a name needs to match FuzzyName
+	NamePrefix    *string    // for listing all servers in a namespace
 	Version       *string    // for exact version matching
 	IsLatest      *bool      // for filtering latest versions only
+	Status        *string    // for filtering by lifecycle status
+	HasRemotes    *bool      // for filtering by whether a server has any remotes
+	HasPackages   *bool      // for filtering by whether a server has any packages
+	HasProvenance *bool      // for filtering by whether a server has any provenance attestations
+	Tag           *string    // for filtering by a tag the server carries
+	TransportType *string    // for filtering by the transport type of a server's remotes, e.g. "sse"
+	Author        *string    // for filtering by the owner of a server's repository or the identity that published it, ignoring case, e.g. "acme" for github.com/acme/weather
+	Offset        int        // for offset pagination: matching servers to skip; ignored with a cursor or fuzzy search
+}
+
+// Change is an entry in the changes feed: a server version as it is after its most recent change, or the removal
+// of one from storage. Every change is numbered from a single sequence in the order changes become visible.
+type Change struct {
+	Sequence int64
+	Server   *apiv0.ServerResponse // the changed version; nil when it was removed
+	Removed  *ServerRef            // the removed version; nil unless Server is
+}
+
+// ReloadChange is a change a reload made to a stored server version
+type ReloadChange struct {
+	Change
+	Created bool // the version wasn't stored before the reload
+}
+
+// ServerRef identifies a specific version of a server
+type ServerRef struct {
+	Name    string
+	Version string
 }
 
 // Database defines the interface for database operations
@@ -35,24 +72,47 @@ type Database interface {
 	CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *apiv0.ServerJSON, officialMeta *apiv0.RegistryExtensions) (*apiv0.ServerResponse, error)
 	// UpdateServer updates an existing server record
 	UpdateServer(ctx context.Context, tx pgx.Tx, serverName, version string, serverJSON *apiv0.ServerJSON) (*apiv0.ServerResponse, error)
-	// SetServerStatus updates the status of a specific server version
-	SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status string) (*apiv0.ServerResponse, error)
+	// SetServerStatus updates the status of a specific server version, recording why it was changed if reason is set
+	SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status, reason string) (*apiv0.ServerResponse, error)
+	// DeprecateServer marks a specific server version as deprecated, optionally pointing at its replacement
+	DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, replacedBy string) (*apiv0.ServerResponse, error)
+	// RenameServer moves every version of a server to a new name, returning the renamed versions
+	RenameServer(ctx context.Context, tx pgx.Tx, serverName, newName string) ([]*apiv0.ServerResponse, error)
 	// ListServers retrieve server entries with optional filtering
 	ListServers(ctx context.Context, tx pgx.Tx, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
+	// ListChanges retrieve up to limit changes numbered after the after sequence number (from the start when 0),
+	// in sequence order. Only a version's most recent change is listed.
+	ListChanges(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]Change, error)
+	// ListServerNames retrieve the distinct server names in name order, optionally limited to those starting with prefix.
+	// The cursor is the last name of the previous page, and a limit of 0 lists every remaining name.
+	ListServerNames(ctx context.Context, tx pgx.Tx, prefix *string, cursor string, limit int) ([]string, string, error)
 	// GetServerByName retrieve a single server by its name
 	GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error)
 	// GetServerByNameAndVersion retrieve specific version of a server by server name and version
 	GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx, serverName string, version string) (*apiv0.ServerResponse, error)
+	// GetServerVersions retrieve the server versions identified by refs, in the order of refs. Versions that don't
+	// exist are omitted rather than reported as an error.
+	GetServerVersions(ctx context.Context, tx pgx.Tx, refs []ServerRef) ([]*apiv0.ServerResponse, error)
+	// GetVersionsAsOf retrieve all versions of a server published at or before a point in time
+	GetVersionsAsOf(ctx context.Context, tx pgx.Tx, serverName string, at time.Time) ([]*apiv0.ServerResponse, error)
 	// GetAllVersionsByServerName retrieve all versions of a server by server name
 	GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error)
 	// GetCurrentLatestVersion retrieve the current latest version of a server by server name
 	GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error)
 	// CountServerVersions count the number of versions for a server
 	CountServerVersions(ctx context.Context, tx pgx.Tx, serverName string) (int, error)
+	// CountServerNames count the number of distinct server names, across every status
+	CountServerNames(ctx context.Context, tx pgx.Tx) (int, error)
 	// CheckVersionExists check if a specific version exists for a server
 	CheckVersionExists(ctx context.Context, tx pgx.Tx, serverName, version string) (bool, error)
-	// UnmarkAsLatest marks the current latest version of a server as no longer latest
+	// UnmarkAsLatest marks the current latest version of a server as no longer latest, removing any pin
 	UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName string) error
+	// SetLatestVersion marks one version of a server as the latest and every other version as not,
+	// returning how many versions had their latest flag or pin changed. A pinned latest version was
+	// chosen by an administrator, and recomputing latest versions leaves it alone.
+	SetLatestVersion(ctx context.Context, tx pgx.Tx, serverName, version string, pinned bool) (int, error)
+	// DeleteServerVersion permanently removes a specific server version
+	DeleteServerVersion(ctx context.Context, tx pgx.Tx, serverName, version string) error
 	// AcquirePublishLock acquires an exclusive advisory lock for publishing a server
 	// This prevents race conditions when multiple versions are published concurrently
 	AcquirePublishLock(ctx context.Context, tx pgx.Tx, serverName string) error
@@ -62,6 +122,58 @@ type Database interface {
 	Close() error
 }
 
+// Flusher is implemented by databases that hold changes in memory and can persist them on demand
+type Flusher interface {
+	// Flush persists any pending in-memory changes; it is a no-op when nothing has changed
+	Flush(ctx context.Context) error
+}
+
+// FileReloader is implemented by databases that serve data loaded from a local file
+type FileReloader interface {
+	// FilePath returns the path of the data file
+	FilePath() string
+	// ReloadFrom replaces the in-memory data with the contents of the data file, recording where it came from
+	ReloadFrom(source string) error
+	// ReloadFromWithChanges reloads like ReloadFrom and returns the changes the reload made to stored versions
+	ReloadFromWithChanges(source string) ([]ReloadChange, error)
+	// Count returns the number of stored server records
+	Count() int
+}
+
+// SyncStatus describes the last successful refresh of a database's data from its source
+type SyncStatus struct {
+	At     time.Time // when the data was refreshed
+	Source string    // where the data came from, e.g. an s3:// URI
+}
+
+// Compactor is implemented by databases that can drop server versions that are no longer needed
+type Compactor interface {
+	// Compact removes the versions selected by opts, never leaving a server without its latest version
+	// unless every version of it is removed
+	Compact(ctx context.Context, opts CompactOptions) (CompactResult, error)
+}
+
+// SyncTracker is implemented by databases whose data is periodically refreshed from an external source
+type SyncTracker interface {
+	// LastSync returns the last successful refresh, and false if there has been none
+	LastSync() (SyncStatus, bool)
+}
+
+// GracefulCloser is implemented by databases that can let in-flight queries finish before closing
+type GracefulCloser interface {
+	// CloseContext stops new queries, waits for those in flight to finish or ctx to be done, then closes the
+	// database. It returns ctx's error if in-flight queries were still running when ctx was done.
+	CloseContext(ctx context.Context) error
+}
+
+// CloseContext closes db, letting in-flight queries finish until ctx is done if the database supports it
+func CloseContext(ctx context.Context, db Database) error {
+	if closer, ok := db.(GracefulCloser); ok {
+		return closer.CloseContext(ctx)
+	}
+	return db.Close()
+}
+
 // InTransactionT is a generic helper that wraps InTransaction for functions returning a value
 // This exists because Go does not support generic methods on interfaces - only the Database interface
 // method InTransaction (without generics) can exist, so we provide this generic wrapper function.
@@ -82,3 +194,14 @@ func InTransactionT[T any](ctx context.Context, db Database, fn func(ctx context
 
 	return result, nil
 }
+
+// PinnedLatest returns the version an administrator pinned as the latest among a server's versions, or nil if
+// none is pinned
+func PinnedLatest(versions []*apiv0.ServerResponse) *apiv0.ServerResponse {
+	for _, version := range versions {
+		if official := version.Meta.Official; official != nil && official.IsLatest && official.LatestPinned {
+			return version
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"encoding/json"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// MarshalJSONFile serializes servers in the JSON file database format, so the result can be served by a
+// JSON file database or imported into another database with UpsertFromJSONFile
+func MarshalJSONFile(servers []*apiv0.ServerResponse) ([]byte, error) {
+	data := jsonFileData{Servers: make([]serverRecord, 0, len(servers))}
+	for _, server := range servers {
+		value := server.Server
+		record := serverRecord{
+			ServerName: value.Name,
+			Version:    value.Version,
+			Value:      &value,
+		}
+		if official := server.Meta.Official; official != nil {
+			record.Status = string(official.Status)
+			record.PublishedAt = official.PublishedAt
+			record.UpdatedAt = official.UpdatedAt
+			record.IsLatest = official.IsLatest
+			record.LatestPinned = official.LatestPinned
+			record.ReplacedBy = official.ReplacedBy
+			record.StatusReason = official.StatusReason
+			record.PublishedBy = official.PublishedBy
+		}
+		data.Servers = append(data.Servers, record)
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// Factory creates a Database from the application configuration
+type Factory func(ctx context.Context, cfg *config.Config) (Database, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a custom database backend available under name, so it can be selected with
+// MCP_REGISTRY_DATABASE_TYPE. Backends typically register themselves from an init function.
+// Registered backends take precedence over the built-in "jsonfile" and "postgres" types.
+// Register panics if name is empty, factory is nil, or name is already registered.
+func Register(name string, factory Factory) {
+	if name == "" {
+		panic("database: Register called with an empty name")
+	}
+	if factory == nil {
+		panic("database: Register factory is nil for " + name)
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, exists := factories[name]; exists {
+		panic("database: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Lookup returns the factory registered under name
+func Lookup(name string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	factory, ok := factories[name]
+	return factory, ok
+}
+
+// Registered returns the names of the registered database backends in sorted order
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open creates a database using the backend registered under name
+func Open(ctx context.Context, name string, cfg *config.Config) (Database, error) {
+	factory, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: no database backend registered as %q", ErrInvalidInput, name)
+	}
+	db, err := factory(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", name, err)
+	}
+	return db, nil
+}
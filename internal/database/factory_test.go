@@ -0,0 +1,60 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// fakeBackend stands in for a custom Database implementation
+type fakeBackend struct {
+	database.Database
+	url string
+}
+
+func TestRegister(t *testing.T) {
+	database.Register("fake-test-backend", func(_ context.Context, cfg *config.Config) (database.Database, error) {
+		return &fakeBackend{url: cfg.DatabaseURL}, nil
+	})
+	database.Register("failing-test-backend", func(_ context.Context, _ *config.Config) (database.Database, error) {
+		return nil, errors.New("table not found")
+	})
+
+	t.Run("registered backend is constructed through its factory", func(t *testing.T) {
+		_, ok := database.Lookup("fake-test-backend")
+		require.True(t, ok)
+		assert.Contains(t, database.Registered(), "fake-test-backend")
+
+		db, err := database.Open(context.Background(), "fake-test-backend", &config.Config{DatabaseURL: "dynamodb://registry"})
+		require.NoError(t, err)
+		backend, ok := db.(*fakeBackend)
+		require.True(t, ok)
+		assert.Equal(t, "dynamodb://registry", backend.url)
+	})
+
+	t.Run("factory errors are returned", func(t *testing.T) {
+		_, err := database.Open(context.Background(), "failing-test-backend", &config.Config{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "table not found")
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		_, ok := database.Lookup("no-such-backend")
+		assert.False(t, ok)
+		_, err := database.Open(context.Background(), "no-such-backend", &config.Config{})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("invalid registrations panic", func(t *testing.T) {
+		factory := func(_ context.Context, _ *config.Config) (database.Database, error) { return &fakeBackend{}, nil }
+		assert.Panics(t, func() { database.Register("fake-test-backend", factory) })
+		assert.Panics(t, func() { database.Register("", factory) })
+		assert.Panics(t, func() { database.Register("nil-test-backend", nil) })
+	})
+}
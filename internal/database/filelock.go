@@ -0,0 +1,48 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Defaults for waiting on another process's lock of the data file
+const (
+	defaultFileLockTimeout = 10 * time.Second
+	fileLockPollInterval   = 10 * time.Millisecond
+)
+
+// errWouldBlock is returned by tryLockFile when another process holds a conflicting lock
+var errWouldBlock = errors.New("lock is held")
+
+// lockFile takes an advisory lock on the sidecar "<path>.lock" file, shared for reading the data file or
+// exclusive for writing it, so registry processes on the same host sharing a data file don't read it
+// half-written or clobber each other's writes. The lock is on a sidecar because writes replace the data
+// file rather than modify it. It waits up to timeout for other processes to release a conflicting lock
+// before failing with ErrFileLocked. The returned function releases the lock.
+func lockFile(path string, exclusive bool, timeout time.Duration) (func(), error) {
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLockFile(f, exclusive)
+		if err == nil {
+			// Closing the file releases the lock
+			return func() { f.Close() }, nil
+		}
+		if !errors.Is(err, errWouldBlock) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s is held by another process after waiting %s", ErrFileLocked, lockPath, timeout)
+		}
+		time.Sleep(fileLockPollInterval)
+	}
+}
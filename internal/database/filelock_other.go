@@ -0,0 +1,10 @@
+//go:build !unix
+
+package database
+
+import "os"
+
+// tryLockFile does nothing on platforms without flock: processes sharing a data file are not coordinated there
+func tryLockFile(_ *os.File, _ bool) error {
+	return nil
+}
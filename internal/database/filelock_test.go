@@ -0,0 +1,185 @@
+//go:build unix
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileLockSharedFile tests that databases sharing a data file, as separate processes would, never read it
+// half-written or corrupt it with interleaved writes. flock locks belong to open files, so instances in one
+// process contend for them like processes do.
+func TestFileLockSharedFile(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"servers":[]}`), 0600))
+
+	writers := make([]*JSONFileDB, 2)
+	for i := range writers {
+		db, err := NewJSONFileDB(ctx, filePath)
+		require.NoError(t, err)
+		writers[i] = db
+	}
+	reader, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	const versions = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*versions+1)
+	for i, db := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range versions {
+				now := time.Now()
+				_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+					Schema:      model.CurrentSchemaURL,
+					Name:        fmt.Sprintf("com.example/writer-%d", i),
+					Description: "File lock test server",
+					Version:     fmt.Sprintf("1.0.%d", v),
+				}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now})
+				if err == nil {
+					err = db.Flush(ctx)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := reader.Reload(); err != nil {
+				errs <- fmt.Errorf("reload: %w", err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	<-readerDone
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Each writer merges the other's flushes before writing, so every publish survives
+	require.NoError(t, ValidateJSONFile(filePath))
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, 2*versions, reopened.Count())
+}
+
+// TestFileLockTimeout tests that reading or writing the data file fails clearly while another process holds its lock
+func TestFileLockTimeout(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	db.fileLockTimeout = 50 * time.Millisecond
+	now := time.Now()
+	_, err = db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/locked",
+		Description: "File lock test server",
+		Version:     "1.0.0",
+	}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Flush(ctx))
+
+	unlock, err := lockFile(filePath, true, time.Second)
+	require.NoError(t, err)
+
+	err = db.Reload()
+	require.ErrorIs(t, err, ErrFileLocked)
+	assert.Contains(t, err.Error(), filePath+".lock")
+
+	_, err = db.SetServerStatus(ctx, nil, "com.example/locked", "1.0.0", string(model.StatusDeprecated), "")
+	require.NoError(t, err)
+	err = db.Flush(ctx)
+	require.ErrorIs(t, err, ErrDatabase)
+	assert.Contains(t, err.Error(), ErrFileLocked.Error())
+
+	// Once the lock is released the pending change is written
+	unlock()
+	require.NoError(t, db.Flush(ctx))
+}
+
+// TestFileLockWriteAheadLog tests that only one process at a time can own a write-ahead log
+func TestFileLockWriteAheadLog(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+
+	_, err = NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.ErrorIs(t, err, ErrFileLocked)
+	assert.Contains(t, err.Error(), db.walPath())
+
+	require.NoError(t, db.Close())
+	reopened, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	require.NoError(t, reopened.Close())
+}
+
+// TestFileLockMergeKeepsDeletes tests that a deletion flushed after another process wrote the file is not
+// undone by merging that process's changes
+func TestFileLockMergeKeepsDeletes(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	publish := func(db *JSONFileDB, name string) {
+		t.Helper()
+		now := time.Now()
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "File lock test server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: true})
+		require.NoError(t, err)
+		require.NoError(t, db.Flush(ctx))
+	}
+
+	first, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	publish(first, "com.example/deleted")
+
+	second, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	require.NoError(t, first.DeleteServerVersion(ctx, nil, "com.example/deleted", "1.0.0"))
+	publish(second, "com.example/other")
+	require.NoError(t, first.Flush(ctx))
+
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	_, err = reopened.GetServerByNameAndVersion(ctx, nil, "com.example/deleted", "1.0.0")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = reopened.GetServerByNameAndVersion(ctx, nil, "com.example/other", "1.0.0")
+	require.NoError(t, err)
+}
@@ -1,12 +1,19 @@
 package database
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"log"
+	"net/url"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -20,30 +27,129 @@ import (
 
 // JSONFileDB implements the Database interface using a local JSON file
 type JSONFileDB struct {
-	filePath       string
-	mu             sync.RWMutex
-	data           *jsonFileData
-	locks          map[uint64]*sync.Mutex // advisory locks by server name hash
-	locksMu        sync.Mutex
-	loggedInvalid  map[string]bool // tracks which invalid records have been logged
+	filePath        string
+	mu              sync.RWMutex
+	data            *jsonFileData
+	locks           map[uint64]*publishLock // advisory locks by server name hash
+	locksMu         sync.Mutex
+	loggedInvalid   map[string]bool // tracks which invalid records have been logged
 	loggedInvalidMu sync.Mutex
+	dirty           bool          // in-memory data has changes not yet written to filePath
+	writeFailing    bool          // the last write to filePath failed, so unlogged changes are written through
+	lastSync        *SyncStatus   // last successful load of the data file, guarded by mu
+	tolerantLoad    bool          // skip malformed server records on load instead of failing
+	skippedRecords  int           // malformed server records skipped by the last load, guarded by mu
+	compact         bool          // write the data file without indentation
+	walEnabled      bool          // log mutations to a write-ahead log until they are flushed
+	wal             *os.File      // open write-ahead log, guarded by mu; nil unless walEnabled
+	lockTimeout     time.Duration // longest to wait for a publish lock; 0 waits as long as the context allows
+	reloadGate      sync.RWMutex  // held for reading by transactions and for writing by reloads
+	fileLockTimeout time.Duration // longest to wait for other processes to release the data file's lock
+	fileInfo        os.FileInfo   // the data file as last loaded or written, guarded by mu; nil before either
+	pickLatest      LatestPicker  // chooses a server's latest version after merging; nil lets local changes decide
+}
+
+// JSONFileOption configures a JSONFileDB
+type JSONFileOption func(*JSONFileDB)
+
+// WithTolerantLoad makes loading skip and log server records that fail to unmarshal, keeping the
+// valid ones, instead of rejecting the whole file. The file must still be a JSON object with a
+// "servers" array.
+func WithTolerantLoad() JSONFileOption {
+	return func(db *JSONFileDB) {
+		db.tolerantLoad = true
+	}
+}
+
+// WithCompactJSON makes writes to the data file use compact JSON instead of two-space indentation,
+// roughly halving its size for large registries. Loading accepts either format.
+func WithCompactJSON() JSONFileOption {
+	return func(db *JSONFileDB) {
+		db.compact = true
+	}
+}
+
+// WithWriteAheadLog makes every mutation append a record to a sidecar "<file>.wal" log before it is
+// applied in memory. Flushing the JSON file truncates the log, and opening the database replays
+// whatever the log holds, so changes made since the last flush survive a crash.
+func WithWriteAheadLog() JSONFileOption {
+	return func(db *JSONFileDB) {
+		db.walEnabled = true
+	}
+}
+
+// WithLockTimeout bounds how long acquiring a publish lock waits before failing with ErrLockTimeout, so a lock
+// that is never released makes publishes of that server fail instead of hanging forever
+func WithLockTimeout(timeout time.Duration) JSONFileOption {
+	return func(db *JSONFileDB) {
+		db.lockTimeout = timeout
+	}
+}
+
+// LatestPicker chooses which of a server's versions should be marked latest, returning nil if none should
+type LatestPicker func(versions []*apiv0.ServerResponse) *apiv0.ServerResponse
+
+// WithLatestPicker makes merging local changes into reloaded data recompute the latest version of every
+// server they touch with pick, so a local publish only becomes the latest if it orders above the loaded
+// versions. Without it, a kept local latest version takes over from the loaded ones.
+func WithLatestPicker(pick LatestPicker) JSONFileOption {
+	return func(db *JSONFileDB) {
+		db.pickLatest = pick
+	}
 }
 
 // jsonFileData represents the structure stored in the JSON file
 type jsonFileData struct {
-	Servers []serverRecord `json:"servers"`
+	Servers   []serverRecord  `json:"servers"`
+	Removed   []removedRecord `json:"removed,omitempty"`    // tombstones of removed versions, for the changes feed
+	ChangeSeq int64           `json:"change_seq,omitempty"` // last change sequence number handed out
+}
+
+// removedRecord is the tombstone of a server version removed from storage, numbered like any other change
+type removedRecord struct {
+	ServerName string `json:"server_name"`
+	Version    string `json:"version"`
+	ChangeSeq  int64  `json:"change_seq"`
+
+	unsynced bool // removed locally while a loaded JSON file still had the version
 }
 
 // serverRecord represents a single server version in storage
 type serverRecord struct {
-	ServerName  string                    `json:"server_name"`
-	Version     string                    `json:"version"`
-	Status      string                    `json:"status"`
-	PublishedAt time.Time                 `json:"published_at"`
-	UpdatedAt   time.Time                 `json:"updated_at"`
-	IsLatest    bool                      `json:"is_latest"`
-	Value       *apiv0.ServerJSON         `json:"value"`
-	Meta        *apiv0.RegistryExtensions `json:"meta,omitempty"`
+	ServerName   string                    `json:"server_name"`
+	Version      string                    `json:"version"`
+	Status       string                    `json:"status"`
+	PublishedAt  time.Time                 `json:"published_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+	IsLatest     bool                      `json:"is_latest"`
+	LatestPinned bool                      `json:"latest_pinned,omitempty"`
+	Value        *apiv0.ServerJSON         `json:"value"`
+	Meta         *apiv0.RegistryExtensions `json:"meta,omitempty"`
+	ReplacedBy   string                    `json:"replaced_by,omitempty"`
+	StatusReason string                    `json:"status_reason,omitempty"`
+	PublishedBy  string                    `json:"published_by,omitempty"`
+	ChangeSeq    int64                     `json:"change_seq,omitempty"` // sequence number of the record's last change
+
+	unsynced bool // changed locally since it was last loaded from the JSON file
+}
+
+// response builds the API representation of a stored server record
+func (r *serverRecord) response() *apiv0.ServerResponse {
+	return &apiv0.ServerResponse{
+		Server: *r.Value,
+		Meta: apiv0.ResponseMeta{
+			Official: &apiv0.RegistryExtensions{
+				Status:       model.Status(r.Status),
+				PublishedAt:  r.PublishedAt,
+				UpdatedAt:    r.UpdatedAt,
+				IsLatest:     r.IsLatest,
+				LatestPinned: r.LatestPinned,
+				ReplacedBy:   r.ReplacedBy,
+				StatusReason: r.StatusReason,
+				PublishedBy:  r.PublishedBy,
+			},
+		},
+	}
 }
 
 // jsonTx is a mock transaction type for JSON file database
@@ -51,62 +157,256 @@ type jsonTx struct {
 	db         *JSONFileDB
 	committed  bool
 	rolledBack bool
-	locks      []*sync.Mutex
+	locks      []*publishLock
+	undo       []undoRecord    // server versions as they were before the transaction first changed them
+	undoKeys   map[string]bool // keys of the server versions in undo
+}
+
+// undoRecord is a server version as it was before a transaction changed it; before is nil if it didn't exist
+type undoRecord struct {
+	serverName string
+	version    string
+	before     *serverRecord
 }
 
 // NewJSONFileDB creates a new JSON file-based database
-func NewJSONFileDB(ctx context.Context, filePath string) (*JSONFileDB, error) {
+func NewJSONFileDB(ctx context.Context, filePath string, opts ...JSONFileOption) (*JSONFileDB, error) {
 	db := &JSONFileDB{
-		filePath:      filePath,
-		data:          &jsonFileData{Servers: []serverRecord{}},
-		locks:         make(map[uint64]*sync.Mutex),
-		loggedInvalid: make(map[string]bool),
+		filePath:        filePath,
+		data:            &jsonFileData{Servers: []serverRecord{}},
+		locks:           make(map[uint64]*publishLock),
+		loggedInvalid:   make(map[string]bool),
+		fileLockTimeout: defaultFileLockTimeout,
+	}
+	for _, opt := range opts {
+		opt(db)
 	}
 
 	// Try to load existing data
-	if _, err := os.Stat(filePath); err == nil {
+	if info, err := os.Stat(filePath); err == nil {
 		if err := db.load(); err != nil {
 			return nil, fmt.Errorf("failed to load existing data: %w", err)
 		}
+		db.numberUnsequenced()
+		// The file on disk is as fresh as its last write
+		db.lastSync = &SyncStatus{At: info.ModTime(), Source: filePath}
 	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to check file: %w", err)
 	}
 
+	if db.walEnabled {
+		if err := db.openWAL(); err != nil {
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
 
-// load reads data from the JSON file
+// load reads data from the JSON file, under a shared lock so another process can't write it meanwhile
 func (db *JSONFileDB) load() error {
-	data, err := os.ReadFile(db.filePath)
+	unlock, err := lockFile(db.filePath, false, db.fileLockTimeout)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	if len(data) == 0 {
-		return nil
+	info, err := os.Stat(db.filePath)
+	if err != nil {
+		return err
 	}
-
-	var fileData jsonFileData
-	if err := json.Unmarshal(data, &fileData); err != nil {
+	fileData, skipped, err := readJSONFile(db.filePath, db.tolerantLoad)
+	if err != nil {
 		return err
 	}
+	db.fileInfo = info
+	if fileData != nil {
+		db.data = fileData
+	}
+	db.skippedRecords = skipped
+	if skipped > 0 {
+		log.Printf("Warning: skipped %d malformed server record(s) loading %s", skipped, db.filePath)
+	}
+	return nil
+}
 
-	/*
-		var serverResponses []apiv0.ServerJSON
-		if err := json.Unmarshal(data, &serverResponses); err != nil {
-			return err
+// readJSONFile parses a JSON database file, returning nil data for an empty file.
+// In tolerant mode server records that fail to unmarshal are skipped and counted.
+func readJSONFile(filePath string, tolerant bool) (*jsonFileData, int, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	return decodeJSONFile(bufio.NewReader(f), tolerant)
+}
+
+// decodeJSONFile streams a JSON database file, decoding the servers array one element at a
+// time so that peak memory stays close to the size of the decoded records rather than the
+// file plus the records
+func decodeJSONFile(r io.Reader, tolerant bool) (*jsonFileData, int, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fileData := &jsonFileData{}
+	skipped := 0
+	switch tok {
+	case nil:
+		// A null document decodes to no data, as json.Unmarshal would
+	case json.Delim('{'):
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, 0, err
+			}
+			key, _ := keyTok.(string)
+			switch {
+			case strings.EqualFold(key, "removed"):
+				if err := dec.Decode(&fileData.Removed); err != nil {
+					return nil, 0, fmt.Errorf("removed: %w", err)
+				}
+				continue
+			case strings.EqualFold(key, "change_seq"):
+				if err := dec.Decode(&fileData.ChangeSeq); err != nil {
+					return nil, 0, fmt.Errorf("change_seq: %w", err)
+				}
+				continue
+			case !strings.EqualFold(key, "servers"):
+				// Skip unknown fields without holding onto them
+				var ignored json.RawMessage
+				if err := dec.Decode(&ignored); err != nil {
+					return nil, 0, err
+				}
+				continue
+			}
+
+			servers, n, err := decodeServerRecords(dec, tolerant)
+			if err != nil {
+				return nil, 0, err
+			}
+			fileData.Servers = servers
+			skipped = n
 		}
+		if _, err := dec.Token(); err != nil {
+			return nil, 0, err
+		}
+	default:
+		return nil, 0, fmt.Errorf("expected a JSON object, found %v", tok)
+	}
 
-		var fileData jsonFileData
-		fileData.Servers = make([]serverRecord, 0, len(serverResponses))
-	*/
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("unexpected data after the top-level JSON value")
+	}
 
-	db.data = &fileData
-	return nil
+	return fileData, skipped, nil
+}
+
+// decodeServerRecords decodes the servers array element by element. In tolerant mode elements
+// that fail to unmarshal are logged and skipped; malformed JSON is always an error.
+func decodeServerRecords(dec *json.Decoder, tolerant bool) ([]serverRecord, int, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, 0, err
+	}
+	if tok == nil {
+		return nil, 0, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, 0, fmt.Errorf("servers: expected an array, found %v", tok)
+	}
+
+	var servers []serverRecord
+	skipped := 0
+	for i := 0; dec.More(); i++ {
+		var record serverRecord
+		if !tolerant {
+			if err := dec.Decode(&record); err != nil {
+				return nil, 0, fmt.Errorf("servers[%d]: %w", i, err)
+			}
+			servers = append(servers, record)
+			continue
+		}
+
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			return nil, 0, fmt.Errorf("servers[%d]: %w", i, err)
+		}
+		if err := json.Unmarshal(element, &record); err != nil {
+			log.Printf("Warning: skipping malformed server record at index %d: %v", i, err)
+			skipped++
+			continue
+		}
+		servers = append(servers, record)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, 0, err
+	}
+	if servers == nil {
+		servers = []serverRecord{}
+	}
+	return servers, skipped, nil
+}
+
+// ValidateJSONFile checks that a file can be loaded by JSONFileDB, without loading it.
+// Use it to vet a replacement file before swapping it in for a live database's file.
+func ValidateJSONFile(filePath string) error {
+	_, _, err := readJSONFile(filePath, false)
+	return err
+}
+
+// FilePath returns the path of the JSON file backing the database
+func (db *JSONFileDB) FilePath() string {
+	return db.filePath
+}
+
+// SkippedRecords returns the number of malformed server records skipped by the last load,
+// which is always 0 unless the database was opened WithTolerantLoad (thread-safe)
+func (db *JSONFileDB) SkippedRecords() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.skippedRecords
+}
+
+// Count returns the number of stored server records (thread-safe)
+func (db *JSONFileDB) Count() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return len(db.data.Servers)
 }
 
 // Reload reloads data from the JSON file (thread-safe)
 func (db *JSONFileDB) Reload() error {
+	return db.ReloadFrom(db.filePath)
+}
+
+// ReloadFrom reloads data from the JSON file and records source, such as the s3:// URI the file
+// was downloaded from, as the last successful sync (thread-safe). Versions published, updated or
+// removed locally that the file doesn't reflect yet are kept; see mergeUnsynced.
+func (db *JSONFileDB) ReloadFrom(source string) error {
+	_, err := db.ReloadFromWithChanges(source)
+	return err
+}
+
+// ReloadFromWithChanges reloads data like ReloadFrom and returns the changes the reload made to the stored
+// versions, as they are numbered in the changes feed (thread-safe)
+func (db *JSONFileDB) ReloadFromWithChanges(source string) ([]ReloadChange, error) {
+	// Wait for transactions in flight, refusing new ones with ErrReloading meanwhile, so no publish
+	// checks the old data and then writes to the new
+	db.reloadGate.Lock()
+	defer db.reloadGate.Unlock()
+
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -115,26 +415,365 @@ func (db *JSONFileDB) Reload() error {
 	db.loggedInvalid = make(map[string]bool)
 	db.loggedInvalidMu.Unlock()
 
-	return db.load()
+	previous := db.data
+	if err := db.load(); err != nil {
+		return nil, err
+	}
+	kept, keptRemoved := db.mergeUnsynced(previous, false)
+	changes := db.renumberReloaded(previous, keptRemoved)
+
+	// The in-memory data now matches the file again apart from the kept changes, which are logged anew
+	db.dirty = len(kept) > 0 || len(keptRemoved) > 0
+	if err := db.truncateWAL(); err != nil {
+		log.Printf("Warning: failed to truncate write-ahead log after reload: %v", err)
+	}
+	for i := range kept {
+		if err := db.logWAL(walEntry{Op: walPut, Record: &kept[i]}); err != nil {
+			log.Printf("Warning: failed to log kept local change after reload: %v", err)
+		}
+	}
+	for _, tombstone := range keptRemoved {
+		entry := walEntry{Op: walDelete, ServerName: tombstone.ServerName, Version: tombstone.Version, ChangeSeq: tombstone.ChangeSeq}
+		if err := db.logWAL(entry); err != nil {
+			log.Printf("Warning: failed to log kept local removal after reload: %v", err)
+		}
+	}
+	if len(kept) > 0 || len(keptRemoved) > 0 {
+		log.Printf("Kept %d locally published or updated and %d locally removed version(s) not yet in %s", len(kept), len(keptRemoved), source)
+	}
+	db.lastSync = &SyncStatus{At: time.Now(), Source: source}
+	return changes, nil
 }
 
-// save writes data to the JSON file
-func (db *JSONFileDB) save() error {
-	// Note: Actual implementation of saving to JSON file is omitted until ephemral writes succeed
-	/*
-		data, err := json.MarshalIndent(db.data.Servers, "", "  ")
-		if err != nil {
-			return err
+// mergeUnsynced carries the changes made locally over from previous, the data before the file was loaded, into
+// the freshly loaded data, returning the records and tombstones it kept. A local version is kept unless the
+// loaded data has it updated at least as recently, in which case the file has caught up with it; a local
+// removal is kept while the loaded data still has the version. The latest version of each server with kept
+// changes is then chosen again, see WithLatestPicker. With keepCaughtUp, as when merging before a write,
+// changes the file has caught up with stay local changes too, since the file's source, e.g. an S3 export, may
+// still lack them. Callers must hold db.mu for writing.
+func (db *JSONFileDB) mergeUnsynced(previous *jsonFileData, keepCaughtUp bool) ([]serverRecord, []removedRecord) {
+	servers := slices.Clone(db.data.Servers)
+	index := make(map[string]int, len(servers))
+	for i, record := range servers {
+		index[recordKey(record.ServerName, record.Version)] = i
+	}
+
+	var kept []serverRecord
+	for _, record := range previous.Servers {
+		if !record.unsynced {
+			continue
 		}
+		i, exists := index[recordKey(record.ServerName, record.Version)]
+		switch {
+		case !exists:
+			servers = append(servers, record)
+		case servers[i].UpdatedAt.Before(record.UpdatedAt),
+			keepCaughtUp && servers[i].UpdatedAt.Equal(record.UpdatedAt):
+			servers[i] = record
+		default:
+			continue
+		}
+		kept = append(kept, record)
+	}
 
-		// Write to temp file first, then rename (atomic on most systems)
-		tempFile := db.filePath + ".tmp"
-		if err := os.WriteFile(tempFile, data, 0644); err != nil {
-			return err
+	var keptRemoved []removedRecord
+	removedKeys := make(map[string]bool)
+	for _, tombstone := range previous.Removed {
+		key := recordKey(tombstone.ServerName, tombstone.Version)
+		if _, exists := index[key]; tombstone.unsynced && (exists || keepCaughtUp) {
+			keptRemoved = append(keptRemoved, tombstone)
+			removedKeys[key] = true
+		}
+	}
+	servers = slices.DeleteFunc(servers, func(r serverRecord) bool { return removedKeys[recordKey(r.ServerName, r.Version)] })
+
+	if db.pickLatest == nil {
+		for _, record := range kept {
+			if !record.IsLatest {
+				continue
+			}
+			for i := range servers {
+				if servers[i].ServerName == record.ServerName && servers[i].Version != record.Version {
+					servers[i].IsLatest = false
+					servers[i].LatestPinned = false
+				}
+			}
+		}
+	} else {
+		touched := make(map[string]bool, len(kept)+len(keptRemoved))
+		for _, record := range kept {
+			touched[record.ServerName] = true
 		}
+		for _, tombstone := range keptRemoved {
+			touched[tombstone.ServerName] = true
+		}
+		kept = db.repickLatest(servers, touched, kept)
+	}
+
+	db.data.Servers = servers
+	return kept, keptRemoved
+}
 
-		return os.Rename(tempFile, db.filePath)
-	*/
+// repickLatest marks the version pick chooses as the latest of each server in names, unless one is pinned, and adds
+// the records whose flags that changes to kept as local changes, replacing any kept record of the same version. Callers must hold
+// db.mu for writing.
+func (db *JSONFileDB) repickLatest(servers []serverRecord, names map[string]bool, kept []serverRecord) []serverRecord {
+	byName := make(map[string][]int, len(names))
+	for i := range servers {
+		if names[servers[i].ServerName] {
+			byName[servers[i].ServerName] = append(byName[servers[i].ServerName], i)
+		}
+	}
+
+	for _, indexes := range byName {
+		versions := make([]*apiv0.ServerResponse, len(indexes))
+		for j, i := range indexes {
+			versions[j] = servers[i].response()
+		}
+		// A version an administrator pinned stays the latest
+		latest := PinnedLatest(versions)
+		if latest == nil {
+			latest = db.pickLatest(versions)
+		}
+		for j, i := range indexes {
+			isLatest := versions[j] == latest
+			if servers[i].IsLatest == isLatest && (isLatest || !servers[i].LatestPinned) {
+				continue
+			}
+			servers[i].IsLatest = isLatest
+			servers[i].LatestPinned = isLatest && servers[i].LatestPinned
+			servers[i].unsynced = true
+			kept = slices.DeleteFunc(kept, func(r serverRecord) bool {
+				return r.ServerName == servers[i].ServerName && r.Version == servers[i].Version
+			})
+			kept = append(kept, servers[i])
+		}
+	}
+	return kept
+}
+
+// numberUnsequenced gives loaded records that have no change sequence number yet, such as those of files written
+// before the changes feed was numbered, the next ones in file order, and moves the sequence past every number in
+// use. Callers must hold db.mu for writing or have exclusive access to db.
+func (db *JSONFileDB) numberUnsequenced() {
+	for _, record := range db.data.Servers {
+		db.data.ChangeSeq = max(db.data.ChangeSeq, record.ChangeSeq)
+	}
+	for _, removed := range db.data.Removed {
+		db.data.ChangeSeq = max(db.data.ChangeSeq, removed.ChangeSeq)
+	}
+	for i := range db.data.Servers {
+		if db.data.Servers[i].ChangeSeq == 0 {
+			db.data.Servers[i].ChangeSeq = db.nextChangeSeq()
+		}
+	}
+}
+
+// renumberReloaded carries the changes feed on across a load that replaced previous: versions that are new or
+// differ from before get the next sequence numbers, unchanged ones keep theirs, and versions that are gone get
+// tombstones. The numbers and tombstones in the loaded file are another writer's and are replaced. Of the
+// earlier tombstones, those mergeUnsynced kept remain local removals. It returns the changes it numbered.
+// Callers must hold db.mu for writing.
+func (db *JSONFileDB) renumberReloaded(previous *jsonFileData, keptRemoved []removedRecord) []ReloadChange {
+	db.data.ChangeSeq = max(db.data.ChangeSeq, previous.ChangeSeq)
+
+	before := make(map[string]*serverRecord, len(previous.Servers))
+	for i := range previous.Servers {
+		before[recordKey(previous.Servers[i].ServerName, previous.Servers[i].Version)] = &previous.Servers[i]
+	}
+	servers := slices.Clone(db.data.Servers)
+	present := make(map[string]bool, len(servers))
+	var changes []ReloadChange
+	for i := range servers {
+		record := &servers[i]
+		key := recordKey(record.ServerName, record.Version)
+		present[key] = true
+		old, existed := before[key]
+		if existed && sameRecord(*old, *record) {
+			record.ChangeSeq = old.ChangeSeq
+		} else {
+			record.ChangeSeq = db.nextChangeSeq()
+			changes = append(changes, ReloadChange{Change: Change{Sequence: record.ChangeSeq, Server: record.response()}, Created: !existed})
+		}
+	}
+
+	unsynced := make(map[string]bool, len(keptRemoved))
+	for _, tombstone := range keptRemoved {
+		unsynced[recordKey(tombstone.ServerName, tombstone.Version)] = true
+	}
+	var removed []removedRecord
+	for _, tombstone := range previous.Removed {
+		key := recordKey(tombstone.ServerName, tombstone.Version)
+		if !present[key] {
+			tombstone.unsynced = unsynced[key]
+			removed = append(removed, tombstone)
+		}
+	}
+	for _, record := range previous.Servers {
+		if !present[recordKey(record.ServerName, record.Version)] {
+			tombstone := removedRecord{ServerName: record.ServerName, Version: record.Version, ChangeSeq: db.nextChangeSeq()}
+			removed = append(removed, tombstone)
+			changes = append(changes, ReloadChange{Change: Change{Sequence: tombstone.ChangeSeq, Removed: &ServerRef{Name: record.ServerName, Version: record.Version}}})
+		}
+	}
+	db.data.Servers = servers
+	db.data.Removed = removed
+	return changes
+}
+
+// recordKey identifies a server version in maps keyed by name and version
+func recordKey(serverName, version string) string {
+	return serverName + "@" + version
+}
+
+// sameRecord reports whether two records of a server version hold the same data, ignoring their change numbers
+func sameRecord(a, b serverRecord) bool {
+	a.ChangeSeq, b.ChangeSeq = 0, 0
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// nextChangeSeq hands out the next change sequence number. Callers must hold db.mu for writing.
+func (db *JSONFileDB) nextChangeSeq() int64 {
+	db.data.ChangeSeq++
+	return db.data.ChangeSeq
+}
+
+// LastSync returns the last successful load of the data file, and false if none has happened
+func (db *JSONFileDB) LastSync() (SyncStatus, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.lastSync == nil {
+		return SyncStatus{}, false
+	}
+	return *db.lastSync, true
+}
+
+// save records that the in-memory data has changed since it was last written to the JSON file.
+// Note: writing to the JSON file on every change is omitted until ephemeral writes succeed;
+// Flush persists pending changes on demand. While writes are failing, though, a change that isn't
+// write-ahead logged would only live in memory, so it is written through instead; if that fails too,
+// the data is restored to previous and the change is refused until the disk recovers.
+// Callers must hold db.mu for writing.
+func (db *JSONFileDB) save(previous jsonFileData) error {
+	db.dirty = true
+	if !db.writeFailing || db.wal != nil {
+		return nil
+	}
+
+	if err := db.writeFile(); err != nil {
+		*db.data = previous
+		return fmt.Errorf("failed to write %s, so the change was not applied: %v", db.filePath, err)
+	}
+	db.writeFailing = false
+	db.dirty = false
+	return nil
+}
+
+// Flush immediately writes the in-memory data to the JSON file (thread-safe).
+// It is a no-op when there are no pending changes.
+func (db *JSONFileDB) Flush(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.flushLocked()
+}
+
+// flushLocked writes pending changes to the JSON file and truncates the write-ahead log. Callers must hold
+// db.mu for writing.
+func (db *JSONFileDB) flushLocked() error {
+	if !db.dirty {
+		return nil
+	}
+
+	if err := db.writeFile(); err != nil {
+		db.writeFailing = true
+		return fmt.Errorf("%w: failed to flush %s: %v", ErrDatabase, db.filePath, err)
+	}
+	db.dirty = false
+	db.writeFailing = false
+
+	// The JSON file now holds every logged change
+	if err := db.truncateWAL(); err != nil {
+		return fmt.Errorf("%w: failed to truncate write-ahead log: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// writeFile writes data to the JSON file in the format read by load. It holds an exclusive lock from reading
+// the file to replacing it, so other processes sharing the file neither read it nor write it meanwhile, and
+// merges in whatever they wrote since this one last loaded or wrote it, so no process's write drops another's
+// changes. Callers must hold db.mu for writing.
+func (db *JSONFileDB) writeFile() error {
+	unlock, err := lockFile(db.filePath, true, db.fileLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := db.mergeFileChanges(); err != nil {
+		return fmt.Errorf("failed to merge changes from %s: %w", db.filePath, err)
+	}
+
+	var data []byte
+	if db.compact {
+		data, err = json.Marshal(db.data)
+	} else {
+		data, err = json.MarshalIndent(db.data, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Write to temp file first, then rename (atomic on most systems)
+	tempFile := db.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile, db.filePath); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(db.filePath)
+	if err != nil {
+		return err
+	}
+	db.fileInfo = info
+	return nil
+}
+
+// mergeFileChanges merges what other processes wrote to the JSON file since this one last loaded or wrote it
+// into the in-memory data, keeping every local change. Callers must hold db.mu for writing and the file's lock.
+func (db *JSONFileDB) mergeFileChanges() error {
+	info, err := os.Stat(db.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Writes replace the file, so the same file with the same modification time hasn't been written since
+	if db.fileInfo != nil && os.SameFile(info, db.fileInfo) && info.ModTime().Equal(db.fileInfo.ModTime()) {
+		return nil
+	}
+
+	fileData, _, err := readJSONFile(db.filePath, db.tolerantLoad)
+	if err != nil {
+		return err
+	}
+	if fileData == nil {
+		fileData = &jsonFileData{Servers: []serverRecord{}}
+	}
+
+	previous := db.data
+	db.data = fileData
+	_, keptRemoved := db.mergeUnsynced(previous, true)
+	db.renumberReloaded(previous, keptRemoved)
 	return nil
 }
 
@@ -167,13 +806,24 @@ func (db *JSONFileDB) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *a
 		PublishedAt: officialMeta.PublishedAt,
 		UpdatedAt:   officialMeta.UpdatedAt,
 		IsLatest:    officialMeta.IsLatest,
+		PublishedBy: officialMeta.PublishedBy,
 		Value:       serverJSON,
 		Meta:        officialMeta,
+		unsynced:    true,
 	}
 
-	db.data.Servers = append(db.data.Servers, record)
+	db.remember(tx, record.ServerName, record.Version)
+	record.ChangeSeq = db.data.ChangeSeq + 1
+	entry := walEntry{Op: walPut, Record: &record}
+	if err := db.logWAL(entry); err != nil {
+		return nil, fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	previous := *db.data
+	if err := db.applyWALEntry(entry); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
 
-	if err := db.save(); err != nil {
+	if err := db.save(previous); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
 	}
 
@@ -190,88 +840,170 @@ func (db *JSONFileDB) UpdateServer(ctx context.Context, tx pgx.Tx, serverName, v
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	for i := range db.data.Servers {
-		if db.data.Servers[i].ServerName == serverName && db.data.Servers[i].Version == version {
-			db.data.Servers[i].Value = serverJSON
-			db.data.Servers[i].UpdatedAt = time.Now()
+	previous := *db.data
+	db.remember(tx, serverName, version)
+	record, err := db.updateRecord(serverName, version, func(r *serverRecord) {
+		r.Value = serverJSON
+		r.UpdatedAt = time.Now()
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			if err := db.save(); err != nil {
-				return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
-			}
+	if err := db.save(previous); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+
+	return record.response(), nil
+}
+
+// SetServerStatus implements Database.SetServerStatus
+func (db *JSONFileDB) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status, reason string) (*apiv0.ServerResponse, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	previous := *db.data
+	db.remember(tx, serverName, version)
+	record, err := db.updateRecord(serverName, version, func(r *serverRecord) {
+		r.Status = status
+		r.StatusReason = reason
+		r.UpdatedAt = time.Now()
+		// A replacement pointer only makes sense while the server is deprecated
+		if status != string(model.StatusDeprecated) {
+			r.ReplacedBy = ""
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.save(previous); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+
+	return record.response(), nil
+}
+
+// DeprecateServer implements Database.DeprecateServer
+func (db *JSONFileDB) DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, replacedBy string) (*apiv0.ServerResponse, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	previous := *db.data
+	db.remember(tx, serverName, version)
+	record, err := db.updateRecord(serverName, version, func(r *serverRecord) {
+		r.Status = string(model.StatusDeprecated)
+		r.StatusReason = ""
+		r.ReplacedBy = replacedBy
+		r.UpdatedAt = time.Now()
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			return &apiv0.ServerResponse{
-				Server: *serverJSON,
-				Meta: apiv0.ResponseMeta{
-					Official: &apiv0.RegistryExtensions{
-						Status:      model.Status(db.data.Servers[i].Status),
-						PublishedAt: db.data.Servers[i].PublishedAt,
-						UpdatedAt:   db.data.Servers[i].UpdatedAt,
-						IsLatest:    db.data.Servers[i].IsLatest,
-					},
-				},
-			}, nil
-		}
+	if err := db.save(previous); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
 	}
 
-	return nil, ErrNotFound
+	return record.response(), nil
 }
 
-// SetServerStatus implements Database.SetServerStatus
-func (db *JSONFileDB) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status string) (*apiv0.ServerResponse, error) {
+// RenameServer implements Database.RenameServer
+func (db *JSONFileDB) RenameServer(ctx context.Context, tx pgx.Tx, serverName, newName string) ([]*apiv0.ServerResponse, error) {
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	for i := range db.data.Servers {
-		if db.data.Servers[i].ServerName == serverName && db.data.Servers[i].Version == version {
-			db.data.Servers[i].Status = status
-			db.data.Servers[i].UpdatedAt = time.Now()
+	now := time.Now()
+	var renamed []serverRecord
+	for _, record := range db.data.Servers {
+		if record.ServerName != serverName {
+			continue
+		}
+		value := *record.Value
+		value.Name = newName
+		record.ServerName = newName
+		record.Value = &value
+		record.UpdatedAt = now
+		record.unsynced = true
+		renamed = append(renamed, record)
+	}
+	if len(renamed) == 0 {
+		return nil, ErrNotFound
+	}
 
-			if err := db.save(); err != nil {
-				return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
-			}
+	for _, record := range renamed {
+		db.remember(tx, serverName, record.Version)
+		db.remember(tx, newName, record.Version)
+	}
+	entry := walEntry{Op: walRename, ServerName: serverName, Records: renamed, ChangeSeq: db.data.ChangeSeq + 1}
+	if err := db.logWAL(entry); err != nil {
+		return nil, fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	previous := *db.data
+	if err := db.applyWALEntry(entry); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
 
-			return &apiv0.ServerResponse{
-				Server: *db.data.Servers[i].Value,
-				Meta: apiv0.ResponseMeta{
-					Official: &apiv0.RegistryExtensions{
-						Status:      model.Status(db.data.Servers[i].Status),
-						PublishedAt: db.data.Servers[i].PublishedAt,
-						UpdatedAt:   db.data.Servers[i].UpdatedAt,
-						IsLatest:    db.data.Servers[i].IsLatest,
-					},
-				},
-			}, nil
-		}
+	if err := db.save(previous); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabase, err)
 	}
 
-	return nil, ErrNotFound
+	results := make([]*apiv0.ServerResponse, len(renamed))
+	for i := range renamed {
+		results[i] = renamed[i].response()
+	}
+	return results, nil
 }
 
+// ctxCheckInterval is how many records long scans iterate between checks for a cancelled context
+const ctxCheckInterval = 1024
+
 // ListServers implements Database.ListServers
 func (db *JSONFileDB) ListServers(ctx context.Context, tx pgx.Tx, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error) {
-	db.mu.RLock()
-	defer db.mu.RUnlock()
+	// Iterate a snapshot so long listings don't hold the lock against writers
+	servers := db.snapshot()
 
-	var results []*apiv0.ServerResponse
-	var startIndex int
+	// Paginate in a stable order, independent of storage order, so page boundaries don't shift when
+	// the data is reloaded or rewritten between pages
+	order, err := listOrder(ctx, servers)
+	if err != nil {
+		return nil, "", err
+	}
 
-	// Handle cursor
-	if cursor != "" {
-		parts := strings.Split(cursor, ":")
-		if len(parts) == 2 {
-			cursorName, cursorVersion := parts[0], parts[1]
-			for i, record := range db.data.Servers {
-				if record.ServerName == cursorName && record.Version == cursorVersion {
-					startIndex = i + 1
-					break
-				}
+	// Fuzzy matches are ranked by similarity rather than paginated, so they are collected in full and sorted
+	fuzzy := filter != nil && filter.FuzzyName != nil
+	var scores []float64
+
+	// Handle cursor: start after the cursor's position in the order, even if that record no longer exists
+	var startIndex int
+	if cursor != "" && !fuzzy {
+		// Server names never contain ':', so split on the first one to allow versions that do
+		cursorName, cursorVersion, ok := strings.Cut(cursor, ":")
+		startIndex = sort.Search(len(order), func(i int) bool {
+			record := &servers[order[i]]
+			if !ok {
+				// Fall back to treating a malformed cursor as a server name, as the PostgreSQL backend does
+				return record.ServerName > cursor
 			}
-		}
+			return compareRecordKeys(record.ServerName, record.Version, cursorName, cursorVersion) > 0
+		})
+	}
+
+	// Offset pagination skips that many matches instead of starting after a cursor
+	var skip int
+	if filter != nil && cursor == "" && !fuzzy {
+		skip = filter.Offset
 	}
 
 	// Filter and collect results
-	for i := startIndex; i < len(db.data.Servers); i++ {
-		record := db.data.Servers[i]
+	var results []*apiv0.ServerResponse
+	for i := startIndex; i < len(order); i++ {
+		// Stop promptly if the client has gone away
+		if (i-startIndex)%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+
+		record := servers[order[i]]
 
 		// Skip records with nil Value (corrupted or incompatible data)
 		if record.Value == nil {
@@ -280,7 +1012,7 @@ func (db *JSONFileDB) ListServers(ctx context.Context, tx pgx.Tx, filter *Server
 			db.loggedInvalidMu.Lock()
 			if !db.loggedInvalid[recordKey] {
 				log.Printf("Warning: Skipping invalid server record at index %d (ServerName: %s, Version: %s) - nil Value field, possibly due to incompatible data format",
-					i, record.ServerName, record.Version)
+					order[i], record.ServerName, record.Version)
 				db.loggedInvalid[recordKey] = true
 			}
 			db.loggedInvalidMu.Unlock()
@@ -298,12 +1030,36 @@ func (db *JSONFileDB) ListServers(ctx context.Context, tx pgx.Tx, filter *Server
 			if filter.IsLatest != nil && record.IsLatest != *filter.IsLatest {
 				continue
 			}
-			if filter.SubstringName != nil && !strings.Contains(strings.ToLower(record.ServerName), strings.ToLower(*filter.SubstringName)) {
+			if filter.Status != nil && record.Status != *filter.Status {
+				continue
+			}
+			if filter.SubstringName != nil && !matchesSubstring(record.ServerName, *filter.SubstringName) {
+				continue
+			}
+			if filter.NamePrefix != nil && !strings.HasPrefix(record.ServerName, *filter.NamePrefix) {
 				continue
 			}
 			if filter.UpdatedSince != nil && !record.UpdatedAt.After(*filter.UpdatedSince) {
 				continue
 			}
+			if filter.HasRemotes != nil && (len(record.Value.Remotes) > 0) != *filter.HasRemotes {
+				continue
+			}
+			if filter.HasPackages != nil && (len(record.Value.Packages) > 0) != *filter.HasPackages {
+				continue
+			}
+			if filter.HasProvenance != nil && hasAttestations(record.Value) != *filter.HasProvenance {
+				continue
+			}
+			if filter.Tag != nil && !hasTag(record.Value, *filter.Tag) {
+				continue
+			}
+			if filter.TransportType != nil && !hasRemoteTransport(record.Value, *filter.TransportType) {
+				continue
+			}
+			if filter.Author != nil && !strings.EqualFold(repositoryOwner(record.Value), *filter.Author) && !strings.EqualFold(record.PublishedBy, *filter.Author) {
+				continue
+			}
 			if filter.RemoteURL != nil {
 				found := false
 				for _, remote := range record.Value.Remotes {
@@ -318,51 +1074,159 @@ func (db *JSONFileDB) ListServers(ctx context.Context, tx pgx.Tx, filter *Server
 			}
 		}
 
-		results = append(results, &apiv0.ServerResponse{
-			Server: *record.Value,
-			Meta: apiv0.ResponseMeta{
-				Official: &apiv0.RegistryExtensions{
-					Status:      model.Status(record.Status),
-					PublishedAt: record.PublishedAt,
-					UpdatedAt:   record.UpdatedAt,
-					IsLatest:    record.IsLatest,
-				},
-			},
-		})
+		if fuzzy {
+			score := nameSimilarity(record.ServerName, *filter.FuzzyName)
+			if score < filter.MinSimilarity {
+				continue
+			}
+			scores = append(scores, score)
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		results = append(results, record.response())
 
-		if len(results) >= limit {
+		if !fuzzy && len(results) >= limit {
 			break
 		}
 	}
 
-	// Generate next cursor
+	if fuzzy {
+		return rankBySimilarity(results, scores, limit), "", nil
+	}
+
+	// Generate next cursor from the last emitted record, since filters may have skipped
+	// records in the underlying array
 	var nextCursor string
-	if len(results) == limit && startIndex+len(results) < len(db.data.Servers) {
-		lastRecord := db.data.Servers[startIndex+len(results)-1]
-		nextCursor = fmt.Sprintf("%s:%s", lastRecord.ServerName, lastRecord.Version)
+	if len(results) > 0 && len(results) >= limit {
+		lastResult := results[len(results)-1]
+		nextCursor = lastResult.Server.Name + ":" + lastResult.Server.Version
 	}
 
 	return results, nextCursor, nil
 }
 
-// GetServerByName implements Database.GetServerByName (returns latest version)
-func (db *JSONFileDB) GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error) {
+// rankBySimilarity returns the limit results with the highest scores, best first. Results are already in
+// name and version order, which the stable sort keeps for equal scores, as the PostgreSQL backend does.
+func rankBySimilarity(results []*apiv0.ServerResponse, scores []float64, limit int) []*apiv0.ServerResponse {
+	ranked := make([]int, len(results))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+
+	top := make([]*apiv0.ServerResponse, 0, min(limit, len(ranked)))
+	for _, i := range ranked[:min(limit, len(ranked))] {
+		top = append(top, results[i])
+	}
+	return top
+}
+
+// listOrder returns the indexes of servers sorted by server name, then version, matching the order
+// of the PostgreSQL backend's listings
+func listOrder(ctx context.Context, servers []serverRecord) ([]int, error) {
+	order := make([]int, len(servers))
+	for i := range order {
+		if i%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return compareRecordKeys(servers[a].ServerName, servers[a].Version, servers[b].ServerName, servers[b].Version)
+	})
+	return order, nil
+}
+
+// compareRecordKeys orders server records by name, then version
+func compareRecordKeys(nameA, versionA, nameB, versionB string) int {
+	if c := strings.Compare(nameA, nameB); c != 0 {
+		return c
+	}
+	return strings.Compare(versionA, versionB)
+}
+
+// ListChanges implements Database.ListChanges
+func (db *JSONFileDB) ListChanges(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]Change, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
 	db.mu.RLock()
-	defer db.mu.RUnlock()
+	servers, removed := db.data.Servers, db.data.Removed
+	db.mu.RUnlock()
+
+	// Collect the changes by sequence number first, so only the page returned is converted to responses
+	type change struct {
+		seq     int64
+		record  *serverRecord
+		removed *removedRecord
+	}
+	var changed []change
+	for i := range servers {
+		if i%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if servers[i].ChangeSeq > after {
+			changed = append(changed, change{seq: servers[i].ChangeSeq, record: &servers[i]})
+		}
+	}
+	for i := range removed {
+		if removed[i].ChangeSeq > after {
+			changed = append(changed, change{seq: removed[i].ChangeSeq, removed: &removed[i]})
+		}
+	}
 
-	for _, record := range db.data.Servers {
+	slices.SortFunc(changed, func(a, b change) int { return cmp.Compare(a.seq, b.seq) })
+	if len(changed) > limit {
+		changed = changed[:limit]
+	}
+
+	results := make([]Change, len(changed))
+	for i, c := range changed {
+		results[i].Sequence = c.seq
+		if c.record != nil {
+			results[i].Server = c.record.response()
+		} else {
+			results[i].Removed = &ServerRef{Name: c.removed.ServerName, Version: c.removed.Version}
+		}
+	}
+	return results, nil
+}
+
+// ListServerNames implements Database.ListServerNames
+func (db *JSONFileDB) ListServerNames(ctx context.Context, tx pgx.Tx, prefix *string, cursor string, limit int) ([]string, string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, record := range db.snapshot() {
+		name := record.ServerName
+		if seen[name] || (prefix != nil && !strings.HasPrefix(name, *prefix)) || (cursor != "" && name <= cursor) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var nextCursor string
+	if limit > 0 && len(names) >= limit {
+		names = names[:limit]
+		nextCursor = names[limit-1]
+	}
+
+	return names, nextCursor, nil
+}
+
+// GetServerByName implements Database.GetServerByName (returns latest version)
+func (db *JSONFileDB) GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error) {
+	for _, record := range db.snapshot() {
 		if record.ServerName == serverName && record.IsLatest {
-			return &apiv0.ServerResponse{
-				Server: *record.Value,
-				Meta: apiv0.ResponseMeta{
-					Official: &apiv0.RegistryExtensions{
-						Status:      model.Status(record.Status),
-						PublishedAt: record.PublishedAt,
-						UpdatedAt:   record.UpdatedAt,
-						IsLatest:    record.IsLatest,
-					},
-				},
-			}, nil
+			return record.response(), nil
 		}
 	}
 
@@ -371,47 +1235,65 @@ func (db *JSONFileDB) GetServerByName(ctx context.Context, tx pgx.Tx, serverName
 
 // GetServerByNameAndVersion implements Database.GetServerByNameAndVersion
 func (db *JSONFileDB) GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx, serverName string, version string) (*apiv0.ServerResponse, error) {
-	db.mu.RLock()
-	defer db.mu.RUnlock()
-
-	for _, record := range db.data.Servers {
+	for _, record := range db.snapshot() {
 		if record.ServerName == serverName && record.Version == version {
-			return &apiv0.ServerResponse{
-				Server: *record.Value,
-				Meta: apiv0.ResponseMeta{
-					Official: &apiv0.RegistryExtensions{
-						Status:      model.Status(record.Status),
-						PublishedAt: record.PublishedAt,
-						UpdatedAt:   record.UpdatedAt,
-						IsLatest:    record.IsLatest,
-					},
-				},
-			}, nil
+			return record.response(), nil
 		}
 	}
 
 	return nil, ErrNotFound
 }
 
+// GetServerVersions implements Database.GetServerVersions
+func (db *JSONFileDB) GetServerVersions(ctx context.Context, tx pgx.Tx, refs []ServerRef) ([]*apiv0.ServerResponse, error) {
+	servers := db.snapshot()
+	byRef := make(map[ServerRef]*serverRecord, len(servers))
+	for i := range servers {
+		byRef[ServerRef{Name: servers[i].ServerName, Version: servers[i].Version}] = &servers[i]
+	}
+
+	var results []*apiv0.ServerResponse
+	for _, ref := range refs {
+		if record, ok := byRef[ref]; ok {
+			results = append(results, record.response())
+		}
+	}
+	return results, nil
+}
+
+// GetVersionsAsOf implements Database.GetVersionsAsOf
+func (db *JSONFileDB) GetVersionsAsOf(ctx context.Context, tx pgx.Tx, serverName string, at time.Time) ([]*apiv0.ServerResponse, error) {
+	var results []*apiv0.ServerResponse
+	for i, record := range db.snapshot() {
+		if i%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if record.ServerName == serverName && record.Value != nil && !record.PublishedAt.After(at) {
+			results = append(results, record.response())
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	// Sort by published_at descending, matching PostgreSQL
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Meta.Official.PublishedAt.After(results[j].Meta.Official.PublishedAt)
+	})
+
+	return results, nil
+}
+
 // GetAllVersionsByServerName implements Database.GetAllVersionsByServerName
 func (db *JSONFileDB) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
-	db.mu.RLock()
-	defer db.mu.RUnlock()
-
 	var results []*apiv0.ServerResponse
-	for _, record := range db.data.Servers {
+	for i, record := range db.snapshot() {
+		if i%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if record.ServerName == serverName {
-			results = append(results, &apiv0.ServerResponse{
-				Server: *record.Value,
-				Meta: apiv0.ResponseMeta{
-					Official: &apiv0.RegistryExtensions{
-						Status:      model.Status(record.Status),
-						PublishedAt: record.PublishedAt,
-						UpdatedAt:   record.UpdatedAt,
-						IsLatest:    record.IsLatest,
-					},
-				},
-			})
+			results = append(results, record.response())
 		}
 	}
 
@@ -434,11 +1316,8 @@ func (db *JSONFileDB) GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, se
 
 // CountServerVersions implements Database.CountServerVersions
 func (db *JSONFileDB) CountServerVersions(ctx context.Context, tx pgx.Tx, serverName string) (int, error) {
-	db.mu.RLock()
-	defer db.mu.RUnlock()
-
 	count := 0
-	for _, record := range db.data.Servers {
+	for _, record := range db.snapshot() {
 		if record.ServerName == serverName {
 			count++
 		}
@@ -447,12 +1326,19 @@ func (db *JSONFileDB) CountServerVersions(ctx context.Context, tx pgx.Tx, server
 	return count, nil
 }
 
+// CountServerNames implements Database.CountServerNames
+func (db *JSONFileDB) CountServerNames(ctx context.Context, tx pgx.Tx) (int, error) {
+	names := make(map[string]struct{})
+	for _, record := range db.snapshot() {
+		names[record.ServerName] = struct{}{}
+	}
+
+	return len(names), nil
+}
+
 // CheckVersionExists implements Database.CheckVersionExists
 func (db *JSONFileDB) CheckVersionExists(ctx context.Context, tx pgx.Tx, serverName, version string) (bool, error) {
-	db.mu.RLock()
-	defer db.mu.RUnlock()
-
-	for _, record := range db.data.Servers {
+	for _, record := range db.snapshot() {
 		if record.ServerName == serverName && record.Version == version {
 			return true, nil
 		}
@@ -466,19 +1352,205 @@ func (db *JSONFileDB) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
+	if !slices.ContainsFunc(db.data.Servers, func(r serverRecord) bool {
+		return r.ServerName == serverName && (r.IsLatest || r.LatestPinned)
+	}) {
+		return nil // Not an error, just nothing to do
+	}
+
+	db.rememberServer(tx, serverName)
+	entry := walEntry{Op: walUnmarkLatest, ServerName: serverName, ChangeSeq: db.data.ChangeSeq + 1}
+	if err := db.logWAL(entry); err != nil {
+		return fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	previous := *db.data
+	if err := db.applyWALEntry(entry); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+
+	if err := db.save(previous); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// SetLatestVersion implements Database.SetLatestVersion
+func (db *JSONFileDB) SetLatestVersion(ctx context.Context, tx pgx.Tx, serverName, version string, pinned bool) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
 	found := false
-	for i := range db.data.Servers {
-		if db.data.Servers[i].ServerName == serverName && db.data.Servers[i].IsLatest {
-			db.data.Servers[i].IsLatest = false
+	changed := 0
+	for _, record := range db.data.Servers {
+		if record.ServerName != serverName {
+			continue
+		}
+		if record.Version == version {
 			found = true
 		}
+		if record.IsLatest != (record.Version == version) || record.LatestPinned != (record.Version == version && pinned) {
+			changed++
+		}
 	}
-
 	if !found {
-		return nil // Not an error, just nothing to do
+		return 0, ErrNotFound
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	db.rememberServer(tx, serverName)
+	entry := walEntry{Op: walSetLatest, ServerName: serverName, Version: version, Pinned: pinned, ChangeSeq: db.data.ChangeSeq + 1}
+	if err := db.logWAL(entry); err != nil {
+		return 0, fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	previous := *db.data
+	if err := db.applyWALEntry(entry); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+
+	if err := db.save(previous); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+
+	return changed, nil
+}
+
+// DeleteServerVersion implements Database.DeleteServerVersion
+func (db *JSONFileDB) DeleteServerVersion(ctx context.Context, tx pgx.Tx, serverName, version string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !slices.ContainsFunc(db.data.Servers, func(r serverRecord) bool {
+		return r.ServerName == serverName && r.Version == version
+	}) {
+		return ErrNotFound
+	}
+
+	db.remember(tx, serverName, version)
+	entry := walEntry{Op: walDelete, ServerName: serverName, Version: version, ChangeSeq: db.data.ChangeSeq + 1}
+	if err := db.logWAL(entry); err != nil {
+		return fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	previous := *db.data
+	if err := db.applyWALEntry(entry); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabase, err)
 	}
 
-	return db.save()
+	if err := db.save(previous); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// remember adds a server version to the undo log of tx, a transaction from InTransaction, unless tx already
+// changed it, so rolling tx back restores it. Callers must hold db.mu for writing.
+func (db *JSONFileDB) remember(tx pgx.Tx, serverName, version string) {
+	jtx, ok := tx.(*jsonTx)
+	if !ok {
+		return
+	}
+	key := recordKey(serverName, version)
+	if jtx.undoKeys[key] {
+		return
+	}
+	if jtx.undoKeys == nil {
+		jtx.undoKeys = make(map[string]bool)
+	}
+	jtx.undoKeys[key] = true
+
+	undo := undoRecord{serverName: serverName, version: version}
+	if i := slices.IndexFunc(db.data.Servers, func(r serverRecord) bool {
+		return r.ServerName == serverName && r.Version == version
+	}); i >= 0 {
+		before := db.data.Servers[i]
+		undo.before = &before
+	}
+	jtx.undo = append(jtx.undo, undo)
+}
+
+// rememberServer adds every version of a server to the undo log of tx, see remember. Callers must hold db.mu
+// for writing.
+func (db *JSONFileDB) rememberServer(tx pgx.Tx, serverName string) {
+	for _, record := range db.data.Servers {
+		if record.ServerName == serverName {
+			db.remember(tx, serverName, record.Version)
+		}
+	}
+}
+
+// rollback restores the server versions tx changed to how they were before it. The restorations are logged
+// and numbered like any other change, so the write-ahead log replays them and the changes feed reports them.
+// The transaction still holds its publish locks, so no other transaction changed those versions meanwhile.
+func (tx *jsonTx) rollback() error {
+	db := tx.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	previous := *db.data
+	for _, undo := range slices.Backward(tx.undo) {
+		i := slices.IndexFunc(db.data.Servers, func(r serverRecord) bool {
+			return r.ServerName == undo.serverName && r.Version == undo.version
+		})
+
+		var entry walEntry
+		switch {
+		case undo.before != nil:
+			if i >= 0 && sameRecord(db.data.Servers[i], *undo.before) {
+				continue
+			}
+			record := *undo.before
+			record.ChangeSeq = db.data.ChangeSeq + 1
+			entry = walEntry{Op: walPut, Record: &record}
+		case i >= 0:
+			entry = walEntry{Op: walDelete, ServerName: undo.serverName, Version: undo.version, ChangeSeq: db.data.ChangeSeq + 1}
+		default:
+			continue
+		}
+
+		if err := db.logWAL(entry); err != nil {
+			return fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+		}
+		if err := db.applyWALEntry(entry); err != nil {
+			return fmt.Errorf("%w: %v", ErrDatabase, err)
+		}
+	}
+
+	if err := db.save(previous); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// snapshot returns the current server records for reading without holding the lock.
+// Writers never modify a published slice in place, so a snapshot stays consistent for as long
+// as the caller keeps it, at the cost of copying the slice header only.
+func (db *JSONFileDB) snapshot() []serverRecord {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.data.Servers
+}
+
+// updateRecord applies update to a copy of the record for serverName and version, publishing a new
+// slice so snapshots held by readers are untouched. Callers must hold db.mu for writing.
+func (db *JSONFileDB) updateRecord(serverName, version string, update func(*serverRecord)) (*serverRecord, error) {
+	i := slices.IndexFunc(db.data.Servers, func(r serverRecord) bool {
+		return r.ServerName == serverName && r.Version == version
+	})
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	servers := slices.Clone(db.data.Servers)
+	update(&servers[i])
+	servers[i].unsynced = true
+	servers[i].ChangeSeq = db.data.ChangeSeq + 1
+	if err := db.logWAL(walEntry{Op: walPut, Record: &servers[i]}); err != nil {
+		return nil, fmt.Errorf("%w: failed to write ahead: %v", ErrDatabase, err)
+	}
+	db.data.ChangeSeq = servers[i].ChangeSeq
+	db.data.Servers = servers
+	return &servers[i], nil
 }
 
 // AcquirePublishLock implements Database.AcquirePublishLock
@@ -488,15 +1560,11 @@ func (db *JSONFileDB) AcquirePublishLock(ctx context.Context, tx pgx.Tx, serverN
 	h.Write([]byte(serverName))
 	lockID := h.Sum64()
 
-	db.locksMu.Lock()
-	if _, exists := db.locks[lockID]; !exists {
-		db.locks[lockID] = &sync.Mutex{}
+	// Acquire the lock, waiting while it is held for as long as ctx and the lock timeout allow
+	lock, err := db.acquirePublishLock(ctx, lockID, serverName)
+	if err != nil {
+		return err
 	}
-	lock := db.locks[lockID]
-	db.locksMu.Unlock()
-
-	// Acquire the lock (will block if already held)
-	lock.Lock()
 
 	// Store the lock in the transaction context so we can release it later
 	if jtx, ok := tx.(*jsonTx); ok {
@@ -506,22 +1574,33 @@ func (db *JSONFileDB) AcquirePublishLock(ctx context.Context, tx pgx.Tx, serverN
 	return nil
 }
 
-// InTransaction implements Database.InTransaction
+// InTransaction implements Database.InTransaction. Transactions are refused with ErrReloading
+// while a reload is waiting to swap in new data or doing so. When fn fails, the server versions it
+// changed are restored, as PostgreSQL would roll them back.
 func (db *JSONFileDB) InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	if !db.reloadGate.TryRLock() {
+		return fmt.Errorf("%w: %w", ErrDatabase, ErrReloading)
+	}
+	defer db.reloadGate.RUnlock()
+
 	tx := &jsonTx{
 		db:    db,
-		locks: make([]*sync.Mutex, 0),
+		locks: make([]*publishLock, 0),
 	}
 
 	defer func() {
 		// Release all locks acquired during the transaction
 		for _, lock := range tx.locks {
-			lock.Unlock()
+			lock.unlock()
 		}
 	}()
 
 	err := fn(ctx, tx)
 	if err != nil {
+		// Undo the changes fn made before failing while its locks are still held
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			log.Printf("Warning: failed to roll back a failed transaction: %v", rollbackErr)
+		}
 		tx.rolledBack = true
 		return err
 	}
@@ -532,14 +1611,22 @@ func (db *JSONFileDB) InTransaction(ctx context.Context, fn func(ctx context.Con
 
 // Close implements Database.Close
 func (db *JSONFileDB) Close() error {
-	// Final save on close
+	// Final flush on close. The write-ahead log is closed even if it fails, keeping the unflushed changes
+	// to replay on the next start.
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	return db.save()
+	flushErr := db.flushLocked()
+	if db.wal != nil {
+		if err := db.wal.Close(); err != nil && flushErr == nil {
+			return err
+		}
+		db.wal = nil
+	}
+	return flushErr
 }
 
 // addLock adds a lock to the transaction's list of held locks
-func (tx *jsonTx) addLock(lock *sync.Mutex) {
+func (tx *jsonTx) addLock(lock *publishLock) {
 	tx.locks = append(tx.locks, lock)
 }
 
@@ -565,3 +1652,37 @@ func (tx *jsonTx) QueryRow(ctx context.Context, sql string, args ...interface{})
 	return nil
 }
 func (tx *jsonTx) Conn() *pgx.Conn { return nil }
+
+// hasTag reports whether a server carries a tag
+func hasTag(server *apiv0.ServerJSON, tag string) bool {
+	return server.Meta != nil && slices.Contains(server.Meta.Tags, tag)
+}
+
+// hasRemoteTransport reports whether a server has a remote of the given transport type
+func hasRemoteTransport(server *apiv0.ServerJSON, transportType string) bool {
+	if server == nil {
+		return false
+	}
+	return slices.ContainsFunc(server.Remotes, func(remote model.Transport) bool {
+		return remote.Type == transportType
+	})
+}
+
+// repositoryOwner returns the first path segment of a server's repository URL, the user or organization that
+// maintains it on forges such as GitHub, or "" when the server has no repository URL
+func repositoryOwner(server *apiv0.ServerJSON) string {
+	if server == nil || server.Repository == nil {
+		return ""
+	}
+	repoURL, err := url.Parse(server.Repository.URL)
+	if err != nil || repoURL.Host == "" {
+		return ""
+	}
+	owner, _, _ := strings.Cut(strings.TrimPrefix(repoURL.Path, "/"), "/")
+	return owner
+}
+
+// hasAttestations reports whether a server carries at least one provenance attestation
+func hasAttestations(server *apiv0.ServerJSON) bool {
+	return server.Meta != nil && server.Meta.Provenance != nil && len(server.Meta.Provenance.Attestations) > 0
+}
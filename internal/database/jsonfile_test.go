@@ -3,10 +3,18 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
@@ -146,3 +154,1479 @@ func TestListServers_WithRemoteURLFilter_NilValue(t *testing.T) {
 	assert.Len(t, results, 1, "Should find 1 server with matching remote URL")
 	assert.Equal(t, "io.github.test/remote-server", results[0].Server.Name)
 }
+
+// TestListServers_FilteredPagination tests that paginating a heavily filtered result set
+// returns every matching record exactly once
+func TestListServers_FilteredPagination(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	const sharedURL = "https://example.com/mcp"
+	want := map[string]bool{}
+	for i := 0; i < 40; i++ {
+		server := &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        fmt.Sprintf("com.example/server-%02d", i),
+			Description: "Pagination test server",
+			Version:     "1.0.0",
+		}
+		// Only every third server matches the filters
+		if i%3 == 0 {
+			server.Remotes = []model.Transport{{Type: "streamable-http", URL: sharedURL}}
+			server.Name = fmt.Sprintf("com.example/match-%02d", i)
+			want[server.Name+":"+server.Version] = true
+		} else {
+			server.Remotes = []model.Transport{{Type: "streamable-http", URL: fmt.Sprintf("https://example.com/other-%d", i)}}
+		}
+		_, err := db.CreateServer(ctx, nil, server, nil)
+		require.NoError(t, err)
+	}
+
+	remoteURL := sharedURL
+	substring := "match"
+	filter := &ServerFilter{RemoteURL: &remoteURL, SubstringName: &substring}
+
+	for _, limit := range []int{1, 2, 4, 5, 100} {
+		seen := map[string]int{}
+		cursor := ""
+		for page := 0; page < 100; page++ {
+			results, nextCursor, err := db.ListServers(ctx, nil, filter, cursor, limit)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(results), limit)
+			for _, r := range results {
+				seen[r.Server.Name+":"+r.Server.Version]++
+			}
+			if nextCursor == "" {
+				break
+			}
+			last := results[len(results)-1]
+			assert.Equal(t, last.Server.Name+":"+last.Server.Version, nextCursor)
+			cursor = nextCursor
+		}
+
+		assert.Len(t, seen, len(want), "limit %d", limit)
+		for key, count := range seen {
+			assert.True(t, want[key], "unexpected record %s with limit %d", key, limit)
+			assert.Equal(t, 1, count, "record %s returned %d times with limit %d", key, count, limit)
+		}
+	}
+}
+
+// TestListServers_AccentInsensitiveSearch tests that substring search ignores case and diacritics
+// on both the server name and the query
+func TestListServers_AccentInsensitiveSearch(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	for _, name := range []string{"com.example/café-tools", "com.example/cafe-menu", "com.example/NAÏVE-search", "com.example/ﬁle-server", "com.example/tea"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Accent search test server",
+			Version:     "1.0.0",
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"cafe", []string{"com.example/cafe-menu", "com.example/café-tools"}},
+		{"café", []string{"com.example/cafe-menu", "com.example/café-tools"}},
+		{"CAFÉ-T", []string{"com.example/café-tools"}},
+		{"naive", []string{"com.example/NAÏVE-search"}},
+		{"file", []string{"com.example/ﬁle-server"}}, // the ligature decomposes to "fi"
+		{"tèa", []string{"com.example/tea"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := tt.query
+			results, _, err := db.ListServers(ctx, nil, &ServerFilter{SubstringName: &query}, "", 100)
+			require.NoError(t, err)
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Server.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestListServers_FuzzySearch(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	for _, name := range []string{"io.github.user/weather", "io.github.user/weather-alerts", "io.github.user/filesystem", "com.example/leather-goods"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Fuzzy search test server",
+			Version:     "1.0.0",
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	search := func(t *testing.T, query string, minSimilarity float64, limit int) []string {
+		t.Helper()
+		results, cursor, err := db.ListServers(ctx, nil, &ServerFilter{FuzzyName: &query, MinSimilarity: minSimilarity}, "", limit)
+		require.NoError(t, err)
+		assert.Empty(t, cursor, "fuzzy results are not paginated")
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Server.Name)
+		}
+		return names
+	}
+
+	// The misspelled query matches the intended server, but not unrelated names that share a few trigrams
+	assert.Equal(t, []string{"io.github.user/weather"}, search(t, "wether", DefaultMinSimilarity, 10))
+	// A looser threshold also admits weaker matches, ranked after the closest one
+	assert.Equal(t, []string{"io.github.user/weather", "io.github.user/weather-alerts"}, search(t, "wether", 0.2, 10))
+	// The limit keeps the best matches
+	assert.Equal(t, []string{"io.github.user/weather"}, search(t, "wether", 0.2, 1))
+	// Typos elsewhere in a name are tolerated too
+	assert.Equal(t, []string{"io.github.user/filesystem"}, search(t, "filesytem", DefaultMinSimilarity, 10))
+	assert.Empty(t, search(t, "unrelated", DefaultMinSimilarity, 10))
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	// Values match PostgreSQL's pg_trgm similarity()
+	assert.InDelta(t, 0.363636, trigramSimilarity("word", "two words"), 0.0001)
+	assert.InDelta(t, 1.0, trigramSimilarity("Weather", "weather"), 0.0001)
+	assert.InDelta(t, 0.5, trigramSimilarity("weather", "wether"), 0.0001)
+	assert.Zero(t, trigramSimilarity("", ""))
+}
+
+// TestListServers_HasRemotesAndPackages tests filtering on whether servers have remotes or packages
+func TestListServers_HasRemotesAndPackages(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	remote := model.Transport{Type: "streamable-http", URL: "https://example.com/mcp"}
+	pkg := model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "@example/server", Version: "1.0.0", Transport: model.Transport{Type: "stdio"}}
+	servers := []*apiv0.ServerJSON{
+		{Name: "com.example/remote-only", Remotes: []model.Transport{remote}},
+		{Name: "com.example/packages-only", Packages: []model.Package{pkg}},
+		{Name: "com.example/both", Remotes: []model.Transport{{Type: "sse", URL: "https://example.com/sse"}}, Packages: []model.Package{pkg}},
+		{Name: "com.example/neither", Remotes: []model.Transport{}},
+	}
+	for _, server := range servers {
+		server.Schema = model.CurrentSchemaURL
+		server.Description = "Transport filter test server"
+		server.Version = "1.0.0"
+		_, err := db.CreateServer(ctx, nil, server, nil)
+		require.NoError(t, err)
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter ServerFilter
+		want   []string
+	}{
+		{"has remotes", ServerFilter{HasRemotes: &yes}, []string{"com.example/both", "com.example/remote-only"}},
+		{"no remotes", ServerFilter{HasRemotes: &no}, []string{"com.example/neither", "com.example/packages-only"}},
+		{"has packages", ServerFilter{HasPackages: &yes}, []string{"com.example/both", "com.example/packages-only"}},
+		{"packages only", ServerFilter{HasPackages: &yes, HasRemotes: &no}, []string{"com.example/packages-only"}},
+		{"remotes only", ServerFilter{HasRemotes: &yes, HasPackages: &no}, []string{"com.example/remote-only"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := db.ListServers(ctx, nil, &tt.filter, "", 100)
+			require.NoError(t, err)
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Server.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+// TestListServers_StableOrderAcrossReload tests that pages follow name then version order regardless
+// of storage order, so reloading reordered data between pages neither skips nor repeats records
+func TestListServers_StableOrderAcrossReload(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	// Insert out of order so storage order differs from listing order
+	for i := 9; i >= 0; i-- {
+		for _, version := range []string{"2.0.0", "1.0.0"} {
+			_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        fmt.Sprintf("com.example/server-%02d", i),
+				Description: "Stable order test server",
+				Version:     version,
+			}, nil)
+			require.NoError(t, err)
+		}
+	}
+
+	all, _, err := db.ListServers(ctx, nil, nil, "", 100)
+	require.NoError(t, err)
+	require.Len(t, all, 20)
+	var want []string
+	for _, r := range all {
+		want = append(want, r.Server.Name+":"+r.Server.Version)
+	}
+	assert.True(t, slices.IsSorted(want), "listing should be sorted by name then version: %v", want)
+
+	// Reverse the stored records and reload before each page
+	reloadReversed := func() {
+		servers := slices.Clone(db.snapshot())
+		slices.Reverse(servers)
+		payload, err := json.Marshal(jsonFileData{Servers: servers})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(db.FilePath(), payload, 0600))
+		require.NoError(t, db.ReloadFrom("test"))
+	}
+
+	var got []string
+	cursor := ""
+	for page := 0; page < 20; page++ {
+		reloadReversed()
+		results, nextCursor, err := db.ListServers(ctx, nil, nil, cursor, 3)
+		require.NoError(t, err)
+		for _, r := range results {
+			got = append(got, r.Server.Name+":"+r.Server.Version)
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	assert.Equal(t, want, got)
+}
+
+// TestListChanges_Sequence tests that change sequence numbers survive a crash through the write-ahead log, and
+// that a reload numbers what it changed after everything numbered before it
+func TestListChanges_Sequence(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	publish := func(db *JSONFileDB, name string) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Change sequence test server",
+			Version:     "1.0.0",
+		}, nil)
+		require.NoError(t, err)
+	}
+	// changes lists the changes after a sequence number as name@version:sequence, prefixing removals with "-"
+	changes := func(db *JSONFileDB, after int64) []string {
+		page, err := db.ListChanges(ctx, nil, after, 100)
+		require.NoError(t, err)
+		var result []string
+		for _, change := range page {
+			if change.Server == nil {
+				result = append(result, fmt.Sprintf("-%s@%s:%d", change.Removed.Name, change.Removed.Version, change.Sequence))
+				continue
+			}
+			result = append(result, fmt.Sprintf("%s@%s:%d", change.Server.Server.Name, change.Server.Server.Version, change.Sequence))
+		}
+		return result
+	}
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	publish(db, "com.example/a")
+	require.NoError(t, db.Flush(ctx))
+	publish(db, "com.example/b")
+	publish(db, "com.example/c")
+	require.NoError(t, db.DeleteServerVersion(ctx, nil, "com.example/b", "1.0.0"))
+	want := []string{"com.example/a@1.0.0:1", "com.example/c@1.0.0:3", "-com.example/b@1.0.0:4"}
+	assert.Equal(t, want, changes(db, 0))
+
+	// Reopen without flushing, as after a crash
+	require.NoError(t, db.wal.Close())
+	db, err = NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	assert.Equal(t, want, changes(db, 0))
+
+	// Reloading what was flushed changes nothing
+	require.NoError(t, db.Flush(ctx))
+	require.NoError(t, db.Reload())
+	assert.Equal(t, want, changes(db, 0))
+
+	// A reload brings an edit to a, drops c and adds d: each is numbered after everything seen so far
+	servers := slices.Clone(db.snapshot())
+	edited := *servers[0].Value
+	edited.Description = "Edited upstream"
+	servers[0].Value = &edited
+	servers[1] = serverRecord{ServerName: "com.example/d", Version: "1.0.0", Status: string(model.StatusActive), IsLatest: true,
+		Value: &apiv0.ServerJSON{Schema: model.CurrentSchemaURL, Name: "com.example/d", Description: "Published upstream", Version: "1.0.0"}}
+	payload, err := json.Marshal(jsonFileData{Servers: servers})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, payload, 0600))
+	require.NoError(t, db.Reload())
+	assert.Equal(t, []string{"com.example/a@1.0.0:5", "com.example/d@1.0.0:6", "-com.example/c@1.0.0:7"}, changes(db, 4))
+}
+
+// TestListServerNames tests that names are deduplicated across versions, filtered by prefix and paginated
+func TestListServerNames(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	for _, name := range []string{"com.acme/alpha", "com.acme/beta", "com.acmecorp/other", "com.example/other"} {
+		for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+			_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "Names test server",
+				Version:     version,
+			}, nil)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("deduplicates versions", func(t *testing.T) {
+		names, nextCursor, err := db.ListServerNames(ctx, nil, nil, "", 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta", "com.acmecorp/other", "com.example/other"}, names)
+		assert.Empty(t, nextCursor)
+	})
+
+	t.Run("filters by prefix", func(t *testing.T) {
+		prefix := "com.acme/"
+		names, _, err := db.ListServerNames(ctx, nil, &prefix, "", 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta"}, names)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		var names []string
+		cursor := ""
+		for range 10 {
+			page, nextCursor, err := db.ListServerNames(ctx, nil, nil, cursor, 3)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(page), 3)
+			names = append(names, page...)
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+		assert.Equal(t, []string{"com.acme/alpha", "com.acme/beta", "com.acmecorp/other", "com.example/other"}, names)
+	})
+}
+
+// TestGetVersionsAsOf tests listing the versions of a server that had been published at a point in time
+func TestGetVersionsAsOf(t *testing.T) {
+	ctx := context.Background()
+
+	published := func(date string) time.Time {
+		ts, err := time.Parse(time.DateOnly, date)
+		require.NoError(t, err)
+		return ts
+	}
+	record := func(name, version, date string) serverRecord {
+		return serverRecord{
+			ServerName:  name,
+			Version:     version,
+			Status:      string(model.StatusActive),
+			PublishedAt: published(date),
+			UpdatedAt:   published(date),
+			Value: &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "As-of test server",
+				Version:     version,
+			},
+		}
+	}
+
+	testData := jsonFileData{
+		Servers: []serverRecord{
+			record("com.example/timeline", "1.0.0", "2023-06-01"),
+			record("com.example/timeline", "2.0.0", "2024-03-01"),
+			record("com.example/timeline", "1.1.0", "2023-11-15"),
+			record("com.example/timeline", "1.1.1", "2024-05-01"),
+			record("com.example/other", "9.0.0", "2023-12-01"),
+		},
+	}
+	testData.Servers[1].IsLatest = true
+
+	data, err := json.Marshal(testData)
+	require.NoError(t, err)
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, data, 0600))
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		serverName       string
+		at               time.Time
+		expectedVersions []string
+		expectedErr      error
+	}{
+		{
+			name:             "before second release",
+			serverName:       "com.example/timeline",
+			at:               published("2024-01-01"),
+			expectedVersions: []string{"1.1.0", "1.0.0"},
+		},
+		{
+			name:             "exactly at publish time",
+			serverName:       "com.example/timeline",
+			at:               published("2023-06-01"),
+			expectedVersions: []string{"1.0.0"},
+		},
+		{
+			name:             "after all releases",
+			serverName:       "com.example/timeline",
+			at:               published("2025-01-01"),
+			expectedVersions: []string{"1.1.1", "2.0.0", "1.1.0", "1.0.0"},
+		},
+		{
+			name:        "before first release",
+			serverName:  "com.example/timeline",
+			at:          published("2023-01-01"),
+			expectedErr: ErrNotFound,
+		},
+		{
+			name:        "unknown server",
+			serverName:  "com.example/missing",
+			at:          published("2025-01-01"),
+			expectedErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.GetVersionsAsOf(ctx, nil, tt.serverName, tt.at)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			var versions []string
+			for _, result := range results {
+				assert.Equal(t, tt.serverName, result.Server.Name)
+				versions = append(versions, result.Server.Version)
+			}
+			assert.Equal(t, tt.expectedVersions, versions, "versions should be ordered by publication, newest first")
+		})
+	}
+}
+
+// TestFlush tests that Flush persists pending in-memory changes to the JSON file
+func TestGetServerVersions(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	for _, ref := range []ServerRef{
+		{Name: "com.example/alpha", Version: "1.0.0"},
+		{Name: "com.example/alpha", Version: "2.0.0"},
+		{Name: "com.example/beta", Version: "0.1.0"},
+	} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        ref.Name,
+			Description: "Resolve test server",
+			Version:     ref.Version,
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	results, err := db.GetServerVersions(ctx, nil, []ServerRef{
+		{Name: "com.example/beta", Version: "0.1.0"},
+		{Name: "com.example/alpha", Version: "3.0.0"}, // missing version
+		{Name: "com.example/alpha", Version: "1.0.0"},
+		{Name: "com.example/gamma", Version: "1.0.0"}, // missing server
+	})
+	require.NoError(t, err)
+
+	var found []ServerRef
+	for _, r := range results {
+		found = append(found, ServerRef{Name: r.Server.Name, Version: r.Server.Version})
+	}
+	assert.Equal(t, []ServerRef{
+		{Name: "com.example/beta", Version: "0.1.0"},
+		{Name: "com.example/alpha", Version: "1.0.0"},
+	}, found)
+
+	results, err = db.GetServerVersions(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestFlush(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	// Nothing is dirty yet, so flushing must not create the file
+	require.NoError(t, db.Flush(ctx))
+	_, err = os.Stat(filePath)
+	require.True(t, os.IsNotExist(err), "flush without pending changes should not write the file")
+
+	serverJSON := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/flushed",
+		Description: "Flush test server",
+		Version:     "1.0.0",
+	}
+	now := time.Now()
+	_, err = db.CreateServer(ctx, nil, serverJSON, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: now,
+		UpdatedAt:   now,
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Flush(ctx))
+
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	server, err := reopened.GetServerByName(ctx, nil, "com.example/flushed")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", server.Server.Version)
+	assert.True(t, server.Meta.Official.IsLatest)
+
+	// A second flush with nothing pending is a no-op
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	require.NoError(t, db.Flush(ctx))
+	after, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, info.ModTime(), after.ModTime())
+}
+
+// TestCloseFlushes tests that closing the database writes pending changes to the JSON file
+func TestCloseFlushes(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/closed",
+		Description: "Close test server",
+		Version:     "1.0.0",
+	}, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: now,
+		UpdatedAt:   now,
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+	_, err = os.Stat(filePath)
+	require.True(t, os.IsNotExist(err), "changes should only be written on flush")
+
+	require.NoError(t, db.Close())
+
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	server, err := reopened.GetServerByName(ctx, nil, "com.example/closed")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", server.Server.Version)
+}
+
+// TestSaveFailureRollsBack tests that once writing the data file fails, changes are refused and rolled back
+// rather than kept only in memory, and accepted again once the file can be written
+func TestSaveFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	create := func(version string) error {
+		now := time.Now()
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/unsaved",
+			Description: "Save failure test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: now,
+			UpdatedAt:   now,
+			IsLatest:    true,
+		})
+		return err
+	}
+	require.NoError(t, create("1.0.0"))
+	require.NoError(t, db.Flush(ctx))
+
+	// A directory in the way of the temp file makes every write of the data file fail
+	tempPath := filePath + ".tmp"
+	require.NoError(t, os.Mkdir(tempPath, 0700))
+
+	// The failure isn't known until a write is attempted, so this change is only held in memory
+	require.NoError(t, create("1.1.0"))
+	require.ErrorIs(t, db.Flush(ctx), ErrDatabase)
+
+	// From now on, changes that can't be written are refused and leave memory as it was
+	err = create("2.0.0")
+	require.ErrorIs(t, err, ErrDatabase)
+	_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/unsaved", "2.0.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = db.SetServerStatus(ctx, nil, "com.example/unsaved", "1.0.0", string(model.StatusDeprecated), "")
+	require.ErrorIs(t, err, ErrDatabase)
+	_, err = db.SetLatestVersion(ctx, nil, "com.example/unsaved", "1.1.0", false)
+	require.ErrorIs(t, err, ErrDatabase)
+	require.ErrorIs(t, db.DeleteServerVersion(ctx, nil, "com.example/unsaved", "1.1.0"), ErrDatabase)
+
+	stored, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/unsaved", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusActive, stored.Meta.Official.Status)
+	assert.True(t, stored.Meta.Official.IsLatest)
+	_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/unsaved", "1.1.0")
+	require.NoError(t, err)
+
+	// Once the file can be written again, the next change is written through along with the held one
+	require.NoError(t, os.Remove(tempPath))
+	require.NoError(t, create("2.0.0"))
+
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	count, err := reopened.CountServerVersions(ctx, nil, "com.example/unsaved")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+// TestCompactJSON tests that data written in either compact or indented mode loads back in both modes
+func TestCompactJSON(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	write := func(name string, opts ...JSONFileOption) string {
+		filePath := filepath.Join(dir, name)
+		db, err := NewJSONFileDB(ctx, filePath, opts...)
+		require.NoError(t, err)
+		for i := range 20 {
+			_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        fmt.Sprintf("com.example/server-%02d", i),
+				Description: "Compact test server",
+				Version:     "1.0.0",
+				Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://example.com/mcp"}},
+			}, nil)
+			require.NoError(t, err)
+		}
+		require.NoError(t, db.Flush(ctx))
+		return filePath
+	}
+
+	indentedPath := write("indented.json")
+	compactPath := write("compact.json", WithCompactJSON())
+
+	indented, err := os.ReadFile(indentedPath)
+	require.NoError(t, err)
+	compact, err := os.ReadFile(compactPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(indented), "\n  ")
+	assert.NotContains(t, string(compact), "\n")
+	assert.Less(t, len(compact), len(indented)*3/4, "compact file should be substantially smaller")
+
+	for _, filePath := range []string{indentedPath, compactPath} {
+		for _, opts := range [][]JSONFileOption{nil, {WithCompactJSON()}} {
+			db, err := NewJSONFileDB(ctx, filePath, opts...)
+			require.NoError(t, err)
+			servers, _, err := db.ListServers(ctx, nil, nil, "", 100)
+			require.NoError(t, err)
+			require.Len(t, servers, 20, filePath)
+			assert.Equal(t, "https://example.com/mcp", servers[0].Server.Remotes[0].URL)
+		}
+	}
+}
+
+// writeLargeJSONFile generates a JSON database file with count server records
+func writeLargeJSONFile(tb testing.TB, count int) string {
+	tb.Helper()
+
+	records := make([]serverRecord, 0, count)
+	now := time.Now().UTC()
+	for i := range count {
+		name := fmt.Sprintf("com.example/server-%05d", i)
+		records = append(records, serverRecord{
+			ServerName:  name,
+			Version:     "1.0.0",
+			Status:      string(model.StatusActive),
+			IsLatest:    true,
+			PublishedAt: now,
+			UpdatedAt:   now,
+			Value: &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: strings.Repeat("A generated server used to exercise large file loads. ", 4),
+				Version:     "1.0.0",
+				Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://example.com/" + name}},
+			},
+		})
+	}
+
+	data, err := json.Marshal(jsonFileData{Servers: records})
+	require.NoError(tb, err)
+	filePath := filepath.Join(tb.TempDir(), "registry.json")
+	require.NoError(tb, os.WriteFile(filePath, data, 0o600))
+	return filePath
+}
+
+// TestLoadLargeFile tests that a large generated file is fully loaded by the streaming decoder
+func TestLoadLargeFile(t *testing.T) {
+	ctx := context.Background()
+	filePath := writeLargeJSONFile(t, 5000)
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, 5000, db.Count())
+
+	server, err := db.GetServerByName(ctx, nil, "com.example/server-04999")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/com.example/server-04999", server.Server.Remotes[0].URL)
+}
+
+// TestDecodeJSONFile tests the streaming decoder's handling of unusual documents
+func TestDecodeJSONFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		servers int
+		nilData bool
+		wantErr bool
+	}{
+		{name: "empty file", content: "", nilData: true},
+		{name: "null document", content: "null"},
+		{name: "null servers", content: `{"servers": null}`},
+		{name: "unknown fields are ignored", content: `{"version": 2, "servers": [{"server_name": "com.example/a", "version": "1.0.0"}], "extra": {"nested": [1, 2]}}`, servers: 1},
+		{name: "servers not an array", content: `{"servers": {}}`, wantErr: true},
+		{name: "top level array", content: `[]`, wantErr: true},
+		{name: "trailing data", content: `{"servers": []} {}`, wantErr: true},
+		{name: "truncated", content: `{"servers": [{"server_name": "com.example/a"`, wantErr: true},
+		{name: "mistyped record", content: `{"servers": [{"server_name": "com.example/a", "version": 42}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileData, _, err := decodeJSONFile(strings.NewReader(tt.content), false)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.nilData {
+				assert.Nil(t, fileData)
+				return
+			}
+			require.NotNil(t, fileData)
+			assert.Len(t, fileData.Servers, tt.servers)
+		})
+	}
+}
+
+// BenchmarkLoadLargeFile reports the allocations of loading a large file; run with -benchmem
+func BenchmarkLoadLargeFile(b *testing.B) {
+	filePath := writeLargeJSONFile(b, 10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, _, err := readJSONFile(filePath, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// cancelAfterContext reports itself cancelled once Err has been called a given number of times,
+// simulating a client that disconnects partway through a scan
+type cancelAfterContext struct {
+	context.Context
+	remaining atomic.Int32
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.remaining.Add(-1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+// TestScanCancellation tests that long scans stop with the context's error when it is cancelled mid-iteration
+func TestScanCancellation(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewJSONFileDB(ctx, writeLargeJSONFile(t, 5000))
+	require.NoError(t, err)
+
+	midScan := func() context.Context {
+		c := &cancelAfterContext{Context: ctx}
+		c.remaining.Store(2)
+		return c
+	}
+
+	t.Run("ListServers", func(t *testing.T) {
+		name := "com.example/no-such-server"
+		results, nextCursor, err := db.ListServers(midScan(), nil, &ServerFilter{Name: &name}, "", 10)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+		assert.Empty(t, nextCursor)
+	})
+
+	t.Run("ListServers with cursor", func(t *testing.T) {
+		_, _, err := db.ListServers(midScan(), nil, nil, "com.example/server-04999:1.0.0", 10)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("GetAllVersionsByServerName", func(t *testing.T) {
+		results, err := db.GetAllVersionsByServerName(midScan(), nil, "com.example/server-04999")
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+	})
+
+	t.Run("uncancelled scans complete", func(t *testing.T) {
+		results, err := db.GetAllVersionsByServerName(ctx, nil, "com.example/server-04999")
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+}
+
+// TestLastSync tests that a successful reload records when and where the data came from
+func TestLastSync(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	// No file has been loaded yet
+	_, ok := db.LastSync()
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"servers":[]}`), 0o600))
+	before := time.Now()
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+
+	first, ok := db.LastSync()
+	require.True(t, ok)
+	assert.Equal(t, "s3://registry-bucket/registry.json", first.Source)
+	assert.False(t, first.At.Before(before))
+
+	// A failed reload leaves the last sync untouched
+	require.NoError(t, os.WriteFile(filePath, []byte(`not json`), 0o600))
+	require.Error(t, db.ReloadFrom("s3://registry-bucket/broken.json"))
+	unchanged, ok := db.LastSync()
+	require.True(t, ok)
+	assert.Equal(t, first, unchanged)
+
+	// A later reload moves the timestamp forward
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"servers":[]}`), 0o600))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, db.Reload())
+	second, ok := db.LastSync()
+	require.True(t, ok)
+	assert.True(t, second.At.After(first.At))
+	assert.Equal(t, filePath, second.Source)
+
+	// Opening an existing file counts as a sync from that file
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	initial, ok := reopened.LastSync()
+	require.True(t, ok)
+	assert.Equal(t, filePath, initial.Source)
+}
+
+// TestTolerantLoad tests that tolerant mode keeps the valid records of a partially malformed file
+func TestTolerantLoad(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	content := `{"servers": [
+		{"server_name": "com.example/good-one", "version": "1.0.0", "status": "active", "is_latest": true,
+		 "value": {"name": "com.example/good-one", "description": "Good", "version": "1.0.0"}},
+		{"server_name": "com.example/broken", "version": 42, "value": "not an object"},
+		{"server_name": "com.example/good-two", "version": "2.0.0", "status": "active", "is_latest": true,
+		 "value": {"name": "com.example/good-two", "description": "Good", "version": "2.0.0"}}
+	]}`
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o600))
+
+	t.Run("strict load fails", func(t *testing.T) {
+		_, err := NewJSONFileDB(ctx, filePath)
+		require.Error(t, err)
+	})
+
+	t.Run("tolerant load skips the malformed record", func(t *testing.T) {
+		db, err := NewJSONFileDB(ctx, filePath, WithTolerantLoad())
+		require.NoError(t, err)
+		assert.Equal(t, 1, db.SkippedRecords())
+		assert.Equal(t, 2, db.Count())
+
+		server, err := db.GetServerByName(ctx, nil, "com.example/good-two")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", server.Server.Version)
+
+		_, err = db.GetServerByName(ctx, nil, "com.example/broken")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("tolerant load still rejects a file that is not a servers object", func(t *testing.T) {
+		brokenPath := filepath.Join(t.TempDir(), "registry.json")
+		require.NoError(t, os.WriteFile(brokenPath, []byte(`{"servers": [`), 0o600))
+		_, err := NewJSONFileDB(ctx, brokenPath, WithTolerantLoad())
+		require.Error(t, err)
+	})
+}
+
+// TestConcurrentReadersAndWriters exercises snapshot reads alongside copy-on-write updates.
+// Run with -race to check that readers never observe records being modified in place.
+func TestConcurrentReadersAndWriters(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	const servers = 20
+	newServer := func(i int, version string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        fmt.Sprintf("com.example/concurrent-%d", i),
+			Description: "Concurrency test server",
+			Version:     version,
+		}
+	}
+	for i := 0; i < servers; i++ {
+		_, err := db.CreateServer(ctx, nil, newServer(i, "1.0.0"), nil)
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+
+	// Writers: publish new versions and update existing ones
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < servers; i++ {
+				name := fmt.Sprintf("com.example/concurrent-%d", i)
+				if _, err := db.UpdateServer(ctx, nil, name, "1.0.0", newServer(i, "1.0.0")); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := db.SetServerStatus(ctx, nil, name, "1.0.0", string(model.StatusActive), ""); err != nil {
+					errs <- err
+					return
+				}
+				if err := db.UnmarkAsLatest(ctx, nil, name); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := db.CreateServer(ctx, nil, newServer(i, fmt.Sprintf("2.%d.0", w)), nil); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+
+	// Readers: page through the full list and look up individual servers
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < 20; round++ {
+				cursor := ""
+				for {
+					page, next, err := db.ListServers(ctx, nil, nil, cursor, 7)
+					if err != nil {
+						errs <- err
+						return
+					}
+					for _, server := range page {
+						if server.Server.Name == "" || server.Meta.Official == nil {
+							errs <- fmt.Errorf("incomplete record in page: %+v", server)
+							return
+						}
+					}
+					if next == "" {
+						break
+					}
+					cursor = next
+				}
+				if _, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/concurrent-0", "1.0.0"); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	count, err := db.CountServerVersions(ctx, nil, "com.example/concurrent-0")
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
+
+func TestJSONFileDB_PublishLockTimeout(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewJSONFileDB(ctx, filepath.Join(t.TempDir(), "registry.json"), WithLockTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	const serverName = "com.example/locked"
+
+	// Hold the lock in one transaction until released
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	holderDone := make(chan error, 1)
+	go func() {
+		holderDone <- db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			if err := db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+				return err
+			}
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	<-acquired
+
+	held := db.HeldPublishLocks()
+	require.Len(t, held, 1)
+	assert.Equal(t, serverName, held[0].ServerName)
+	assert.False(t, held[0].HeldSince.IsZero())
+
+	t.Run("lock timeout", func(t *testing.T) {
+		start := time.Now()
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			return db.AcquirePublishLock(ctx, tx, serverName)
+		})
+		require.ErrorIs(t, err, ErrLockTimeout)
+		assert.ErrorIs(t, err, ErrDatabase)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		deadlineCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err := db.InTransaction(deadlineCtx, func(ctx context.Context, tx pgx.Tx) error {
+			return db.AcquirePublishLock(ctx, tx, serverName)
+		})
+		assert.ErrorIs(t, err, ErrLockTimeout)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := db.InTransaction(cancelledCtx, func(ctx context.Context, tx pgx.Tx) error {
+			return db.AcquirePublishLock(ctx, tx, serverName)
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrLockTimeout)
+	})
+
+	t.Run("other servers are not blocked", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			return db.AcquirePublishLock(ctx, tx, "com.example/unlocked")
+		})
+		assert.NoError(t, err)
+	})
+
+	// Once released, the lock can be acquired again
+	close(release)
+	require.NoError(t, <-holderDone)
+	assert.Empty(t, db.HeldPublishLocks())
+	err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return db.AcquirePublishLock(ctx, tx, serverName)
+	})
+	assert.NoError(t, err)
+}
+
+// TestInTransactionRollback tests that a failed transaction's changes are undone, including on replay of the
+// write-ahead log, while changes made outside it meanwhile are kept
+func TestInTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+
+	publish := func(tx pgx.Tx, name, version string) error {
+		now := time.Now()
+		_, err := db.CreateServer(ctx, tx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Rollback test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: true})
+		return err
+	}
+	require.NoError(t, publish(nil, "com.example/kept", "1.0.0"))
+	require.NoError(t, publish(nil, "com.example/moved", "1.0.0"))
+	require.NoError(t, publish(nil, "com.example/pruned", "1.0.0"))
+	before := changesAfter(t, db, 0)
+
+	errFailed := errors.New("failed after writing")
+	err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := db.UnmarkAsLatest(ctx, tx, "com.example/kept"); err != nil {
+			return err
+		}
+		if err := publish(tx, "com.example/kept", "2.0.0"); err != nil {
+			return err
+		}
+		if _, err := db.SetServerStatus(ctx, tx, "com.example/kept", "1.0.0", string(model.StatusDeprecated), "replaced"); err != nil {
+			return err
+		}
+		if _, err := db.RenameServer(ctx, tx, "com.example/moved", "org.example/moved"); err != nil {
+			return err
+		}
+		if err := db.DeleteServerVersion(ctx, tx, "com.example/pruned", "1.0.0"); err != nil {
+			return err
+		}
+		// A change outside the transaction is not undone with it
+		if err := publish(nil, "com.example/other", "1.0.0"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	assertRolledBack := func(db *JSONFileDB) {
+		t.Helper()
+		kept, err := db.GetAllVersionsByServerName(ctx, nil, "com.example/kept")
+		require.NoError(t, err)
+		require.Len(t, kept, 1)
+		assert.Equal(t, "1.0.0", kept[0].Server.Version)
+		assert.True(t, kept[0].Meta.Official.IsLatest)
+		assert.Equal(t, model.StatusActive, kept[0].Meta.Official.Status)
+		assert.Empty(t, kept[0].Meta.Official.StatusReason)
+
+		_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/moved", "1.0.0")
+		require.NoError(t, err)
+		_, err = db.GetServerByName(ctx, nil, "org.example/moved")
+		require.ErrorIs(t, err, ErrNotFound)
+		_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/pruned", "1.0.0")
+		require.NoError(t, err)
+		_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/other", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, 4, db.Count())
+	}
+	assertRolledBack(db)
+
+	// The changes feed reports the restored versions again rather than skipping the transaction's changes
+	after := changesAfter(t, db, before[len(before)-1].Sequence)
+	var changed []string
+	for _, change := range after {
+		if change.Server != nil {
+			changed = append(changed, change.Server.Server.Name+"@"+change.Server.Server.Version)
+		} else {
+			changed = append(changed, "-"+change.Removed.Name+"@"+change.Removed.Version)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"com.example/kept@1.0.0", "-com.example/kept@2.0.0", "com.example/moved@1.0.0",
+		"-org.example/moved@1.0.0", "com.example/pruned@1.0.0", "com.example/other@1.0.0",
+	}, changed)
+
+	// Replaying the write-ahead log after a crash arrives at the same state
+	require.NoError(t, db.wal.Close())
+	recovered, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = recovered.Close() })
+	assertRolledBack(recovered)
+}
+
+// changesAfter lists every change numbered after a sequence number
+func changesAfter(t *testing.T, db *JSONFileDB, after int64) []Change {
+	t.Helper()
+	changes, err := db.ListChanges(context.Background(), nil, after, 1000)
+	require.NoError(t, err)
+	return changes
+}
+
+// TestSnapshotServers tests that the servers for a snapshot are every stored version, ordered by name, then
+// version, and unaffected by changes made after they were taken
+func TestSnapshotServers(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestJSONFileDB(t)
+
+	publish := func(name, version string) {
+		t.Helper()
+		now := time.Now()
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Snapshot test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+	}
+	refs := func(servers []*apiv0.ServerResponse) []string {
+		var result []string
+		for _, server := range servers {
+			result = append(result, server.Server.Name+"@"+server.Server.Version)
+		}
+		return result
+	}
+	publish("com.example/b", "1.0.0")
+	publish("com.example/a", "2.0.0")
+	publish("com.example/a", "1.0.0")
+
+	servers, err := db.SnapshotServers(ctx)
+	require.NoError(t, err)
+	publish("com.example/c", "1.0.0")
+	require.NoError(t, db.DeleteServerVersion(ctx, nil, "com.example/b", "1.0.0"))
+
+	assert.Equal(t, []string{"com.example/a@1.0.0", "com.example/a@2.0.0", "com.example/b@1.0.0"}, refs(servers))
+}
+
+// TestPublishDuringReload tests that publishes racing reloads are either refused with ErrReloading or survive
+// the reload, and that a reload waits for transactions in flight
+func TestPublishDuringReload(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	upstream := serverRecord{
+		ServerName:  "com.example/upstream",
+		Version:     "1.0.0",
+		Status:      string(model.StatusActive),
+		PublishedAt: time.Now(),
+		UpdatedAt:   time.Now(),
+		IsLatest:    true,
+		Value: &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/upstream",
+			Description: "Upstream server",
+			Version:     "1.0.0",
+		},
+	}
+	payload, err := json.Marshal(jsonFileData{Servers: []serverRecord{upstream}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, payload, 0600))
+
+	db, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	publish := func(name, version string) error {
+		return db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			if err := db.AcquirePublishLock(ctx, tx, name); err != nil {
+				return err
+			}
+			// Leave room for a reload to start between the publish's checks and its writes
+			time.Sleep(200 * time.Microsecond)
+			if err := db.UnmarkAsLatest(ctx, tx, name); err != nil {
+				return err
+			}
+			now := time.Now()
+			_, err := db.CreateServer(ctx, tx, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "Published during reloads",
+				Version:     version,
+			}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: true})
+			return err
+		})
+	}
+
+	t.Run("no publish is lost", func(t *testing.T) {
+		var wg sync.WaitGroup
+		var refused atomic.Int64
+		errs := make(chan error, 8)
+		done := make(chan struct{})
+
+		// The file never changes, so every local publish must be carried over by each reload
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if err := db.ReloadFrom("s3://registry-bucket/registry.json"); err != nil {
+					errs <- err
+					return
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}()
+
+		for w := range 4 {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				name := fmt.Sprintf("com.example/local-%d", w)
+				for v := range 25 {
+					version := fmt.Sprintf("1.%d.0", v)
+					// Refused publishes are retried, as clients do on 503
+					for {
+						err := publish(name, version)
+						if errors.Is(err, ErrReloading) {
+							require.ErrorIs(t, err, ErrDatabase)
+							refused.Add(1)
+							time.Sleep(time.Millisecond)
+							continue
+						}
+						if err != nil {
+							errs <- err
+							return
+						}
+						break
+					}
+				}
+			}(w)
+		}
+
+		wg.Wait()
+		close(done)
+		require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		t.Logf("%d publishes were refused during a reload and retried", refused.Load())
+
+		for w := range 4 {
+			versions, err := db.GetAllVersionsByServerName(ctx, nil, fmt.Sprintf("com.example/local-%d", w))
+			require.NoError(t, err)
+			assert.Len(t, versions, 25)
+			latest := 0
+			for _, version := range versions {
+				if version.Meta.Official.IsLatest {
+					latest++
+					assert.Equal(t, "1.24.0", version.Server.Version)
+				}
+			}
+			assert.Equal(t, 1, latest)
+		}
+		_, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/upstream", "1.0.0")
+		require.NoError(t, err)
+	})
+
+	t.Run("reload waits for transactions in flight", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		inFlight := make(chan error, 1)
+		go func() {
+			inFlight <- db.InTransaction(ctx, func(context.Context, pgx.Tx) error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+
+		reloaded := make(chan error, 1)
+		go func() { reloaded <- db.ReloadFrom("s3://registry-bucket/registry.json") }()
+
+		// Once the reload is waiting, new transactions are refused
+		require.Eventually(t, func() bool {
+			err := db.InTransaction(ctx, func(context.Context, pgx.Tx) error { return nil })
+			return errors.Is(err, ErrReloading)
+		}, 5*time.Second, time.Millisecond)
+
+		select {
+		case err := <-reloaded:
+			t.Fatalf("reload finished while a transaction was in flight: %v", err)
+		default:
+		}
+
+		close(release)
+		require.NoError(t, <-inFlight)
+		require.NoError(t, <-reloaded)
+		require.NoError(t, db.InTransaction(ctx, func(context.Context, pgx.Tx) error { return nil }))
+	})
+}
+
+// TestReloadRepicksLatest tests that merging local changes into reloaded data lets the picker choose each
+// server's latest version, and that local deletions stay deleted until the file catches up
+func TestReloadRepicksLatest(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	record := func(version string, isLatest bool) serverRecord {
+		return serverRecord{
+			ServerName:  "com.example/merged",
+			Version:     version,
+			Status:      string(model.StatusActive),
+			PublishedAt: time.Now(),
+			UpdatedAt:   time.Now(),
+			IsLatest:    isLatest,
+			Value: &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/merged",
+				Description: "Merge test server",
+				Version:     version,
+			},
+		}
+	}
+	writeUpstream := func(records ...serverRecord) {
+		t.Helper()
+		payload, err := json.Marshal(jsonFileData{Servers: records})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filePath, payload, 0600))
+	}
+	latestVersion := func(db *JSONFileDB) string {
+		t.Helper()
+		latest, err := db.GetServerByName(ctx, nil, "com.example/merged")
+		require.NoError(t, err)
+		return latest.Server.Version
+	}
+
+	// The version strings in this test order the same lexically as by semver
+	highest := func(versions []*apiv0.ServerResponse) *apiv0.ServerResponse {
+		var latest *apiv0.ServerResponse
+		for _, version := range versions {
+			if latest == nil || version.Server.Version > latest.Server.Version {
+				latest = version
+			}
+		}
+		return latest
+	}
+
+	writeUpstream(record("1.0.0", true))
+	db, err := NewJSONFileDB(ctx, filePath, WithLatestPicker(highest))
+	require.NoError(t, err)
+
+	require.NoError(t, db.UnmarkAsLatest(ctx, nil, "com.example/merged"))
+	now := time.Now()
+	_, err = db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/merged",
+		Description: "Merge test server",
+		Version:     "1.5.0",
+	}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: true})
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteServerVersion(ctx, nil, "com.example/merged", "1.0.0"))
+
+	// Upstream published 2.0.0 meanwhile and still has 1.0.0
+	writeUpstream(record("1.0.0", false), record("2.0.0", true))
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+	assert.Equal(t, "2.0.0", latestVersion(db), "a local publish must not override a higher upstream latest")
+	_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/merged", "1.5.0")
+	require.NoError(t, err)
+	_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/merged", "1.0.0")
+	require.ErrorIs(t, err, ErrNotFound, "a local deletion must survive reloads until the file catches up")
+
+	// Once written, the merge holds in the file too
+	require.NoError(t, db.Flush(ctx))
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", latestVersion(reopened))
+	assert.Equal(t, 2, reopened.Count())
+
+	// A version an administrator pinned upstream stays the latest when local changes make the latest be chosen again
+	now = time.Now()
+	_, err = db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/merged",
+		Description: "Merge test server",
+		Version:     "1.2.0",
+	}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	pinned := record("1.5.0", true)
+	pinned.LatestPinned = true
+	writeUpstream(pinned, record("2.0.0", false), record("2.1.0", false))
+	require.NoError(t, db.ReloadFrom("s3://registry-bucket/registry.json"))
+	assert.Equal(t, "1.5.0", latestVersion(db), "reloads must keep a pinned latest version")
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HeldLock describes a publish lock that is currently held
+type HeldLock struct {
+	ServerName string
+	HeldSince  time.Time
+}
+
+// LockMonitor is implemented by databases that can report the publish locks they currently hold, so locks
+// that are never released can be detected
+type LockMonitor interface {
+	// HeldPublishLocks returns the publish locks currently held, longest held first
+	HeldPublishLocks() []HeldLock
+}
+
+// publishLock is an exclusive lock on publishing a server that, unlike sync.Mutex, can be waited for
+// with a deadline and remembers since when it has been held
+type publishLock struct {
+	sem        chan struct{} // holds a token while the lock is held
+	serverName string
+
+	mu        sync.Mutex
+	heldSince time.Time // zero while the lock is free
+}
+
+// newPublishLock creates a free lock for publishing serverName
+func newPublishLock(serverName string) *publishLock {
+	return &publishLock{sem: make(chan struct{}, 1), serverName: serverName}
+}
+
+// lock acquires the lock, waiting until ctx is done at the latest
+func (l *publishLock) lock(ctx context.Context) error {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	l.heldSince = time.Now()
+	l.mu.Unlock()
+	return nil
+}
+
+// unlock releases the lock
+func (l *publishLock) unlock() {
+	l.mu.Lock()
+	l.heldSince = time.Time{}
+	l.mu.Unlock()
+	<-l.sem
+}
+
+// held returns since when the lock has been held, and false if it is free
+func (l *publishLock) held() (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.heldSince, !l.heldSince.IsZero()
+}
+
+// acquirePublishLock waits for the publish lock of serverName, giving up after the configured lock timeout
+// if ctx doesn't end the wait first
+func (db *JSONFileDB) acquirePublishLock(ctx context.Context, lockID uint64, serverName string) (*publishLock, error) {
+	db.locksMu.Lock()
+	lock, exists := db.locks[lockID]
+	if !exists {
+		lock = newPublishLock(serverName)
+		db.locks[lockID] = lock
+	}
+	db.locksMu.Unlock()
+
+	waitCtx := ctx
+	if db.lockTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, db.lockTimeout)
+		defer cancel()
+	}
+
+	if err := lock.lock(waitCtx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w for %s", ErrDatabase, ErrLockTimeout, serverName)
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// HeldPublishLocks implements LockMonitor.HeldPublishLocks
+func (db *JSONFileDB) HeldPublishLocks() []HeldLock {
+	db.locksMu.Lock()
+	defer db.locksMu.Unlock()
+
+	var held []HeldLock
+	for _, lock := range db.locks {
+		if since, ok := lock.held(); ok {
+			held = append(held, HeldLock{ServerName: lock.serverName, HeldSince: since})
+		}
+	}
+	sort.Slice(held, func(i, j int) bool {
+		return held[i].HeldSince.Before(held[j].HeldSince)
+	})
+	return held
+}
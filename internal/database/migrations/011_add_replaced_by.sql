@@ -0,0 +1,3 @@
+-- Record which server replaces a deprecated server version
+
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS replaced_by VARCHAR(255);
@@ -0,0 +1,65 @@
+-- Number every change to a server version from one sequence for the changes feed, including latest flag flips,
+-- renames and removals. server_changes holds each version's most recent change; a row with no matching server
+-- is the tombstone of a removed version.
+
+CREATE SEQUENCE IF NOT EXISTS server_change_seq;
+
+CREATE TABLE IF NOT EXISTS server_changes (
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    change_seq BIGINT NOT NULL,
+    PRIMARY KEY (server_name, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_changes_change_seq ON server_changes (change_seq);
+
+-- Existing versions enter the feed in the order they were last updated
+INSERT INTO server_changes (server_name, version, change_seq)
+SELECT server_name, version, nextval('server_change_seq')
+FROM (SELECT server_name, version FROM servers ORDER BY updated_at, server_name, version) AS existing
+ON CONFLICT (server_name, version) DO NOTHING;
+
+-- Runs when the changing transaction commits. The advisory lock is held from numbering until the commit
+-- completes, so changes become visible in sequence order and a consumer that resumes after a number never
+-- misses a lower one committed later.
+CREATE OR REPLACE FUNCTION record_server_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(7135642130461473638);
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO server_changes (server_name, version, change_seq)
+        VALUES (OLD.server_name, OLD.version, nextval('server_change_seq'))
+        ON CONFLICT (server_name, version) DO UPDATE SET change_seq = EXCLUDED.change_seq;
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.server_name <> NEW.server_name OR OLD.version <> NEW.version THEN
+            INSERT INTO server_changes (server_name, version, change_seq)
+            VALUES (OLD.server_name, OLD.version, nextval('server_change_seq'))
+            ON CONFLICT (server_name, version) DO UPDATE SET change_seq = EXCLUDED.change_seq;
+        END IF;
+    END IF;
+
+    INSERT INTO server_changes (server_name, version, change_seq)
+    VALUES (NEW.server_name, NEW.version, nextval('server_change_seq'))
+    ON CONFLICT (server_name, version) DO UPDATE SET change_seq = EXCLUDED.change_seq;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_server_insert_delete ON servers;
+CREATE CONSTRAINT TRIGGER record_server_insert_delete
+    AFTER INSERT OR DELETE ON servers
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION record_server_change();
+
+DROP TRIGGER IF EXISTS record_server_update ON servers;
+CREATE CONSTRAINT TRIGGER record_server_update
+    AFTER UPDATE ON servers
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.*)
+    EXECUTE FUNCTION record_server_change();
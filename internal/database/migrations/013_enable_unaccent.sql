@@ -0,0 +1,3 @@
+-- Enable unaccent so substring search on server names ignores diacritics
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
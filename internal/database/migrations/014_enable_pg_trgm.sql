@@ -0,0 +1,3 @@
+-- Enable pg_trgm for typo-tolerant (fuzzy) search on server names
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
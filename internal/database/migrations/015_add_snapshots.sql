@@ -0,0 +1,8 @@
+-- Store immutable snapshots of the registry, served at /v0/snapshots/{id}
+
+CREATE TABLE IF NOT EXISTS snapshots (
+    id VARCHAR(64) PRIMARY KEY,
+    sha256 CHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    content BYTEA NOT NULL
+);
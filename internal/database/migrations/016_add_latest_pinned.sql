@@ -0,0 +1,3 @@
+-- Record which latest versions an administrator chose, so recomputing latest versions leaves them alone
+
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS latest_pinned BOOLEAN NOT NULL DEFAULT false;
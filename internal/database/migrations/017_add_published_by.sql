@@ -0,0 +1,3 @@
+-- Record the identity that published each server version
+
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS published_by VARCHAR(255);
@@ -0,0 +1,3 @@
+-- Record why a server version was given its current status
+
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS status_reason TEXT;
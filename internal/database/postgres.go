@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"strings"
 	"time"
 
@@ -19,7 +20,8 @@ import (
 
 // PostgreSQL is an implementation of the Database interface using PostgreSQL
 type PostgreSQL struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool // read replica for reads outside transactions; nil reads from pool
 }
 
 // Executor is an interface for executing queries (satisfied by both pgx.Tx and pgxpool.Pool)
@@ -37,8 +39,108 @@ func (db *PostgreSQL) getExecutor(tx pgx.Tx) Executor {
 	return db.pool
 }
 
+// serverColumns is the column list selected by every query that returns a full server row, in scanServerRow order
+const serverColumns = "server_name, version, status, published_at, updated_at, is_latest, value, replaced_by, published_by, status_reason, latest_pinned"
+
+// likeEscaper escapes LIKE wildcards so a value is matched literally (backslash is the default escape character)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes a value for literal use in a LIKE pattern
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// unavailableCodes are SQLSTATE codes, besides the connection exception class 08, that mean the
+// server cannot serve queries right now rather than that the query is wrong
+var unavailableCodes = map[string]bool{
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// queryError wraps a failed query, marking transient connection failures with ErrDatabase so
+// callers can tell an unavailable database from a missing record or a bad query
+func queryError(msg string, err error) error {
+	if !errors.Is(err, ErrDatabase) && isUnavailable(err) {
+		return fmt.Errorf("%w: %s: %w", ErrDatabase, msg, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// isUnavailable reports whether err means PostgreSQL could not be reached or refused the connection
+func isUnavailable(err error) bool {
+	if pgconn.Timeout(err) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || unavailableCodes[pgErr.Code]
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// scanServerRow scans a row selected with serverColumns into a ServerResponse with separated metadata
+func scanServerRow(row pgx.Row) (*apiv0.ServerResponse, error) {
+	var name, version, status string
+	var publishedAt, updatedAt time.Time
+	var isLatest, latestPinned bool
+	var valueJSON []byte
+	var replacedBy, publishedBy, statusReason *string
+
+	if err := row.Scan(&name, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON, &replacedBy, &publishedBy, &statusReason, &latestPinned); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, queryError("failed to scan server row", err)
+	}
+
+	// Parse the ServerJSON from JSONB
+	var serverJSON apiv0.ServerJSON
+	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+	}
+
+	officialMeta := &apiv0.RegistryExtensions{
+		Status:       model.Status(status),
+		PublishedAt:  publishedAt,
+		UpdatedAt:    updatedAt,
+		IsLatest:     isLatest,
+		LatestPinned: latestPinned,
+	}
+	if replacedBy != nil {
+		officialMeta.ReplacedBy = *replacedBy
+	}
+	if publishedBy != nil {
+		officialMeta.PublishedBy = *publishedBy
+	}
+	if statusReason != nil {
+		officialMeta.StatusReason = *statusReason
+	}
+
+	return &apiv0.ServerResponse{
+		Server: serverJSON,
+		Meta: apiv0.ResponseMeta{
+			Official: officialMeta,
+		},
+	}, nil
+}
+
 // NewPostgreSQL creates a new instance of the PostgreSQL database
-func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, error) {
+func NewPostgreSQL(ctx context.Context, connectionURI string, opts ...PostgreSQLOption) (*PostgreSQL, error) {
+	var options postgresOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// Parse connection config for pool settings
 	config, err := pgxpool.ParseConfig(connectionURI)
 	if err != nil {
@@ -74,9 +176,14 @@ func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, erro
 		return nil, fmt.Errorf("failed to run database migrations: %w", err)
 	}
 
-	return &PostgreSQL{
-		pool: pool,
-	}, nil
+	db := &PostgreSQL{pool: pool}
+	if options.replicaURI != "" {
+		if db.replica, err = newReplicaPool(ctx, options.replicaURI); err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+	return db, nil
 }
 
 func (db *PostgreSQL) ListServers(
@@ -99,6 +206,9 @@ func (db *PostgreSQL) ListServers(
 	args := []any{}
 	argIndex := 1
 
+	// Score of fuzzy name matches, which are ranked by it instead of paginated; empty unless searching fuzzily
+	var similarity string
+
 	// Add filters using dedicated columns for better performance
 	if filter != nil {
 		if filter.Name != nil {
@@ -117,10 +227,22 @@ func (db *PostgreSQL) ListServers(
 			argIndex++
 		}
 		if filter.SubstringName != nil {
-			whereConditions = append(whereConditions, fmt.Sprintf("server_name ILIKE $%d", argIndex))
+			// Both sides are unaccented so the match ignores diacritics as well as case
+			whereConditions = append(whereConditions, fmt.Sprintf("unaccent(server_name) ILIKE unaccent($%d)", argIndex))
 			args = append(args, "%"+*filter.SubstringName+"%")
 			argIndex++
 		}
+		if filter.FuzzyName != nil {
+			similarity = fmt.Sprintf("GREATEST(similarity(server_name, $%[1]d), similarity(split_part(server_name, '/', 2), $%[1]d))", argIndex)
+			whereConditions = append(whereConditions, fmt.Sprintf("%s >= $%d", similarity, argIndex+1))
+			args = append(args, *filter.FuzzyName, filter.MinSimilarity)
+			argIndex += 2
+		}
+		if filter.NamePrefix != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("server_name LIKE $%d", argIndex))
+			args = append(args, escapeLike(*filter.NamePrefix)+"%")
+			argIndex++
+		}
 		if filter.Version != nil {
 			whereConditions = append(whereConditions, fmt.Sprintf("version = $%d", argIndex))
 			args = append(args, *filter.Version)
@@ -131,10 +253,46 @@ func (db *PostgreSQL) ListServers(
 			args = append(args, *filter.IsLatest)
 			argIndex++
 		}
+		if filter.Status != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+			args = append(args, *filter.Status)
+			argIndex++
+		}
+		if filter.HasRemotes != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("%s = $%d", hasArrayEntries("value->'remotes'"), argIndex))
+			args = append(args, *filter.HasRemotes)
+			argIndex++
+		}
+		if filter.HasPackages != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("%s = $%d", hasArrayEntries("value->'packages'"), argIndex))
+			args = append(args, *filter.HasPackages)
+			argIndex++
+		}
+		if filter.HasProvenance != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("%s = $%d", hasArrayEntries("value->'_meta'->'io.modelcontextprotocol.registry/provenance'->'attestations'"), argIndex))
+			args = append(args, *filter.HasProvenance)
+			argIndex++
+		}
+		if filter.Tag != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("value->'_meta'->'io.modelcontextprotocol.registry/tags' @> jsonb_build_array($%d::text)", argIndex))
+			args = append(args, *filter.Tag)
+			argIndex++
+		}
+		if filter.TransportType != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(COALESCE(value->'remotes', '[]'::jsonb)) AS remote WHERE remote->>'type' = $%d)", argIndex))
+			args = append(args, *filter.TransportType)
+			argIndex++
+		}
+		if filter.Author != nil {
+			// The owner is the first path segment of the repository URL, as repositoryOwner takes it
+			whereConditions = append(whereConditions, fmt.Sprintf("(lower(split_part(substring(value->'repository'->>'url' from '^[^:/]+://[^/]+/(.*)$'), '/', 1)) = lower($%[1]d) OR lower(published_by) = lower($%[1]d))", argIndex))
+			args = append(args, *filter.Author)
+			argIndex++
+		}
 	}
 
 	// Add cursor pagination using compound serverName:version cursor
-	if cursor != "" {
+	if cursor != "" && similarity == "" {
 		// Parse cursor format: "serverName:version"
 		parts := strings.SplitN(cursor, ":", 2)
 		if len(parts) == 2 {
@@ -159,63 +317,50 @@ func (db *PostgreSQL) ListServers(
 		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
+	orderBy := "server_name, version"
+	if similarity != "" {
+		orderBy = similarity + " DESC, " + orderBy
+	}
+
 	// Query servers table with hybrid column/JSON data
 	query := fmt.Sprintf(`
-        SELECT server_name, version, status, published_at, updated_at, is_latest, value
+        SELECT %s
         FROM servers
         %s
-        ORDER BY server_name, version
+        ORDER BY %s
         LIMIT $%d
-    `, whereClause, argIndex)
+    `, serverColumns, whereClause, orderBy, argIndex)
 	args = append(args, limit)
+	argIndex++
+
+	// Offset pagination skips that many matches instead of starting after a cursor
+	if filter != nil && filter.Offset > 0 && cursor == "" && similarity == "" {
+		query += fmt.Sprintf("OFFSET $%d\n", argIndex)
+		args = append(args, filter.Offset)
+	}
 
-	rows, err := db.getExecutor(tx).Query(ctx, query, args...)
+	rows, err := db.getReadExecutor(ctx, tx).Query(ctx, query, args...)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to query servers: %w", err)
+		return nil, "", queryError("failed to query servers", err)
 	}
 	defer rows.Close()
 
 	var results []*apiv0.ServerResponse
 	for rows.Next() {
-		var serverName, version, status string
-		var publishedAt, updatedAt time.Time
-		var isLatest bool
-		var valueJSON []byte
-
-		err := rows.Scan(&serverName, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+		serverResponse, err := scanServerRow(rows)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to scan server row: %w", err)
-		}
-
-		// Parse the ServerJSON from JSONB
-		var serverJSON apiv0.ServerJSON
-		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-			return nil, "", fmt.Errorf("failed to unmarshal server JSON: %w", err)
+			return nil, "", err
 		}
-
-		// Build ServerResponse with separated metadata
-		serverResponse := &apiv0.ServerResponse{
-			Server: serverJSON,
-			Meta: apiv0.ResponseMeta{
-				Official: &apiv0.RegistryExtensions{
-					Status:      model.Status(status),
-					PublishedAt: publishedAt,
-					UpdatedAt:   updatedAt,
-					IsLatest:    isLatest,
-				},
-			},
-		}
-
 		results = append(results, serverResponse)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, "", fmt.Errorf("error iterating rows: %w", err)
+		return nil, "", queryError("error iterating rows", err)
 	}
 
 	// Determine next cursor using compound serverName:version format
 	nextCursor := ""
-	if len(results) > 0 && len(results) >= limit {
+	if len(results) > 0 && len(results) >= limit && similarity == "" {
 		lastResult := results[len(results)-1]
 		nextCursor = lastResult.Server.Name + ":" + lastResult.Server.Version
 	}
@@ -223,6 +368,144 @@ func (db *PostgreSQL) ListServers(
 	return results, nextCursor, nil
 }
 
+// hasArrayEntries returns a boolean SQL expression that is true when the jsonb expression is a non-empty array.
+// Missing keys and non-array values count as empty.
+func hasArrayEntries(expr string) string {
+	return fmt.Sprintf("(CASE WHEN jsonb_typeof(%[1]s) = 'array' THEN jsonb_array_length(%[1]s) > 0 ELSE false END)", expr)
+}
+
+// ListChanges retrieves the changes numbered after a sequence number, in sequence order, for incremental sync.
+// Versions whose most recent change removed them come back as tombstones. It always reads from the primary,
+// since a lagging replica would hand out tokens past changes it hasn't applied yet.
+func (db *PostgreSQL) ListChanges(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]Change, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+        SELECT change_seq, server_name, version
+        FROM server_changes
+        WHERE change_seq > $1
+        ORDER BY change_seq
+        LIMIT $2
+    `
+	executor := db.getExecutor(tx)
+	rows, err := executor.Query(ctx, query, after, limit)
+	if err != nil {
+		return nil, queryError("failed to query changes", err)
+	}
+	defer rows.Close()
+
+	var changes []Change
+	var refs []ServerRef
+	for rows.Next() {
+		var change Change
+		var ref ServerRef
+		if err := rows.Scan(&change.Sequence, &ref.Name, &ref.Version); err != nil {
+			return nil, queryError("failed to scan change row", err)
+		}
+		changes = append(changes, change)
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError("error iterating rows", err)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	// A version that is gone by now has been removed since; its removal is numbered later, so reporting it as
+	// removed here only repeats a change, never skips one
+	servers, err := queryServerVersions(ctx, executor, refs)
+	if err != nil {
+		return nil, err
+	}
+	byRef := make(map[ServerRef]*apiv0.ServerResponse, len(servers))
+	for _, server := range servers {
+		byRef[ServerRef{Name: server.Server.Name, Version: server.Server.Version}] = server
+	}
+	for i := range changes {
+		if server, ok := byRef[refs[i]]; ok {
+			changes[i].Server = server
+		} else {
+			changes[i].Removed = &refs[i]
+		}
+	}
+
+	return changes, nil
+}
+
+// ListServerNames retrieves distinct server names in name order, with optional prefix filtering and pagination
+func (db *PostgreSQL) ListServerNames(ctx context.Context, tx pgx.Tx, prefix *string, cursor string, limit int) ([]string, string, error) {
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	var whereConditions []string
+	args := []any{}
+	argIndex := 1
+
+	if prefix != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("server_name LIKE $%d", argIndex))
+		args = append(args, escapeLike(*prefix)+"%")
+		argIndex++
+	}
+	if cursor != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("server_name > $%d", argIndex))
+		args = append(args, cursor)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	// A NULL limit returns every row
+	var limitArg *int
+	if limit > 0 {
+		limitArg = &limit
+	}
+	query := fmt.Sprintf(`
+        SELECT DISTINCT server_name
+        FROM servers
+        %s
+        ORDER BY server_name
+        LIMIT $%d
+    `, whereClause, argIndex)
+	args = append(args, limitArg)
+
+	rows, err := db.getReadExecutor(ctx, tx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", queryError("failed to query server names", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, "", queryError("failed to scan server name", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", queryError("error iterating rows", err)
+	}
+
+	nextCursor := ""
+	if limit > 0 && len(names) >= limit {
+		nextCursor = names[len(names)-1]
+	}
+
+	return names, nextCursor, nil
+}
+
 // GetServerByName retrieves the latest version of a server by server name
 func (db *PostgreSQL) GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error) {
 	if ctx.Err() != nil {
@@ -230,43 +513,19 @@ func (db *PostgreSQL) GetServerByName(ctx context.Context, tx pgx.Tx, serverName
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND is_latest = true
 		ORDER BY published_at DESC
 		LIMIT 1
 	`
 
-	var name, version, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, serverName).Scan(&name, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+	serverResponse, err := scanServerRow(db.getReadExecutor(ctx, tx).QueryRow(ctx, query, serverName))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to get server by name: %w", err)
-	}
-
-	// Parse the ServerJSON from JSONB
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-	}
-
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+		return nil, queryError("failed to get server by name", err)
 	}
 
 	return serverResponse, nil
@@ -279,102 +538,138 @@ func (db *PostgreSQL) GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx,
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND version = $2
 		LIMIT 1
 	`
 
-	var name, vers, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, serverName, version).Scan(&name, &vers, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+	serverResponse, err := scanServerRow(db.getReadExecutor(ctx, tx).QueryRow(ctx, query, serverName, version))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to get server by name and version: %w", err)
+		return nil, queryError("failed to get server by name and version", err)
 	}
 
-	// Parse the ServerJSON from JSONB
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+	return serverResponse, nil
+}
+
+// GetServerVersions retrieves the server versions identified by refs in a single query, in the order of refs
+func (db *PostgreSQL) GetServerVersions(ctx context.Context, tx pgx.Tx, refs []ServerRef) ([]*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
+	return queryServerVersions(ctx, db.getReadExecutor(ctx, tx), refs)
+}
 
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+// queryServerVersions retrieves the server versions identified by refs with executor, in the order of refs
+func queryServerVersions(ctx context.Context, executor Executor, refs []ServerRef) ([]*apiv0.ServerResponse, error) {
+	names := make([]string, len(refs))
+	versions := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Name
+		versions[i] = ref.Version
 	}
 
-	return serverResponse, nil
+	query := `
+		SELECT ` + serverColumns + `
+		FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS refs(server_name, version, position)
+		JOIN servers USING (server_name, version)
+		ORDER BY refs.position
+	`
+
+	rows, err := executor.Query(ctx, query, names, versions)
+	if err != nil {
+		return nil, queryError("failed to query server versions", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerResponse
+	for rows.Next() {
+		serverResponse, err := scanServerRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, serverResponse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError("error iterating rows", err)
+	}
+
+	return results, nil
 }
 
-// GetAllVersionsByServerName retrieves all versions of a server by server name
-func (db *PostgreSQL) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+// GetVersionsAsOf retrieves all versions of a server published at or before a point in time
+func (db *PostgreSQL) GetVersionsAsOf(ctx context.Context, tx pgx.Tx, serverName string, at time.Time) ([]*apiv0.ServerResponse, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
-		WHERE server_name = $1
+		WHERE server_name = $1 AND published_at <= $2
 		ORDER BY published_at DESC
 	`
 
-	rows, err := db.getExecutor(tx).Query(ctx, query, serverName)
+	rows, err := db.getReadExecutor(ctx, tx).Query(ctx, query, serverName, at)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query server versions: %w", err)
+		return nil, queryError("failed to query server versions as of "+at.Format(time.RFC3339), err)
 	}
 	defer rows.Close()
 
 	var results []*apiv0.ServerResponse
 	for rows.Next() {
-		var name, version, status string
-		var publishedAt, updatedAt time.Time
-		var isLatest bool
-		var valueJSON []byte
-
-		err := rows.Scan(&name, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+		serverResponse, err := scanServerRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan server row: %w", err)
+			return nil, err
 		}
+		results = append(results, serverResponse)
+	}
 
-		// Parse the ServerJSON from JSONB
-		var serverJSON apiv0.ServerJSON
-		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-		}
+	if err := rows.Err(); err != nil {
+		return nil, queryError("error iterating rows", err)
+	}
 
-		// Build ServerResponse with separated metadata
-		serverResponse := &apiv0.ServerResponse{
-			Server: serverJSON,
-			Meta: apiv0.ResponseMeta{
-				Official: &apiv0.RegistryExtensions{
-					Status:      model.Status(status),
-					PublishedAt: publishedAt,
-					UpdatedAt:   updatedAt,
-					IsLatest:    isLatest,
-				},
-			},
-		}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return results, nil
+}
 
+// GetAllVersionsByServerName retrieves all versions of a server by server name
+func (db *PostgreSQL) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT ` + serverColumns + `
+		FROM servers
+		WHERE server_name = $1
+		ORDER BY published_at DESC
+	`
+
+	rows, err := db.getReadExecutor(ctx, tx).Query(ctx, query, serverName)
+	if err != nil {
+		return nil, queryError("failed to query server versions", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerResponse
+	for rows.Next() {
+		serverResponse, err := scanServerRow(rows)
+		if err != nil {
+			return nil, err
+		}
 		results = append(results, serverResponse)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		return nil, queryError("error iterating rows", err)
 	}
 
 	if len(results) == 0 {
@@ -407,8 +702,8 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *a
 
 	// Insert the new server version using composite primary key
 	insertQuery := `
-		INSERT INTO servers (server_name, version, status, published_at, updated_at, is_latest, value)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO servers (server_name, version, status, published_at, updated_at, is_latest, value, published_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
 	`
 
 	_, err = db.getExecutor(tx).Exec(ctx, insertQuery,
@@ -419,10 +714,11 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *a
 		officialMeta.UpdatedAt,
 		officialMeta.IsLatest,
 		valueJSON,
+		officialMeta.PublishedBy,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert server: %w", err)
+		return nil, queryError("failed to insert server", err)
 	}
 
 	// Return the complete ServerResponse
@@ -463,86 +759,108 @@ func (db *PostgreSQL) UpdateServer(ctx context.Context, tx pgx.Tx, serverName, v
 		UPDATE servers
 		SET value = $1, updated_at = NOW()
 		WHERE server_name = $2 AND version = $3
-		RETURNING server_name, version, status, published_at, updated_at, is_latest
-	`
-
-	var name, vers, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
+		RETURNING ` + serverColumns
 
-	err = db.getExecutor(tx).QueryRow(ctx, query, valueJSON, serverName, version).Scan(&name, &vers, &status, &publishedAt, &updatedAt, &isLatest)
+	serverResponse, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, valueJSON, serverName, version))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to update server: %w", err)
-	}
-
-	// Return the updated ServerResponse
-	serverResponse := &apiv0.ServerResponse{
-		Server: *serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+		return nil, queryError("failed to update server", err)
 	}
 
 	return serverResponse, nil
 }
 
 // SetServerStatus updates the status of a specific server version
-func (db *PostgreSQL) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status string) (*apiv0.ServerResponse, error) {
+func (db *PostgreSQL) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status, reason string) (*apiv0.ServerResponse, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	// Update the status column
+	// Update the status column; a replacement pointer only makes sense while the server is deprecated
 	query := `
 		UPDATE servers
-		SET status = $1, updated_at = NOW()
+		SET status = $1,
+			replaced_by = CASE WHEN $4 THEN replaced_by END,
+			status_reason = NULLIF($5, ''),
+			updated_at = NOW()
 		WHERE server_name = $2 AND version = $3
-		RETURNING server_name, version, status, value, published_at, updated_at, is_latest
-	`
+		RETURNING ` + serverColumns
 
-	var name, vers, currentStatus string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, status, serverName, version).Scan(&name, &vers, &currentStatus, &valueJSON, &publishedAt, &updatedAt, &isLatest)
+	keepReplacement := status == string(model.StatusDeprecated)
+	serverResponse, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, status, serverName, version, keepReplacement, reason))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to update server status: %w", err)
+		return nil, queryError("failed to update server status", err)
 	}
 
-	// Unmarshal the JSON data
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+	return serverResponse, nil
+}
+
+// DeprecateServer marks a specific server version as deprecated, optionally pointing at its replacement
+func (db *PostgreSQL) DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, replacedBy string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	// Return the updated ServerResponse
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(currentStatus),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+	query := `
+		UPDATE servers
+		SET status = 'deprecated', replaced_by = NULLIF($1, ''), status_reason = NULL, updated_at = NOW()
+		WHERE server_name = $2 AND version = $3
+		RETURNING ` + serverColumns
+
+	serverResponse, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, replacedBy, serverName, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, queryError("failed to deprecate server", err)
 	}
 
 	return serverResponse, nil
 }
 
+// RenameServer moves every version of a server to a new name, keeping the server JSON's name in step
+func (db *PostgreSQL) RenameServer(ctx context.Context, tx pgx.Tx, serverName, newName string) ([]*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		UPDATE servers
+		SET server_name = $1, value = jsonb_set(value, '{name}', to_jsonb($1::text)), updated_at = NOW()
+		WHERE server_name = $2
+		RETURNING ` + serverColumns
+
+	rows, err := db.getExecutor(tx).Query(ctx, query, newName, serverName)
+	if err != nil {
+		return nil, queryError("failed to rename server", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerResponse
+	for rows.Next() {
+		serverResponse, err := scanServerRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, serverResponse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError("error iterating rows", err)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return results, nil
+}
+
 // InTransaction executes a function within a database transaction
 func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
 	if ctx.Err() != nil {
@@ -551,7 +869,7 @@ func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Con
 
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return queryError("failed to begin transaction", err)
 	}
 	//nolint:contextcheck // Intentionally using separate context for rollback to ensure cleanup even if request is cancelled
 	defer func() {
@@ -567,7 +885,7 @@ func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Con
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return queryError("failed to commit transaction", err)
 	}
 
 	return nil
@@ -584,7 +902,7 @@ func (db *PostgreSQL) AcquirePublishLock(ctx context.Context, tx pgx.Tx, serverN
 	lockID := hashServerName(serverName)
 
 	if _, err := db.getExecutor(tx).Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockID); err != nil {
-		return fmt.Errorf("failed to acquire publish lock: %w", err)
+		return queryError("failed to acquire publish lock", err)
 	}
 
 	return nil
@@ -612,45 +930,18 @@ func (db *PostgreSQL) GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, se
 		return nil, ctx.Err()
 	}
 
-	executor := db.getExecutor(tx)
-
 	query := `
-		SELECT server_name, version, status, value, published_at, updated_at, is_latest
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND is_latest = true
 	`
 
-	row := executor.QueryRow(ctx, query, serverName)
-
-	var name, version, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var jsonValue []byte
-
-	err := row.Scan(&name, &version, &status, &jsonValue, &publishedAt, &updatedAt, &isLatest)
+	serverResponse, err := scanServerRow(db.getReadExecutor(ctx, tx).QueryRow(ctx, query, serverName))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to scan server row: %w", err)
-	}
-
-	// Parse the JSON value to get the server details
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(jsonValue, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-	}
-
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+		return nil, err
 	}
 
 	return serverResponse, nil
@@ -662,14 +953,33 @@ func (db *PostgreSQL) CountServerVersions(ctx context.Context, tx pgx.Tx, server
 		return 0, ctx.Err()
 	}
 
-	executor := db.getExecutor(tx)
+	executor := db.getReadExecutor(ctx, tx)
 
 	query := `SELECT COUNT(*) FROM servers WHERE server_name = $1`
 
 	var count int
 	err := executor.QueryRow(ctx, query, serverName).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count server versions: %w", err)
+		return 0, queryError("failed to count server versions", err)
+	}
+
+	return count, nil
+}
+
+// CountServerNames counts the number of distinct server names
+func (db *PostgreSQL) CountServerNames(ctx context.Context, tx pgx.Tx) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	executor := db.getReadExecutor(ctx, tx)
+
+	query := `SELECT COUNT(DISTINCT server_name) FROM servers`
+
+	var count int
+	err := executor.QueryRow(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, queryError("failed to count server names", err)
 	}
 
 	return count, nil
@@ -681,20 +991,20 @@ func (db *PostgreSQL) CheckVersionExists(ctx context.Context, tx pgx.Tx, serverN
 		return false, ctx.Err()
 	}
 
-	executor := db.getExecutor(tx)
+	executor := db.getReadExecutor(ctx, tx)
 
 	query := `SELECT EXISTS(SELECT 1 FROM servers WHERE server_name = $1 AND version = $2)`
 
 	var exists bool
 	err := executor.QueryRow(ctx, query, serverName, version).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("failed to check version existence: %w", err)
+		return false, queryError("failed to check version existence", err)
 	}
 
 	return exists, nil
 }
 
-// UnmarkAsLatest marks the current latest version of a server as no longer latest
+// UnmarkAsLatest marks the current latest version of a server as no longer latest, removing any pin
 func (db *PostgreSQL) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName string) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
@@ -702,11 +1012,58 @@ func (db *PostgreSQL) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName
 
 	executor := db.getExecutor(tx)
 
-	query := `UPDATE servers SET is_latest = false WHERE server_name = $1 AND is_latest = true`
+	query := `UPDATE servers SET is_latest = false, latest_pinned = false WHERE server_name = $1 AND (is_latest OR latest_pinned)`
 
 	_, err := executor.Exec(ctx, query, serverName)
 	if err != nil {
-		return fmt.Errorf("failed to unmark latest version: %w", err)
+		return queryError("failed to unmark latest version", err)
+	}
+
+	return nil
+}
+
+// SetLatestVersion marks one version of a server as the latest, pinned or not, and every other version as
+// neither
+func (db *PostgreSQL) SetLatestVersion(ctx context.Context, tx pgx.Tx, serverName, version string, pinned bool) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	exists, err := db.CheckVersionExists(ctx, tx, serverName, version)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrNotFound
+	}
+
+	query := `
+		UPDATE servers SET is_latest = (version = $2), latest_pinned = (version = $2 AND $3)
+		WHERE server_name = $1 AND (is_latest, latest_pinned) IS DISTINCT FROM (version = $2, version = $2 AND $3)
+	`
+
+	tag, err := db.getExecutor(tx).Exec(ctx, query, serverName, version, pinned)
+	if err != nil {
+		return 0, queryError("failed to set latest version", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteServerVersion permanently removes a specific server version
+func (db *PostgreSQL) DeleteServerVersion(ctx context.Context, tx pgx.Tx, serverName, version string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `DELETE FROM servers WHERE server_name = $1 AND version = $2`
+
+	tag, err := db.getExecutor(tx).Exec(ctx, query, serverName, version)
+	if err != nil {
+		return queryError("failed to delete server version", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
 	}
 
 	return nil
@@ -715,5 +1072,29 @@ func (db *PostgreSQL) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName
 // Close closes the database connection
 func (db *PostgreSQL) Close() error {
 	db.pool.Close()
+	if db.replica != nil {
+		db.replica.Close()
+	}
 	return nil
 }
+
+// CloseContext implements GracefulCloser. The pool stops handing out connections straight away and closes
+// each borrowed connection once it is returned, so queries in flight finish cleanly. If ctx is done first,
+// CloseContext returns while the pool finishes closing in the background.
+func (db *PostgreSQL) CloseContext(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		db.pool.Close()
+		if db.replica != nil {
+			db.replica.Close()
+		}
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("closed with %d connections still in use: %w", db.pool.Stat().AcquiredConns(), ctx.Err())
+	}
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		unavailable bool
+	}{
+		{name: "connection exception", err: &pgconn.PgError{Code: "08006", Message: "connection failure"}, unavailable: true},
+		{name: "server shutting down", err: &pgconn.PgError{Code: "57P01", Message: "terminating connection due to administrator command"}, unavailable: true},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300", Message: "sorry, too many clients already"}, unavailable: true},
+		{name: "query timeout", err: fmt.Errorf("scan: %w", context.DeadlineExceeded), unavailable: true},
+		{name: "syntax error", err: &pgconn.PgError{Code: "42601", Message: "syntax error"}, unavailable: false},
+		{name: "no rows", err: pgx.ErrNoRows, unavailable: false},
+		{name: "plain error", err: errors.New("boom"), unavailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := queryError("failed to get server by name", tt.err)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.unavailable, errors.Is(err, ErrDatabase))
+			assert.Contains(t, err.Error(), "failed to get server by name")
+		})
+	}
+
+	t.Run("already marked errors are not marked twice", func(t *testing.T) {
+		inner := queryError("failed to scan server row", &pgconn.PgError{Code: "08006", Message: "connection failure"})
+		err := queryError("failed to get server by name", inner)
+		assert.ErrorIs(t, err, ErrDatabase)
+		assert.Equal(t, 1, strings.Count(err.Error(), ErrDatabase.Error()), err.Error())
+	})
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgreSQLOption configures a PostgreSQL database
+type PostgreSQLOption func(*postgresOptions)
+
+// postgresOptions holds the settings PostgreSQLOptions apply before NewPostgreSQL connects
+type postgresOptions struct {
+	replicaURI string
+}
+
+// WithReadReplica sends queries made outside a transaction with a context marked ReadOnly to a read replica,
+// leaving writes, transactions and every other read on the primary. Reads fall back to the primary while the
+// replica is unavailable.
+func WithReadReplica(connectionURI string) PostgreSQLOption {
+	return func(o *postgresOptions) {
+		o.replicaURI = connectionURI
+	}
+}
+
+// newReplicaPool connects to a read replica. The replica not answering yet isn't fatal, since reads fall back to
+// the primary until it does, but a malformed connection string is.
+func newReplicaPool(ctx context.Context, connectionURI string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(connectionURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PostgreSQL replica config: %w", err)
+	}
+	config.MaxConns = 30
+	config.MaxConnIdleTime = 30 * time.Minute
+	config.MaxConnLifetime = 2 * time.Hour
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PostgreSQL replica pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		log.Printf("Warning: PostgreSQL read replica is unavailable, reading from the primary until it is: %v", err)
+	}
+	return pool, nil
+}
+
+// readOnlyKey is the context key ReadOnly marks contexts with
+type readOnlyKey struct{}
+
+// ReadOnly marks ctx as serving a request that only reads and tolerates replication lag, such as listing servers,
+// so its queries may go to a read replica. Reads without the mark, such as the checks a write makes before
+// writing, see the primary's latest data.
+func ReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx was marked by ReadOnly
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// getReadExecutor returns the executor for a query that only reads: the transaction if there is one, otherwise
+// the read replica with the primary as a fallback when a replica is configured and ctx is ReadOnly, otherwise
+// the primary
+func (db *PostgreSQL) getReadExecutor(ctx context.Context, tx pgx.Tx) Executor {
+	if tx != nil || db.replica == nil || !IsReadOnly(ctx) {
+		return db.getExecutor(tx)
+	}
+	return &replicaExecutor{replica: db.replica, primary: db.pool}
+}
+
+// replicaExecutor runs queries on a read replica, retrying them on the primary when the replica is unavailable.
+// Exec always runs on the primary, since it may write.
+type replicaExecutor struct {
+	replica Executor
+	primary Executor
+}
+
+func (e *replicaExecutor) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return e.primary.Exec(ctx, sql, arguments...)
+}
+
+func (e *replicaExecutor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := e.replica.Query(ctx, sql, args...)
+	if err != nil && ctx.Err() == nil && isUnavailable(err) {
+		log.Printf("Read replica unavailable, querying the primary: %v", err)
+		return e.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+func (e *replicaExecutor) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &replicaRow{ctx: ctx, executor: e, sql: sql, args: args}
+}
+
+// replicaRow defers a single-row query until it is scanned, since pgx only reports a row's errors then
+type replicaRow struct {
+	ctx      context.Context
+	executor *replicaExecutor
+	sql      string
+	args     []any
+}
+
+func (r *replicaRow) Scan(dest ...any) error {
+	err := r.executor.replica.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	if err != nil && r.ctx.Err() == nil && isUnavailable(err) {
+		log.Printf("Read replica unavailable, querying the primary: %v", err)
+		return r.executor.primary.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingExecutor is an Executor that records the queries sent to it and fails them with err
+type recordingExecutor struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (e *recordingExecutor) Exec(_ context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+	*e.calls = append(*e.calls, "exec "+e.name)
+	return pgconn.CommandTag{}, e.err
+}
+
+func (e *recordingExecutor) Query(_ context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	*e.calls = append(*e.calls, "query "+e.name)
+	return nil, e.err
+}
+
+func (e *recordingExecutor) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	return recordingRow{e}
+}
+
+type recordingRow struct {
+	executor *recordingExecutor
+}
+
+func (r recordingRow) Scan(_ ...any) error {
+	*r.executor.calls = append(*r.executor.calls, "scan "+r.executor.name)
+	return r.executor.err
+}
+
+func TestReplicaExecutor(t *testing.T) {
+	ctx := context.Background()
+	unavailable := &pgconn.PgError{Code: "57P03"} // cannot_connect_now
+
+	tests := []struct {
+		name       string
+		replicaErr error
+		wantCalls  []string
+		wantErr    error
+	}{
+		{
+			name:      "reads use the replica",
+			wantCalls: []string{"query replica", "scan replica", "exec primary"},
+		},
+		{
+			name:       "reads fall back to the primary while the replica is unavailable",
+			replicaErr: unavailable,
+			wantCalls:  []string{"query replica", "query primary", "scan replica", "scan primary", "exec primary"},
+		},
+		{
+			name:       "other replica errors are returned",
+			replicaErr: pgx.ErrNoRows,
+			wantCalls:  []string{"query replica", "scan replica", "exec primary"},
+			wantErr:    pgx.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			executor := &replicaExecutor{
+				replica: &recordingExecutor{name: "replica", calls: &calls, err: tt.replicaErr},
+				primary: &recordingExecutor{name: "primary", calls: &calls},
+			}
+
+			_, err := executor.Query(ctx, "SELECT 1")
+			assert.ErrorIs(t, err, tt.wantErr)
+			err = executor.QueryRow(ctx, "SELECT 1").Scan()
+			assert.ErrorIs(t, err, tt.wantErr)
+			_, err = executor.Exec(ctx, "UPDATE servers SET status = 'active'")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestReplicaExecutor_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls []string
+	executor := &replicaExecutor{
+		replica: &recordingExecutor{name: "replica", calls: &calls, err: context.Canceled},
+		primary: &recordingExecutor{name: "primary", calls: &calls},
+	}
+
+	// A canceled read isn't the replica's fault, so it isn't retried on the primary
+	_, err := executor.Query(ctx, "SELECT 1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"query replica"}, calls)
+}
+
+func TestGetReadExecutor(t *testing.T) {
+	db := &PostgreSQL{replica: &pgxpool.Pool{}}
+
+	_, onReplica := db.getReadExecutor(ReadOnly(context.Background()), nil).(*replicaExecutor)
+	assert.True(t, onReplica, "read-only reads should use the replica")
+
+	_, onReplica = db.getReadExecutor(context.Background(), nil).(*replicaExecutor)
+	assert.False(t, onReplica, "reads that aren't marked read-only should use the primary")
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldSearchText normalizes text for substring search: compatibility decomposition (NFKD) splits accented
+// letters from their combining marks, which are dropped, and the result is lowercased, so "Café" and
+// "cafe" fold to the same string. The PostgreSQL backend gets the same effect from unaccent and ILIKE.
+func foldSearchText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// matchesSubstring reports whether text contains substring, ignoring case and diacritics
+func matchesSubstring(text, substring string) bool {
+	return strings.Contains(foldSearchText(text), foldSearchText(substring))
+}
+
+// DefaultMinSimilarity is the trigram similarity a server name needs to match a fuzzy search when none is
+// configured, the same as pg_trgm's default similarity threshold
+const DefaultMinSimilarity = 0.3
+
+// nameSimilarity scores how closely a server name matches a fuzzy search query, from 0 to 1: the trigram
+// similarity of the query to the whole name or, if higher, to the part after the namespace, so a query for
+// "wether" finds "io.github.user/weather". The PostgreSQL backend computes the same score with pg_trgm.
+func nameSimilarity(serverName, query string) float64 {
+	_, name, _ := strings.Cut(serverName, "/")
+	return max(trigramSimilarity(serverName, query), trigramSimilarity(name, query))
+}
+
+// trigramSimilarity mirrors pg_trgm's similarity(): the number of trigrams two strings share divided by the
+// number of distinct trigrams in either
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	shared := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+	total := len(ta) + len(tb) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+// trigrams returns the distinct trigrams of s as pg_trgm extracts them: s is lowercased and split into words
+// of letters and digits, and each word is padded with two spaces in front and one behind
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
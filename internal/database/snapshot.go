@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// snapshotIDPattern restricts snapshot IDs to characters that are safe in URLs and file names
+var snapshotIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Snapshot is an immutable copy of the registry's server versions, stored in the JSON file database format
+type Snapshot struct {
+	ID        string
+	SHA256    string // hex digest of Content
+	CreatedAt time.Time
+	Content   []byte
+}
+
+// NewSnapshot builds a snapshot of content, identified by id or, when id is empty, by a prefix of the
+// content's digest so that identical registries get the same ID
+func NewSnapshot(id string, content []byte, createdAt time.Time) (*Snapshot, error) {
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	if id == "" {
+		id = digest[:16]
+	}
+	if !snapshotIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: snapshot ID must be 1 to 64 letters, digits, '.', '_' or '-', starting with a letter or digit", ErrInvalidInput)
+	}
+	return &Snapshot{ID: id, SHA256: digest, CreatedAt: createdAt, Content: content}, nil
+}
+
+// SnapshotStore is implemented by databases that can keep snapshots of the registry
+type SnapshotStore interface {
+	// CreateSnapshot stores a snapshot, failing with ErrAlreadyExists if its ID is taken; snapshots are never replaced
+	CreateSnapshot(ctx context.Context, snapshot *Snapshot) error
+	// GetSnapshot retrieves a snapshot by ID
+	GetSnapshot(ctx context.Context, id string) (*Snapshot, error)
+	// SnapshotServers retrieves every server version as of a single point in time, ordered by name, then version
+	SnapshotServers(ctx context.Context) ([]*apiv0.ServerResponse, error)
+}
+
+// snapshotDir returns the directory snapshots are kept in, next to the JSON file
+func (db *JSONFileDB) snapshotDir() string {
+	return db.filePath + ".snapshots"
+}
+
+// CreateSnapshot implements SnapshotStore.CreateSnapshot by writing the snapshot to its own file
+func (db *JSONFileDB) CreateSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if !snapshotIDPattern.MatchString(snapshot.ID) {
+		return fmt.Errorf("%w: invalid snapshot ID %q", ErrInvalidInput, snapshot.ID)
+	}
+
+	dir := db.snapshotDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("%w: failed to create snapshot directory: %v", ErrDatabase, err)
+	}
+
+	tempFile, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("%w: failed to write snapshot: %v", ErrDatabase, err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(snapshot.Content); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("%w: failed to write snapshot: %v", ErrDatabase, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("%w: failed to write snapshot: %v", ErrDatabase, err)
+	}
+	if err := os.Chtimes(tempFile.Name(), snapshot.CreatedAt, snapshot.CreatedAt); err != nil {
+		return fmt.Errorf("%w: failed to write snapshot: %v", ErrDatabase, err)
+	}
+
+	// Linking, unlike renaming, fails rather than replacing an existing snapshot
+	if err := os.Link(tempFile.Name(), filepath.Join(dir, snapshot.ID+".json")); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("%w: failed to store snapshot: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// GetSnapshot implements SnapshotStore.GetSnapshot
+func (db *JSONFileDB) GetSnapshot(ctx context.Context, id string) (*Snapshot, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if !snapshotIDPattern.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	path := filepath.Join(db.snapshotDir(), id+".json")
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read snapshot: %v", ErrDatabase, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read snapshot: %v", ErrDatabase, err)
+	}
+
+	sum := sha256.Sum256(content)
+	return &Snapshot{
+		ID:        id,
+		SHA256:    hex.EncodeToString(sum[:]),
+		CreatedAt: info.ModTime().UTC(),
+		Content:   content,
+	}, nil
+}
+
+// SnapshotServers implements SnapshotStore.SnapshotServers from one copy of the server records, which writers
+// never modify in place
+func (db *JSONFileDB) SnapshotServers(ctx context.Context) ([]*apiv0.ServerResponse, error) {
+	servers := db.snapshot()
+	order, err := listOrder(ctx, servers)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*apiv0.ServerResponse, 0, len(servers))
+	for _, i := range order {
+		if servers[i].Value != nil {
+			results = append(results, servers[i].response())
+		}
+	}
+	return results, nil
+}
+
+// SnapshotServers implements SnapshotStore.SnapshotServers in a read-only REPEATABLE READ transaction on the
+// primary, so the snapshot neither mixes states nor lags behind like the read replica may
+func (db *PostgreSQL) SnapshotServers(ctx context.Context) ([]*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, queryError("failed to begin snapshot transaction", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `SELECT `+serverColumns+` FROM servers ORDER BY server_name, version`)
+	if err != nil {
+		return nil, queryError("failed to query servers for snapshot", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerResponse
+	for rows.Next() {
+		serverResponse, err := scanServerRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, serverResponse)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError("error iterating rows", err)
+	}
+	return results, nil
+}
+
+// CreateSnapshot implements SnapshotStore.CreateSnapshot
+func (db *PostgreSQL) CreateSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO snapshots (id, sha256, created_at, content)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+	`
+	tag, err := db.pool.Exec(ctx, query, snapshot.ID, snapshot.SHA256, snapshot.CreatedAt, snapshot.Content)
+	if err != nil {
+		return queryError("failed to create snapshot", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+// GetSnapshot implements SnapshotStore.GetSnapshot
+func (db *PostgreSQL) GetSnapshot(ctx context.Context, id string) (*Snapshot, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `SELECT id, sha256, created_at, content FROM snapshots WHERE id = $1`
+
+	var snapshot Snapshot
+	err := db.pool.QueryRow(ctx, query, id).Scan(&snapshot.ID, &snapshot.SHA256, &snapshot.CreatedAt, &snapshot.Content)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, queryError("failed to get snapshot", err)
+	}
+	return &snapshot, nil
+}
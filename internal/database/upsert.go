@@ -0,0 +1,241 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// LatestPolicy decides which version of each imported server UpsertFromJSONFile leaves marked as the latest
+type LatestPolicy string
+
+const (
+	// LatestFromFile makes a version the file marks as latest the latest, overriding the stored flags, whether the
+	// version is new or already stored, and pins it if the file does. Servers with no version marked latest in the
+	// file keep their stored one.
+	LatestFromFile LatestPolicy = "file"
+	// LatestRecompute ignores the file's flags and recomputes the latest version of each imported server from all
+	// of its stored versions once the file's records are in place, leaving servers whose stored latest version is
+	// pinned alone
+	LatestRecompute LatestPolicy = "recompute"
+)
+
+// UpsertOptions configures UpsertFromJSONFile
+type UpsertOptions struct {
+	LatestPolicy LatestPolicy // defaults to LatestFromFile
+	// PickLatest picks the latest of a server's versions under LatestRecompute, which requires it
+	PickLatest func(versions []*apiv0.ServerResponse) *apiv0.ServerResponse
+	// OnChange, if set, is called once the import has committed with each version of an imported server that the
+	// import created or changed, including versions whose latest flag it changed, and whether it created it
+	OnChange func(server *apiv0.ServerResponse, created bool)
+}
+
+// UpsertFromJSONFile imports the records of a JSON file database file into db, so databases that
+// aren't served from the file can be synced from it. Versions that already exist are updated in place,
+// new versions are created, and versions missing from the file are left alone. Latest flags follow
+// opts.LatestPolicy. The import runs in a single transaction and returns the number of records imported.
+func UpsertFromJSONFile(ctx context.Context, db Database, filePath string, opts UpsertOptions) (int, error) {
+	switch opts.LatestPolicy {
+	case "":
+		opts.LatestPolicy = LatestFromFile
+	case LatestFromFile:
+	case LatestRecompute:
+		if opts.PickLatest == nil {
+			return 0, fmt.Errorf("%w: recomputing latest versions needs a way to pick them", ErrInvalidInput)
+		}
+	default:
+		return 0, fmt.Errorf("%w: unknown latest policy %q (must be %q or %q)", ErrInvalidInput, opts.LatestPolicy, LatestFromFile, LatestRecompute)
+	}
+
+	data, _, err := readJSONFile(filePath, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var changes []importChange
+	err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		locked := make(map[string]bool)
+		var imported []string                              // server names in the order they first appear
+		before := make(map[string][]*apiv0.ServerResponse) // stored versions of each imported server, if reported
+		for i := range data.Servers {
+			record := &data.Servers[i]
+			if record.Value == nil {
+				return fmt.Errorf("%w: record %s@%s has no server", ErrInvalidInput, record.ServerName, record.Version)
+			}
+
+			// Serialize with concurrent publishes of the same server, once per server as locks aren't reentrant
+			if !locked[record.ServerName] {
+				if err := db.AcquirePublishLock(ctx, tx, record.ServerName); err != nil {
+					return err
+				}
+				locked[record.ServerName] = true
+				imported = append(imported, record.ServerName)
+				if opts.OnChange != nil {
+					versions, err := storedVersions(ctx, db, tx, record.ServerName)
+					if err != nil {
+						return err
+					}
+					before[record.ServerName] = versions
+				}
+			}
+
+			if err := upsertRecord(ctx, db, tx, record, opts.LatestPolicy); err != nil {
+				return fmt.Errorf("failed to import %s@%s: %w", record.ServerName, record.Version, err)
+			}
+		}
+
+		if opts.LatestPolicy == LatestRecompute {
+			for _, serverName := range imported {
+				if err := recomputeLatest(ctx, db, tx, serverName, opts.PickLatest); err != nil {
+					return fmt.Errorf("failed to recompute the latest version of %s: %w", serverName, err)
+				}
+			}
+		}
+
+		if opts.OnChange != nil {
+			for _, serverName := range imported {
+				after, err := storedVersions(ctx, db, tx, serverName)
+				if err != nil {
+					return err
+				}
+				changes = append(changes, diffVersions(before[serverName], after)...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, change := range changes {
+		opts.OnChange(change.server, change.created)
+	}
+	return len(data.Servers), nil
+}
+
+// importChange is a version an import created or changed
+type importChange struct {
+	server  *apiv0.ServerResponse
+	created bool
+}
+
+// storedVersions returns every stored version of a server, or none if it has no versions
+func storedVersions(ctx context.Context, db Database, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	versions, err := db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return versions, err
+}
+
+// diffVersions returns the versions in after that are missing from before or differ from it in more than when
+// they were last updated
+func diffVersions(before, after []*apiv0.ServerResponse) []importChange {
+	previous := make(map[string]*apiv0.ServerResponse, len(before))
+	for _, version := range before {
+		previous[version.Server.Version] = version
+	}
+
+	var changes []importChange
+	for _, version := range after {
+		old, ok := previous[version.Server.Version]
+		if !ok || !sameStoredVersion(old, version) {
+			changes = append(changes, importChange{server: version, created: !ok})
+		}
+	}
+	return changes
+}
+
+// sameStoredVersion reports whether two reads of a server version differ only in when it was last updated
+func sameStoredVersion(a, b *apiv0.ServerResponse) bool {
+	aJSON, errA := json.Marshal(withoutUpdatedAt(a))
+	bJSON, errB := json.Marshal(withoutUpdatedAt(b))
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// withoutUpdatedAt returns a copy of server with its last update time cleared
+func withoutUpdatedAt(server *apiv0.ServerResponse) apiv0.ServerResponse {
+	result := *server
+	if server.Meta.Official != nil {
+		official := *server.Meta.Official
+		official.UpdatedAt = time.Time{}
+		result.Meta.Official = &official
+	}
+	return result
+}
+
+// upsertRecord creates or updates a single server version, bringing its status in line with the record. Under
+// LatestFromFile the record's latest flag is applied; otherwise new versions are created as not the latest.
+func upsertRecord(ctx context.Context, db Database, tx pgx.Tx, record *serverRecord, policy LatestPolicy) error {
+	exists, err := db.CheckVersionExists(ctx, tx, record.ServerName, record.Version)
+	if err != nil {
+		return err
+	}
+
+	markLatest := record.IsLatest && policy == LatestFromFile
+	var current *apiv0.ServerResponse
+	if exists {
+		current, err = db.UpdateServer(ctx, tx, record.ServerName, record.Version, record.Value)
+		if err == nil && markLatest && (current.Meta.Official == nil || !current.Meta.Official.IsLatest || current.Meta.Official.LatestPinned != record.LatestPinned) {
+			if _, err := db.SetLatestVersion(ctx, tx, record.ServerName, record.Version, record.LatestPinned); err != nil {
+				return err
+			}
+		}
+	} else {
+		if markLatest {
+			// Only one version of a server can be the latest
+			if err := db.UnmarkAsLatest(ctx, tx, record.ServerName); err != nil {
+				return err
+			}
+		}
+		current, err = db.CreateServer(ctx, tx, record.Value, &apiv0.RegistryExtensions{
+			Status:      model.Status(record.Status),
+			PublishedAt: record.PublishedAt,
+			UpdatedAt:   record.UpdatedAt,
+			IsLatest:    markLatest,
+			PublishedBy: record.PublishedBy,
+		})
+		if err == nil && markLatest && record.LatestPinned {
+			_, err = db.SetLatestVersion(ctx, tx, record.ServerName, record.Version, true)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	official := current.Meta.Official
+	if official != nil && string(official.Status) == record.Status && official.ReplacedBy == record.ReplacedBy && official.StatusReason == record.StatusReason {
+		return nil
+	}
+	if record.Status == string(model.StatusDeprecated) {
+		_, err = db.DeprecateServer(ctx, tx, record.ServerName, record.Version, record.ReplacedBy)
+	} else {
+		_, err = db.SetServerStatus(ctx, tx, record.ServerName, record.Version, record.Status, record.StatusReason)
+	}
+	return err
+}
+
+// recomputeLatest marks the version pick chooses among all stored versions of a server as its latest, unless an
+// administrator pinned one
+func recomputeLatest(ctx context.Context, db Database, tx pgx.Tx, serverName string, pick func([]*apiv0.ServerResponse) *apiv0.ServerResponse) error {
+	versions, err := db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if err != nil {
+		return err
+	}
+	if PinnedLatest(versions) != nil {
+		return nil
+	}
+	latest := pick(versions)
+	if latest == nil {
+		return nil
+	}
+	_, err = db.SetLatestVersion(ctx, tx, serverName, latest.Server.Version, false)
+	return err
+}
@@ -0,0 +1,292 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// recordingDatabase is an in-memory Database that records the writes made to it
+type recordingDatabase struct {
+	database.Database
+	servers map[string]*apiv0.ServerResponse // keyed by name@version
+	calls   []string
+}
+
+func (d *recordingDatabase) InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	return fn(ctx, nil)
+}
+
+func (d *recordingDatabase) AcquirePublishLock(_ context.Context, _ pgx.Tx, serverName string) error {
+	d.calls = append(d.calls, "lock "+serverName)
+	return nil
+}
+
+func (d *recordingDatabase) CheckVersionExists(_ context.Context, _ pgx.Tx, serverName, version string) (bool, error) {
+	_, ok := d.servers[serverName+"@"+version]
+	return ok, nil
+}
+
+func (d *recordingDatabase) CreateServer(_ context.Context, _ pgx.Tx, serverJSON *apiv0.ServerJSON, officialMeta *apiv0.RegistryExtensions) (*apiv0.ServerResponse, error) {
+	d.calls = append(d.calls, "create "+serverJSON.Name+"@"+serverJSON.Version)
+	server := &apiv0.ServerResponse{Server: *serverJSON, Meta: apiv0.ResponseMeta{Official: officialMeta}}
+	d.servers[serverJSON.Name+"@"+serverJSON.Version] = server
+	return server, nil
+}
+
+func (d *recordingDatabase) UpdateServer(_ context.Context, _ pgx.Tx, serverName, version string, serverJSON *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+	d.calls = append(d.calls, "update "+serverName+"@"+version)
+	server := d.servers[serverName+"@"+version]
+	server.Server = *serverJSON
+	return server, nil
+}
+
+func (d *recordingDatabase) UnmarkAsLatest(_ context.Context, _ pgx.Tx, serverName string) error {
+	d.calls = append(d.calls, "unmark "+serverName)
+	for _, server := range d.servers {
+		if server.Server.Name == serverName {
+			server.Meta.Official.IsLatest = false
+		}
+	}
+	return nil
+}
+
+func (d *recordingDatabase) SetServerStatus(_ context.Context, _ pgx.Tx, serverName, version string, status, reason string) (*apiv0.ServerResponse, error) {
+	d.calls = append(d.calls, "status "+serverName+"@"+version+" "+status)
+	server := d.servers[serverName+"@"+version]
+	server.Meta.Official.Status = model.Status(status)
+	server.Meta.Official.StatusReason = reason
+	return server, nil
+}
+
+func (d *recordingDatabase) DeprecateServer(_ context.Context, _ pgx.Tx, serverName, version, replacedBy string) (*apiv0.ServerResponse, error) {
+	d.calls = append(d.calls, "deprecate "+serverName+"@"+version)
+	server := d.servers[serverName+"@"+version]
+	server.Meta.Official.Status = model.StatusDeprecated
+	server.Meta.Official.ReplacedBy = replacedBy
+	return server, nil
+}
+
+func TestUpsertFromJSONFile(t *testing.T) {
+	ctx := context.Background()
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	server := func(name, version, description string) apiv0.ServerJSON {
+		return apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: description,
+			Version:     version,
+		}
+	}
+	record := func(name, version, status string, isLatest bool, replacedBy string) map[string]any {
+		return map[string]any{
+			"server_name":  name,
+			"version":      version,
+			"status":       status,
+			"published_at": published,
+			"updated_at":   published,
+			"is_latest":    isLatest,
+			"replaced_by":  replacedBy,
+			"published_by": "octocat",
+			"value":        server(name, version, "Description from S3"),
+		}
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"servers": []map[string]any{
+			record("com.example/existing", "1.0.0", string(model.StatusDeprecated), true, "com.example/successor"),
+			record("com.example/successor", "1.0.0", string(model.StatusActive), false, ""),
+			record("com.example/successor", "2.0.0", string(model.StatusActive), true, ""),
+		},
+	})
+	require.NoError(t, err)
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, payload, 0600))
+
+	existing := server("com.example/existing", "1.0.0", "Stale description")
+	db := &recordingDatabase{servers: map[string]*apiv0.ServerResponse{
+		"com.example/existing@1.0.0": {
+			Server: existing,
+			Meta: apiv0.ResponseMeta{Official: &apiv0.RegistryExtensions{
+				Status:   model.StatusActive,
+				IsLatest: true,
+			}},
+		},
+	}}
+
+	imported, err := database.UpsertFromJSONFile(ctx, db, filePath, database.UpsertOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, imported)
+
+	assert.Equal(t, []string{
+		"lock com.example/existing",
+		"update com.example/existing@1.0.0",
+		"deprecate com.example/existing@1.0.0",
+		"lock com.example/successor",
+		"create com.example/successor@1.0.0",
+		"unmark com.example/successor",
+		"create com.example/successor@2.0.0",
+	}, db.calls)
+
+	updated := db.servers["com.example/existing@1.0.0"]
+	assert.Equal(t, "Description from S3", updated.Server.Description)
+	assert.Equal(t, model.StatusDeprecated, updated.Meta.Official.Status)
+	assert.Equal(t, "com.example/successor", updated.Meta.Official.ReplacedBy)
+
+	created := db.servers["com.example/successor@2.0.0"]
+	assert.True(t, created.Meta.Official.IsLatest)
+	assert.Equal(t, published, created.Meta.Official.PublishedAt)
+	assert.Equal(t, "octocat", created.Meta.Official.PublishedBy)
+	assert.False(t, db.servers["com.example/successor@1.0.0"].Meta.Official.IsLatest)
+
+	t.Run("unreadable file makes no changes", func(t *testing.T) {
+		badPath := filepath.Join(t.TempDir(), "bad.json")
+		require.NoError(t, os.WriteFile(badPath, []byte(`{"servers": [`), 0600))
+		db := &recordingDatabase{servers: map[string]*apiv0.ServerResponse{}}
+
+		_, err := database.UpsertFromJSONFile(ctx, db, badPath, database.UpsertOptions{})
+		require.Error(t, err)
+		assert.Empty(t, db.calls)
+	})
+}
+
+func TestUpsertFromJSONFile_LatestPolicy(t *testing.T) {
+	ctx := context.Background()
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	record := func(name, version string, isLatest bool) map[string]any {
+		return map[string]any{
+			"server_name":  name,
+			"version":      version,
+			"status":       string(model.StatusActive),
+			"published_at": published,
+			"updated_at":   published,
+			"is_latest":    isLatest,
+			"value": apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "Imported server",
+				Version:     version,
+			},
+		}
+	}
+
+	// The file marks older versions as the latest, conflicting with what is stored for the existing server
+	payload, err := json.Marshal(map[string]any{
+		"servers": []map[string]any{
+			record("com.example/existing", "1.0.0", true),
+			record("com.example/existing", "2.0.0", false),
+			record("com.example/new", "1.0.0", true),
+			record("com.example/new", "1.1.0", false),
+		},
+	})
+	require.NoError(t, err)
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(filePath, payload, 0600))
+
+	// Picks the highest version; comparing as strings is enough for the versions used here
+	pickHighest := func(versions []*apiv0.ServerResponse) *apiv0.ServerResponse {
+		var highest *apiv0.ServerResponse
+		for _, version := range versions {
+			if highest == nil || version.Server.Version > highest.Server.Version {
+				highest = version
+			}
+		}
+		return highest
+	}
+
+	tests := []struct {
+		name       string
+		opts       database.UpsertOptions
+		wantLatest map[string]string
+	}{
+		{
+			name:       "file flags override stored ones",
+			opts:       database.UpsertOptions{LatestPolicy: database.LatestFromFile},
+			wantLatest: map[string]string{"com.example/existing": "1.0.0", "com.example/new": "1.0.0"},
+		},
+		{
+			name:       "latest is recomputed",
+			opts:       database.UpsertOptions{LatestPolicy: database.LatestRecompute, PickLatest: pickHighest},
+			wantLatest: map[string]string{"com.example/existing": "2.0.0", "com.example/new": "1.1.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewTestJSONFileDB(t)
+			for _, version := range []string{"1.0.0", "2.0.0"} {
+				_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+					Schema:      model.CurrentSchemaURL,
+					Name:        "com.example/existing",
+					Description: "Stored server",
+					Version:     version,
+				}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: published, UpdatedAt: published, IsLatest: version == "2.0.0"})
+				require.NoError(t, err)
+			}
+
+			imported, err := database.UpsertFromJSONFile(ctx, db, filePath, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, 4, imported)
+
+			for name, want := range tt.wantLatest {
+				versions, err := db.GetAllVersionsByServerName(ctx, nil, name)
+				require.NoError(t, err)
+				var latest []string
+				for _, version := range versions {
+					if version.Meta.Official.IsLatest {
+						latest = append(latest, version.Server.Version)
+					}
+				}
+				assert.Equal(t, []string{want}, latest, "latest versions of %s", name)
+			}
+		})
+	}
+
+	t.Run("invalid policies are rejected before importing", func(t *testing.T) {
+		for _, opts := range []database.UpsertOptions{
+			{LatestPolicy: "newest"},
+			{LatestPolicy: database.LatestRecompute},
+		} {
+			db := &recordingDatabase{servers: map[string]*apiv0.ServerResponse{}}
+			_, err := database.UpsertFromJSONFile(ctx, db, filePath, opts)
+			require.ErrorIs(t, err, database.ErrInvalidInput)
+			assert.Empty(t, db.calls)
+		}
+	})
+
+	t.Run("pinned latest versions are not recomputed", func(t *testing.T) {
+		db := database.NewTestJSONFileDB(t)
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/existing",
+				Description: "Stored server",
+				Version:     version,
+			}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: published, UpdatedAt: published})
+			require.NoError(t, err)
+		}
+		_, err := db.SetLatestVersion(ctx, nil, "com.example/existing", "1.0.0", true)
+		require.NoError(t, err)
+
+		_, err = database.UpsertFromJSONFile(ctx, db, filePath, database.UpsertOptions{LatestPolicy: database.LatestRecompute, PickLatest: pickHighest})
+		require.NoError(t, err)
+
+		latest, err := db.GetServerByName(ctx, nil, "com.example/existing")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Server.Version)
+		assert.True(t, latest.Meta.Official.LatestPinned)
+	})
+}
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+)
+
+// Write-ahead log operations
+const (
+	walPut          = "put"           // insert or replace a server record
+	walUnmarkLatest = "unmark_latest" // clear is_latest and latest_pinned on every version of a server
+	walRename       = "rename"        // replace every version of a server with its renamed record
+	walSetLatest    = "set_latest"    // mark one version of a server as latest, pinned or not, and the others as neither
+	walDelete       = "delete"        // remove one version of a server
+)
+
+// walEntry is one mutation in the write-ahead log, stored as a line of compact JSON.
+// Entries carry the resulting record rather than the operation's arguments, so replaying them is
+// idempotent and doesn't depend on the time it happens. Records carry their change sequence numbers;
+// the versions other operations change are numbered consecutively from ChangeSeq.
+type walEntry struct {
+	Op         string         `json:"op"`
+	Record     *serverRecord  `json:"record,omitempty"`
+	Records    []serverRecord `json:"records,omitempty"`
+	ServerName string         `json:"server_name,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Pinned     bool           `json:"pinned,omitempty"`
+	ChangeSeq  int64          `json:"change_seq,omitempty"`
+}
+
+// walPath returns the path of the write-ahead log kept next to the JSON file
+func (db *JSONFileDB) walPath() string {
+	return db.filePath + ".wal"
+}
+
+// openWAL opens the log for appending and replays any mutations logged since the JSON file was last
+// written. The log holds one process's unflushed changes, and its flushes truncate it, so the process
+// keeps an exclusive lock on it while it is open and opening it fails with ErrFileLocked while another
+// process has it open. Callers must hold db.mu for writing or have exclusive access to db.
+func (db *JSONFileDB) openWAL() error {
+	f, err := os.OpenFile(db.walPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	if err := tryLockFile(f, true); err != nil {
+		f.Close()
+		if errors.Is(err, errWouldBlock) {
+			return fmt.Errorf("%w: write-ahead log %s is in use by another process", ErrFileLocked, db.walPath())
+		}
+		return fmt.Errorf("failed to lock write-ahead log %s: %w", db.walPath(), err)
+	}
+
+	replayed, err := db.replayWAL()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to replay write-ahead log %s: %w", db.walPath(), err)
+	}
+	if replayed > 0 {
+		log.Printf("Replayed %d write-ahead log entries from %s", replayed, db.walPath())
+		// The replayed changes aren't in the JSON file until the next flush
+		db.dirty = true
+	}
+
+	db.wal = f
+	return nil
+}
+
+// replayWAL applies the entries of an existing write-ahead log to the in-memory data, returning
+// how many were applied. A final entry cut short by a crash mid-append is ignored.
+func (db *JSONFileDB) replayWAL() (int, error) {
+	data, err := os.ReadFile(db.walPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	applied := 0
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry walEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			if i == len(lines)-1 {
+				log.Printf("Warning: ignoring incomplete final write-ahead log entry: %v", err)
+				break
+			}
+			return applied, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		// Logged records are local changes the JSON file doesn't have yet
+		if entry.Record != nil {
+			entry.Record.unsynced = true
+		}
+		for j := range entry.Records {
+			entry.Records[j].unsynced = true
+		}
+		if err := db.applyWALEntry(entry); err != nil {
+			return applied, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// applyWALEntry applies a logged mutation to the in-memory data, numbering the changes it makes and
+// keeping a tombstone for each version it removes
+func (db *JSONFileDB) applyWALEntry(entry walEntry) error {
+	servers := slices.Clone(db.data.Servers)
+	removed := db.data.Removed
+	changeSeq := db.data.ChangeSeq
+
+	// next numbers the entry's changes from its first sequence number, or after the database's when
+	// it was logged without one
+	seq := entry.ChangeSeq
+	next := func() int64 {
+		if seq == 0 {
+			seq = changeSeq + 1
+		}
+		changeSeq = max(changeSeq, seq)
+		seq++
+		return seq - 1
+	}
+	remove := func(serverName, version string) {
+		// Clip forces append to allocate, so slices held by readers are never written to
+		removed = append(slices.Clip(dropRemoved(removed, serverName, version)), removedRecord{ServerName: serverName, Version: version, ChangeSeq: next(), unsynced: true})
+	}
+
+	switch entry.Op {
+	case walPut:
+		if entry.Record == nil {
+			return errors.New("put entry without a record")
+		}
+		record := *entry.Record
+		if record.ChangeSeq == 0 {
+			record.ChangeSeq = next()
+		}
+		changeSeq = max(changeSeq, record.ChangeSeq)
+		i := slices.IndexFunc(servers, func(r serverRecord) bool {
+			return r.ServerName == record.ServerName && r.Version == record.Version
+		})
+		if i < 0 {
+			servers = append(servers, record)
+		} else {
+			servers[i] = record
+		}
+		removed = dropRemoved(removed, record.ServerName, record.Version)
+	case walUnmarkLatest:
+		for i := range servers {
+			if servers[i].ServerName == entry.ServerName && (servers[i].IsLatest || servers[i].LatestPinned) {
+				servers[i].IsLatest = false
+				servers[i].LatestPinned = false
+				servers[i].ChangeSeq = next()
+			}
+		}
+	case walRename:
+		for i := range servers {
+			if servers[i].ServerName != entry.ServerName {
+				continue
+			}
+			j := slices.IndexFunc(entry.Records, func(r serverRecord) bool { return r.Version == servers[i].Version })
+			if j < 0 {
+				return fmt.Errorf("rename entry has no record for version %s", servers[i].Version)
+			}
+			servers[i] = entry.Records[j]
+			servers[i].ChangeSeq = next()
+			removed = dropRemoved(removed, servers[i].ServerName, servers[i].Version)
+			remove(entry.ServerName, servers[i].Version)
+		}
+	case walSetLatest:
+		for i := range servers {
+			isLatest := servers[i].Version == entry.Version
+			if servers[i].ServerName == entry.ServerName && (servers[i].IsLatest != isLatest || servers[i].LatestPinned != (isLatest && entry.Pinned)) {
+				servers[i].IsLatest = isLatest
+				servers[i].LatestPinned = isLatest && entry.Pinned
+				servers[i].ChangeSeq = next()
+			}
+		}
+	case walDelete:
+		before := len(servers)
+		servers = slices.DeleteFunc(servers, func(r serverRecord) bool {
+			return r.ServerName == entry.ServerName && r.Version == entry.Version
+		})
+		if len(servers) < before {
+			remove(entry.ServerName, entry.Version)
+		}
+	default:
+		return fmt.Errorf("unknown operation %q", entry.Op)
+	}
+
+	db.data.Servers = servers
+	db.data.Removed = removed
+	db.data.ChangeSeq = changeSeq
+	return nil
+}
+
+// dropRemoved returns removed without the tombstone of a server version, as a new slice when it had one so
+// slices held by readers are never written to
+func dropRemoved(removed []removedRecord, serverName, version string) []removedRecord {
+	i := slices.IndexFunc(removed, func(r removedRecord) bool { return r.ServerName == serverName && r.Version == version })
+	if i < 0 {
+		return removed
+	}
+	return slices.Delete(slices.Clone(removed), i, i+1)
+}
+
+// logWAL durably appends a mutation to the write-ahead log before it is applied in memory.
+// It is a no-op unless the database was opened WithWriteAheadLog. Callers must hold db.mu for writing.
+func (db *JSONFileDB) logWAL(entry walEntry) error {
+	if db.wal == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.wal.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return db.wal.Sync()
+}
+
+// truncateWAL discards the logged mutations once the JSON file holds them, or the data they
+// applied to has been replaced. Callers must hold db.mu for writing.
+func (db *JSONFileDB) truncateWAL() error {
+	if db.wal == nil {
+		return nil
+	}
+	if err := db.wal.Truncate(0); err != nil {
+		return err
+	}
+	return db.wal.Sync()
+}
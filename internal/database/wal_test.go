@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteAheadLogReplay tests that changes made after the last flush are restored from the
+// write-ahead log when the database is reopened after a crash
+func TestWriteAheadLogReplay(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	publish := func(db *JSONFileDB, version string) {
+		now := time.Now()
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/wal-server",
+			Description: "WAL test server " + version,
+			Version:     version,
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: now,
+			UpdatedAt:   now,
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	publish(db, "1.0.0")
+	require.NoError(t, db.Flush(ctx))
+	info, err := os.Stat(db.walPath())
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "flushing should truncate the write-ahead log")
+
+	// Changes after the flush only reach the write-ahead log
+	require.NoError(t, db.UnmarkAsLatest(ctx, nil, "com.example/wal-server"))
+	publish(db, "2.0.0")
+	_, err = db.DeprecateServer(ctx, nil, "com.example/wal-server", "1.0.0", "")
+	require.NoError(t, err)
+	_, err = db.UpdateServer(ctx, nil, "com.example/wal-server", "2.0.0", &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/wal-server",
+		Description: "Edited after publish",
+		Version:     "2.0.0",
+	})
+	require.NoError(t, err)
+
+	// Simulate a crash: the process never flushes, and a new one opens the same files once its log lock is released
+	withoutWAL, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, withoutWAL.Count(), "the JSON file alone should be missing the unflushed changes")
+
+	require.NoError(t, db.wal.Close())
+	recovered, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = recovered.Close() })
+
+	assertRecovered := func(db *JSONFileDB) {
+		t.Helper()
+		assert.Equal(t, 2, db.Count())
+
+		latest, err := db.GetServerByName(ctx, nil, "com.example/wal-server")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", latest.Server.Version)
+		assert.Equal(t, "Edited after publish", latest.Server.Description)
+
+		old, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/wal-server", "1.0.0")
+		require.NoError(t, err)
+		assert.False(t, old.Meta.Official.IsLatest)
+		assert.Equal(t, model.StatusDeprecated, old.Meta.Official.Status)
+	}
+	assertRecovered(recovered)
+
+	// Flushing the recovered data makes the JSON file complete and empties the log
+	require.NoError(t, recovered.Flush(ctx))
+	info, err = os.Stat(recovered.walPath())
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	reopened, err := NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	assertRecovered(reopened)
+}
+
+// TestWriteAheadLogRename tests that renaming a server is replayed from the write-ahead log
+func TestWriteAheadLogRename(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/renamed",
+			Description: "Rename WAL test server",
+			Version:     version,
+		}, nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Flush(ctx))
+
+	renamed, err := db.RenameServer(ctx, nil, "com.example/renamed", "org.example/renamed")
+	require.NoError(t, err)
+	assert.Len(t, renamed, 2)
+
+	require.NoError(t, db.wal.Close())
+	recovered, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = recovered.Close() })
+
+	versions, err := recovered.GetAllVersionsByServerName(ctx, nil, "org.example/renamed")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	for _, version := range versions {
+		assert.Equal(t, "org.example/renamed", version.Server.Name)
+	}
+	_, err = recovered.GetAllVersionsByServerName(ctx, nil, "com.example/renamed")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestWriteAheadLogSetLatest(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/latest-fixed",
+			Description: "Set latest WAL test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, IsLatest: true})
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Flush(ctx))
+
+	changed, err := db.SetLatestVersion(ctx, nil, "com.example/latest-fixed", "2.0.0", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	_, err = db.SetLatestVersion(ctx, nil, "com.example/latest-fixed", "3.0.0", false)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.wal.Close())
+	recovered, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = recovered.Close() })
+
+	latest, err := recovered.GetServerByName(ctx, nil, "com.example/latest-fixed")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", latest.Server.Version)
+	older, err := recovered.GetServerByNameAndVersion(ctx, nil, "com.example/latest-fixed", "1.0.0")
+	require.NoError(t, err)
+	assert.False(t, older.Meta.Official.IsLatest)
+}
+
+// TestWriteAheadLogTornEntry tests that a final entry cut short by a crash is ignored on replay,
+// while corruption before the end of the log is an error
+func TestWriteAheadLogTornEntry(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	db, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	_, err = db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/torn",
+		Description: "Torn WAL test server",
+		Version:     "1.0.0",
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	logged, err := os.ReadFile(db.walPath())
+	require.NoError(t, err)
+
+	torn := append(append([]byte{}, logged...), []byte(`{"op":"put","record":{"server_na`)...)
+	require.NoError(t, os.WriteFile(db.walPath(), torn, 0o600))
+	recovered, err := NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.NoError(t, err)
+	assert.Equal(t, 1, recovered.Count())
+	require.NoError(t, recovered.Close())
+
+	corrupt := append([]byte("not json\n"), logged...)
+	require.NoError(t, os.WriteFile(db.walPath(), corrupt, 0o600))
+	_, err = NewJSONFileDB(ctx, filePath, WithWriteAheadLog())
+	require.Error(t, err)
+}
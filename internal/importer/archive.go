@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+const (
+	// tarMagicOffset is where the "ustar" magic lives in a POSIX tar header
+	tarMagicOffset = 257
+	tarMagic       = "ustar"
+)
+
+// decodeSeedStream decodes seed data from r, transparently handling gzip compression and tar archives.
+// Formats are detected by the source name's extension and by magic bytes, so a mislabelled file still works.
+// The stream is never fully buffered in memory: gzip and tar are decoded on the fly.
+func decodeSeedStream(name string, r io.Reader) ([]apiv0.ServerJSON, error) {
+	ext := seedExtension(name)
+	br := bufio.NewReader(r)
+
+	if ext == ".gz" || ext == ".tgz" || ext == ".tar.gz" || hasPrefix(br, gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	if ext == ".tar" || ext == ".tgz" || ext == ".tar.gz" || isTar(br) {
+		return decodeTar(tar.NewReader(br))
+	}
+
+	return decodeSeedJSON(br)
+}
+
+// decodeTar imports every JSON file contained in a tar archive, in archive order
+func decodeTar(tr *tar.Reader) ([]apiv0.ServerJSON, error) {
+	var servers []apiv0.ServerJSON
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(hdr.Name, ".json") {
+			continue
+		}
+		// Skip macOS resource forks and other hidden files bundled by archivers
+		if strings.HasPrefix(path.Base(hdr.Name), ".") {
+			continue
+		}
+
+		entries, err := decodeSeedJSON(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s in tar archive: %w", hdr.Name, err)
+		}
+		servers = append(servers, entries...)
+	}
+
+	return servers, nil
+}
+
+// decodeSeedJSON decodes either a ServerJSON array (the seed.json format) or a single ServerJSON object
+// (the per-server file format used inside tarballs)
+func decodeSeedJSON(r io.Reader) ([]apiv0.ServerJSON, error) {
+	br := bufio.NewReader(r)
+	first, err := firstNonSpace(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse seed data as ServerJSON array format: empty input")
+		}
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	if first == '{' {
+		var server apiv0.ServerJSON
+		if err := dec.Decode(&server); err != nil {
+			return nil, fmt.Errorf("failed to parse seed data as ServerJSON object: %w", err)
+		}
+		return []apiv0.ServerJSON{server}, nil
+	}
+
+	var servers []apiv0.ServerJSON
+	if err := dec.Decode(&servers); err != nil {
+		return nil, fmt.Errorf("failed to parse seed data as ServerJSON array format: %w", err)
+	}
+	return servers, nil
+}
+
+// seedExtension returns the lower-cased archive extension of a file path or URL, treating ".tar.gz" as one unit
+func seedExtension(name string) string {
+	if u, err := url.Parse(name); err == nil && u.Scheme != "" {
+		name = u.Path
+	}
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, ".tar.gz") {
+		return ".tar.gz"
+	}
+	return path.Ext(name)
+}
+
+// hasPrefix reports whether the buffered stream starts with magic, without consuming it
+func hasPrefix(br *bufio.Reader, magic []byte) bool {
+	b, err := br.Peek(len(magic))
+	return err == nil && bytes.Equal(b, magic)
+}
+
+// isTar reports whether the buffered stream starts with a POSIX tar header, without consuming it
+func isTar(br *bufio.Reader) bool {
+	b, err := br.Peek(tarMagicOffset + len(tarMagic))
+	return err == nil && string(b[tarMagicOffset:]) == tarMagic
+}
+
+// firstNonSpace peeks at the first non-whitespace byte of the stream, discarding leading whitespace
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
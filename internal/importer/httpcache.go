@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"errors"
+	"sync"
+)
+
+// errNotModified is returned when a conditional fetch finds the seed file unchanged since the last import
+var errNotModified = errors.New("seed file not modified")
+
+// httpValidators are the cache validators a server sent with a seed file
+type httpValidators struct {
+	etag         string // ETag response header
+	lastModified string // Last-Modified response header
+}
+
+// httpCache remembers the validators of the last successful import from each direct file URL, so
+// repeated imports can ask the server to skip sending a file that hasn't changed
+type httpCache struct {
+	mu         sync.Mutex
+	validators map[string]httpValidators
+}
+
+func newHTTPCache() *httpCache {
+	return &httpCache{validators: make(map[string]httpValidators)}
+}
+
+// get returns the validators stored for url, which are empty if there are none
+func (c *httpCache) get(url string) httpValidators {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.validators[url]
+}
+
+// set stores the validators for url
+func (c *httpCache) set(url string, validators httpValidators) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators[url] = validators
+}
+
+// forget drops the validators for url, so its next fetch is unconditional
+func (c *httpCache) forget(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.validators, url)
+}
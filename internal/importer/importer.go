@@ -3,15 +3,19 @@ package importer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/registry/internal/aws"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
 	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
@@ -19,11 +23,25 @@ import (
 // Service handles importing seed data into the registry
 type Service struct {
 	registry service.RegistryService
+	metrics  telemetry.Recorder
+	http     *httpCache // validators of the last import from each direct file URL
+}
+
+// ImportResult summarizes the outcome of a single seed import
+type ImportResult struct {
+	Source   string        // Seed source the import read from
+	Created  int           // New server versions created
+	Updated  int           // Existing server versions overwritten by the import
+	Skipped  int           // Entries skipped because they failed validation or already exist
+	Failed   int           // Entries the registry rejected
+	Failures []string      // Human-readable reason for each failed entry
+	Duration time.Duration // Wall-clock time taken by the import
 }
 
 // NewService creates a new importer service
-func NewService(registry service.RegistryService) *Service {
-	return &Service{registry: registry}
+// metrics may be nil, in which case no import metrics are recorded
+func NewService(registry service.RegistryService, metrics telemetry.Recorder) *Service {
+	return &Service{registry: registry, metrics: metrics, http: newHTTPCache()}
 }
 
 // ImportFromPath imports seed data from various sources:
@@ -31,70 +49,179 @@ func NewService(registry service.RegistryService) *Service {
 // 2. Direct HTTP URLs to seed.json files - expects ServerJSON array format
 // 3. Registry root URLs (automatically appends /v0/servers and paginates)
 // 4. S3 URIs (s3://bucket/key) - downloads from S3, expects ServerJSON array format
-func (s *Service) ImportFromPath(ctx context.Context, path string) error {
-	servers, err := readSeedFile(ctx, path)
+// File, HTTP and S3 sources may also be gzipped (.gz) or tarballs (.tar, .tar.gz, .tgz) of per-server
+// JSON files; these are detected by extension or magic bytes and decoded while streaming.
+// Direct file URLs are fetched conditionally after the first successful import, and skipped when the
+// server reports the file hasn't changed.
+//
+// The returned ImportResult is populated even when an error is returned for failed entries.
+func (s *Service) ImportFromPath(ctx context.Context, path string) (*ImportResult, error) {
+	return s.importPath(ctx, path, false)
+}
+
+// ImportFromPaths imports seed data from each path in order, as ImportFromPath does. Sources after the
+// first upsert: a version they share with an earlier source is overwritten with their content.
+// A failing source does not stop the remaining ones; the returned ImportResult aggregates all sources.
+func (s *Service) ImportFromPaths(ctx context.Context, paths []string) (*ImportResult, error) {
+	total := &ImportResult{Source: strings.Join(paths, ",")}
+	var errs []error
+
+	for i, path := range paths {
+		result, err := s.importPath(ctx, path, i > 0)
+		total.Created += result.Created
+		total.Updated += result.Updated
+		total.Skipped += result.Skipped
+		total.Failed += result.Failed
+		total.Failures = append(total.Failures, result.Failures...)
+		total.Duration += result.Duration
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	if len(paths) > 1 {
+		log.Printf("Import summary for %d sources: created=%d updated=%d skipped=%d failed=%d duration=%s",
+			len(paths), total.Created, total.Updated, total.Skipped, total.Failed, total.Duration.Round(time.Millisecond))
+	}
+
+	return total, errors.Join(errs...)
+}
+
+// Seed imports seed data from a comma-separated list of sources at startup, as ImportFromPaths does.
+// A failed import is logged and otherwise ignored, leaving whatever loaded in place, unless required is
+// set, in which case the error is returned so startup can be aborted.
+func (s *Service) Seed(ctx context.Context, sources string, required bool) error {
+	_, err := s.ImportFromPaths(ctx, SplitSources(sources))
+	if err == nil {
+		return nil
+	}
+	if required {
+		return fmt.Errorf("required seed import failed: %w", err)
+	}
+	log.Printf("Failed to import seed data: %v", err)
+	return nil
+}
+
+// SplitSources splits a comma-separated list of seed sources, dropping empty entries
+func SplitSources(sources string) []string {
+	var paths []string
+	for _, path := range strings.Split(sources, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// importPath imports a single seed source. With upsert, versions that already exist with different
+// content are overwritten instead of being skipped.
+func (s *Service) importPath(ctx context.Context, path string, upsert bool) (*ImportResult, error) {
+	start := time.Now()
+	result := &ImportResult{Source: path}
+
+	servers, skipped, err := readSeedFile(ctx, path, s.http)
+	if errors.Is(err, errNotModified) {
+		result.Duration = time.Since(start)
+		log.Printf("Import summary: source=%s not modified since the last import, skipped", path)
+		return result, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read seed data: %w", err)
+		return result, fmt.Errorf("failed to read seed data: %w", err)
 	}
+	result.Skipped = skipped
+	s.recordOutcome(ctx, outcomeSkipped, skipped)
 
 	// Import each server using registry service CreateServer
-	var successfullyCreated []string
-	var failedCreations []string
-
 	for _, server := range servers {
 		_, err := s.registry.CreateServer(ctx, server)
-		if err != nil {
-			failedCreations = append(failedCreations, fmt.Sprintf("%s: %v", server.Name, err))
+		switch {
+		case err == nil:
+			result.Created++
+			s.recordOutcome(ctx, outcomeCreated, 1)
+		case upsert && errors.Is(err, database.ErrInvalidVersion):
+			// A later source overrides the content an earlier one published for this version
+			if _, err := s.registry.UpdateServer(ctx, server.Name, server.Version, server, nil); err != nil {
+				result.Failed++
+				result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", server.Name, err))
+				s.recordOutcome(ctx, outcomeFailed, 1)
+				log.Printf("Failed to update server %s: %v", server.Name, err)
+				continue
+			}
+			result.Updated++
+			s.recordOutcome(ctx, outcomeUpdated, 1)
+		case errors.Is(err, database.ErrAlreadyPublished) || errors.Is(err, database.ErrInvalidVersion) ||
+			errors.Is(err, database.ErrAlreadyExists):
+			// Re-seeding a persistent database is expected to hit versions it already has
+			result.Skipped++
+			s.recordOutcome(ctx, outcomeSkipped, 1)
+		default:
+			result.Failed++
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", server.Name, err))
+			s.recordOutcome(ctx, outcomeFailed, 1)
 			log.Printf("Failed to create server %s: %v", server.Name, err)
-		} else {
-			successfullyCreated = append(successfullyCreated, server.Name)
 		}
 	}
+	result.Duration = time.Since(start)
 
 	// Report import results after actual creation attempts
-	if len(failedCreations) > 0 {
-		log.Printf("Import completed with errors: %d servers created successfully, %d servers failed",
-			len(successfullyCreated), len(failedCreations))
-		log.Printf("Failed servers: %v", failedCreations)
-		return fmt.Errorf("failed to import %d servers", len(failedCreations))
+	log.Printf("Import summary: source=%s created=%d updated=%d skipped=%d failed=%d duration=%s",
+		result.Source, result.Created, result.Updated, result.Skipped, result.Failed, result.Duration.Round(time.Millisecond))
+	if result.Failed > 0 {
+		log.Printf("Failed servers: %v", result.Failures)
+		// Import the whole file again next time, even if it hasn't changed
+		s.http.forget(path)
+		return result, fmt.Errorf("failed to import %d servers", result.Failed)
 	}
 
-	log.Printf("Import completed successfully: all %d servers created", len(successfullyCreated))
-	return nil
+	return result, nil
+}
+
+// Import outcomes, recorded as the "outcome" attribute of the imported servers counter
+const (
+	outcomeCreated = "created"
+	outcomeUpdated = "updated"
+	outcomeSkipped = "skipped"
+	outcomeFailed  = "failed"
+)
+
+// recordOutcome adds n to the imported servers counter for the given outcome
+func (s *Service) recordOutcome(ctx context.Context, outcome string, n int) {
+	if s.metrics == nil || n == 0 {
+		return
+	}
+	s.metrics.RecordImportedServers(ctx, outcome, n)
 }
 
 // readSeedFile reads seed data from various sources
-func readSeedFile(ctx context.Context, path string) ([]*apiv0.ServerJSON, error) {
-	var data []byte
+// It returns the valid servers along with the number of entries skipped for failing validation.
+// Direct file URLs are fetched conditionally using the validators in cache.
+func readSeedFile(ctx context.Context, path string, cache *httpCache) ([]*apiv0.ServerJSON, int, error) {
+	var serverResponses []apiv0.ServerJSON
 	var err error
 
 	if strings.HasPrefix(path, "s3://") {
 		// Handle S3 URIs
-		data, err = fetchFromS3(ctx, path)
+		serverResponses, err = readFromS3(ctx, path)
 	} else if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
 		// Handle HTTP URLs
 		if strings.HasSuffix(path, "/v0/servers") || strings.Contains(path, "/v0/servers") {
 			// This is a registry API endpoint - fetch paginated data
-			return fetchFromRegistryAPI(ctx, path)
+			servers, err := fetchFromRegistryAPI(ctx, path)
+			return servers, 0, err
 		}
 		// This is a direct file URL
-		data, err = fetchFromHTTP(ctx, path)
+		serverResponses, err = readFromHTTP(ctx, path, cache)
 	} else {
 		// Handle local file paths
-		data, err = os.ReadFile(path)
+		serverResponses, err = readFromFile(path)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to read seed data from %s: %w", path, err)
-	}
-
-	var serverResponses []apiv0.ServerJSON
-	if err := json.Unmarshal(data, &serverResponses); err != nil {
-		return nil, fmt.Errorf("failed to parse seed data as ServerJSON array format: %w", err)
+		return nil, 0, fmt.Errorf("failed to read seed data from %s: %w", path, err)
 	}
 
 	if len(serverResponses) == 0 {
-		return []*apiv0.ServerJSON{}, nil
+		return []*apiv0.ServerJSON{}, 0, nil
 	}
 
 	// Validate servers and collect warnings instead of failing the whole batch
@@ -126,26 +253,80 @@ func readSeedFile(ctx context.Context, path string) ([]*apiv0.ServerJSON, error)
 		log.Printf("Validation summary: All %d servers passed validation", len(validRecords))
 	}
 
-	return validRecords, nil
+	return validRecords, len(invalidServers), nil
 }
 
-func fetchFromHTTP(ctx context.Context, url string) ([]byte, error) {
+// readFromFile decodes seed data from a local file, which may be plain JSON, gzipped or a tarball
+func readFromFile(path string) ([]apiv0.ServerJSON, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeSeedStream(path, f)
+}
+
+// readFromHTTP streams seed data from a direct file URL, sending the validators cache holds for it and
+// returning errNotModified if the server reports the file is unchanged. The response's validators are
+// cached once the file decodes.
+func readFromHTTP(ctx context.Context, url string, cache *httpCache) ([]apiv0.ServerJSON, error) {
+	body, validators, err := openHTTP(ctx, url, cache.get(url))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	servers, err := decodeSeedStream(url, body)
+	if err != nil {
+		return nil, err
+	}
+	cache.set(url, validators)
+	return servers, nil
+}
+
+// openHTTP issues a GET request, made conditional by any non-empty validators, and returns the response
+// body for streaming along with the validators the server sent for it
+func openHTTP(ctx context.Context, url string, validators httpValidators) (io.ReadCloser, httpValidators, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, httpValidators{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if validators.etag != "" {
+		req.Header.Set("If-None-Match", validators.etag)
+	}
+	if validators.lastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.lastModified)
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from HTTP: %w", err)
+		return nil, httpValidators{}, fmt.Errorf("failed to fetch from HTTP: %w", err)
 	}
-	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, httpValidators{}, errNotModified
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
+		resp.Body.Close()
+		return nil, httpValidators{}, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return resp.Body, httpValidators{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+func fetchFromHTTP(ctx context.Context, url string) ([]byte, error) {
+	body, _, err := openHTTP(ctx, url, httpValidators{})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
 }
 
 func fetchFromRegistryAPI(ctx context.Context, baseURL string) ([]*apiv0.ServerJSON, error) {
@@ -178,8 +359,16 @@ func fetchFromRegistryAPI(ctx context.Context, baseURL string) ([]*apiv0.ServerJ
 			return nil, fmt.Errorf("failed to parse registry API response: %w", err)
 		}
 
-		// Extract ServerJSON from each ServerResponse
+		// Extract ServerJSON from each ServerResponse, keeping the source's status for the new version
 		for _, serverResponse := range response.Servers {
+			if official := serverResponse.Meta.Official; official != nil && official.Status != "" {
+				meta := apiv0.ServerMeta{}
+				if serverResponse.Server.Meta != nil {
+					meta = *serverResponse.Server.Meta
+				}
+				meta.Official = &apiv0.PublishExtensions{Status: official.Status}
+				serverResponse.Server.Meta = &meta
+			}
 			allRecords = append(allRecords, &serverResponse.Server)
 		}
 
@@ -193,8 +382,8 @@ func fetchFromRegistryAPI(ctx context.Context, baseURL string) ([]*apiv0.ServerJ
 	return allRecords, nil
 }
 
-// fetchFromS3 downloads a file from S3 and returns its contents
-func fetchFromS3(ctx context.Context, s3URI string) ([]byte, error) {
+// readFromS3 downloads a file from S3 and decodes its contents
+func readFromS3(ctx context.Context, s3URI string) ([]apiv0.ServerJSON, error) {
 	// Parse the S3 URI or URL
 	bucket, key, err := aws.ParseS3URL(s3URI)
 	if err != nil {
@@ -210,7 +399,7 @@ func fetchFromS3(ctx context.Context, s3URI string) ([]byte, error) {
 	}
 
 	// Create a temporary file to download the S3 object
-	tmpFile, err := os.CreateTemp("", "seed-*.json")
+	tmpFile, err := os.CreateTemp("", "seed-*")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
@@ -219,16 +408,16 @@ func fetchFromS3(ctx context.Context, s3URI string) ([]byte, error) {
 	defer os.Remove(tmpPath) // Clean up temp file after reading
 
 	// Download the file from S3
-	if err := downloader.DownloadFile(ctx, bucket, key, tmpPath); err != nil {
+	if _, err := downloader.DownloadFile(ctx, bucket, key, tmpPath); err != nil {
 		return nil, fmt.Errorf("failed to download from S3: %w", err)
 	}
 
-	// Read the downloaded file
-	data, err := os.ReadFile(tmpPath)
+	f, err := os.Open(tmpPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read downloaded file: %w", err)
+		return nil, fmt.Errorf("failed to open downloaded file: %w", err)
 	}
+	defer f.Close()
 
-	log.Printf("Successfully fetched %d bytes from S3", len(data))
-	return data, nil
+	// Use the object key for format detection since the temp file has no meaningful extension
+	return decodeSeedStream(key, f)
 }
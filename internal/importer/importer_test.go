@@ -1,21 +1,29 @@
 package importer_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/importer"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestImportService_LocalFile(t *testing.T) {
@@ -47,8 +55,8 @@ func TestImportService_LocalFile(t *testing.T) {
 	registryService := service.NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false})
 
 	// Create importer service and test import
-	importerService := importer.NewService(registryService)
-	err = importerService.ImportFromPath(context.Background(), tempFile)
+	importerService := importer.NewService(registryService, nil)
+	_, err = importerService.ImportFromPath(context.Background(), tempFile)
 	require.NoError(t, err)
 
 	// Verify the server was imported using registry service
@@ -93,8 +101,8 @@ func TestImportService_HTTPFile(t *testing.T) {
 	registryService := service.NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false})
 
 	// Create importer service and test import
-	importerService := importer.NewService(registryService)
-	err = importerService.ImportFromPath(context.Background(), httpServer.URL+"/seed.json")
+	importerService := importer.NewService(registryService, nil)
+	_, err = importerService.ImportFromPath(context.Background(), httpServer.URL+"/seed.json")
 	require.NoError(t, err)
 
 	// Verify the server was imported
@@ -162,8 +170,8 @@ func TestImportService_RegistryPagination(t *testing.T) {
 	targetRegistryService := service.NewRegistryService(targetDB, &config.Config{EnableRegistryValidation: false})
 
 	// Create importer service and test registry import
-	importerService := importer.NewService(targetRegistryService)
-	err := importerService.ImportFromPath(context.Background(), httpServer.URL+"/v0/servers")
+	importerService := importer.NewService(targetRegistryService, nil)
+	_, err := importerService.ImportFromPath(context.Background(), httpServer.URL+"/v0/servers")
 	require.NoError(t, err)
 
 	// Verify servers were imported
@@ -184,7 +192,7 @@ func TestImportService_ErrorHandling(t *testing.T) {
 	// Create registry service
 	testDB := database.NewTestDB(t)
 	registryService := service.NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false})
-	importerService := importer.NewService(registryService)
+	importerService := importer.NewService(registryService, nil)
 
 	tests := []struct {
 		name        string
@@ -230,7 +238,7 @@ func TestImportService_ErrorHandling(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := importerService.ImportFromPath(context.Background(), tt.path)
+			_, err := importerService.ImportFromPath(context.Background(), tt.path)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -243,3 +251,417 @@ func TestImportService_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestImportService_GzipFile(t *testing.T) {
+	seedData := []*apiv0.ServerJSON{
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.test/gzip-server",
+			Description: "Gzipped seed server",
+			Version:     "1.0.0",
+		},
+	}
+
+	jsonData, err := json.Marshal(seedData)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(jsonData)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	seedPath := filepath.Join(t.TempDir(), "seed.json.gz")
+	require.NoError(t, os.WriteFile(seedPath, buf.Bytes(), 0600))
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+	importerService := importer.NewService(registryService, nil)
+	_, err = importerService.ImportFromPath(context.Background(), seedPath)
+	require.NoError(t, err)
+
+	servers, _, err := registryService.ListServers(context.Background(), nil, "", 10)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "io.github.test/gzip-server", servers[0].Server.Name)
+	assert.Equal(t, "Gzipped seed server", servers[0].Server.Description)
+}
+
+func TestImportService_TarGzArchive(t *testing.T) {
+	servers := []apiv0.ServerJSON{
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.test/tar-server-1",
+			Description: "Tarball server 1",
+			Version:     "1.0.0",
+		},
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.test/tar-server-2",
+			Description: "Tarball server 2",
+			Version:     "2.0.0",
+		},
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.test/tar-server-3",
+			Description: "Tarball server 3",
+			Version:     "3.0.0",
+		},
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for i, server := range servers {
+		data, err := json.Marshal(server)
+		require.NoError(t, err)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     fmt.Sprintf("servers/server-%d.json", i),
+			Mode:     0600,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err = tw.Write(data)
+		require.NoError(t, err)
+	}
+	// Non-JSON entries are ignored
+	readme := []byte("not a server")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "servers/README.md", Mode: 0600, Size: int64(len(readme)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(readme)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	// Served over HTTP without an archive extension, so detection has to rely on magic bytes
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer httpServer.Close()
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+	importerService := importer.NewService(registryService, nil)
+	_, err = importerService.ImportFromPath(context.Background(), httpServer.URL+"/seed")
+	require.NoError(t, err)
+
+	imported, _, err := registryService.ListServers(context.Background(), nil, "", 10)
+	require.NoError(t, err)
+	require.Len(t, imported, 3)
+
+	names := make([]string, len(imported))
+	for i, server := range imported {
+		names[i] = server.Server.Name
+	}
+	assert.ElementsMatch(t, []string{
+		"io.github.test/tar-server-1",
+		"io.github.test/tar-server-2",
+		"io.github.test/tar-server-3",
+	}, names)
+}
+
+func TestImportService_OutcomeMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	metrics, err := telemetry.NewMetrics(meterProvider.Meter("test"))
+	require.NoError(t, err)
+
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	// Pre-existing server whose version and remote URL the seed collides with
+	_, err = registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/existing",
+		Description: "Already in the registry",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://mcp.example.com/existing"}},
+	})
+	require.NoError(t, err)
+
+	seedData := []apiv0.ServerJSON{
+		{
+			// created
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/new-server",
+			Description: "A brand new server",
+			Version:     "1.0.0",
+		},
+		{
+			// skipped: fails validation (missing $schema)
+			Name:        "com.example/invalid-server",
+			Description: "Missing schema",
+			Version:     "1.0.0",
+		},
+		{
+			// skipped: version already exists
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/existing",
+			Description: "Already in the registry",
+			Version:     "1.0.0",
+		},
+		{
+			// failed: remote URL owned by another server
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/squatter",
+			Description: "Reuses someone else's remote",
+			Version:     "1.0.0",
+			Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://mcp.example.com/existing"}},
+		},
+	}
+	jsonData, err := json.Marshal(seedData)
+	require.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, jsonData, 0600))
+
+	importerService := importer.NewService(registryService, metrics)
+	result, err := importerService.ImportFromPath(ctx, seedPath)
+	require.Error(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, seedPath, result.Source)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 0, result.Updated)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Failures, 1)
+	assert.Contains(t, result.Failures[0], "com.example/squatter")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != telemetry.Namespace+".importer.servers" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok, "imported servers metric should be an int64 sum")
+			for _, dp := range sum.DataPoints {
+				outcome, _ := dp.Attributes.Value("outcome")
+				counts[outcome.AsString()] += dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"created": 1, "skipped": 2, "failed": 1}, counts)
+}
+
+func TestImportService_ReimportIdenticalSeed(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	seedData := []apiv0.ServerJSON{
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/reimported-server",
+			Description: "Imported twice",
+			Version:     "1.0.0",
+		},
+	}
+	jsonData, err := json.Marshal(seedData)
+	require.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, jsonData, 0600))
+
+	importerService := importer.NewService(registryService, nil)
+
+	result, err := importerService.ImportFromPath(ctx, seedPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+
+	// Identical republishes succeed, but are reported as skipped rather than created
+	result, err = importerService.ImportFromPath(ctx, seedPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Skipped)
+}
+
+func TestImportService_InitialStatus(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	seedData := []apiv0.ServerJSON{
+		{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/historical-server",
+			Description: "Deprecated before it was imported",
+			Version:     "1.0.0",
+			Meta:        &apiv0.ServerMeta{Official: &apiv0.PublishExtensions{Status: model.StatusDeprecated}},
+		},
+	}
+	jsonData, err := json.Marshal(seedData)
+	require.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, jsonData, 0600))
+
+	importerService := importer.NewService(registryService, nil)
+	result, err := importerService.ImportFromPath(ctx, seedPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+
+	imported, err := registryService.GetServerByNameAndVersion(ctx, "com.example/historical-server", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, imported.Meta.Official.Status)
+
+	// Re-importing the same seed is still recognized as an identical republish
+	result, err = importerService.ImportFromPath(ctx, seedPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+}
+
+func TestImportService_ConditionalHTTPFetch(t *testing.T) {
+	ctx := context.Background()
+
+	seed := func(version string) []byte {
+		jsonData, err := json.Marshal([]apiv0.ServerJSON{
+			{Schema: model.CurrentSchemaURL, Name: "com.example/conditional", Description: "Fetched conditionally", Version: version},
+		})
+		require.NoError(t, err)
+		return jsonData
+	}
+
+	tests := []struct {
+		name      string
+		validator func(version string) (header, value string)
+		unchanged func(r *http.Request, value string) bool
+	}{
+		{
+			name:      "etag",
+			validator: func(version string) (string, string) { return "ETag", `"` + version + `"` },
+			unchanged: func(r *http.Request, value string) bool { return r.Header.Get("If-None-Match") == value },
+		},
+		{
+			name: "last modified",
+			validator: func(version string) (string, string) {
+				modified := map[string]string{"1.0.0": "Sun, 01 Jun 2025 00:00:00 GMT", "2.0.0": "Mon, 02 Jun 2025 00:00:00 GMT"}
+				return "Last-Modified", modified[version]
+			},
+			unchanged: func(r *http.Request, value string) bool { return r.Header.Get("If-Modified-Since") == value },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+			version := "1.0.0"
+			var fetches, notModified int
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches++
+				header, value := tt.validator(version)
+				if tt.unchanged(r, value) {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(header, value)
+				_, _ = w.Write(seed(version))
+			}))
+			defer httpServer.Close()
+
+			importerService := importer.NewService(registryService, nil)
+			seedURL := httpServer.URL + "/seed.json"
+
+			result, err := importerService.ImportFromPath(ctx, seedURL)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Created)
+
+			// Unchanged: the server answers 304 and nothing is imported, not even as skipped republishes
+			result, err = importerService.ImportFromPath(ctx, seedURL)
+			require.NoError(t, err)
+			assert.Equal(t, 1, notModified)
+			assert.Zero(t, result.Created+result.Updated+result.Skipped+result.Failed)
+
+			// Changed: the new validator doesn't match, so the file is fetched and imported
+			version = "2.0.0"
+			result, err = importerService.ImportFromPath(ctx, seedURL)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Created)
+			assert.Equal(t, 3, fetches)
+
+			latest, err := registryService.GetServerByName(ctx, "com.example/conditional")
+			require.NoError(t, err)
+			assert.Equal(t, "2.0.0", latest.Server.Version)
+		})
+	}
+}
+
+func TestImportService_MultipleSources(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+	dir := t.TempDir()
+
+	writeSeed := func(name string, servers []apiv0.ServerJSON) string {
+		jsonData, err := json.Marshal(servers)
+		require.NoError(t, err)
+		seedPath := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(seedPath, jsonData, 0600))
+		return seedPath
+	}
+
+	corePath := writeSeed("core.json", []apiv0.ServerJSON{
+		{Schema: model.CurrentSchemaURL, Name: "com.example/shared", Description: "From core", Version: "1.0.0"},
+		{Schema: model.CurrentSchemaURL, Name: "com.example/core-only", Description: "From core", Version: "1.0.0"},
+	})
+	partnerPath := writeSeed("partner.json", []apiv0.ServerJSON{
+		{Schema: model.CurrentSchemaURL, Name: "com.example/shared", Description: "From partner", Version: "1.0.0"},
+		{Schema: model.CurrentSchemaURL, Name: "com.example/partner-only", Description: "From partner", Version: "1.0.0"},
+	})
+
+	importerService := importer.NewService(registryService, nil)
+	result, err := importerService.ImportFromPaths(ctx, importer.SplitSources(corePath+", "+partnerPath+","))
+	require.NoError(t, err)
+	assert.Equal(t, corePath+","+partnerPath, result.Source)
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 0, result.Failed)
+
+	shared, err := registryService.GetServerByNameAndVersion(ctx, "com.example/shared", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "From partner", shared.Server.Description)
+
+	for _, name := range []string{"com.example/core-only", "com.example/partner-only"} {
+		_, err := registryService.GetServerByName(ctx, name)
+		require.NoError(t, err, name)
+	}
+
+	t.Run("a failing source does not stop the others", func(t *testing.T) {
+		result, err := importerService.ImportFromPaths(ctx, []string{filepath.Join(dir, "missing.json"), partnerPath})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.json")
+		// The partner seed is already imported, so its entries are identical republishes
+		assert.Equal(t, 2, result.Skipped)
+	})
+}
+
+func TestImportService_Seed(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+	importerService := importer.NewService(registryService, nil)
+
+	dir := t.TempDir()
+	seedPath := filepath.Join(dir, "seed.json")
+	jsonData, err := json.Marshal([]apiv0.ServerJSON{
+		{Schema: model.CurrentSchemaURL, Name: "com.example/seeded", Description: "Seeded server", Version: "1.0.0"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(seedPath, jsonData, 0600))
+	missingPath := filepath.Join(dir, "missing.json")
+
+	t.Run("successful seed", func(t *testing.T) {
+		require.NoError(t, importerService.Seed(ctx, seedPath, true))
+		_, err := registryService.GetServerByName(ctx, "com.example/seeded")
+		require.NoError(t, err)
+	})
+
+	t.Run("failure is ignored when not required", func(t *testing.T) {
+		require.NoError(t, importerService.Seed(ctx, missingPath, false))
+	})
+
+	t.Run("failure is returned when required", func(t *testing.T) {
+		err := importerService.Seed(ctx, seedPath+","+missingPath, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "required seed import failed")
+		assert.Contains(t, err.Error(), "missing.json")
+	})
+}
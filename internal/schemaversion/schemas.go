@@ -0,0 +1,26 @@
+package schemaversion
+
+import (
+	"embed"
+	"strings"
+)
+
+// schemaFiles holds the server.json JSON Schema the registry ships, generated alongside
+// docs/reference/server-json/server.schema.json by `make generate-schema`. Only the current schema version is
+// shipped; the older versions servers can be converted to (see Versions) have no schema here.
+//
+//go:embed schemas/*/server.schema.json
+var schemaFiles embed.FS
+
+// Schema returns the server.json JSON Schema of a schema version, and false if the registry doesn't ship it,
+// which is the case for every version but the current one
+func Schema(version string) ([]byte, bool) {
+	if version == "" || strings.ContainsAny(version, `/\.`) {
+		return nil, false
+	}
+	data, err := schemaFiles.ReadFile("schemas/" + version + "/server.schema.json")
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
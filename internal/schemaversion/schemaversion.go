@@ -0,0 +1,168 @@
+// Package schemaversion converts serialized server.json documents between versions of the server.json schema,
+// so clients that expect an older or newer schema can be served records stored in another.
+package schemaversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const schemaURLPrefix = "https://static.modelcontextprotocol.io/schemas/"
+
+// converter rewrites a decoded server.json in place from one schema version to an adjacent one
+type converter func(server map[string]any)
+
+// step is a conversion between two adjacent schema versions
+type step struct {
+	up   converter // from the older version to the newer one
+	down converter // from the newer version to the older one
+}
+
+// versions are the schema versions that can be converted between, oldest first
+var versions = []string{"2025-09-29", "2025-10-11", model.CurrentSchemaVersion}
+
+// steps holds the converters between each version and the next, keyed by the older version
+var steps = map[string]step{
+	"2025-09-29": {up: foldOCIVersionIntoIdentifier, down: splitOCIVersionFromIdentifier},
+	"2025-10-11": {up: func(map[string]any) {}, down: dropMCPBVersion},
+}
+
+// Versions returns the schema versions that servers can be converted between, oldest first
+func Versions() []string {
+	return slices.Clone(versions)
+}
+
+// IsSupported reports whether servers can be converted to and from a schema version
+func IsSupported(version string) bool {
+	return slices.Contains(versions, version)
+}
+
+// IsOutdated reports whether a schema URL refers to a schema version older than the current one, which servers
+// can be upgraded from
+func IsOutdated(schemaURL string) bool {
+	i := slices.Index(versions, VersionOf(schemaURL))
+	return i >= 0 && i < len(versions)-1
+}
+
+// URL returns the schema URL of a schema version
+func URL(version string) string {
+	return schemaURLPrefix + version + "/server.schema.json"
+}
+
+// VersionOf returns the schema version a schema URL refers to, or "" if it isn't a server.json schema URL
+func VersionOf(schemaURL string) string {
+	rest, ok := strings.CutPrefix(schemaURL, schemaURLPrefix)
+	if !ok {
+		return ""
+	}
+	version, _, ok := strings.Cut(rest, "/")
+	if !ok {
+		return ""
+	}
+	return version
+}
+
+// Convert rewrites a serialized server.json to a schema version, applying the converters between each pair of
+// adjacent versions in turn. Servers already in that version, or whose own version can't be converted from,
+// are returned unchanged.
+func Convert(server []byte, version string) ([]byte, error) {
+	target := slices.Index(versions, version)
+	if target < 0 {
+		return nil, fmt.Errorf("unsupported schema version %q", version)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(server))
+	decoder.UseNumber() // keep numbers exactly as stored
+	var decoded map[string]any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode server: %w", err)
+	}
+
+	schemaURL, _ := decoded["$schema"].(string)
+	current := slices.Index(versions, VersionOf(schemaURL))
+	if current < 0 || current == target {
+		return server, nil
+	}
+
+	for ; current < target; current++ {
+		steps[versions[current]].up(decoded)
+	}
+	for ; current > target; current-- {
+		steps[versions[current-1]].down(decoded)
+	}
+	decoded["$schema"] = URL(version)
+
+	return json.Marshal(decoded)
+}
+
+// Upgrade rewrites a serialized server.json stored under an older schema version to the current one, applying the
+// converters between each pair of adjacent versions in turn. Other servers are returned unchanged.
+func Upgrade(server []byte) ([]byte, error) {
+	return Convert(server, model.CurrentSchemaVersion)
+}
+
+// packagesOfType returns the decoded packages of a server with the given registry type
+func packagesOfType(server map[string]any, registryType string) []map[string]any {
+	packages, _ := server["packages"].([]any)
+	var matching []map[string]any
+	for _, p := range packages {
+		pkg, ok := p.(map[string]any)
+		if ok && pkg["registryType"] == registryType {
+			matching = append(matching, pkg)
+		}
+	}
+	return matching
+}
+
+// splitImageTag splits the tag off an OCI image reference, reporting false if it has no tag.
+// References pinned by digest are treated as having no tag.
+func splitImageTag(identifier string) (image, tag string, ok bool) {
+	if strings.Contains(identifier, "@") {
+		return identifier, "", false
+	}
+	i := strings.LastIndex(identifier, ":")
+	if i < 0 || strings.Contains(identifier[i:], "/") {
+		return identifier, "", false // no tag, or the colon belongs to a registry port
+	}
+	return identifier[:i], identifier[i+1:], true
+}
+
+// foldOCIVersionIntoIdentifier moves the version of OCI packages into the image reference's tag,
+// as 2025-10-11 dropped the version field for OCI packages
+func foldOCIVersionIntoIdentifier(server map[string]any) {
+	for _, pkg := range packagesOfType(server, model.RegistryTypeOCI) {
+		version, _ := pkg["version"].(string)
+		identifier, _ := pkg["identifier"].(string)
+		if version == "" {
+			continue
+		}
+		if _, _, tagged := splitImageTag(identifier); !tagged && !strings.Contains(identifier, "@") {
+			pkg["identifier"] = identifier + ":" + version
+		}
+		delete(pkg, "version")
+	}
+}
+
+// splitOCIVersionFromIdentifier moves the tag of OCI image references into the package version,
+// as 2025-09-29 required a version for every package
+func splitOCIVersionFromIdentifier(server map[string]any) {
+	for _, pkg := range packagesOfType(server, model.RegistryTypeOCI) {
+		identifier, _ := pkg["identifier"].(string)
+		if image, tag, ok := splitImageTag(identifier); ok {
+			pkg["identifier"] = image
+			pkg["version"] = tag
+		}
+	}
+}
+
+// dropMCPBVersion removes the version of MCPB packages, which 2025-10-11 didn't allow
+func dropMCPBVersion(server map[string]any) {
+	for _, pkg := range packagesOfType(server, model.RegistryTypeMCPB) {
+		delete(pkg, "version")
+	}
+}
@@ -0,0 +1,120 @@
+package schemaversion_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/schemaversion"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestConvert(t *testing.T) {
+	current := apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/converted",
+		Description: "Schema conversion test server",
+		Version:     "1.2.3",
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeOCI, Identifier: "ghcr.io/example/server:1.2.3", Transport: model.Transport{Type: "stdio"}},
+			{RegistryType: model.RegistryTypeOCI, Identifier: "localhost:5000/example/pinned@sha256:fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce", Transport: model.Transport{Type: "stdio"}},
+			{RegistryType: model.RegistryTypeMCPB, Identifier: "https://example.com/server.mcpb", Version: "1.2.3", FileSHA256: "fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce", Transport: model.Transport{Type: "stdio"}},
+			{RegistryType: model.RegistryTypeNPM, Identifier: "@example/server", Version: "1.2.3", Transport: model.Transport{Type: "stdio"}},
+		},
+	}
+	stored, err := json.Marshal(current)
+	require.NoError(t, err)
+
+	convert := func(t *testing.T, server []byte, version string) apiv0.ServerJSON {
+		t.Helper()
+		converted, err := schemaversion.Convert(server, version)
+		require.NoError(t, err)
+		var result apiv0.ServerJSON
+		require.NoError(t, json.Unmarshal(converted, &result))
+		return result
+	}
+
+	t.Run("down to 2025-09-29", func(t *testing.T) {
+		old := convert(t, stored, "2025-09-29")
+		assert.Equal(t, schemaversion.URL("2025-09-29"), old.Schema)
+		assert.Equal(t, current.Name, old.Name)
+
+		// OCI tags move into the version, digest references are left alone
+		assert.Equal(t, "ghcr.io/example/server", old.Packages[0].Identifier)
+		assert.Equal(t, "1.2.3", old.Packages[0].Version)
+		assert.Equal(t, current.Packages[1].Identifier, old.Packages[1].Identifier)
+		assert.Empty(t, old.Packages[1].Version)
+		// MCPB versions didn't exist before 2025-10-17
+		assert.Empty(t, old.Packages[2].Version)
+		assert.Equal(t, current.Packages[2].FileSHA256, old.Packages[2].FileSHA256)
+		// Other packages are untouched
+		assert.Equal(t, current.Packages[3], old.Packages[3])
+	})
+
+	t.Run("round trip back up", func(t *testing.T) {
+		old, err := schemaversion.Convert(stored, "2025-09-29")
+		require.NoError(t, err)
+
+		upgraded := convert(t, old, model.CurrentSchemaVersion)
+		assert.Equal(t, model.CurrentSchemaURL, upgraded.Schema)
+		assert.Equal(t, "ghcr.io/example/server:1.2.3", upgraded.Packages[0].Identifier)
+		assert.Empty(t, upgraded.Packages[0].Version)
+		assert.Equal(t, current.Packages[1], upgraded.Packages[1])
+		assert.Equal(t, current.Packages[3], upgraded.Packages[3])
+	})
+
+	t.Run("same version is unchanged", func(t *testing.T) {
+		converted, err := schemaversion.Convert(stored, model.CurrentSchemaVersion)
+		require.NoError(t, err)
+		assert.Equal(t, stored, converted)
+	})
+
+	t.Run("unknown stored version is unchanged", func(t *testing.T) {
+		unknown := []byte(`{"$schema":"https://example.com/custom.schema.json","name":"com.example/custom"}`)
+		converted, err := schemaversion.Convert(unknown, "2025-09-29")
+		require.NoError(t, err)
+		assert.Equal(t, unknown, converted)
+	})
+
+	t.Run("unsupported target version", func(t *testing.T) {
+		_, err := schemaversion.Convert(stored, "2024-01-01")
+		require.Error(t, err)
+	})
+}
+
+func TestVersionOf(t *testing.T) {
+	assert.Equal(t, model.CurrentSchemaVersion, schemaversion.VersionOf(model.CurrentSchemaURL))
+	assert.Equal(t, "2025-09-29", schemaversion.VersionOf(schemaversion.URL("2025-09-29")))
+	assert.Empty(t, schemaversion.VersionOf("https://example.com/server.schema.json"))
+}
+
+func TestUpgrade(t *testing.T) {
+	assert.True(t, schemaversion.IsOutdated(schemaversion.URL("2025-09-29")))
+	assert.True(t, schemaversion.IsOutdated(schemaversion.URL("2025-10-11")))
+	assert.False(t, schemaversion.IsOutdated(model.CurrentSchemaURL))
+	assert.False(t, schemaversion.IsOutdated(schemaversion.URL("2024-01-01")))
+	assert.False(t, schemaversion.IsOutdated(""))
+
+	old := []byte(`{"$schema":"` + schemaversion.URL("2025-09-29") + `","name":"com.example/old","version":"1.0.0",` +
+		`"packages":[{"registryType":"oci","identifier":"ghcr.io/example/old","version":"1.0.0","transport":{"type":"stdio"}}]}`)
+	upgraded, err := schemaversion.Upgrade(old)
+	require.NoError(t, err)
+	var server apiv0.ServerJSON
+	require.NoError(t, json.Unmarshal(upgraded, &server))
+	assert.Equal(t, model.CurrentSchemaURL, server.Schema)
+	assert.Equal(t, "ghcr.io/example/old:1.0.0", server.Packages[0].Identifier)
+	assert.Empty(t, server.Packages[0].Version)
+
+	// Servers in the current or an unknown schema version are left alone
+	current := []byte(`{"$schema":"` + model.CurrentSchemaURL + `","name":"com.example/current"}`)
+	unchanged, err := schemaversion.Upgrade(current)
+	require.NoError(t, err)
+	assert.Equal(t, current, unchanged)
+	unknown := []byte(`{"$schema":"https://example.com/server.schema.json","name":"com.example/unknown"}`)
+	unchanged, err = schemaversion.Upgrade(unknown)
+	require.NoError(t, err)
+	assert.Equal(t, unknown, unchanged)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// Audit actions, one for each kind of mutation the registry service records
+const (
+	AuditActionCreate   = "create"   // a new server version was published
+	AuditActionUpdate   = "update"   // a server version's content was edited
+	AuditActionStatus   = "status"   // a server version's status changed, including deprecation
+	AuditActionTransfer = "transfer" // a server version moved to a new name
+	AuditActionLatest   = "latest"   // a server version was designated the latest by an operator, or its flag was reconciled
+	AuditActionDelete   = "delete"   // a server version was removed from storage, e.g. by compaction
+)
+
+// Actor identifies who made a change
+type Actor struct {
+	AuthMethod string `json:"authMethod,omitempty"` // how the actor authenticated, e.g. github-at or admin_api_key
+	Subject    string `json:"subject"`              // the authenticated identity
+	IP         string `json:"ip,omitempty"`         // the client address of a change requested over the API
+}
+
+// SystemActor is recorded for changes made without an authenticated identity, such as seeding and scheduled jobs
+var SystemActor = Actor{Subject: "system"}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes the changes made with it to actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or SystemActor if there is none
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return SystemActor
+}
+
+// AuditEntry records one change to one server version
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Actor      Actor     `json:"actor"`
+	Action     string    `json:"action"`
+	ServerName string    `json:"serverName"`
+	Version    string    `json:"version"`
+	Status     string    `json:"status,omitempty"`  // the version's status after the change
+	Details    string    `json:"details,omitempty"` // action-specific context, such as the name a server was transferred from
+}
+
+// AuditSink receives an entry for every change the registry service makes. Entries are recorded after the change
+// is committed; a failure to record one is logged rather than undoing the change.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// jsonAuditSink writes audit entries to a writer as JSON lines
+type jsonAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditSink returns an AuditSink that writes each entry to w as a line of JSON
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{w: w}
+}
+
+// Record implements AuditSink.Record
+func (s *jsonAuditSink) Record(_ context.Context, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	// One write per entry, so concurrent entries never interleave
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// OpenAuditSink opens the audit sink an MCP_REGISTRY_AUDIT_LOG setting names: "stdout" writes JSON lines to
+// standard output and any other value is a file to append JSON lines to. The returned function closes the file.
+func OpenAuditSink(target string) (AuditSink, func() error, error) {
+	if target == "stdout" {
+		return NewJSONAuditSink(os.Stdout), func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return NewJSONAuditSink(f), f.Close, nil
+}
+
+// audit records a change to a server version, attributed to the actor in ctx
+func (s *registryServiceImpl) audit(ctx context.Context, action string, server *apiv0.ServerResponse, details string) {
+	if server == nil {
+		return
+	}
+
+	entry := AuditEntry{
+		Action:     action,
+		ServerName: server.Server.Name,
+		Version:    server.Server.Version,
+		Details:    details,
+	}
+	if server.Meta.Official != nil {
+		entry.Status = string(server.Meta.Official.Status)
+	}
+	s.recordAudit(ctx, entry)
+}
+
+// recordAudit stamps an audit entry with the current time and the actor in ctx, and passes it to the audit sinks
+func (s *registryServiceImpl) recordAudit(ctx context.Context, entry AuditEntry) {
+	if len(s.auditSinks) == 0 {
+		return
+	}
+
+	entry.Time = time.Now().UTC()
+	entry.Actor = ActorFromContext(ctx)
+	for _, sink := range s.auditSinks {
+		if err := sink.Record(ctx, entry); err != nil {
+			log.Printf("Failed to record audit entry for %s %s@%s: %v", entry.Action, entry.ServerName, entry.Version, err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+//nolint:testpackage
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditSink collects audit entries in memory
+type recordingAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (s *recordingAuditSink) Record(_ context.Context, entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// take returns the entries recorded since the last call
+func (s *recordingAuditSink) take() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries
+	s.entries = nil
+	return entries
+}
+
+func TestAuditLog(t *testing.T) {
+	sink := &recordingAuditSink{}
+	registry := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+		EnableRegistryValidation: false,
+		CompactMaxVersions:       1,
+	}, WithAuditSink(sink))
+
+	publisher := Actor{AuthMethod: "github-at", Subject: "octocat"}
+	ctx := WithActor(context.Background(), publisher)
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/audited",
+		Description: "Audited server",
+		Version:     "1.0.0",
+	}
+
+	// requireEntry checks that a mutation recorded exactly one entry, and returns it
+	requireEntry := func(t *testing.T, action, serverName, version string) AuditEntry {
+		t.Helper()
+		entries := sink.take()
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		assert.Equal(t, action, entry.Action)
+		assert.Equal(t, serverName, entry.ServerName)
+		assert.Equal(t, version, entry.Version)
+		assert.WithinDuration(t, time.Now(), entry.Time, time.Minute)
+		return entry
+	}
+
+	t.Run("create", func(t *testing.T) {
+		_, err := registry.CreateServer(ctx, server)
+		require.NoError(t, err)
+		entry := requireEntry(t, AuditActionCreate, "com.example/audited", "1.0.0")
+		assert.Equal(t, publisher, entry.Actor)
+		assert.Equal(t, string(model.StatusActive), entry.Status)
+
+		// An identical republish changes nothing, so it isn't audited
+		_, err = registry.CreateServer(ctx, server)
+		require.ErrorIs(t, err, database.ErrAlreadyPublished)
+		assert.Empty(t, sink.take())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated := *server
+		updated.Description = "Edited"
+		_, err := registry.UpdateServer(ctx, server.Name, server.Version, &updated, nil)
+		require.NoError(t, err)
+		requireEntry(t, AuditActionUpdate, "com.example/audited", "1.0.0")
+
+		_, err = registry.PatchServer(ctx, server.Name, server.Version, map[string]any{"description": "Patched"})
+		require.NoError(t, err)
+		requireEntry(t, AuditActionUpdate, "com.example/audited", "1.0.0")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		_, err := registry.SetServerStatus(ctx, server.Name, server.Version, model.StatusDeprecated, "Superseded by v2")
+		require.NoError(t, err)
+		entry := requireEntry(t, AuditActionStatus, "com.example/audited", "1.0.0")
+		assert.Equal(t, string(model.StatusDeprecated), entry.Status)
+		assert.Equal(t, "Superseded by v2", entry.Details)
+
+		_, err = registry.DeprecateServer(ctx, server.Name, server.Version, "")
+		require.NoError(t, err)
+		requireEntry(t, AuditActionStatus, "com.example/audited", "1.0.0")
+	})
+
+	t.Run("failed mutations are not audited", func(t *testing.T) {
+		_, err := registry.SetServerStatus(ctx, server.Name, "9.9.9", model.StatusDeleted, "")
+		require.Error(t, err)
+		assert.Empty(t, sink.take())
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		_, err := registry.TransferServer(ctx, server.Name, "com.example/audited-renamed", true)
+		require.NoError(t, err)
+		entries := sink.take()
+		require.Len(t, entries, 2)
+		assert.Equal(t, AuditActionTransfer, entries[0].Action)
+		assert.Equal(t, "com.example/audited-renamed", entries[0].ServerName)
+		assert.Equal(t, "from com.example/audited", entries[0].Details)
+		// The tombstone left at the old name is a new version
+		assert.Equal(t, AuditActionCreate, entries[1].Action)
+		assert.Equal(t, "com.example/audited", entries[1].ServerName)
+	})
+
+	t.Run("changes without an identity are attributed to the system", func(t *testing.T) {
+		second := *server
+		second.Name = "com.example/audited-renamed"
+		second.Version = "2.0.0"
+		_, err := registry.CreateServer(context.Background(), &second)
+		require.NoError(t, err)
+		entry := requireEntry(t, AuditActionCreate, "com.example/audited-renamed", "2.0.0")
+		assert.Equal(t, SystemActor, entry.Actor)
+	})
+
+	t.Run("compaction", func(t *testing.T) {
+		_, err := registry.Compact(context.Background(), false)
+		require.NoError(t, err)
+		entry := requireEntry(t, AuditActionDelete, "com.example/audited-renamed", "1.0.0")
+		assert.Equal(t, "compacted: max_versions", entry.Details)
+	})
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONAuditSink(&buf)
+
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		require.NoError(t, sink.Record(context.Background(), AuditEntry{
+			Time:       at,
+			Actor:      Actor{AuthMethod: "github-at", Subject: "octocat"},
+			Action:     AuditActionCreate,
+			ServerName: "com.example/audited",
+			Version:    version,
+			Status:     string(model.StatusActive),
+		}))
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(lines[1], &decoded))
+	assert.Equal(t, map[string]any{
+		"time":       "2025-06-01T00:00:00Z",
+		"actor":      map[string]any{"authMethod": "github-at", "subject": "octocat"},
+		"action":     "create",
+		"serverName": "com.example/audited",
+		"version":    "2.0.0",
+		"status":     "active",
+	}, decoded)
+}
+
+func TestAuditLog_ReconcileAndImport(t *testing.T) {
+	ctx := context.Background()
+	sink := &recordingAuditSink{}
+	db := database.NewTestJSONFileDB(t)
+	registry := NewRegistryService(db, &config.Config{EnableRegistryValidation: false}, WithAuditSink(sink))
+
+	version := func(v, description string, isLatest bool) *apiv0.ServerResponse {
+		now := time.Now()
+		return &apiv0.ServerResponse{
+			Server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/synced",
+				Description: description,
+				Version:     v,
+			},
+			Meta: apiv0.ResponseMeta{Official: &apiv0.RegistryExtensions{
+				Status: model.StatusActive, PublishedAt: now, UpdatedAt: now, IsLatest: isLatest,
+			}},
+		}
+	}
+	// actions summarizes audit entries as action, version and details
+	actions := func(entries []AuditEntry) []string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Action+" "+entry.Version+": "+entry.Details)
+		}
+		return result
+	}
+	writeFile := func(servers ...*apiv0.ServerResponse) string {
+		t.Helper()
+		body, err := database.MarshalJSONFile(servers)
+		require.NoError(t, err)
+		filePath := filepath.Join(t.TempDir(), "import.json")
+		require.NoError(t, os.WriteFile(filePath, body, 0600))
+		return filePath
+	}
+
+	// Seeded directly with the lower version marked latest
+	for _, seeded := range []*apiv0.ServerResponse{version("1.0.0", "Synced server", true), version("2.0.0", "Synced server", false)} {
+		_, err := db.CreateServer(ctx, nil, &seeded.Server, seeded.Meta.Official)
+		require.NoError(t, err)
+	}
+
+	t.Run("reconcile", func(t *testing.T) {
+		_, err := registry.ReconcileLatest(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"latest 1.0.0: reconciled: no longer the latest",
+			"latest 2.0.0: reconciled: now the latest",
+		}, actions(sink.take()))
+	})
+
+	t.Run("import", func(t *testing.T) {
+		filePath := writeFile(version("2.0.0", "Edited upstream", false), version("3.0.0", "Synced server", true))
+		opts := database.UpsertOptions{LatestPolicy: database.LatestFromFile}
+		_, err := registry.ImportJSONFile(ctx, filePath, "s3://registry-bucket/registry.json", opts)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"update 2.0.0: imported from s3://registry-bucket/registry.json",
+			"create 3.0.0: imported from s3://registry-bucket/registry.json",
+		}, actions(sink.take()))
+
+		// Importing the same data again changes nothing
+		_, err = registry.ImportJSONFile(ctx, filePath, "s3://registry-bucket/registry.json", opts)
+		require.NoError(t, err)
+		assert.Empty(t, sink.take())
+	})
+}
+
+func TestAuditLog_Reload(t *testing.T) {
+	ctx := context.Background()
+	sink := &recordingAuditSink{}
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	version := func(v, description string, updatedAt time.Time) *apiv0.ServerResponse {
+		return &apiv0.ServerResponse{
+			Server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/reloaded",
+				Description: description,
+				Version:     v,
+			},
+			Meta: apiv0.ResponseMeta{Official: &apiv0.RegistryExtensions{
+				Status: model.StatusActive, PublishedAt: published, UpdatedAt: updatedAt,
+			}},
+		}
+	}
+	writeFile := func(servers ...*apiv0.ServerResponse) {
+		t.Helper()
+		body, err := database.MarshalJSONFile(servers)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filePath, body, 0600))
+	}
+
+	writeFile(version("1.0.0", "Reloaded server", published), version("2.0.0", "Reloaded server", published))
+	db, err := database.NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	registry := NewRegistryService(db, &config.Config{EnableRegistryValidation: false}, WithAuditSink(sink))
+
+	edited := published.Add(time.Hour)
+	writeFile(version("2.0.0", "Edited upstream", edited), version("3.0.0", "Reloaded server", edited))
+	require.NoError(t, registry.ReloadFile(ctx, "s3://registry-bucket/registry.json"))
+	entries := sink.take()
+	var summary []string
+	for _, entry := range entries {
+		summary = append(summary, entry.Action+" "+entry.Version+": "+entry.Details)
+	}
+	assert.ElementsMatch(t, []string{
+		"delete 1.0.0: reloaded from s3://registry-bucket/registry.json",
+		"update 2.0.0: reloaded from s3://registry-bucket/registry.json",
+		"create 3.0.0: reloaded from s3://registry-bucket/registry.json",
+	}, summary)
+	for _, entry := range entries {
+		assert.Equal(t, SystemActor, entry.Actor)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// changeToken is the position in the changes feed that a change token encodes: the sequence number of the last
+// change the consumer has seen
+type changeToken struct {
+	Sequence int64 `json:"s"`
+}
+
+// encodeChangeToken returns the opaque token that resumes the changes feed after the change numbered sequence
+func encodeChangeToken(sequence int64) string {
+	data, _ := json.Marshal(changeToken{Sequence: sequence}) // marshaling an integer can't fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeChangeToken returns the sequence number a change token encodes
+func decodeChangeToken(since string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed change token", database.ErrInvalidInput)
+	}
+	var token changeToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Sequence < 0 {
+		return 0, fmt.Errorf("%w: malformed change token", database.ErrInvalidInput)
+	}
+	return token.Sequence, nil
+}
+
+// ListChanges returns the changes made since a change token in the order they became visible, each server
+// version's most recent change only, so resuming from the returned token neither repeats nor skips a change.
+// Every change is included: publishes, edits, status and latest flag changes, renames and removals.
+func (s *registryServiceImpl) ListChanges(ctx context.Context, since string, limit int) ([]database.Change, string, error) {
+	// If limit is not set or negative, use a default limit
+	if limit <= 0 {
+		limit = 30
+	}
+
+	var after int64
+	if since != "" {
+		sequence, err := decodeChangeToken(since)
+		if err != nil {
+			return nil, "", err
+		}
+		after = sequence
+	}
+
+	changes, err := s.db.ListChanges(ctx, nil, after, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// With nothing new, the consumer resumes from where it already is
+	next := since
+	if len(changes) > 0 {
+		next = encodeChangeToken(changes[len(changes)-1].Sequence)
+	}
+	return changes, next, nil
+}
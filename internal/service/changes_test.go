@@ -0,0 +1,94 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListChanges(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	registry := NewRegistryService(db, &config.Config{})
+
+	seed := func(name, version string, updatedAt time.Time) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Change feed test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: updatedAt, UpdatedAt: updatedAt, IsLatest: true})
+		require.NoError(t, err)
+	}
+	// refs lists the changes as name@version, prefixing removals with "-"
+	refs := func(changes []database.Change) []string {
+		var result []string
+		for _, change := range changes {
+			if change.Server == nil {
+				result = append(result, "-"+change.Removed.Name+"@"+change.Removed.Version)
+				continue
+			}
+			result = append(result, change.Server.Server.Name+"@"+change.Server.Server.Version)
+		}
+		return result
+	}
+
+	// Changes come in the order they were made, whatever their timestamps say
+	now := time.Now()
+	seed("com.example/c", "1.0.0", now)
+	seed("com.example/a", "1.0.0", now.Add(-time.Hour))
+	seed("com.example/b", "1.0.0", now.Add(-2*time.Hour))
+
+	page, token, err := registry.ListChanges(ctx, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"com.example/c@1.0.0", "com.example/a@1.0.0"}, refs(page))
+
+	page, token, err = registry.ListChanges(ctx, token, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"com.example/b@1.0.0"}, refs(page))
+
+	caughtUp := token
+	page, token, err = registry.ListChanges(ctx, token, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+	assert.Equal(t, caughtUp, token)
+
+	t.Run("latest flag changes and removals are changes", func(t *testing.T) {
+		require.NoError(t, db.UnmarkAsLatest(ctx, nil, "com.example/a"))
+		seed("com.example/a", "2.0.0", now)
+		_, err := db.SetLatestVersion(ctx, nil, "com.example/a", "1.0.0", false)
+		require.NoError(t, err)
+		require.NoError(t, db.DeleteServerVersion(ctx, nil, "com.example/c", "1.0.0"))
+
+		page, next, err := registry.ListChanges(ctx, token, 10)
+		require.NoError(t, err)
+		// Each version appears once, at its most recent change
+		assert.Equal(t, []string{"com.example/a@1.0.0", "com.example/a@2.0.0", "-com.example/c@1.0.0"}, refs(page))
+		assert.True(t, page[0].Server.Meta.Official.IsLatest)
+		assert.False(t, page[1].Server.Meta.Official.IsLatest)
+		token = next
+	})
+
+	t.Run("publishing a removed version again replaces its tombstone", func(t *testing.T) {
+		seed("com.example/c", "1.0.0", now)
+
+		page, _, err := registry.ListChanges(ctx, token, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"com.example/c@1.0.0"}, refs(page))
+
+		page, _, err = registry.ListChanges(ctx, "", 10)
+		require.NoError(t, err)
+		assert.NotContains(t, refs(page), "-com.example/c@1.0.0")
+	})
+
+	_, _, err = registry.ListChanges(ctx, "%%%", 2)
+	require.ErrorIs(t, err, database.ErrInvalidInput)
+}
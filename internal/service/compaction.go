@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// NewCompactionJob returns a job for RunOnSchedule that compacts the registry, logging what was removed and
+// recording each run and removed version in metrics, which may be nil
+func NewCompactionJob(registry RegistryService, metrics telemetry.Recorder) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		result, err := registry.Compact(ctx, false)
+		if err != nil {
+			recordCompactionRun(ctx, metrics, "failure")
+			return err
+		}
+		recordCompactionRun(ctx, metrics, "success")
+
+		for _, removed := range result.Removed {
+			log.Printf("Compaction removed %s@%s (%s)", removed.ServerName, removed.Version, removed.Reason)
+			if metrics != nil {
+				metrics.RecordCompactedVersion(ctx, string(removed.Reason))
+			}
+		}
+		log.Printf("Compaction removed %d server versions", len(result.Removed))
+		return nil
+	}
+}
+
+// recordCompactionRun counts a compaction run by outcome
+func recordCompactionRun(ctx context.Context, metrics telemetry.Recorder, outcome string) {
+	if metrics == nil {
+		return
+	}
+	metrics.RecordCompactionRun(ctx, outcome)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// NewStaleLockJob returns a job for RunPeriodically that logs every publish lock held longer than threshold,
+// which usually means a publish failed without releasing it, and records how many there are in metrics,
+// which may be nil
+func NewStaleLockJob(monitor database.LockMonitor, threshold time.Duration, metrics telemetry.Recorder) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		stale := 0
+		for _, lock := range monitor.HeldPublishLocks() {
+			heldFor := now.Sub(lock.HeldSince)
+			if heldFor < threshold {
+				continue
+			}
+			stale++
+			log.Printf("Publish lock for %s has been held for %s, longer than %s", lock.ServerName, heldFor.Round(time.Second), threshold)
+		}
+
+		if metrics != nil {
+			metrics.RecordStalePublishLocks(ctx, stale)
+		}
+		return nil
+	}
+}
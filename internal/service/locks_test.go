@@ -0,0 +1,62 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// fakeLockMonitor reports a fixed set of held locks
+type fakeLockMonitor []database.HeldLock
+
+func (m fakeLockMonitor) HeldPublishLocks() []database.HeldLock { return m }
+
+func TestStaleLockJob(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	metrics, err := telemetry.NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	staleCount := func() int64 {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(ctx, &rm))
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != telemetry.Namespace+".publish_locks.stale" {
+					continue
+				}
+				gauge, ok := m.Data.(metricdata.Gauge[int64])
+				require.True(t, ok)
+				require.Len(t, gauge.DataPoints, 1)
+				return gauge.DataPoints[0].Value
+			}
+		}
+		t.Fatal("stale publish locks gauge not recorded")
+		return 0
+	}
+
+	now := time.Now()
+	monitor := fakeLockMonitor{
+		{ServerName: "com.example/wedged", HeldSince: now.Add(-10 * time.Minute)},
+		{ServerName: "com.example/slow", HeldSince: now.Add(-2 * time.Minute)},
+		{ServerName: "com.example/publishing", HeldSince: now},
+	}
+
+	require.NoError(t, NewStaleLockJob(monitor, time.Minute, metrics)(ctx))
+	assert.Equal(t, int64(2), staleCount())
+
+	require.NoError(t, NewStaleLockJob(fakeLockMonitor{}, time.Minute, metrics)(ctx))
+	assert.Equal(t, int64(0), staleCount())
+
+	// Metrics are optional
+	assert.NoError(t, NewStaleLockJob(monitor, time.Minute, nil)(ctx))
+}
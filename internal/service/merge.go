@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// applyMergePatch applies a JSON merge patch (RFC 7396) to a server, returning the merged server.
+// Fields absent from the patch are left intact, null removes a field, and objects are merged recursively.
+// Arrays are replaced wholesale, as the RFC requires.
+func applyMergePatch(server apiv0.ServerJSON, patch map[string]any) (*apiv0.ServerJSON, error) {
+	original, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode server: %w", err)
+	}
+
+	var target map[string]any
+	if err := json.Unmarshal(original, &target); err != nil {
+		return nil, fmt.Errorf("failed to decode server: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatchValue(target, patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged server: %w", err)
+	}
+
+	var result apiv0.ServerJSON
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return nil, fmt.Errorf("invalid merged server: %w", err)
+	}
+	return &result, nil
+}
+
+// mergePatchValue implements the RFC 7396 MergePatch algorithm on decoded JSON values
+func mergePatchValue(target any, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatchValue(targetObj[key], value)
+	}
+	return targetObj
+}
@@ -0,0 +1,90 @@
+//nolint:testpackage
+package service
+
+import (
+	"testing"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	original := apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/merge-test",
+		Description: "Original description",
+		Title:       "Original title",
+		Version:     "1.0.0",
+		Repository: &model.Repository{
+			URL:    "https://github.com/example/merge-test",
+			Source: "github",
+		},
+		Packages: []model.Package{
+			{RegistryType: "npm", Identifier: "@example/merge-test", Version: "1.0.0"},
+		},
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		patch       map[string]any
+		checkResult func(*testing.T, *apiv0.ServerJSON)
+	}{
+		{
+			name:  "only patched fields change",
+			patch: map[string]any{"description": "Patched description"},
+			checkResult: func(t *testing.T, result *apiv0.ServerJSON) {
+				t.Helper()
+				assert.Equal(t, "Patched description", result.Description)
+				assert.Equal(t, original.Title, result.Title)
+				assert.Equal(t, original.Packages, result.Packages)
+				assert.Equal(t, original.Remotes, result.Remotes)
+			},
+		},
+		{
+			name:  "null removes a field",
+			patch: map[string]any{"title": nil},
+			checkResult: func(t *testing.T, result *apiv0.ServerJSON) {
+				t.Helper()
+				assert.Empty(t, result.Title)
+				assert.Equal(t, original.Description, result.Description)
+			},
+		},
+		{
+			name:  "nested objects are merged",
+			patch: map[string]any{"repository": map[string]any{"subfolder": "src/server"}},
+			checkResult: func(t *testing.T, result *apiv0.ServerJSON) {
+				t.Helper()
+				require.NotNil(t, result.Repository)
+				assert.Equal(t, original.Repository.URL, result.Repository.URL)
+				assert.Equal(t, "src/server", result.Repository.Subfolder)
+			},
+		},
+		{
+			name: "arrays are replaced",
+			patch: map[string]any{"remotes": []any{
+				map[string]any{"type": "sse", "url": "https://example.com/sse"},
+			}},
+			checkResult: func(t *testing.T, result *apiv0.ServerJSON) {
+				t.Helper()
+				require.Len(t, result.Remotes, 1)
+				assert.Equal(t, "https://example.com/sse", result.Remotes[0].URL)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := applyMergePatch(original, tt.patch)
+			require.NoError(t, err)
+			tt.checkResult(t, result)
+		})
+	}
+
+	// The original server is never mutated
+	assert.Equal(t, "Original description", original.Description)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/aws"
+)
+
+const (
+	// notifyQueueSize is how many changes can wait to be announced before further ones are dropped
+	notifyQueueSize = 1000
+	// notifyTimeout bounds announcing a batch of changes, including refreshing the export announced with them
+	notifyTimeout = time.Minute
+)
+
+// errNotifyQueueFull is returned by changeNotifierSink.Record when a change can't be queued for announcing
+var errNotifyQueueFull = errors.New("change notification queue is full")
+
+// ChangePublisher announces changes to server versions to downstream consumers, such as an SQS queue.
+// announceExport asks it to also announce its registry export, which reflects the change.
+type ChangePublisher interface {
+	Publish(ctx context.Context, change aws.Change, announceExport bool) error
+}
+
+// ChangeNotifierOption configures a change notifier sink
+type ChangeNotifierOption func(*changeNotifierSink)
+
+// WithExportRefresh makes the notifier run refresh to upload the registry export its publisher announces before
+// announcing changes, and announce the export only once it has been refreshed. Changes that queue up while one
+// batch is announced share the next refresh.
+func WithExportRefresh(refresh func(ctx context.Context) error) ChangeNotifierOption {
+	return func(s *changeNotifierSink) {
+		s.refresh = refresh
+	}
+}
+
+// changeNotifierSink is an AuditSink that announces each change through a ChangePublisher from a background
+// worker, so slow announcements don't hold up the changes and outlive the requests that made them
+type changeNotifierSink struct {
+	publisher ChangePublisher
+	refresh   func(ctx context.Context) error // nil unless the announced export is refreshed before announcing
+
+	mu     sync.Mutex // guards closed and sends on queue
+	closed bool
+	queue  chan aws.Change
+	done   chan struct{} // closed when the worker has announced every queued change
+}
+
+// NewChangeNotifierSink returns an AuditSink that publishes every change the registry service makes, for
+// deployments where this registry is the source of truth for downstream registries. Like other audit entries,
+// changes are published after they are committed, and a failure to publish one is logged rather than undoing it.
+// Changes are queued and published in order by a background worker; the returned function stops accepting
+// changes and waits until the queued ones are published or ctx is done.
+func NewChangeNotifierSink(publisher ChangePublisher, opts ...ChangeNotifierOption) (AuditSink, func(ctx context.Context) error) {
+	s := &changeNotifierSink{
+		publisher: publisher,
+		queue:     make(chan aws.Change, notifyQueueSize),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return s, s.close
+}
+
+// Record implements AuditSink.Record by queuing the change for the worker. The request's context isn't used,
+// so a client that disconnects doesn't cancel the announcement.
+func (s *changeNotifierSink) Record(_ context.Context, entry AuditEntry) error {
+	change := aws.Change{
+		Op:         entry.Action,
+		ServerName: entry.ServerName,
+		Version:    entry.Version,
+		Status:     entry.Status,
+		Time:       entry.Time,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("change notifier is closed")
+	}
+	select {
+	case s.queue <- change:
+		return nil
+	default:
+		return errNotifyQueueFull
+	}
+}
+
+// close stops accepting changes and waits for the queued ones to be published, or for ctx to be done
+func (s *changeNotifierSink) close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run publishes queued changes until the queue is closed, taking every change waiting at once as a batch
+func (s *changeNotifierSink) run() {
+	defer close(s.done)
+	for change := range s.queue {
+		s.announce(s.takeQueued([]aws.Change{change}))
+	}
+}
+
+// takeQueued appends the changes waiting in the queue to batch without blocking
+func (s *changeNotifierSink) takeQueued(batch []aws.Change) []aws.Change {
+	for {
+		select {
+		case next, ok := <-s.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, next)
+		default:
+			return batch
+		}
+	}
+}
+
+// announce refreshes the export if configured, then publishes each change in the batch, announcing the export
+// only if it was refreshed
+func (s *changeNotifierSink) announce(batch []aws.Change) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	refreshed := false
+	if s.refresh != nil {
+		if err := s.refresh(ctx); err != nil {
+			log.Printf("Failed to refresh the registry export for %d change notifications, announcing them without it: %v", len(batch), err)
+		} else {
+			refreshed = true
+		}
+	}
+
+	for _, change := range batch {
+		if err := s.publisher.Publish(ctx, change, refreshed); err != nil {
+			log.Printf("Failed to publish change notification for %s %s@%s: %v", change.Op, change.ServerName, change.Version, err)
+		}
+	}
+}
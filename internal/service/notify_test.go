@@ -0,0 +1,160 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/aws"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher collects published changes in memory, failing with err if set and waiting for release
+// to be closed before publishing if it is set
+type recordingPublisher struct {
+	mu       sync.Mutex
+	changes  []aws.Change
+	exported []bool // whether each change announced the export
+	err      error
+	release  chan struct{}
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, change aws.Change, announceExport bool) error {
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.changes = append(p.changes, change)
+	p.exported = append(p.exported, announceExport)
+	return nil
+}
+
+func (p *recordingPublisher) published() []aws.Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.changes)
+}
+
+func TestChangeNotifierSink(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	audit := &recordingAuditSink{}
+	notifier, closeNotifier := NewChangeNotifierSink(publisher)
+	registry := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false},
+		WithAuditSink(audit), WithAuditSink(notifier))
+
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/notified",
+		Description: "Notified server",
+		Version:     "1.0.0",
+	}
+	waitForPublished := func(n int) []aws.Change {
+		t.Helper()
+		require.Eventually(t, func() bool { return len(publisher.published()) == n }, time.Second, time.Millisecond)
+		return publisher.published()
+	}
+
+	t.Run("create is published", func(t *testing.T) {
+		_, err := registry.CreateServer(ctx, server)
+		require.NoError(t, err)
+
+		changes := waitForPublished(1)
+		change := changes[0]
+		assert.Equal(t, AuditActionCreate, change.Op)
+		assert.Equal(t, "com.example/notified", change.ServerName)
+		assert.Equal(t, "1.0.0", change.Version)
+		assert.Equal(t, string(model.StatusActive), change.Status)
+		assert.False(t, change.Time.IsZero())
+
+		// Every sink sees the change
+		assert.Len(t, audit.take(), 1)
+	})
+
+	t.Run("update is published", func(t *testing.T) {
+		updated := *server
+		updated.Description = "Updated description"
+		_, err := registry.UpdateServer(ctx, server.Name, server.Version, &updated, nil)
+		require.NoError(t, err)
+
+		changes := waitForPublished(2)
+		assert.Equal(t, AuditActionUpdate, changes[1].Op)
+		assert.Equal(t, []bool{false, false}, publisher.exported, "no export is announced unless it is refreshed")
+	})
+
+	t.Run("slow publishes don't hold up changes", func(t *testing.T) {
+		publisher.release = make(chan struct{})
+		requestCtx, cancel := context.WithCancel(ctx)
+		_, err := registry.CreateServer(requestCtx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/notified",
+			Description: "Notified server",
+			Version:     "1.5.0",
+		})
+		require.NoError(t, err)
+		// The request ending doesn't cancel the announcement
+		cancel()
+		close(publisher.release)
+		waitForPublished(3)
+		publisher.release = nil
+	})
+
+	t.Run("publish failures don't fail the change", func(t *testing.T) {
+		publisher.mu.Lock()
+		publisher.err = errors.New("queue unavailable")
+		publisher.mu.Unlock()
+		_, err := registry.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/notified",
+			Description: "Notified server",
+			Version:     "2.0.0",
+		})
+		require.NoError(t, err)
+		assert.Len(t, audit.take(), 3)
+	})
+
+	t.Run("closing waits for queued changes", func(t *testing.T) {
+		require.NoError(t, closeNotifier(ctx))
+		assert.Error(t, notifier.Record(ctx, AuditEntry{Action: AuditActionCreate}))
+	})
+}
+
+func TestChangeNotifierSink_ExportRefresh(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{release: make(chan struct{})}
+	var refreshes atomic.Int32
+	var refreshErr error
+	notifier, closeNotifier := NewChangeNotifierSink(publisher, WithExportRefresh(func(context.Context) error {
+		refreshes.Add(1)
+		return refreshErr
+	}))
+
+	// Changes that queue up while a batch is announced share one refresh
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		require.NoError(t, notifier.Record(ctx, AuditEntry{Action: AuditActionCreate, ServerName: "com.example/exported", Version: version}))
+	}
+	close(publisher.release)
+	require.Eventually(t, func() bool { return len(publisher.published()) == 3 }, time.Second, time.Millisecond)
+	assert.LessOrEqual(t, refreshes.Load(), int32(2))
+	assert.Equal(t, []bool{true, true, true}, publisher.exported)
+
+	// A stale export isn't announced
+	refreshErr = errors.New("upload failed")
+	require.NoError(t, notifier.Record(ctx, AuditEntry{Action: AuditActionUpdate, ServerName: "com.example/exported", Version: "1.2.0"}))
+	require.NoError(t, closeNotifier(ctx))
+	assert.Equal(t, []bool{true, true, true, false}, publisher.exported)
+}
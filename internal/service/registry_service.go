@@ -1,12 +1,19 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/aws"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/validators"
@@ -14,27 +21,64 @@ import (
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
-const maxServerVersionsPerServer = 10000
+// Policies for publishing a server that has reached MCP_REGISTRY_MAX_VERSIONS_PER_SERVER versions
+const (
+	MaxVersionsPolicyReject = "reject" // reject the publish with ErrMaxServersReached
+	MaxVersionsPolicyPrune  = "prune"  // delete the earliest published versions that won't be the latest to make room
+)
 
 // registryServiceImpl implements the RegistryService interface using our Database
 type registryServiceImpl struct {
 	db  database.Database
 	cfg *config.Config
+
+	// s3Downloader fetches registry data for ReloadFromS3, checks its reachability and uploads catalogs; created on first use
+	s3Downloader   aws.S3Client
+	s3DownloaderMu sync.Mutex
+
+	// auditSinks each receive an entry for every committed change; none disables auditing
+	auditSinks []AuditSink
+}
+
+// RegistryOption configures a registry service
+type RegistryOption func(*registryServiceImpl)
+
+// WithAuditSink records every change the service makes to sink, in addition to any sinks added before
+func WithAuditSink(sink AuditSink) RegistryOption {
+	return func(s *registryServiceImpl) {
+		s.auditSinks = append(s.auditSinks, sink)
+	}
 }
 
 // NewRegistryService creates a new registry service with the provided database
-func NewRegistryService(db database.Database, cfg *config.Config) RegistryService {
-	return &registryServiceImpl{
+func NewRegistryService(db database.Database, cfg *config.Config, opts ...RegistryOption) RegistryService {
+	s := &registryServiceImpl{
 		db:  db,
 		cfg: cfg,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ListServers returns registry entries with cursor-based pagination and optional filtering
 func (s *registryServiceImpl) ListServers(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error) {
-	// If limit is not set or negative, use a default limit
+	// If limit is not set or negative, use the configured default, and cap it at the configured maximum
+	defaultLimit, maxLimit := s.cfg.PageSizes()
 	if limit <= 0 {
-		limit = 30
+		limit = defaultLimit
+	}
+	limit = min(limit, maxLimit)
+
+	// Fuzzy searches without their own threshold use the configured one
+	if filter != nil && filter.FuzzyName != nil && filter.MinSimilarity == 0 {
+		filtered := *filter
+		filtered.MinSimilarity = s.cfg.FuzzySearchMinSimilarity
+		if filtered.MinSimilarity <= 0 {
+			filtered.MinSimilarity = database.DefaultMinSimilarity
+		}
+		filter = &filtered
 	}
 
 	// Use the database's ListServers method with pagination and filtering
@@ -46,6 +90,23 @@ func (s *registryServiceImpl) ListServers(ctx context.Context, filter *database.
 	return serverRecords, nextCursor, nil
 }
 
+// ListServerNames returns distinct server names with cursor-based pagination, optionally limited to a name prefix
+func (s *registryServiceImpl) ListServerNames(ctx context.Context, prefix string, cursor string, limit int) ([]string, string, error) {
+	// Names are paged like servers: the configured default when limit is unset, capped at the configured maximum
+	defaultLimit, maxLimit := s.cfg.PageSizes()
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	limit = min(limit, maxLimit)
+
+	var prefixFilter *string
+	if prefix != "" {
+		prefixFilter = &prefix
+	}
+
+	return s.db.ListServerNames(ctx, nil, prefixFilter, cursor, limit)
+}
+
 // GetServerByName retrieves the latest version of a server by its server name
 func (s *registryServiceImpl) GetServerByName(ctx context.Context, serverName string) (*apiv0.ServerResponse, error) {
 	serverRecord, err := s.db.GetServerByName(ctx, nil, serverName)
@@ -66,6 +127,27 @@ func (s *registryServiceImpl) GetServerByNameAndVersion(ctx context.Context, ser
 	return serverRecord, nil
 }
 
+// CheckVersionExists checks if a specific version of a server exists
+func (s *registryServiceImpl) CheckVersionExists(ctx context.Context, serverName string, version string) (bool, error) {
+	return s.db.CheckVersionExists(ctx, nil, serverName, version)
+}
+
+// GetServerVersions retrieves the server versions identified by refs, in the order of refs
+func (s *registryServiceImpl) GetServerVersions(ctx context.Context, refs []database.ServerRef) ([]*apiv0.ServerResponse, error) {
+	return s.db.GetServerVersions(ctx, nil, refs)
+}
+
+// GetServerAsOf retrieves the version of a server that was the latest at a point in time: of the versions
+// published by then, the one publishing orders highest, so a backport published later doesn't displace it
+func (s *registryServiceImpl) GetServerAsOf(ctx context.Context, serverName string, at time.Time) (*apiv0.ServerResponse, error) {
+	versions, err := s.db.GetVersionsAsOf(ctx, nil, serverName, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return LatestVersion(versions), nil
+}
+
 // GetAllVersionsByServerName retrieves all versions of a server by server name
 func (s *registryServiceImpl) GetAllVersionsByServerName(ctx context.Context, serverName string) ([]*apiv0.ServerResponse, error) {
 	serverRecords, err := s.db.GetAllVersionsByServerName(ctx, nil, serverName)
@@ -76,56 +158,215 @@ func (s *registryServiceImpl) GetAllVersionsByServerName(ctx context.Context, se
 	return serverRecords, nil
 }
 
-// CreateServer creates a new server version
+// CreateServer creates a new server version, or returns the identical version already stored along with
+// database.ErrAlreadyPublished
 func (s *registryServiceImpl) CreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
 	// Wrap the entire operation in a transaction
-	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+	result, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (publishResult, error) {
 		return s.createServerInTransaction(ctx, tx, req)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Identical republishes change nothing, so aren't audited
+	if !result.created {
+		return result.server, database.ErrAlreadyPublished
+	}
+	s.audit(ctx, AuditActionCreate, result.server, "")
+	for _, pruned := range result.pruned {
+		s.audit(ctx, AuditActionDelete, pruned, "pruned: over the per-server version limit")
+	}
+	for _, retired := range result.retired {
+		s.audit(ctx, AuditActionDelete, retired, fmt.Sprintf("pruned: beyond the newest %d versions kept", s.cfg.KeepVersions))
+	}
+	return result.server, nil
 }
 
-// createServerInTransaction contains the actual CreateServer logic within a transaction
-func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx pgx.Tx, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+// PreviewServer runs every publish check on req and returns the server version publishing it would store,
+// or the identical version already stored, without changing anything
+func (s *registryServiceImpl) PreviewServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		plan, err := s.planPublish(ctx, tx, req)
+		if err != nil {
+			return nil, err
+		}
+		if plan.existing != nil {
+			return plan.existing, nil
+		}
+		return &apiv0.ServerResponse{
+			Server: plan.server,
+			Meta:   apiv0.ResponseMeta{Official: plan.officialMeta},
+		}, nil
+	})
+}
+
+// publishResult is the outcome of createServerInTransaction
+type publishResult struct {
+	server  *apiv0.ServerResponse
+	created bool                    // a new version was stored, rather than an identical one handed back
+	pruned  []*apiv0.ServerResponse // versions removed to stay within the per-server version limit
+	retired []*apiv0.ServerResponse // versions beyond the newest KeepVersions removed once the new version became the latest
+}
+
+// ValidateServer normalizes and validates a server exactly as publishing would, without touching the database
+func (s *registryServiceImpl) ValidateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	// Collapse repeated packages and remotes so the stored record is clean
+	serverJSON := *req
+	validators.DeduplicateEntries(&serverJSON)
+
+	// Reject packages from registries this deployment doesn't accept, before any registry lookups
+	if err := validators.ValidateAllowedPackageRegistries(serverJSON, s.cfg.AllowedPackageRegistries); err != nil {
+		return nil, err
+	}
+
+	// Require OCI images to be pinned by digest when this deployment asks for it
+	if s.cfg.RequireOCIDigest {
+		if err := validators.ValidateOCIDigestPinning(serverJSON); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate the request
-	if err := validators.ValidatePublishRequest(ctx, *req, s.cfg); err != nil {
+	if err := validators.ValidatePublishRequest(ctx, serverJSON, s.cfg); err != nil {
 		return nil, err
 	}
 
+	return &serverJSON, nil
+}
+
+// createServerInTransaction contains the actual CreateServer logic within a transaction
+func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx pgx.Tx, req *apiv0.ServerJSON) (publishResult, error) {
+	plan, err := s.planPublish(ctx, tx, req)
+	if err != nil {
+		return publishResult{}, err
+	}
+	if plan.existing != nil {
+		return publishResult{server: plan.existing}, nil
+	}
+
+	// Make room for the new version by removing the oldest versions that won't be the latest
+	var pruned []*apiv0.ServerResponse
+	if plan.atLimit {
+		var keep *apiv0.ServerResponse
+		if !plan.officialMeta.IsLatest {
+			keep = plan.currentLatest
+		}
+		pruned, err = s.pruneOldestVersions(ctx, tx, plan.server.Name, plan.versionCount-plan.limits.MaxVersionsPerServer+1, keep)
+		if err != nil {
+			return publishResult{}, err
+		}
+	}
+
+	// Unmark old latest version if needed
+	if plan.officialMeta.IsLatest && plan.currentLatest != nil {
+		if err := s.db.UnmarkAsLatest(ctx, tx, plan.server.Name); err != nil {
+			return publishResult{}, err
+		}
+	}
+
+	// Insert new server version
+	server, err := s.db.CreateServer(ctx, tx, &plan.server, plan.officialMeta)
+	if err != nil {
+		return publishResult{}, err
+	}
+
+	// A new latest version retires the versions beyond the newest ones kept
+	var retired []*apiv0.ServerResponse
+	if s.cfg.KeepVersions > 0 && plan.officialMeta.IsLatest {
+		if excess := plan.versionCount - len(pruned) + 1 - s.cfg.KeepVersions; excess > 0 {
+			retired, err = s.pruneOldestVersions(ctx, tx, plan.server.Name, excess, server)
+			if err != nil {
+				return publishResult{}, err
+			}
+		}
+	}
+	return publishResult{server: server, created: true, pruned: pruned, retired: retired}, nil
+}
+
+// publishPlan is what publishing a server would store, worked out without changing the database
+type publishPlan struct {
+	server        apiv0.ServerJSON          // the validated and normalized server to store
+	officialMeta  *apiv0.RegistryExtensions // registry metadata for the new version
+	existing      *apiv0.ServerResponse     // an identical version already stored, handed back instead of publishing
+	currentLatest *apiv0.ServerResponse     // the server's latest version before publishing, if any
+	versionCount  int                       // versions the server has before publishing
+	atLimit       bool                      // the server is at its version limit and older versions must be pruned
+	limits        config.PublishLimits      // limits that apply to the server
+}
+
+// publisherIdentity returns the identity recorded as the publisher of a new version
+func publisherIdentity(ctx context.Context) string {
+	if subject := ActorFromContext(ctx).Subject; subject != "" {
+		return subject
+	}
+	return "anonymous"
+}
+
+// planPublish runs every publish check on req, from validation to the version limits and latest computation,
+// and works out what publishing it would store without writing anything
+func (s *registryServiceImpl) planPublish(ctx context.Context, tx pgx.Tx, req *apiv0.ServerJSON) (publishPlan, error) {
+	validated, err := s.ValidateServer(ctx, req)
+	if err != nil {
+		return publishPlan{}, err
+	}
+	initialStatus, serverJSON := takeInitialStatus(*validated)
+
 	publishTime := time.Now()
-	serverJSON := *req
 
 	// Acquire advisory lock to prevent concurrent publishes of the same server
 	if err := s.db.AcquirePublishLock(ctx, tx, serverJSON.Name); err != nil {
-		return nil, err
+		return publishPlan{}, err
 	}
 
 	// Check for duplicate remote URLs
 	if err := s.validateNoDuplicateRemoteURLs(ctx, tx, serverJSON); err != nil {
-		return nil, err
+		return publishPlan{}, err
 	}
 
-	// Check we haven't exceeded the maximum versions allowed for a server
+	// Check we haven't exceeded the maximum versions allowed for a server, or for a new server the maximum
+	// servers allowed in its namespace
+	limits := s.cfg.PublishLimits(serverJSON.Name)
 	versionCount, err := s.db.CountServerVersions(ctx, tx, serverJSON.Name)
 	if err != nil && !errors.Is(err, database.ErrNotFound) {
-		return nil, err
+		return publishPlan{}, err
 	}
-	if versionCount >= maxServerVersionsPerServer {
-		return nil, database.ErrMaxServersReached
+	atLimit := limits.MaxVersionsPerServer > 0 && versionCount >= limits.MaxVersionsPerServer
+	if atLimit && s.cfg.MaxVersionsPolicy != MaxVersionsPolicyPrune {
+		return publishPlan{}, fmt.Errorf("%w: %d versions allowed", database.ErrMaxServersReached, limits.MaxVersionsPerServer)
+	}
+	if versionCount == 0 && limits.MaxServers > 0 {
+		if err := s.checkNamespaceCapacity(ctx, tx, limits); err != nil {
+			return publishPlan{}, err
+		}
+	}
+	if versionCount == 0 && s.cfg.MaxServers > 0 {
+		if err := s.checkRegistryCapacity(ctx, tx); err != nil {
+			return publishPlan{}, err
+		}
 	}
 
 	// Check this isn't a duplicate version
 	versionExists, err := s.db.CheckVersionExists(ctx, tx, serverJSON.Name, serverJSON.Version)
 	if err != nil {
-		return nil, err
+		return publishPlan{}, err
 	}
 	if versionExists {
-		return nil, database.ErrInvalidVersion
+		// An identical republish (e.g. a client retry) is harmless, so hand back what's stored
+		existing, err := s.db.GetServerByNameAndVersion(ctx, tx, serverJSON.Name, serverJSON.Version)
+		if err != nil {
+			return publishPlan{}, err
+		}
+		if sameServerJSON(existing.Server, serverJSON) {
+			return publishPlan{existing: existing}, nil
+		}
+		return publishPlan{}, database.ErrInvalidVersion
 	}
 
 	// Get current latest version to determine if new version should be latest
 	currentLatest, err := s.db.GetCurrentLatestVersion(ctx, tx, serverJSON.Name)
 	if err != nil && !errors.Is(err, database.ErrNotFound) {
-		return nil, err
+		return publishPlan{}, err
 	}
 
 	// Determine if this version should be marked as latest
@@ -143,23 +384,124 @@ func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx
 		) > 0
 	}
 
-	// Unmark old latest version if needed
-	if isNewLatest && currentLatest != nil {
-		if err := s.db.UnmarkAsLatest(ctx, tx, serverJSON.Name); err != nil {
+	// Optionally forbid backfilling versions older than the current latest
+	if s.cfg.EnforceMonotonicVersions && !isNewLatest {
+		return publishPlan{}, fmt.Errorf("%w: %s is not newer than %s", database.ErrVersionNotNewer, serverJSON.Version, currentLatest.Server.Version)
+	}
+
+	return publishPlan{
+		server: serverJSON,
+		officialMeta: &apiv0.RegistryExtensions{
+			Status:      initialStatus,
+			PublishedAt: publishTime,
+			UpdatedAt:   publishTime,
+			IsLatest:    isNewLatest,
+			PublishedBy: publisherIdentity(ctx),
+		},
+		currentLatest: currentLatest,
+		versionCount:  versionCount,
+		atLimit:       atLimit,
+		limits:        limits,
+	}, nil
+}
+
+// checkNamespaceCapacity fails with ErrMaxServersReached if the namespace of limits already holds as many
+// servers as it allows, so no new server can be added to it. It locks the namespace until tx ends, so
+// concurrent first publishes of different servers in it count one another.
+func (s *registryServiceImpl) checkNamespaceCapacity(ctx context.Context, tx pgx.Tx, limits config.PublishLimits) error {
+	// Server names can't contain a colon, so the key never contends with a server's publish lock
+	if err := s.db.AcquirePublishLock(ctx, tx, "namespace:"+limits.Namespace); err != nil {
+		return err
+	}
+
+	names, _, err := s.db.ListServerNames(ctx, tx, &limits.Namespace, "", limits.MaxServers)
+	if err != nil {
+		return err
+	}
+	if len(names) >= limits.MaxServers {
+		return fmt.Errorf("%w: namespace %s allows %d servers", database.ErrMaxServersReached, limits.Namespace, limits.MaxServers)
+	}
+	return nil
+}
+
+// checkRegistryCapacity fails with ErrRegistryFull if the registry already holds MCP_REGISTRY_MAX_SERVERS
+// distinct servers, so no new server can be added. It holds a registry-wide lock until tx ends, so
+// concurrent additions of new servers count one another.
+func (s *registryServiceImpl) checkRegistryCapacity(ctx context.Context, tx pgx.Tx) error {
+	// Server names can't contain a colon, so the key never contends with a server's publish lock
+	if err := s.db.AcquirePublishLock(ctx, tx, "registry:servers"); err != nil {
+		return err
+	}
+
+	count, err := s.db.CountServerNames(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if count >= s.cfg.MaxServers {
+		return fmt.Errorf("%w: %d servers allowed", database.ErrRegistryFull, s.cfg.MaxServers)
+	}
+	return nil
+}
+
+// pruneOldestVersions deletes the n earliest published versions of a server other than keep, returning them.
+// It fails with ErrMaxServersReached if the server doesn't have n such versions.
+func (s *registryServiceImpl) pruneOldestVersions(ctx context.Context, tx pgx.Tx, serverName string, n int, keep *apiv0.ServerResponse) ([]*apiv0.ServerResponse, error) {
+	versions, err := s.db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := slices.DeleteFunc(versions, func(v *apiv0.ServerResponse) bool {
+		return keep != nil && v.Server.Version == keep.Server.Version
+	})
+	if len(candidates) < n {
+		return nil, fmt.Errorf("%w: no older versions to prune", database.ErrMaxServersReached)
+	}
+	slices.SortStableFunc(candidates, func(a, b *apiv0.ServerResponse) int {
+		return publishedAt(a).Compare(publishedAt(b))
+	})
+
+	pruned := candidates[:n]
+	for _, version := range pruned {
+		if err := s.db.DeleteServerVersion(ctx, tx, serverName, version.Server.Version); err != nil {
 			return nil, err
 		}
 	}
+	return pruned, nil
+}
 
-	// Create metadata for the new server
-	officialMeta := &apiv0.RegistryExtensions{
-		Status:      model.StatusActive, /* New versions are active by default */
-		PublishedAt: publishTime,
-		UpdatedAt:   publishTime,
-		IsLatest:    isNewLatest,
+// takeInitialStatus returns the status requested for a new version in the official _meta extension, active if
+// none was, along with the server without the extension, which isn't stored
+func takeInitialStatus(serverJSON apiv0.ServerJSON) (model.Status, apiv0.ServerJSON) {
+	status := model.StatusActive
+	if serverJSON.Meta == nil || serverJSON.Meta.Official == nil {
+		return status, serverJSON
+	}
+	if serverJSON.Meta.Official.Status != "" {
+		status = serverJSON.Meta.Official.Status
 	}
 
-	// Insert new server version
-	return s.db.CreateServer(ctx, tx, &serverJSON, officialMeta)
+	meta := *serverJSON.Meta
+	meta.Official = nil
+	serverJSON.Meta = &meta
+	if meta.PublisherProvided == nil && meta.Provenance == nil && meta.Tags == nil {
+		serverJSON.Meta = nil
+	}
+	return status, serverJSON
+}
+
+// sameServerJSON reports whether two servers have identical content.
+// Servers are compared by their JSON encoding, so nil and empty optional fields are treated alike.
+func sameServerJSON(a, b apiv0.ServerJSON) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aJSON, bJSON)
 }
 
 // validateNoDuplicateRemoteURLs checks that no other server is using the same remote URLs
@@ -188,9 +530,15 @@ func (s *registryServiceImpl) validateNoDuplicateRemoteURLs(ctx context.Context,
 // UpdateServer updates an existing server with new details
 func (s *registryServiceImpl) UpdateServer(ctx context.Context, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (*apiv0.ServerResponse, error) {
 	// Wrap the entire operation in a transaction
-	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+	server, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
 		return s.updateServerInTransaction(ctx, tx, serverName, version, req, newStatus)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditActionUpdate, server, "")
+	return server, nil
 }
 
 // updateServerInTransaction contains the actual UpdateServer logic within a transaction
@@ -208,8 +556,13 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 	beingDeleted := newStatus != nil && *newStatus == string(model.StatusDeleted)
 	skipRegistryValidation := currentlyDeleted || beingDeleted
 
+	// Collapse repeated packages and remotes so the stored record is clean. An initial status only applies
+	// on publish; edits change status through newStatus.
+	_, updatedServer := takeInitialStatus(*req)
+	validators.DeduplicateEntries(&updatedServer)
+
 	// Validate the request, potentially skipping registry validation for deleted servers
-	if err := s.validateUpdateRequest(ctx, *req, skipRegistryValidation); err != nil {
+	if err := s.validateUpdateRequest(ctx, updatedServer, skipRegistryValidation); err != nil {
 		return nil, err
 	}
 
@@ -218,9 +571,6 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 		return nil, err
 	}
 
-	// Merge the request with the current server, preserving metadata
-	updatedServer := *req
-
 	// Check for duplicate remote URLs using the updated server
 	if err := s.validateNoDuplicateRemoteURLs(ctx, tx, updatedServer); err != nil {
 		return nil, err
@@ -232,9 +582,13 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 		return nil, err
 	}
 
-	// Handle status change if provided
+	// Handle status change if provided. Edits can't give a reason, so one set through SetServerStatus is kept.
 	if newStatus != nil {
-		updatedWithStatus, err := s.db.SetServerStatus(ctx, tx, serverName, version, *newStatus)
+		var reason string
+		if currentServer.Meta.Official != nil {
+			reason = currentServer.Meta.Official.StatusReason
+		}
+		updatedWithStatus, err := s.db.SetServerStatus(ctx, tx, serverName, version, *newStatus, reason)
 		if err != nil {
 			return nil, err
 		}
@@ -244,24 +598,683 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 	return updatedServerResponse, nil
 }
 
-// validateUpdateRequest validates an update request with optional registry validation skipping
-func (s *registryServiceImpl) validateUpdateRequest(ctx context.Context, req apiv0.ServerJSON, skipRegistryValidation bool) error {
-	// Always validate the server JSON structure
-	if err := validators.ValidateServerJSON(&req); err != nil {
+// PatchServer applies a JSON merge patch to an existing server version
+func (s *registryServiceImpl) PatchServer(ctx context.Context, serverName, version string, patch map[string]any) (*apiv0.ServerResponse, error) {
+	// Wrap the entire operation in a transaction
+	server, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		return s.patchServerInTransaction(ctx, tx, serverName, version, patch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditActionUpdate, server, "")
+	return server, nil
+}
+
+// patchServerInTransaction contains the actual PatchServer logic within a transaction
+func (s *registryServiceImpl) patchServerInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string, patch map[string]any) (*apiv0.ServerResponse, error) {
+	// Acquire advisory lock before reading, so concurrent edits can't be lost between read and write
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedServer, err := applyMergePatch(currentServer.Server, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", database.ErrInvalidInput, err)
+	}
+
+	// Prevent renaming servers or changing their version through a patch
+	if mergedServer.Name != serverName {
+		return nil, fmt.Errorf("%w: cannot rename server", database.ErrInvalidInput)
+	}
+	if mergedServer.Version != version {
+		return nil, fmt.Errorf("%w: cannot change server version", database.ErrInvalidInput)
+	}
+
+	_, *mergedServer = takeInitialStatus(*mergedServer)
+	validators.DeduplicateEntries(mergedServer)
+
+	// Validate the merged result, skipping registry validation for deleted servers
+	currentlyDeleted := currentServer.Meta.Official != nil && currentServer.Meta.Official.Status == model.StatusDeleted
+	if err := s.validateUpdateRequest(ctx, *mergedServer, currentlyDeleted); err != nil {
+		return nil, err
+	}
+
+	// Check for duplicate remote URLs using the merged server
+	if err := s.validateNoDuplicateRemoteURLs(ctx, tx, *mergedServer); err != nil {
+		return nil, err
+	}
+
+	return s.db.UpdateServer(ctx, tx, serverName, version, mergedServer)
+}
+
+// maxStatusReasonLength caps the length of the reason given for a status change, in characters
+const maxStatusReasonLength = 500
+
+// SetServerStatus changes the lifecycle status of a specific server version, recording reason alongside it if set
+func (s *registryServiceImpl) SetServerStatus(ctx context.Context, serverName, version string, status model.Status, reason string) (*apiv0.ServerResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return nil, fmt.Errorf("%w: status reason must be at most %d characters", database.ErrInvalidInput, maxStatusReasonLength)
+	}
+
+	// Wrap the entire operation in a transaction
+	server, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		return s.setServerStatusInTransaction(ctx, tx, serverName, version, status, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditActionStatus, server, reason)
+	return server, nil
+}
+
+// setServerStatusInTransaction contains the actual SetServerStatus logic within a transaction
+func (s *registryServiceImpl) setServerStatusInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string, status model.Status, reason string) (*apiv0.ServerResponse, error) {
+	if !isKnownStatus(status) {
+		return nil, fmt.Errorf("%w: unknown status %q", database.ErrInvalidInput, status)
+	}
+
+	// Acquire advisory lock to prevent concurrent edits of servers with same name
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent undeleting servers - once deleted, they stay deleted
+	if currentServer.Meta.Official != nil &&
+		currentServer.Meta.Official.Status == model.StatusDeleted &&
+		status != model.StatusDeleted {
+		return nil, fmt.Errorf("%w: deleted servers cannot be undeleted", database.ErrInvalidInput)
+	}
+
+	return s.db.SetServerStatus(ctx, tx, serverName, version, string(status), reason)
+}
+
+// DeprecateServer marks a server version as deprecated, optionally recording the server that replaces it
+func (s *registryServiceImpl) DeprecateServer(ctx context.Context, serverName, version, replacedBy string) (*apiv0.ServerResponse, error) {
+	// Wrap the entire operation in a transaction
+	server, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		return s.deprecateServerInTransaction(ctx, tx, serverName, version, replacedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	details := ""
+	if replacedBy != "" {
+		details = "replaced by " + replacedBy
+	}
+	s.audit(ctx, AuditActionStatus, server, details)
+	return server, nil
+}
+
+// deprecateServerInTransaction contains the actual DeprecateServer logic within a transaction
+func (s *registryServiceImpl) deprecateServerInTransaction(ctx context.Context, tx pgx.Tx, serverName, version, replacedBy string) (*apiv0.ServerResponse, error) {
+	// Acquire advisory lock to prevent concurrent edits of servers with same name
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleted servers stay deleted
+	if currentServer.Meta.Official != nil && currentServer.Meta.Official.Status == model.StatusDeleted {
+		return nil, fmt.Errorf("%w: deleted servers cannot be deprecated", database.ErrInvalidInput)
+	}
+
+	if replacedBy != "" {
+		if err := s.validateReplacement(ctx, tx, serverName, replacedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.db.DeprecateServer(ctx, tx, serverName, version, replacedBy)
+}
+
+// TransferServer moves every version of a server to a new name, optionally leaving a deprecated tombstone
+// at the old name that points to the new one
+func (s *registryServiceImpl) TransferServer(ctx context.Context, serverName, newName string, tombstone bool) ([]*apiv0.ServerResponse, error) {
+	if err := validators.ValidateServerName(newName); err != nil {
+		return nil, fmt.Errorf("%w: %v", database.ErrInvalidInput, err)
+	}
+	if newName == serverName {
+		return nil, fmt.Errorf("%w: a server cannot be transferred to its own name", database.ErrInvalidInput)
+	}
+
+	// Wrap the entire operation in a transaction so the rename and tombstone land together
+	var tombstoneServer *apiv0.ServerResponse
+	transferred, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) ([]*apiv0.ServerResponse, error) {
+		transferred, created, err := s.transferServerInTransaction(ctx, tx, serverName, newName, tombstone)
+		tombstoneServer = created
+		return transferred, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server := range transferred {
+		s.audit(ctx, AuditActionTransfer, server, "from "+serverName)
+	}
+	s.audit(ctx, AuditActionCreate, tombstoneServer, "tombstone for "+newName)
+	return transferred, nil
+}
+
+// transferServerInTransaction contains the actual TransferServer logic within a transaction.
+// It also returns the tombstone left at the old name, if one was created.
+func (s *registryServiceImpl) transferServerInTransaction(ctx context.Context, tx pgx.Tx, serverName, newName string, tombstone bool) ([]*apiv0.ServerResponse, *apiv0.ServerResponse, error) {
+	// Lock both names, in a fixed order so concurrent transfers can't deadlock
+	names := []string{serverName, newName}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := s.db.AcquirePublishLock(ctx, tx, name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	existing, err := s.db.CountServerVersions(ctx, tx, newName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing > 0 {
+		return nil, nil, fmt.Errorf("%w: server %s already exists", database.ErrAlreadyExists, newName)
+	}
+
+	// Leaving a tombstone keeps the old name, so the transfer adds a server
+	if tombstone && s.cfg.MaxServers > 0 {
+		if err := s.checkRegistryCapacity(ctx, tx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	latest, err := s.db.GetServerByName(ctx, tx, serverName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transferred, err := s.db.RenameServer(ctx, tx, serverName, newName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tombstone {
+		// The tombstone is a deprecated copy of the latest version that tells clients where the server went.
+		// Its remote URLs now belong to the transferred server, so it leaves them out, and it is checked
+		// for conflicts like any other version.
+		tombstoneJSON := latest.Server
+		tombstoneJSON.Name = serverName
+		tombstoneJSON.Remotes = nil
+		if err := s.validateNoDuplicateRemoteURLs(ctx, tx, tombstoneJSON); err != nil {
+			return nil, nil, err
+		}
+		now := time.Now()
+		if _, err := s.db.CreateServer(ctx, tx, &tombstoneJSON, &apiv0.RegistryExtensions{
+			Status:      model.StatusDeprecated,
+			PublishedAt: now,
+			UpdatedAt:   now,
+			IsLatest:    true,
+			PublishedBy: publisherIdentity(ctx),
+		}); err != nil {
+			return nil, nil, err
+		}
+		tombstoneServer, err := s.db.DeprecateServer(ctx, tx, serverName, tombstoneJSON.Version, newName)
+		if err != nil {
+			return nil, nil, err
+		}
+		return transferred, tombstoneServer, nil
+	}
+
+	return transferred, nil, nil
+}
+
+// SetLatestVersion designates a specific server version as the latest, regardless of how it orders against the
+// others, and unmarks every other version of the server in the same step. The version is pinned, so reconciling,
+// importing and reloading keep it the latest until a newer version is published.
+func (s *registryServiceImpl) SetLatestVersion(ctx context.Context, serverName, version string) (*apiv0.ServerResponse, error) {
+	server, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		return s.setLatestVersionInTransaction(ctx, tx, serverName, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditActionLatest, server, "")
+	return server, nil
+}
+
+// setLatestVersionInTransaction contains the actual SetLatestVersion logic within a transaction
+func (s *registryServiceImpl) setLatestVersionInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string) (*apiv0.ServerResponse, error) {
+	// Serialize with publishes, which also decide which version is the latest
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	server, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+	if server.Meta.Official != nil && server.Meta.Official.Status == model.StatusDeleted {
+		return nil, fmt.Errorf("%w: deleted versions cannot be made the latest", database.ErrInvalidInput)
+	}
+
+	if _, err := s.db.SetLatestVersion(ctx, tx, serverName, version, true); err != nil {
+		return nil, err
+	}
+	return s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+}
+
+// reconcileLatestPageSize is how many server names ReconcileLatest reads at a time
+const reconcileLatestPageSize = 500
+
+// ReconcileLatest recomputes the latest version of every server and fixes any servers whose latest flags disagree,
+// returning how many versions had their flag corrected. Servers whose latest version is pinned are left alone.
+func (s *registryServiceImpl) ReconcileLatest(ctx context.Context) (int, error) {
+	corrected := 0
+	cursor := ""
+	for {
+		names, nextCursor, err := s.db.ListServerNames(ctx, nil, nil, cursor, reconcileLatestPageSize)
+		if err != nil {
+			return corrected, err
+		}
+
+		for _, name := range names {
+			// Each server is fixed in its own transaction so one bad server doesn't hold back the rest
+			changed, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) ([]*apiv0.ServerResponse, error) {
+				return s.reconcileLatestInTransaction(ctx, tx, name)
+			})
+			if err != nil {
+				return corrected, fmt.Errorf("failed to reconcile %s: %w", name, err)
+			}
+			for _, version := range changed {
+				if isLatest(version) {
+					s.audit(ctx, AuditActionLatest, version, "reconciled: now the latest")
+				} else {
+					s.audit(ctx, AuditActionLatest, version, "reconciled: no longer the latest")
+				}
+			}
+			corrected += len(changed)
+		}
+
+		if nextCursor == "" {
+			return corrected, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// reconcileLatestInTransaction contains the actual ReconcileLatest logic for one server within a transaction,
+// returning the versions whose latest flag it corrected as they are now
+func (s *registryServiceImpl) reconcileLatestInTransaction(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	// Serialize with publishes, which also decide which version is the latest
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil // Removed since the names were listed
+		}
+		return nil, err
+	}
+
+	if database.PinnedLatest(versions) != nil {
+		return nil, nil
+	}
+	latest := LatestVersion(versions)
+	if latest == nil {
+		return nil, nil
+	}
+
+	var corrected []*apiv0.ServerResponse
+	for _, version := range versions {
+		if version.Meta.Official == nil || version.Meta.Official.IsLatest == (version == latest) {
+			continue
+		}
+		official := *version.Meta.Official
+		official.IsLatest = version == latest
+		fixed := *version
+		fixed.Meta.Official = &official
+		corrected = append(corrected, &fixed)
+	}
+	if len(corrected) == 0 {
+		return nil, nil
+	}
+	if _, err := s.db.SetLatestVersion(ctx, tx, serverName, latest.Server.Version, false); err != nil {
+		return nil, err
+	}
+	return corrected, nil
+}
+
+// LatestVersion picks the version that should be marked latest using the same ordering as publishing.
+// Among versions that order equally, one already marked latest is kept so reconciling changes as little as possible.
+func LatestVersion(versions []*apiv0.ServerResponse) *apiv0.ServerResponse {
+	var latest *apiv0.ServerResponse
+	for _, version := range versions {
+		if latest == nil {
+			latest = version
+			continue
+		}
+		cmp := CompareVersions(version.Server.Version, latest.Server.Version, publishedAt(version), publishedAt(latest))
+		if cmp > 0 || (cmp == 0 && isLatest(version) && !isLatest(latest)) {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// publishedAt returns when a server version was published, or the zero time if it has no official metadata
+func publishedAt(server *apiv0.ServerResponse) time.Time {
+	if server.Meta.Official == nil {
+		return time.Time{}
+	}
+	return server.Meta.Official.PublishedAt
+}
+
+// isLatest reports whether a server version is marked as the latest
+func isLatest(server *apiv0.ServerResponse) bool {
+	return server.Meta.Official != nil && server.Meta.Official.IsLatest
+}
+
+// validateReplacement checks that a deprecated server's replacement is a different server that exists and isn't deleted
+func (s *registryServiceImpl) validateReplacement(ctx context.Context, tx pgx.Tx, serverName, replacedBy string) error {
+	if replacedBy == serverName {
+		return fmt.Errorf("%w: a server cannot be replaced by itself", database.ErrInvalidInput)
+	}
+
+	replacement, err := s.db.GetServerByName(ctx, tx, replacedBy)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return fmt.Errorf("%w: replacement server %s not found", database.ErrInvalidInput, replacedBy)
+		}
 		return err
 	}
+	if replacement.Meta.Official != nil && replacement.Meta.Official.Status == model.StatusDeleted {
+		return fmt.Errorf("%w: replacement server %s is deleted", database.ErrInvalidInput, replacedBy)
+	}
+
+	return nil
+}
+
+// Compact removes deleted and surplus server versions according to the configured retention settings
+func (s *registryServiceImpl) Compact(ctx context.Context, dryRun bool) (database.CompactResult, error) {
+	compactor, ok := s.db.(database.Compactor)
+	if !ok {
+		return database.CompactResult{}, fmt.Errorf("%w: compaction requires the JSON file database", database.ErrInvalidInput)
+	}
+
+	result, err := compactor.Compact(ctx, database.CompactOptions{
+		DeletedRetention: s.cfg.CompactDeletedRetention,
+		MaxVersions:      s.cfg.CompactMaxVersions,
+		DryRun:           dryRun,
+	})
+	if err != nil || dryRun {
+		return result, err
+	}
 
-	// Skip registry validation if requested (for deleted servers)
-	if skipRegistryValidation || !s.cfg.EnableRegistryValidation {
+	for _, removed := range result.Removed {
+		s.recordAudit(ctx, AuditEntry{
+			Action:     AuditActionDelete,
+			ServerName: removed.ServerName,
+			Version:    removed.Version,
+			Details:    "compacted: " + string(removed.Reason),
+		})
+	}
+	return result, nil
+}
+
+// Flush persists any changes the database holds in memory
+func (s *registryServiceImpl) Flush(ctx context.Context) error {
+	flusher, ok := s.db.(database.Flusher)
+	if !ok {
+		// Write-through databases such as PostgreSQL have nothing to flush
 		return nil
 	}
+	return flusher.Flush(ctx)
+}
 
-	// Perform registry validation for all packages
-	for i, pkg := range req.Packages {
-		if err := validators.ValidatePackage(ctx, pkg, req.Name); err != nil {
-			return fmt.Errorf("registry validation failed for package %d (%s): %w", i, pkg.Identifier, err)
-		}
+// LastSync returns the last successful refresh of the database's data
+func (s *registryServiceImpl) LastSync() (database.SyncStatus, bool) {
+	tracker, ok := s.db.(database.SyncTracker)
+	if !ok {
+		return database.SyncStatus{}, false
+	}
+	return tracker.LastSync()
+}
+
+// ReloadFromS3 downloads registry data from S3, validates it and swaps it in for the database's data file,
+// returning the number of records loaded. An empty s3URL uses the configured S3 URL.
+func (s *registryServiceImpl) ReloadFromS3(ctx context.Context, s3URL string) (int, error) {
+	fileDB, ok := s.db.(database.FileReloader)
+	if !ok {
+		return 0, fmt.Errorf("%w: reloading from S3 requires the JSON file database", database.ErrInvalidInput)
+	}
+
+	if s3URL == "" {
+		s3URL = s.cfg.S3URL
+	}
+	if s3URL == "" {
+		return 0, fmt.Errorf("%w: no S3 URL given and MCP_REGISTRY_S3_URL is not configured", database.ErrInvalidInput)
+	}
+
+	bucket, key, err := aws.ParseS3URL(s3URL)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", database.ErrInvalidInput, err)
+	}
+
+	downloader, err := s.getS3Downloader(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Same validate-before-swap path as the SQS listener
+	reloader := aws.NewS3Reloader(downloader, fileDB.FilePath(), database.ValidateJSONFile, func(source string) error {
+		return s.ReloadFile(ctx, source)
+	})
+	if err := reloader.Reload(ctx, bucket, key); err != nil {
+		return 0, err
+	}
+
+	return fileDB.Count(), nil
+}
+
+// ReloadFile reloads the JSON file database from its data file, which holds data from source, and records an
+// audit entry for each version the reload creates, changes or removes
+func (s *registryServiceImpl) ReloadFile(ctx context.Context, source string) error {
+	fileDB, ok := s.db.(database.FileReloader)
+	if !ok {
+		return fmt.Errorf("%w: reloading requires the JSON file database", database.ErrInvalidInput)
+	}
+
+	changes, err := fileDB.ReloadFromWithChanges(source)
+	if err != nil {
+		return err
 	}
 
+	details := "reloaded from " + source
+	for _, change := range changes {
+		switch {
+		case change.Removed != nil:
+			s.recordAudit(ctx, AuditEntry{
+				Action:     AuditActionDelete,
+				ServerName: change.Removed.Name,
+				Version:    change.Removed.Version,
+				Details:    details,
+			})
+		case change.Created:
+			s.audit(ctx, AuditActionCreate, change.Server, details)
+		default:
+			s.audit(ctx, AuditActionUpdate, change.Server, details)
+		}
+	}
 	return nil
 }
+
+// ImportJSONFile imports a JSON file database file holding data from source into the database with
+// database.UpsertFromJSONFile, and records an audit entry for each version the import creates or changes
+func (s *registryServiceImpl) ImportJSONFile(ctx context.Context, filePath, source string, opts database.UpsertOptions) (int, error) {
+	details := "imported from " + source
+	opts.OnChange = func(server *apiv0.ServerResponse, created bool) {
+		if created {
+			s.audit(ctx, AuditActionCreate, server, details)
+		} else {
+			s.audit(ctx, AuditActionUpdate, server, details)
+		}
+	}
+	return database.UpsertFromJSONFile(ctx, s.db, filePath, opts)
+}
+
+// NewCatalogFilter builds the filter of a published catalog: servers with the given status, or any status if
+// it is empty, optionally limited to latest versions
+func NewCatalogFilter(status string, latestOnly bool) *database.ServerFilter {
+	filter := &database.ServerFilter{}
+	if status != "" {
+		filter.Status = &status
+	}
+	if latestOnly {
+		filter.IsLatest = &latestOnly
+	}
+	return filter
+}
+
+// catalogPageSize is how many servers PublishCatalog reads at a time
+const catalogPageSize = 100
+
+// PublishCatalog uploads the servers matching filter to s3://bucket/key in the JSON file database format,
+// returning the number of servers published
+func (s *registryServiceImpl) PublishCatalog(ctx context.Context, filter *database.ServerFilter, bucket, key string) (int, error) {
+	servers, err := s.listAllServers(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := database.MarshalJSONFile(servers)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize catalog: %w", err)
+	}
+
+	uploader, err := s.getS3Downloader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := uploader.UploadObject(ctx, bucket, key, body, "application/json"); err != nil {
+		return 0, err
+	}
+
+	return len(servers), nil
+}
+
+// listAllServers reads every server matching filter, a page at a time
+func (s *registryServiceImpl) listAllServers(ctx context.Context, filter *database.ServerFilter) ([]*apiv0.ServerResponse, error) {
+	var servers []*apiv0.ServerResponse
+	cursor := ""
+	for {
+		page, nextCursor, err := s.db.ListServers(ctx, nil, filter, cursor, catalogPageSize)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, page...)
+		if nextCursor == "" {
+			return servers, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// CreateSnapshot stores an immutable copy of every server version, as of one point in time, in the JSON file
+// database format
+func (s *registryServiceImpl) CreateSnapshot(ctx context.Context, id string) (*database.Snapshot, int, error) {
+	store, ok := s.db.(database.SnapshotStore)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: the database does not support snapshots", database.ErrInvalidInput)
+	}
+
+	servers, err := store.SnapshotServers(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	content, err := database.MarshalJSONFile(servers)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+
+	snapshot, err := database.NewSnapshot(id, content, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := store.CreateSnapshot(ctx, snapshot); err != nil {
+		return nil, 0, err
+	}
+	return snapshot, len(servers), nil
+}
+
+// GetSnapshot retrieves a snapshot by ID
+func (s *registryServiceImpl) GetSnapshot(ctx context.Context, id string) (*database.Snapshot, error) {
+	store, ok := s.db.(database.SnapshotStore)
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return store.GetSnapshot(ctx, id)
+}
+
+// CheckS3 confirms the configured S3 data file is reachable with the current credentials
+func (s *registryServiceImpl) CheckS3(ctx context.Context) error {
+	if s.cfg.S3URL == "" {
+		return fmt.Errorf("%w: MCP_REGISTRY_S3_URL is not configured", database.ErrInvalidInput)
+	}
+	bucket, key, err := aws.ParseS3URL(s.cfg.S3URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", database.ErrInvalidInput, err)
+	}
+
+	downloader, err := s.getS3Downloader(ctx)
+	if err != nil {
+		return err
+	}
+	return downloader.CheckObject(ctx, bucket, key)
+}
+
+// getS3Downloader returns the S3 downloader, creating it from the default AWS config on first use
+func (s *registryServiceImpl) getS3Downloader(ctx context.Context) (aws.S3Client, error) {
+	s.s3DownloaderMu.Lock()
+	defer s.s3DownloaderMu.Unlock()
+
+	if s.s3Downloader == nil {
+		downloader, err := aws.NewS3Downloader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 downloader: %w", err)
+		}
+		s.s3Downloader = downloader
+	}
+	return s.s3Downloader, nil
+}
+
+// isKnownStatus reports whether status is one of the supported server lifecycle statuses
+func isKnownStatus(status model.Status) bool {
+	switch status {
+	case model.StatusActive, model.StatusDeprecated, model.StatusDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateUpdateRequest validates an update request with optional registry validation skipping
+func (s *registryServiceImpl) validateUpdateRequest(ctx context.Context, req apiv0.ServerJSON, skipRegistryValidation bool) error {
+	// Always validate the server JSON structure; skip registry validation if requested (for deleted servers)
+	return validators.ValidateServer(ctx, req, !skipRegistryValidation && s.cfg.EnableRegistryValidation)
+}
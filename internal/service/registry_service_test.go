@@ -3,11 +3,16 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/aws"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
@@ -786,7 +791,1011 @@ func TestVersionComparison(t *testing.T) {
 	assert.Equal(t, 1, latestCount, "Exactly one version should be marked as latest")
 }
 
+func TestCreateServer_IdenticalRepublish(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/republished-server",
+		Description: "Republished server",
+		Version:     "1.0.0",
+	}
+	created, err := service.CreateServer(ctx, server)
+	require.NoError(t, err)
+
+	// The stored version comes back, marked as already published rather than created
+	republished, err := service.CreateServer(ctx, server)
+	require.ErrorIs(t, err, database.ErrAlreadyPublished)
+	require.NotNil(t, republished)
+	assert.Equal(t, created.Meta.Official.PublishedAt, republished.Meta.Official.PublishedAt)
+
+	// Conflicting content for the same version is still rejected
+	changed := *server
+	changed.Description = "Changed"
+	_, err = service.CreateServer(ctx, &changed)
+	require.ErrorIs(t, err, database.ErrInvalidVersion)
+}
+
+func TestCreateServer_MonotonicVersions(t *testing.T) {
+	ctx := context.Background()
+
+	publish := func(service RegistryService, version string) error {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/monotonic-server",
+			Description: "Version " + version,
+			Version:     version,
+		})
+		return err
+	}
+
+	t.Run("backfill allowed by default", func(t *testing.T) {
+		service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+		require.NoError(t, publish(service, "2.0.0"))
+		require.NoError(t, publish(service, "1.0.0"))
+
+		latest, err := service.GetServerByName(ctx, "com.example/monotonic-server")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", latest.Server.Version)
+	})
+
+	t.Run("enforced", func(t *testing.T) {
+		service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+			EnableRegistryValidation: false,
+			EnforceMonotonicVersions: true,
+		})
+
+		require.NoError(t, publish(service, "2.0.0"))
+
+		err := publish(service, "1.0.0")
+		require.ErrorIs(t, err, database.ErrVersionNotNewer)
+		assert.Contains(t, err.Error(), "1.0.0 is not newer than 2.0.0")
+		_, err = service.GetServerByNameAndVersion(ctx, "com.example/monotonic-server", "1.0.0")
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// A prerelease of the latest version sorts below it, while an identical republish is still accepted
+		require.ErrorIs(t, publish(service, "2.0.0-rc.1"), database.ErrVersionNotNewer)
+		require.ErrorIs(t, publish(service, "2.0.0"), database.ErrAlreadyPublished)
+
+		require.NoError(t, publish(service, "2.0.1"))
+		latest, err := service.GetServerByName(ctx, "com.example/monotonic-server")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.1", latest.Server.Version)
+	})
+}
+
+func TestCreateServer_InitialStatus(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	publish := func(version string, meta *apiv0.ServerMeta) (*apiv0.ServerResponse, error) {
+		return service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/historical-server",
+			Description: "A server imported with its status",
+			Version:     version,
+			Meta:        meta,
+		})
+	}
+
+	t.Run("explicit deprecated status", func(t *testing.T) {
+		created, err := publish("1.0.0", &apiv0.ServerMeta{
+			PublisherProvided: map[string]interface{}{"tool": "importer"},
+			Official:          &apiv0.PublishExtensions{Status: model.StatusDeprecated},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeprecated, created.Meta.Official.Status)
+
+		stored, err := service.GetServerByNameAndVersion(ctx, "com.example/historical-server", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeprecated, stored.Meta.Official.Status)
+		// The requested status isn't kept in the server itself, but publisher metadata is
+		require.NotNil(t, stored.Server.Meta)
+		assert.Nil(t, stored.Server.Meta.Official)
+		assert.Equal(t, "importer", stored.Server.Meta.PublisherProvided["tool"])
+	})
+
+	t.Run("defaults to active", func(t *testing.T) {
+		created, err := publish("2.0.0", &apiv0.ServerMeta{Official: &apiv0.PublishExtensions{}})
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, created.Meta.Official.Status)
+		assert.Nil(t, created.Server.Meta)
+	})
+
+	t.Run("unknown status is rejected", func(t *testing.T) {
+		_, err := publish("3.0.0", &apiv0.ServerMeta{Official: &apiv0.PublishExtensions{Status: "retired"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown status "retired"`)
+		_, err = service.GetServerByNameAndVersion(ctx, "com.example/historical-server", "3.0.0")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestCreateServer_Provenance(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	publish := func(version string, provenance *apiv0.Provenance) (*apiv0.ServerResponse, error) {
+		server := &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/attested-server",
+			Description: "A server with signed attestations",
+			Version:     version,
+		}
+		if provenance != nil {
+			server.Meta = &apiv0.ServerMeta{Provenance: provenance}
+		}
+		return service.CreateServer(ctx, server)
+	}
+
+	t.Run("with provenance", func(t *testing.T) {
+		provenance := &apiv0.Provenance{Attestations: []apiv0.Attestation{
+			{Type: apiv0.AttestationTypeSigstoreBundle, URL: "https://example.com/releases/1.0.0/server.sigstore.json", SHA256: "fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce"},
+			{Type: apiv0.AttestationTypeInToto, URL: "https://example.com/releases/1.0.0/server.intoto.jsonl"},
+		}}
+		created, err := publish("1.0.0", provenance)
+		require.NoError(t, err)
+		require.NotNil(t, created.Server.Meta)
+		assert.Equal(t, provenance, created.Server.Meta.Provenance)
+
+		stored, err := service.GetServerByNameAndVersion(ctx, "com.example/attested-server", "1.0.0")
+		require.NoError(t, err)
+		require.NotNil(t, stored.Server.Meta)
+		assert.Equal(t, provenance, stored.Server.Meta.Provenance)
+	})
+
+	t.Run("without provenance", func(t *testing.T) {
+		created, err := publish("2.0.0", nil)
+		require.NoError(t, err)
+		assert.Nil(t, created.Server.Meta)
+	})
+
+	t.Run("with provenance and an initial status", func(t *testing.T) {
+		provenance := &apiv0.Provenance{Attestations: []apiv0.Attestation{
+			{Type: apiv0.AttestationTypeInToto, URL: "https://example.com/releases/3.0.0/server.intoto.jsonl"},
+		}}
+		created, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/attested-server",
+			Description: "A server with signed attestations",
+			Version:     "3.0.0",
+			Meta: &apiv0.ServerMeta{
+				Official:   &apiv0.PublishExtensions{Status: model.StatusDeprecated},
+				Provenance: provenance,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeprecated, created.Meta.Official.Status)
+		require.NotNil(t, created.Server.Meta, "taking the status must keep the provenance")
+		assert.Nil(t, created.Server.Meta.Official)
+		assert.Equal(t, provenance, created.Server.Meta.Provenance)
+
+		stored, err := service.GetServerByNameAndVersion(ctx, "com.example/attested-server", "3.0.0")
+		require.NoError(t, err)
+		require.NotNil(t, stored.Server.Meta)
+		assert.Equal(t, provenance, stored.Server.Meta.Provenance)
+	})
+
+	invalid := []struct {
+		name       string
+		provenance *apiv0.Provenance
+		wantErr    string
+	}{
+		{"no attestations", &apiv0.Provenance{}, "at least one attestation"},
+		{"unknown type", &apiv0.Provenance{Attestations: []apiv0.Attestation{{Type: "slsa", URL: "https://example.com/a.json"}}}, "unknown attestation type"},
+		{"plain http url", &apiv0.Provenance{Attestations: []apiv0.Attestation{{Type: apiv0.AttestationTypeInToto, URL: "http://example.com/a.json"}}}, "invalid attestation URL"},
+		{"relative url", &apiv0.Provenance{Attestations: []apiv0.Attestation{{Type: apiv0.AttestationTypeInToto, URL: "/a.json"}}}, "invalid attestation URL"},
+		{"malformed hash", &apiv0.Provenance{Attestations: []apiv0.Attestation{{Type: apiv0.AttestationTypeSigstoreBundle, URL: "https://example.com/a.json", SHA256: "sha256:abc"}}}, "invalid attestation hash"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := publish("3.0.0", tt.provenance)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCreateServer_Tags(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{EnableRegistryValidation: false, AllowedTags: []string{"database", "devtools", "ai"}}
+	service := NewRegistryService(database.NewTestJSONFileDB(t), cfg)
+
+	publish := func(version string, meta *apiv0.ServerMeta) (*apiv0.ServerResponse, error) {
+		return service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/tagged-server",
+			Description: "A server with tags",
+			Version:     version,
+			Meta:        meta,
+		})
+	}
+
+	t.Run("with tags", func(t *testing.T) {
+		created, err := publish("1.0.0", &apiv0.ServerMeta{Tags: []string{"database", "ai"}})
+		require.NoError(t, err)
+		require.NotNil(t, created.Server.Meta)
+		assert.Equal(t, []string{"database", "ai"}, created.Server.Meta.Tags)
+
+		stored, err := service.GetServerByNameAndVersion(ctx, "com.example/tagged-server", "1.0.0")
+		require.NoError(t, err)
+		require.NotNil(t, stored.Server.Meta)
+		assert.Equal(t, []string{"database", "ai"}, stored.Server.Meta.Tags)
+	})
+
+	t.Run("tags kept alongside an initial status", func(t *testing.T) {
+		created, err := publish("2.0.0", &apiv0.ServerMeta{
+			Tags:     []string{"devtools"},
+			Official: &apiv0.PublishExtensions{Status: model.StatusDeprecated},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, created.Server.Meta)
+		assert.Equal(t, []string{"devtools"}, created.Server.Meta.Tags)
+		assert.Nil(t, created.Server.Meta.Official)
+	})
+
+	invalid := []struct {
+		name    string
+		tags    []string
+		wantErr string
+	}{
+		{"not on the allowlist", []string{"games"}, "tag is not allowed"},
+		{"uppercase", []string{"Database"}, "invalid tag"},
+		{"empty", []string{""}, "invalid tag"},
+		{"duplicate", []string{"ai", "ai"}, "duplicate tag"},
+		{"too many", []string{"a1", "a2", "a3", "a4", "a5", "a6", "a7", "a8", "a9", "a10", "a11"}, "too many tags"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := publish("3.0.0", &apiv0.ServerMeta{Tags: tt.tags})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("any well-formed tag without an allowlist", func(t *testing.T) {
+		open := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+		created, err := open.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/freely-tagged",
+			Description: "A server with an unlisted tag",
+			Version:     "1.0.0",
+			Meta:        &apiv0.ServerMeta{Tags: []string{"vector-search"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vector-search"}, created.Server.Meta.Tags)
+	})
+}
+
+func TestCreateServer_NamespaceQuotas(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+		EnableRegistryValidation: false,
+		MaxVersionsPerServer:     3,
+		MaxVersionsPolicy:        MaxVersionsPolicyReject,
+		NamespaceQuotas: config.NamespaceQuotas{
+			"io.github.acme/":         {MaxVersionsPerServer: 1, MaxServers: 3},
+			"io.github.acme/partner-": {MaxVersionsPerServer: 2, MaxServers: 1},
+		},
+	})
+
+	publish := func(name, version string) error {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Quota test server",
+			Version:     version,
+		})
+		return err
+	}
+
+	// The partner prefix is the most specific match, so its version limit applies over the namespace's
+	require.NoError(t, publish("io.github.acme/partner-tool", "1.0.0"))
+	require.NoError(t, publish("io.github.acme/partner-tool", "2.0.0"))
+	err := publish("io.github.acme/partner-tool", "3.0.0")
+	require.ErrorIs(t, err, database.ErrMaxServersReached)
+	assert.Contains(t, err.Error(), "2 versions allowed")
+
+	// Other servers in the namespace get the namespace's limit
+	require.NoError(t, publish("io.github.acme/basic", "1.0.0"))
+	err = publish("io.github.acme/basic", "2.0.0")
+	require.ErrorIs(t, err, database.ErrMaxServersReached)
+	assert.Contains(t, err.Error(), "1 versions allowed")
+
+	// Servers outside every quota fall back to the global limit
+	for _, version := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+		require.NoError(t, publish("com.example/unlimited", version))
+	}
+	require.ErrorIs(t, publish("com.example/unlimited", "4.0.0"), database.ErrMaxServersReached)
+
+	// The partner prefix only holds one server
+	err = publish("io.github.acme/partner-other", "1.0.0")
+	require.ErrorIs(t, err, database.ErrMaxServersReached)
+	assert.Contains(t, err.Error(), "namespace io.github.acme/partner- allows 1 servers")
+
+	// The namespace counts every server under it, including the partner's
+	require.NoError(t, publish("io.github.acme/third", "1.0.0"))
+	err = publish("io.github.acme/fourth", "1.0.0")
+	require.ErrorIs(t, err, database.ErrMaxServersReached)
+	assert.Contains(t, err.Error(), "namespace io.github.acme/ allows 3 servers")
+}
+
+// slowCountDatabase pauses after counting servers, so concurrent publishes interleave between their capacity
+// checks and their writes
+type slowCountDatabase struct {
+	database.Database
+}
+
+func (d slowCountDatabase) ListServerNames(ctx context.Context, tx pgx.Tx, prefix *string, cursor string, limit int) ([]string, string, error) {
+	names, next, err := d.Database.ListServerNames(ctx, tx, prefix, cursor, limit)
+	time.Sleep(time.Millisecond)
+	return names, next, err
+}
+
+func (d slowCountDatabase) CountServerNames(ctx context.Context, tx pgx.Tx) (int, error) {
+	count, err := d.Database.CountServerNames(ctx, tx)
+	time.Sleep(time.Millisecond)
+	return count, err
+}
+
+// TestCreateServer_NamespaceQuotaConcurrent tests that concurrent first publishes of different servers in a
+// namespace can't together exceed its server limit
+func TestCreateServer_NamespaceQuotaConcurrent(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(slowCountDatabase{database.NewTestJSONFileDB(t)}, &config.Config{
+		EnableRegistryValidation: false,
+		NamespaceQuotas: config.NamespaceQuotas{
+			"io.github.acme/": {MaxServers: 2},
+		},
+	})
+
+	const concurrency = 20
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.CreateServer(ctx, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        fmt.Sprintf("io.github.acme/server-%d", i),
+				Description: "Quota race test server",
+				Version:     "1.0.0",
+			})
+		}()
+	}
+	wg.Wait()
+
+	published := 0
+	for _, err := range errs {
+		if err == nil {
+			published++
+			continue
+		}
+		require.ErrorIs(t, err, database.ErrMaxServersReached)
+	}
+	assert.Equal(t, 2, published)
+}
+
+// TestCreateServer_MaxServersConcurrent tests that concurrent first publishes can't together exceed the
+// registry's server limit
+func TestCreateServer_MaxServersConcurrent(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(slowCountDatabase{database.NewTestJSONFileDB(t)}, &config.Config{
+		EnableRegistryValidation: false,
+		MaxServers:               2,
+	})
+
+	const concurrency = 20
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.CreateServer(ctx, &apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        fmt.Sprintf("com.example/server-%d", i),
+				Description: "Registry limit race test server",
+				Version:     "1.0.0",
+			})
+		}()
+	}
+	wg.Wait()
+
+	published := 0
+	for _, err := range errs {
+		if err == nil {
+			published++
+			continue
+		}
+		require.ErrorIs(t, err, database.ErrRegistryFull)
+	}
+	assert.Equal(t, 2, published)
+}
+
+// TestTransferServer_MaxServers tests that a transfer leaving a tombstone counts against the registry's server
+// limit, while one that only renames doesn't
+func TestTransferServer_MaxServers(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+		EnableRegistryValidation: false,
+		MaxServers:               2,
+	})
+
+	for _, name := range []string{"com.example/first", "com.example/second"} {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Registry limit transfer test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := service.TransferServer(ctx, "com.example/first", "org.example/first", true)
+	require.ErrorIs(t, err, database.ErrRegistryFull)
+	_, err = service.GetServerByName(ctx, "org.example/first")
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	transferred, err := service.TransferServer(ctx, "com.example/first", "org.example/first", false)
+	require.NoError(t, err)
+	assert.Len(t, transferred, 1)
+}
+
+func TestCreateServer_MaxVersionsPerServer(t *testing.T) {
+	ctx := context.Background()
+
+	publish := func(service RegistryService, version string) error {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/capped-server",
+			Description: "Version " + version,
+			Version:     version,
+		})
+		return err
+	}
+	versions := func(service RegistryService) []string {
+		servers, err := service.GetAllVersionsByServerName(ctx, "com.example/capped-server")
+		require.NoError(t, err)
+		var result []string
+		for _, server := range servers {
+			result = append(result, server.Server.Version)
+		}
+		return result
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+			EnableRegistryValidation: false,
+			MaxVersionsPerServer:     2,
+			MaxVersionsPolicy:        MaxVersionsPolicyReject,
+		})
+
+		require.NoError(t, publish(service, "1.0.0"))
+		require.NoError(t, publish(service, "2.0.0"))
+
+		err := publish(service, "3.0.0")
+		require.ErrorIs(t, err, database.ErrMaxServersReached)
+		assert.ElementsMatch(t, []string{"1.0.0", "2.0.0"}, versions(service))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+			EnableRegistryValidation: false,
+			MaxVersionsPerServer:     2,
+			MaxVersionsPolicy:        MaxVersionsPolicyPrune,
+		})
+
+		require.NoError(t, publish(service, "1.0.0"))
+		require.NoError(t, publish(service, "2.0.0"))
+
+		// The earliest published version makes room for the new one
+		require.NoError(t, publish(service, "3.0.0"))
+		assert.ElementsMatch(t, []string{"2.0.0", "3.0.0"}, versions(service))
+
+		// A backfilled version doesn't become the latest, so the latest is kept and the older version goes
+		require.NoError(t, publish(service, "2.5.0"))
+		assert.ElementsMatch(t, []string{"2.5.0", "3.0.0"}, versions(service))
+		latest, err := service.GetServerByName(ctx, "com.example/capped-server")
+		require.NoError(t, err)
+		assert.Equal(t, "3.0.0", latest.Server.Version)
+	})
+
+	t.Run("prune with only the latest to keep", func(t *testing.T) {
+		service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+			EnableRegistryValidation: false,
+			MaxVersionsPerServer:     1,
+			MaxVersionsPolicy:        MaxVersionsPolicyPrune,
+		})
+
+		require.NoError(t, publish(service, "2.0.0"))
+		// A new latest replaces the old one
+		require.NoError(t, publish(service, "3.0.0"))
+		assert.Equal(t, []string{"3.0.0"}, versions(service))
+
+		// A backfill would have to evict the latest, so it is rejected
+		require.ErrorIs(t, publish(service, "1.0.0"), database.ErrMaxServersReached)
+		assert.Equal(t, []string{"3.0.0"}, versions(service))
+	})
+
+	t.Run("prune is undone when publishing fails", func(t *testing.T) {
+		db := database.NewTestJSONFileDB(t)
+		cfg := &config.Config{
+			EnableRegistryValidation: false,
+			MaxVersionsPerServer:     2,
+			MaxVersionsPolicy:        MaxVersionsPolicyPrune,
+		}
+		service := NewRegistryService(db, cfg)
+		require.NoError(t, publish(service, "1.0.0"))
+		require.NoError(t, publish(service, "2.0.0"))
+
+		require.ErrorIs(t, publish(NewRegistryService(failingCreateDatabase{db}, cfg), "3.0.0"), database.ErrDatabase)
+		assert.ElementsMatch(t, []string{"1.0.0", "2.0.0"}, versions(service))
+		latest, err := service.GetServerByName(ctx, "com.example/capped-server")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", latest.Server.Version)
+	})
+}
+
+// failingCreateDatabase fails every CreateServer call, after a publish has made room for the new version
+type failingCreateDatabase struct {
+	database.Database
+}
+
+func (d failingCreateDatabase) CreateServer(context.Context, pgx.Tx, *apiv0.ServerJSON, *apiv0.RegistryExtensions) (*apiv0.ServerResponse, error) {
+	return nil, database.ErrDatabase
+}
+
+func TestCreateServer_KeepVersions(t *testing.T) {
+	ctx := context.Background()
+	sink := &recordingAuditSink{}
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{
+		EnableRegistryValidation: false,
+		KeepVersions:             2,
+	}, WithAuditSink(sink))
+
+	publish := func(version string) {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/kept-server",
+			Description: "Version " + version,
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+	versions := func() []string {
+		servers, err := service.GetAllVersionsByServerName(ctx, "com.example/kept-server")
+		require.NoError(t, err)
+		var result []string
+		for _, server := range servers {
+			result = append(result, server.Server.Version)
+		}
+		return result
+	}
+
+	publish("1.0.0")
+	publish("2.0.0")
+	assert.ElementsMatch(t, []string{"1.0.0", "2.0.0"}, versions())
+
+	// Each new latest version retires the oldest one beyond the kept two
+	publish("3.0.0")
+	assert.ElementsMatch(t, []string{"2.0.0", "3.0.0"}, versions())
+	publish("4.0.0")
+	assert.ElementsMatch(t, []string{"3.0.0", "4.0.0"}, versions())
+
+	var retired []string
+	for _, entry := range sink.take() {
+		if entry.Action == AuditActionDelete {
+			retired = append(retired, entry.Version)
+			assert.Equal(t, "pruned: beyond the newest 2 versions kept", entry.Details)
+		}
+	}
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, retired)
+
+	// A backfilled version doesn't become the latest, so nothing is retired
+	publish("3.5.0")
+	assert.ElementsMatch(t, []string{"3.0.0", "3.5.0", "4.0.0"}, versions())
+	latest, err := service.GetServerByName(ctx, "com.example/kept-server")
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.0", latest.Server.Version)
+
+	// The next latest prunes back down to the newest two by publish time
+	publish("5.0.0")
+	assert.ElementsMatch(t, []string{"3.5.0", "5.0.0"}, versions())
+}
+
+// failingDeleteDatabase fails every DeleteServerVersion call, after a publish has created the new version
+type failingDeleteDatabase struct {
+	database.Database
+}
+
+func (d failingDeleteDatabase) DeleteServerVersion(context.Context, pgx.Tx, string, string) error {
+	return database.ErrDatabase
+}
+
+// TestCreateServer_KeepVersionsRollback tests that a publish whose retiring of old versions fails is undone
+// as a whole
+func TestCreateServer_KeepVersionsRollback(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	cfg := &config.Config{EnableRegistryValidation: false, KeepVersions: 2}
+
+	publish := func(service RegistryService, version string) error {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/kept-server",
+			Description: "Version " + version,
+			Version:     version,
+		})
+		return err
+	}
+	service := NewRegistryService(db, cfg)
+	require.NoError(t, publish(service, "1.0.0"))
+	require.NoError(t, publish(service, "2.0.0"))
+
+	require.ErrorIs(t, publish(NewRegistryService(failingDeleteDatabase{db}, cfg), "3.0.0"), database.ErrDatabase)
+
+	versions, err := service.GetAllVersionsByServerName(ctx, "com.example/kept-server")
+	require.NoError(t, err)
+	var kept []string
+	for _, version := range versions {
+		kept = append(kept, version.Server.Version)
+	}
+	assert.ElementsMatch(t, []string{"1.0.0", "2.0.0"}, kept)
+	latest, err := service.GetServerByName(ctx, "com.example/kept-server")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", latest.Server.Version)
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestCreateServer_DeduplicatesPackagesAndRemotes(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false})
+
+	npmPackage := model.Package{
+		RegistryType: model.RegistryTypeNPM,
+		Identifier:   "@example/dedupe-server",
+		Version:      "1.0.0",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+	pypiPackage := model.Package{
+		RegistryType: model.RegistryTypePyPI,
+		Identifier:   "dedupe-server",
+		Version:      "1.0.0",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+	httpRemote := model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://example.com/mcp"}
+	sseRemote := model.Transport{Type: model.TransportTypeSSE, URL: "https://example.com/sse"}
+
+	req := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/dedupe-server",
+		Description: "A server with repeated entries",
+		Version:     "1.0.0",
+		Packages:    []model.Package{npmPackage, pypiPackage, npmPackage, pypiPackage},
+		Remotes:     []model.Transport{httpRemote, httpRemote, sseRemote, httpRemote},
+	}
+
+	created, err := service.CreateServer(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Package{npmPackage, pypiPackage}, created.Server.Packages)
+	assert.Equal(t, []model.Transport{httpRemote, sseRemote}, created.Server.Remotes)
+
+	// The stored record is clean, while the caller's request is left untouched
+	stored, err := service.GetServerByNameAndVersion(ctx, req.Name, req.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Package{npmPackage, pypiPackage}, stored.Server.Packages)
+	assert.Equal(t, []model.Transport{httpRemote, sseRemote}, stored.Server.Remotes)
+	assert.Len(t, req.Packages, 4)
+	assert.Len(t, req.Remotes, 4)
+
+	t.Run("packages differing only by version are kept", func(t *testing.T) {
+		newerPackage := npmPackage
+		newerPackage.Version = "2.0.0"
+
+		updated, err := service.UpdateServer(ctx, req.Name, req.Version, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        req.Name,
+			Description: req.Description,
+			Version:     req.Version,
+			Packages:    []model.Package{npmPackage, newerPackage, npmPackage},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Package{npmPackage, newerPackage}, updated.Server.Packages)
+	})
+}
+
+// fakeS3Downloader serves fixed content for any S3 object and records uploads by s3:// URI
+type fakeS3Downloader struct {
+	content []byte
+	uploads map[string][]byte
+}
+
+func (d *fakeS3Downloader) DownloadFile(_ context.Context, _, _, localPath string) (aws.ObjectVersion, error) {
+	return aws.ObjectVersion{}, os.WriteFile(localPath, d.content, 0600)
+}
+
+func (d *fakeS3Downloader) CheckObject(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (d *fakeS3Downloader) UploadObject(_ context.Context, bucket, key string, body []byte, _ string) error {
+	if d.uploads == nil {
+		d.uploads = make(map[string][]byte)
+	}
+	d.uploads["s3://"+bucket+"/"+key] = body
+	return nil
+}
+
+func TestReloadFromS3(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "registry.json")
+	db, err := database.NewJSONFileDB(ctx, filePath)
+	require.NoError(t, err)
+
+	service := NewRegistryService(db, &config.Config{S3URL: "s3://registry-bucket/registry.json"}).(*registryServiceImpl)
+
+	_, err = service.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/local-server",
+		Description: "A server published before the reload",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	record := func(name string) map[string]any {
+		return map[string]any{
+			"server_name":  name,
+			"version":      "1.0.0",
+			"status":       string(model.StatusActive),
+			"published_at": time.Now(),
+			"updated_at":   time.Now(),
+			"is_latest":    true,
+			"value": apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        name,
+				Description: "A server from S3",
+				Version:     "1.0.0",
+			},
+		}
+	}
+	goodPayload, err := json.Marshal(map[string]any{
+		"servers": []map[string]any{record("com.example/s3-alpha"), record("com.example/s3-beta")},
+	})
+	require.NoError(t, err)
+
+	t.Run("bad payload leaves current data in place", func(t *testing.T) {
+		service.s3Downloader = &fakeS3Downloader{content: []byte(`{"servers": [{"server_name": `)}
+
+		_, err := service.ReloadFromS3(ctx, "")
+		require.ErrorIs(t, err, aws.ErrInvalidDownload)
+
+		_, err = service.GetServerByName(ctx, "com.example/local-server")
+		require.NoError(t, err)
+		downloads, err := filepath.Glob(filePath + ".download*")
+		require.NoError(t, err)
+		assert.Empty(t, downloads, "rejected download should be cleaned up")
+	})
+
+	t.Run("good payload is swapped in", func(t *testing.T) {
+		service.s3Downloader = &fakeS3Downloader{content: goodPayload}
+
+		records, err := service.ReloadFromS3(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, 3, records)
+
+		server, err := service.GetServerByName(ctx, "com.example/s3-beta")
+		require.NoError(t, err)
+		assert.Equal(t, "A server from S3", server.Server.Description)
+
+		// The local publish isn't in the S3 file yet, so it is kept rather than lost
+		_, err = service.GetServerByName(ctx, "com.example/local-server")
+		require.NoError(t, err)
+
+		stored, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(goodPayload), string(stored))
+	})
+
+	t.Run("invalid S3 URL is rejected", func(t *testing.T) {
+		_, err := service.ReloadFromS3(ctx, "ftp://registry-bucket/registry.json")
+		require.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestReconcileLatest(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	service := NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	// Seed the database directly, bypassing the publish logic that keeps latest flags consistent
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := func(name, version string, isLatest bool) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Reconcile test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: published,
+			UpdatedAt:   published,
+			IsLatest:    isLatest,
+		})
+		require.NoError(t, err)
+	}
+	seed("com.example/double-latest", "1.0.0", true)
+	seed("com.example/double-latest", "1.5.0", false)
+	seed("com.example/double-latest", "2.0.0", true)
+	seed("com.example/no-latest", "1.0.0", false)
+	seed("com.example/no-latest", "1.1.0", false)
+	seed("com.example/consistent", "1.0.0", false)
+	seed("com.example/consistent", "2.0.0", true)
+
+	corrected, err := service.ReconcileLatest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, corrected)
+
+	expectedLatest := map[string]string{
+		"com.example/double-latest": "2.0.0",
+		"com.example/no-latest":     "1.1.0",
+		"com.example/consistent":    "2.0.0",
+	}
+	for name, expected := range expectedLatest {
+		versions, err := service.GetAllVersionsByServerName(ctx, name)
+		require.NoError(t, err)
+
+		var latest []string
+		for _, version := range versions {
+			if version.Meta.Official.IsLatest {
+				latest = append(latest, version.Server.Version)
+			}
+		}
+		assert.Equal(t, []string{expected}, latest, name)
+	}
+
+	t.Run("consistent data is left alone", func(t *testing.T) {
+		corrected, err := service.ReconcileLatest(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, corrected)
+	})
+
+	t.Run("pinned latest versions are kept until a newer one is published", func(t *testing.T) {
+		pinned, err := service.SetLatestVersion(ctx, "com.example/double-latest", "1.5.0")
+		require.NoError(t, err)
+		assert.True(t, pinned.Meta.Official.LatestPinned)
+
+		corrected, err := service.ReconcileLatest(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, corrected)
+		latest, err := service.GetServerByName(ctx, "com.example/double-latest")
+		require.NoError(t, err)
+		assert.Equal(t, "1.5.0", latest.Server.Version)
+
+		_, err = service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/double-latest",
+			Description: "Reconcile test server",
+			Version:     "3.0.0",
+		})
+		require.NoError(t, err)
+		previous, err := service.GetServerByNameAndVersion(ctx, "com.example/double-latest", "1.5.0")
+		require.NoError(t, err)
+		assert.False(t, previous.Meta.Official.IsLatest)
+		assert.False(t, previous.Meta.Official.LatestPinned)
+	})
+}
+
+func TestPublishCatalog(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewTestJSONFileDB(t), &config.Config{EnableRegistryValidation: false}).(*registryServiceImpl)
+	uploader := &fakeS3Downloader{}
+	service.s3Downloader = uploader
+
+	publish := func(name, version string) {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Catalog test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+	publish("com.example/active", "1.0.0")
+	publish("com.example/active", "2.0.0")
+	publish("com.example/retired", "1.0.0")
+	_, err := service.SetServerStatus(ctx, "com.example/retired", "1.0.0", model.StatusDeprecated, "")
+	require.NoError(t, err)
+
+	// catalogEntries loads an uploaded catalog as a JSON file database and lists its name@version entries
+	catalogEntries := func(t *testing.T, body []byte) []string {
+		t.Helper()
+		filePath := filepath.Join(t.TempDir(), "catalog.json")
+		require.NoError(t, os.WriteFile(filePath, body, 0600))
+		require.NoError(t, database.ValidateJSONFile(filePath))
+		catalog, err := database.NewJSONFileDB(ctx, filePath)
+		require.NoError(t, err)
+		servers, _, err := catalog.ListServers(ctx, nil, nil, "", 100)
+		require.NoError(t, err)
+
+		var entries []string
+		for _, server := range servers {
+			assert.Equal(t, model.StatusActive, server.Meta.Official.Status)
+			entries = append(entries, server.Server.Name+"@"+server.Server.Version)
+		}
+		return entries
+	}
+
+	published, err := service.PublishCatalog(ctx, NewCatalogFilter(string(model.StatusActive), false), "catalog-bucket", "catalog.json")
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"com.example/active@1.0.0", "com.example/active@2.0.0"},
+		catalogEntries(t, uploader.uploads["s3://catalog-bucket/catalog.json"]))
+
+	t.Run("latest only", func(t *testing.T) {
+		published, err := service.PublishCatalog(ctx, NewCatalogFilter(string(model.StatusActive), true), "catalog-bucket", "latest.json")
+		require.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []string{"com.example/active@2.0.0"}, catalogEntries(t, uploader.uploads["s3://catalog-bucket/latest.json"]))
+	})
+}
+
+// failingDeprecateDatabase fails every DeprecateServer call, the last step of a transfer that leaves a tombstone
+type failingDeprecateDatabase struct {
+	database.Database
+}
+
+func (d failingDeprecateDatabase) DeprecateServer(context.Context, pgx.Tx, string, string, string) (*apiv0.ServerResponse, error) {
+	return nil, database.ErrDatabase
+}
+
+// TestTransferServer_Atomic tests that a transfer failing partway leaves the server where it was, and that a
+// tombstone doesn't keep the remotes that moved with the server
+func TestTransferServer_Atomic(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestJSONFileDB(t)
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/transferred",
+		Description: "Transfer test server",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://mcp.example.com/transferred"}},
+	}
+	_, err := NewRegistryService(db, &config.Config{}).CreateServer(ctx, server)
+	require.NoError(t, err)
+
+	t.Run("failure rolls back", func(t *testing.T) {
+		service := NewRegistryService(failingDeprecateDatabase{db}, &config.Config{})
+		_, err := service.TransferServer(ctx, server.Name, "org.example/transferred", true)
+		require.ErrorIs(t, err, database.ErrDatabase)
+
+		versions, err := service.GetAllVersionsByServerName(ctx, server.Name)
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		assert.Equal(t, model.StatusActive, versions[0].Meta.Official.Status)
+		assert.True(t, versions[0].Meta.Official.IsLatest)
+		_, err = service.GetServerByName(ctx, "org.example/transferred")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("tombstone leaves the remotes to the transferred server", func(t *testing.T) {
+		service := NewRegistryService(db, &config.Config{})
+		_, err := service.TransferServer(ctx, server.Name, "org.example/transferred", true)
+		require.NoError(t, err)
+
+		tombstone, err := service.GetServerByName(ctx, server.Name)
+		require.NoError(t, err)
+		assert.Empty(t, tombstone.Server.Remotes)
+		assert.Equal(t, "org.example/transferred", tombstone.Meta.Official.ReplacedBy)
+		transferred, err := service.GetServerByName(ctx, "org.example/transferred")
+		require.NoError(t, err)
+		assert.Equal(t, server.Remotes, transferred.Server.Remotes)
+	})
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// revalidatePageSize is how many server versions RevalidateServers reads from the database at a time
+const revalidatePageSize = 100
+
+// ValidationIssue is a stored server version that fails the current publish validation
+type ValidationIssue struct {
+	ServerName string                  `json:"serverName" doc:"Name of the server" example:"com.example/my-server"`
+	Version    string                  `json:"version" doc:"Version of the server" example:"1.0.0"`
+	Errors     []validators.FieldError `json:"errors" doc:"Every validation failure found in the stored server.json"`
+}
+
+// RevalidationSummary counts the server versions checked by RevalidateServers
+type RevalidationSummary struct {
+	Checked int `json:"checked" doc:"Number of server versions validated" example:"1234"`
+	Invalid int `json:"invalid" doc:"Number of server versions that failed validation" example:"2"`
+}
+
+// RevalidateServers implements RegistryService.RevalidateServers. Deleted versions are skipped, as they
+// are no longer served.
+func (s *registryServiceImpl) RevalidateServers(ctx context.Context, report func(ValidationIssue) error) (RevalidationSummary, error) {
+	var summary RevalidationSummary
+	cursor := ""
+	for {
+		page, nextCursor, err := s.db.ListServers(ctx, nil, nil, cursor, revalidatePageSize)
+		if err != nil {
+			return summary, err
+		}
+
+		for _, server := range page {
+			if server.Meta.Official != nil && server.Meta.Official.Status == model.StatusDeleted {
+				continue
+			}
+
+			summary.Checked++
+			fieldErrs := s.validationErrors(ctx, server)
+			// A cancelled request would otherwise fail registry validation of every remaining server
+			if ctx.Err() != nil {
+				return summary, ctx.Err()
+			}
+			if fieldErrs == nil {
+				continue
+			}
+			summary.Invalid++
+			if err := report(ValidationIssue{ServerName: server.Server.Name, Version: server.Server.Version, Errors: fieldErrs}); err != nil {
+				return summary, err
+			}
+		}
+
+		if nextCursor == "" {
+			return summary, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// validationErrors runs the publish validation pipeline on a stored server, returning its failures or nil if
+// it passes
+func (s *registryServiceImpl) validationErrors(ctx context.Context, server *apiv0.ServerResponse) []validators.FieldError {
+	_, err := s.ValidateServer(ctx, &server.Server)
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validators.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs
+	}
+	return []validators.FieldError{{Message: err.Error()}}
+}
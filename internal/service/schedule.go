@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RunPeriodically calls job every interval until ctx is cancelled. Failures are logged and the job
+// runs again at the next tick, so a transient error doesn't stop the schedule.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Printf("Scheduled %s failed: %v", name, err)
+			}
+		}
+	}
+}
+
+// Schedule decides when a scheduled job runs next
+type Schedule interface {
+	// Next returns the first time after t that the job should run
+	Next(t time.Time) time.Time
+}
+
+// Interval is a Schedule that runs a job at a fixed interval
+type Interval time.Duration
+
+// Next implements Schedule.Next
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// Daily is a Schedule that runs a job once a day at a time of day in UTC
+type Daily struct {
+	Hour   int
+	Minute int
+}
+
+// Next implements Schedule.Next
+func (d Daily) Next(t time.Time) time.Time {
+	t = t.UTC()
+	next := time.Date(t.Year(), t.Month(), t.Day(), d.Hour, d.Minute, 0, 0, time.UTC)
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// ParseSchedule parses a schedule given as an interval such as "6h", or as a daily UTC time of day
+// such as "03:30". An empty string returns a nil Schedule.
+func ParseSchedule(s string) (Schedule, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	if at, err := time.Parse("15:04", s); err == nil {
+		return Daily{Hour: at.Hour(), Minute: at.Minute()}, nil
+	}
+
+	interval, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: expected an interval such as 6h or a UTC time of day such as 03:30", s)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid schedule %q: interval must be positive", s)
+	}
+	return Interval(interval), nil
+}
+
+// RunOnSchedule calls job at each time schedule gives until ctx is cancelled. Like RunPeriodically,
+// failures are logged and don't stop the schedule.
+func RunOnSchedule(ctx context.Context, name string, schedule Schedule, job func(ctx context.Context) error) {
+	runOnSchedule(ctx, name, schedule, job, time.Now, time.After)
+}
+
+// runOnSchedule is RunOnSchedule with the clock and timer injected, so tests can drive the ticks
+func runOnSchedule(ctx context.Context, name string, schedule Schedule, job func(ctx context.Context) error,
+	now func() time.Time, after func(time.Duration) <-chan time.Time) {
+	for {
+		current := now()
+		wait := schedule.Next(current).Sub(current)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-after(wait):
+			if err := job(ctx); err != nil {
+				log.Printf("Scheduled %s failed: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("")
+	require.NoError(t, err)
+	assert.Nil(t, schedule)
+
+	schedule, err = ParseSchedule("6h")
+	require.NoError(t, err)
+	assert.Equal(t, Interval(6*time.Hour), schedule)
+
+	schedule, err = ParseSchedule("03:30")
+	require.NoError(t, err)
+	assert.Equal(t, Daily{Hour: 3, Minute: 30}, schedule)
+
+	for _, invalid := range []string{"often", "-1h", "0s", "25:00"} {
+		_, err := ParseSchedule(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDailyNext(t *testing.T) {
+	daily := Daily{Hour: 3, Minute: 30}
+
+	before := time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 1, 3, 30, 0, 0, time.UTC), daily.Next(before))
+
+	// A run at exactly the scheduled time moves on to the next day
+	at := time.Date(2025, 6, 1, 3, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 2, 3, 30, 0, 0, time.UTC), daily.Next(at))
+}
+
+func TestScheduledCompaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := database.NewTestJSONFileDB(t)
+	registry := NewRegistryService(db, &config.Config{
+		CompactDeletedRetention: 24 * time.Hour,
+		CompactMaxVersions:      2,
+	})
+
+	old := time.Now().Add(-48 * time.Hour)
+	seed := func(version string, status model.Status, isLatest bool) {
+		old = old.Add(time.Minute) // each version is published after the previous one
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/compacted",
+			Description: "Scheduled compaction test server",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{Status: status, PublishedAt: old, UpdatedAt: old, IsLatest: isLatest})
+		require.NoError(t, err)
+	}
+	seed("1.0.0", model.StatusDeleted, false)
+	seed("2.0.0", model.StatusActive, false)
+	seed("3.0.0", model.StatusActive, false)
+	seed("4.0.0", model.StatusActive, true)
+
+	// Drive the schedule by hand: each value sent on tick stands for the timer firing
+	tick := make(chan time.Time)
+	var waits []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		return tick
+	}
+	now := func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	ran := make(chan error)
+	compact := NewCompactionJob(registry, nil)
+	job := func(ctx context.Context) error {
+		err := compact(ctx)
+		ran <- err
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		runOnSchedule(ctx, "compaction", Interval(time.Hour), job, now, after)
+		close(done)
+	}()
+
+	tick <- time.Time{}
+	require.NoError(t, <-ran)
+
+	versions, err := registry.GetAllVersionsByServerName(ctx, "com.example/compacted")
+	require.NoError(t, err)
+	var remaining []string
+	for _, version := range versions {
+		remaining = append(remaining, version.Server.Version)
+	}
+	// 1.0.0 was deleted past the retention, and 2.0.0 is beyond the two versions kept
+	assert.ElementsMatch(t, []string{"3.0.0", "4.0.0"}, remaining)
+
+	cancel()
+	<-done
+	assert.Equal(t, []time.Duration{time.Hour, time.Hour}, waits)
+}
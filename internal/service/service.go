@@ -2,23 +2,84 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/modelcontextprotocol/registry/internal/database"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
 // RegistryService defines the interface for registry operations
 type RegistryService interface {
 	// ListServers retrieve all servers with optional filtering
 	ListServers(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
+	// ListServerNames retrieve distinct server names with cursor-based pagination, optionally limited to a name prefix
+	ListServerNames(ctx context.Context, prefix string, cursor string, limit int) ([]string, string, error)
+	// ListChanges retrieve the changes to server versions made since a change token, oldest first, along with the
+	// token to resume from. An empty token starts from the first change.
+	ListChanges(ctx context.Context, since string, limit int) ([]database.Change, string, error)
 	// GetServerByName retrieve latest version of a server by server name
 	GetServerByName(ctx context.Context, serverName string) (*apiv0.ServerResponse, error)
 	// GetServerByNameAndVersion retrieve specific version of a server by server name and version
 	GetServerByNameAndVersion(ctx context.Context, serverName string, version string) (*apiv0.ServerResponse, error)
+	// CheckVersionExists check if a specific version of a server exists
+	CheckVersionExists(ctx context.Context, serverName string, version string) (bool, error)
+	// GetServerVersions retrieve the server versions identified by refs, in the order of refs, omitting those that don't exist
+	GetServerVersions(ctx context.Context, refs []database.ServerRef) ([]*apiv0.ServerResponse, error)
+	// GetServerAsOf retrieve the version of a server that was the latest at a point in time
+	GetServerAsOf(ctx context.Context, serverName string, at time.Time) (*apiv0.ServerResponse, error)
 	// GetAllVersionsByServerName retrieve all versions of a server by server name
 	GetAllVersionsByServerName(ctx context.Context, serverName string) ([]*apiv0.ServerResponse, error)
-	// CreateServer creates a new server version
+	// ValidateServer runs the publish validation pipeline on a server without storing it, returning the normalized server
+	ValidateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerJSON, error)
+	// CreateServer creates a new server version. An identical republish returns the stored version along with
+	// database.ErrAlreadyPublished.
 	CreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error)
+	// PreviewServer runs every publish check and returns the server version publishing would store, without storing it
+	PreviewServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error)
 	// UpdateServer updates an existing server and optionally its status
 	UpdateServer(ctx context.Context, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (*apiv0.ServerResponse, error)
+	// PatchServer applies a JSON merge patch to an existing server, leaving unspecified fields intact
+	PatchServer(ctx context.Context, serverName, version string, patch map[string]any) (*apiv0.ServerResponse, error)
+	// DeprecateServer marks a server version as deprecated, optionally recording the server that replaces it
+	DeprecateServer(ctx context.Context, serverName, version, replacedBy string) (*apiv0.ServerResponse, error)
+	// TransferServer moves every version of a server to a new name, optionally leaving a deprecated tombstone
+	// at the old name that points to the new one
+	TransferServer(ctx context.Context, serverName, newName string, tombstone bool) ([]*apiv0.ServerResponse, error)
+	// SetLatestVersion designates a specific server version as the latest, unmarking every other version of the server
+	SetLatestVersion(ctx context.Context, serverName, version string) (*apiv0.ServerResponse, error)
+	// ReconcileLatest recomputes the latest version of every server and fixes inconsistent latest flags,
+	// returning how many versions were corrected
+	ReconcileLatest(ctx context.Context) (int, error)
+	// Compact removes deleted and surplus server versions according to the configured retention settings,
+	// returning the versions removed. A dry run returns the versions that would be removed without removing them.
+	Compact(ctx context.Context, dryRun bool) (database.CompactResult, error)
+	// RevalidateServers runs the current publish validation against every stored server version, calling report
+	// for each one that fails, without modifying anything
+	RevalidateServers(ctx context.Context, report func(ValidationIssue) error) (RevalidationSummary, error)
+	// Flush persists any changes the database holds in memory; it is a no-op for write-through databases
+	Flush(ctx context.Context) error
+	// ReloadFromS3 downloads registry data from S3 and swaps it in, returning the number of records loaded
+	ReloadFromS3(ctx context.Context, s3URL string) (int, error)
+	// ReloadFile reloads the JSON file database from its data file, which holds data from source, recording an
+	// audit entry for each version the reload creates, changes or removes
+	ReloadFile(ctx context.Context, source string) error
+	// ImportJSONFile imports a JSON file database file holding data from source into the database, recording an
+	// audit entry for each version the import creates or changes, and returns the number of records imported
+	ImportJSONFile(ctx context.Context, filePath, source string, opts database.UpsertOptions) (int, error)
+	// PublishCatalog uploads the servers matching filter to s3://bucket/key as a JSON file database,
+	// returning the number of servers published
+	PublishCatalog(ctx context.Context, filter *database.ServerFilter, bucket, key string) (int, error)
+	// CreateSnapshot stores an immutable copy of every server version under id, or under a digest of the content
+	// when id is empty, returning the snapshot and the number of server versions in it
+	CreateSnapshot(ctx context.Context, id string) (*database.Snapshot, int, error)
+	// GetSnapshot retrieve a snapshot by ID
+	GetSnapshot(ctx context.Context, id string) (*database.Snapshot, error)
+	// CheckS3 confirms the configured S3 data file is reachable with the current credentials
+	CheckS3(ctx context.Context) error
+	// LastSync returns the last successful refresh of the database's data, and false if there has been none
+	// or the database is not refreshed from an external source
+	LastSync() (database.SyncStatus, bool)
+	// SetServerStatus changes the lifecycle status of a specific server version, with an optional human-readable reason
+	SetServerStatus(ctx context.Context, serverName, version string, status model.Status, reason string) (*apiv0.ServerResponse, error)
 }
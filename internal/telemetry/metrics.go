@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -32,6 +35,31 @@ type Metrics struct {
 
 	// Up tracks the health of the service
 	Up metric.Int64Gauge
+
+	// ImportedServers tracks seed import outcomes per server, keyed by the "outcome" attribute
+	ImportedServers metric.Int64Counter
+
+	// LastSyncTimestamp tracks when registry data was last successfully synced, in Unix seconds
+	LastSyncTimestamp metric.Int64Gauge
+
+	// SQSMessages tracks SQS notifications, keyed by the "event" attribute
+	// (received, processed, failed or deleted)
+	SQSMessages metric.Int64Counter
+
+	// SQSProcessingDuration tracks how long processing an SQS notification takes
+	SQSProcessingDuration metric.Float64Histogram
+
+	// SQSMessageAge tracks how long the latest SQS notification waited in the queue, in seconds
+	SQSMessageAge metric.Float64Gauge
+
+	// CompactionRuns tracks scheduled compaction runs, keyed by the "outcome" attribute (success or failure)
+	CompactionRuns metric.Int64Counter
+
+	// CompactedVersions tracks server versions removed by compaction, keyed by the "reason" attribute
+	CompactedVersions metric.Int64Counter
+
+	// StalePublishLocks tracks how many publish locks have been held longer than the stale lock threshold
+	StalePublishLocks metric.Int64Gauge
 }
 
 // ShutdownFunc is a delegate that shuts down the OpenTelemetry components.
@@ -73,11 +101,89 @@ func NewMetrics(meter metric.Meter) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create service up gauge: %w", err)
 	}
 
+	importedServers, err := meter.Int64Counter(
+		Namespace+".importer.servers",
+		metric.WithDescription("Total number of servers processed by seed imports, by outcome"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create imported servers counter: %w", err)
+	}
+
+	lastSync, err := meter.Int64Gauge(
+		Namespace+".sync.last_success_timestamp",
+		metric.WithDescription("Unix time in seconds of the last successful registry data sync"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create last sync gauge: %w", err)
+	}
+
+	sqsMessages, err := meter.Int64Counter(
+		Namespace+".sqs.messages",
+		metric.WithDescription("Total number of SQS notifications handled, by event"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQS messages counter: %w", err)
+	}
+
+	sqsDuration, err := meter.Float64Histogram(
+		Namespace+".sqs.processing.duration",
+		metric.WithDescription("Duration of processing an SQS notification in seconds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.1, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0, 120.0,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQS processing duration histogram: %w", err)
+	}
+
+	sqsAge, err := meter.Float64Gauge(
+		Namespace+".sqs.message.age",
+		metric.WithDescription("Time the most recently received SQS notification spent in the queue, in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQS message age gauge: %w", err)
+	}
+
+	compactionRuns, err := meter.Int64Counter(
+		Namespace+".compaction.runs",
+		metric.WithDescription("Total number of scheduled compaction runs, by outcome"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compaction runs counter: %w", err)
+	}
+
+	compactedVersions, err := meter.Int64Counter(
+		Namespace+".compaction.removed_versions",
+		metric.WithDescription("Total number of server versions removed by compaction, by reason"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compacted versions counter: %w", err)
+	}
+
+	stalePublishLocks, err := meter.Int64Gauge(
+		Namespace+".publish_locks.stale",
+		metric.WithDescription("Number of publish locks held longer than the stale lock threshold"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stale publish locks gauge: %w", err)
+	}
+
 	return &Metrics{
-		Requests:        req,
-		RequestDuration: reqDuration,
-		ErrorCount:      errCount,
-		Up:              up,
+		Requests:              req,
+		RequestDuration:       reqDuration,
+		ErrorCount:            errCount,
+		Up:                    up,
+		ImportedServers:       importedServers,
+		LastSyncTimestamp:     lastSync,
+		SQSMessages:           sqsMessages,
+		SQSProcessingDuration: sqsDuration,
+		SQSMessageAge:         sqsAge,
+		CompactionRuns:        compactionRuns,
+		CompactedVersions:     compactedVersions,
+		StalePublishLocks:     stalePublishLocks,
 	}, nil
 }
 
@@ -140,8 +246,89 @@ func InitMetrics(version string) (ShutdownFunc, *Metrics, error) {
 	return shutdown, metrics, err
 }
 
+// InitFunc initializes the metrics pipeline for a registry version, as InitMetrics does
+type InitFunc func(version string) (ShutdownFunc, *Metrics, error)
+
+// InitMetricsOrNoop initializes metrics with init, falling back to a NoopRecorder when that fails so a
+// misconfigured telemetry pipeline doesn't stop the registry from serving traffic
+func InitMetricsOrNoop(version string, init InitFunc) (ShutdownFunc, Recorder) {
+	shutdown, metrics, err := init(version)
+	if err == nil {
+		return shutdown, metrics
+	}
+
+	log.Printf("Warning: failed to initialize metrics, continuing without them: %v", err)
+	if shutdown == nil {
+		shutdown = func(_ context.Context) error { return nil }
+	}
+	return shutdown, NoopRecorder{}
+}
+
 // PrometheusHandler returns the HTTP handler for Prometheus metrics
 // This handler serves the metrics endpoint for Prometheus to scrape.
 func (m *Metrics) PrometheusHandler() http.Handler {
 	return promhttp.Handler()
 }
+
+var _ Recorder = (*Metrics)(nil)
+
+func (m *Metrics) RecordRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("path", path),
+		attribute.Int("status_code", statusCode),
+	)
+	m.Requests.Add(ctx, 1, attrs)
+	if statusCode >= 400 {
+		m.ErrorCount.Add(ctx, 1, attrs)
+	}
+	m.RequestDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+func (m *Metrics) RecordHealth(ctx context.Context, path, version string, up bool) {
+	// Up status (1 = healthy, 0 = unhealthy)
+	value := int64(0)
+	if up {
+		value = 1
+	}
+	m.Up.Record(ctx, value, metric.WithAttributes(
+		attribute.String("path", path),
+		attribute.String("version", version),
+		attribute.String("service", Namespace),
+	))
+}
+
+func (m *Metrics) RecordLastSync(ctx context.Context, at time.Time, source string) {
+	m.LastSyncTimestamp.Record(ctx, at.Unix(), metric.WithAttributes(
+		attribute.String("source", source),
+		attribute.String("service", Namespace),
+	))
+}
+
+func (m *Metrics) RecordImportedServers(ctx context.Context, outcome string, n int) {
+	m.ImportedServers.Add(ctx, int64(n), metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+func (m *Metrics) RecordSQSMessage(ctx context.Context, event string) {
+	m.SQSMessages.Add(ctx, 1, metric.WithAttributes(attribute.String("event", event)))
+}
+
+func (m *Metrics) RecordSQSProcessingDuration(ctx context.Context, duration time.Duration) {
+	m.SQSProcessingDuration.Record(ctx, duration.Seconds())
+}
+
+func (m *Metrics) RecordSQSMessageAge(ctx context.Context, age time.Duration) {
+	m.SQSMessageAge.Record(ctx, age.Seconds())
+}
+
+func (m *Metrics) RecordCompactionRun(ctx context.Context, outcome string) {
+	m.CompactionRuns.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+func (m *Metrics) RecordCompactedVersion(ctx context.Context, reason string) {
+	m.CompactedVersions.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+func (m *Metrics) RecordStalePublishLocks(ctx context.Context, n int) {
+	m.StalePublishLocks.Record(ctx, int64(n))
+}
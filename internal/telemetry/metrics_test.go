@@ -1,6 +1,7 @@
 package telemetry_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -82,3 +83,23 @@ func TestNewPrometheusMeterProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestInitMetricsOrNoop(t *testing.T) {
+	t.Run("failed initialization falls back to no-op metrics", func(t *testing.T) {
+		shutdown, metrics := telemetry.InitMetricsOrNoop("test", func(string) (telemetry.ShutdownFunc, *telemetry.Metrics, error) {
+			return nil, nil, errors.New("exporter unavailable")
+		})
+
+		assert.Equal(t, telemetry.NoopRecorder{}, metrics)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("successful initialization is used as is", func(t *testing.T) {
+		want, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+		assert.NoError(t, err)
+		_, metrics := telemetry.InitMetricsOrNoop("test", func(string) (telemetry.ShutdownFunc, *telemetry.Metrics, error) {
+			return func(context.Context) error { return nil }, want, nil
+		})
+		assert.Same(t, want, metrics)
+	})
+}
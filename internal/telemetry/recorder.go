@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Recorder records the registry's metrics. *Metrics records them with OpenTelemetry, while NoopRecorder
+// discards them for when metrics are disabled and serves as a base for fakes in tests.
+type Recorder interface {
+	// RecordRequest records a handled HTTP request, counting it as an error when the status code is 400 or above
+	RecordRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration)
+	// RecordHealth records the outcome of a health check of the given path
+	RecordHealth(ctx context.Context, path, version string, up bool)
+	// RecordLastSync records when registry data was last successfully synced and where it came from
+	RecordLastSync(ctx context.Context, at time.Time, source string)
+	// RecordImportedServers records n servers processed by a seed import with the given outcome
+	RecordImportedServers(ctx context.Context, outcome string, n int)
+	// RecordSQSMessage records an SQS notification event (received, processed, failed or deleted)
+	RecordSQSMessage(ctx context.Context, event string)
+	// RecordSQSProcessingDuration records how long processing an SQS notification took
+	RecordSQSProcessingDuration(ctx context.Context, duration time.Duration)
+	// RecordSQSMessageAge records how long an SQS notification waited in the queue
+	RecordSQSMessageAge(ctx context.Context, age time.Duration)
+	// RecordCompactionRun records a scheduled compaction run with the given outcome (success or failure)
+	RecordCompactionRun(ctx context.Context, outcome string)
+	// RecordCompactedVersion records a server version removed by compaction for the given reason
+	RecordCompactedVersion(ctx context.Context, reason string)
+	// RecordStalePublishLocks records how many publish locks are held longer than the stale lock threshold
+	RecordStalePublishLocks(ctx context.Context, n int)
+}
+
+// Scrapable is implemented by recorders whose metrics can be scraped over HTTP
+type Scrapable interface {
+	// PrometheusHandler returns the HTTP handler serving the metrics to Prometheus
+	PrometheusHandler() http.Handler
+}
+
+// NoopRecorder is a Recorder that records nothing
+type NoopRecorder struct{}
+
+var _ Recorder = NoopRecorder{}
+
+func (NoopRecorder) RecordRequest(context.Context, string, string, int, time.Duration) {}
+func (NoopRecorder) RecordHealth(context.Context, string, string, bool)                {}
+func (NoopRecorder) RecordLastSync(context.Context, time.Time, string)                 {}
+func (NoopRecorder) RecordImportedServers(context.Context, string, int)                {}
+func (NoopRecorder) RecordSQSMessage(context.Context, string)                          {}
+func (NoopRecorder) RecordSQSProcessingDuration(context.Context, time.Duration)        {}
+func (NoopRecorder) RecordSQSMessageAge(context.Context, time.Duration)                {}
+func (NoopRecorder) RecordCompactionRun(context.Context, string)                       {}
+func (NoopRecorder) RecordCompactedVersion(context.Context, string)                    {}
+func (NoopRecorder) RecordStalePublishLocks(context.Context, int)                      {}
@@ -19,6 +19,8 @@ var (
 	// Registry validation errors
 	ErrUnsupportedRegistryBaseURL   = errors.New("unsupported registry base URL")
 	ErrMismatchedRegistryTypeAndURL = errors.New("registry type and base URL do not match")
+	ErrDisallowedPackageRegistry    = errors.New("package registry type is not allowed on this registry")
+	ErrUnpinnedOCIPackage           = errors.New("OCI package must be pinned to an image digest")
 
 	// Argument validation errors
 	ErrNamedArgumentNameRequired     = errors.New("named argument name is required")
@@ -29,6 +31,18 @@ var (
 	// Server name validation errors
 	ErrMultipleSlashesInServerName = errors.New("server name cannot contain multiple slashes")
 	ErrInvalidServerNameFormat     = errors.New("server name format is invalid")
+
+	// Provenance validation errors
+	ErrNoAttestations         = errors.New("provenance must include at least one attestation")
+	ErrUnknownAttestationType = errors.New("unknown attestation type")
+	ErrInvalidAttestationURL  = errors.New("invalid attestation URL")
+	ErrInvalidAttestationHash = errors.New("invalid attestation hash")
+
+	// Tag validation errors
+	ErrTooManyTags   = errors.New("too many tags")
+	ErrInvalidTag    = errors.New("invalid tag")
+	ErrDuplicateTag  = errors.New("duplicate tag")
+	ErrTagNotAllowed = errors.New("tag is not allowed")
 )
 
 // RepositorySource represents valid repository sources
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError describes a single validation failure of a server.json field
+type FieldError struct {
+	Field   string `json:"field" doc:"Path of the invalid field in server.json" example:"packages[0]"`
+	Message string `json:"message" doc:"Description of the problem" example:"package name cannot contain spaces"`
+	err     error
+}
+
+// ValidationErrors collects every validation failure found in a server.json, so publishers can
+// fix all problems in one pass instead of one per attempt
+type ValidationErrors []FieldError
+
+// Error joins the messages of all failures
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap exposes the underlying errors so errors.Is matches any of the failures
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr.err
+	}
+	return errs
+}
+
+// add records err against field; nil errors are ignored so checks can be added unconditionally
+func (e *ValidationErrors) add(field string, err error) {
+	if err == nil {
+		return
+	}
+	*e = append(*e, FieldError{Field: field, Message: err.Error(), err: err})
+}
+
+// merge adds the failures of err, flattening nested ValidationErrors
+func (e *ValidationErrors) merge(err error) {
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		*e = append(*e, nested...)
+		return
+	}
+	e.add("", err)
+}
+
+// errOrNil returns the collected failures as an error, or nil if there were none
+func (e ValidationErrors) errOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
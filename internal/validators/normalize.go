@@ -0,0 +1,61 @@
+package validators
+
+import (
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// packageKey identifies a package entry for de-duplication
+type packageKey struct {
+	registryType string
+	identifier   string
+	version      string
+}
+
+// remoteKey identifies a remote entry for de-duplication
+type remoteKey struct {
+	transportType string
+	url           string
+}
+
+// DeduplicateEntries removes repeated packages and remotes from a server.json, keeping the first
+// occurrence of each in its original position. Packages are identified by registry type, identifier
+// and version; remotes by transport type and URL.
+func DeduplicateEntries(serverJSON *apiv0.ServerJSON) {
+	serverJSON.Packages = dedupeBy(serverJSON.Packages, func(pkg model.Package) packageKey {
+		return packageKey{registryType: pkg.RegistryType, identifier: pkg.Identifier, version: pkg.Version}
+	})
+	serverJSON.Remotes = dedupeBy(serverJSON.Remotes, func(remote model.Transport) remoteKey {
+		return remoteKey{transportType: remote.Type, url: remote.URL}
+	})
+}
+
+// dedupeBy returns items without the entries whose key was already seen. A new slice is
+// allocated when anything is removed, so the caller's backing array is never modified.
+func dedupeBy[T any, K comparable](items []T, key func(T) K) []T {
+	if len(items) < 2 {
+		return items
+	}
+
+	seen := make(map[K]bool, len(items))
+	var unique []T
+	for i, item := range items {
+		k := key(item)
+		if seen[k] {
+			if unique == nil {
+				// First duplicate: copy the entries kept so far
+				unique = append(make([]T, 0, len(items)-1), items[:i]...)
+			}
+			continue
+		}
+		seen[k] = true
+		if unique != nil {
+			unique = append(unique, item)
+		}
+	}
+
+	if unique == nil {
+		return items
+	}
+	return unique
+}
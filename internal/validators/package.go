@@ -3,8 +3,12 @@ package validators
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
@@ -27,3 +31,51 @@ func ValidatePackage(ctx context.Context, pkg model.Package, serverName string)
 		return fmt.Errorf("unsupported registry type: %s", pkg.RegistryType)
 	}
 }
+
+// ValidateAllowedPackageRegistries checks that every package uses one of the allowed registry types.
+// An empty allowlist allows all registry types. The error names each offending package.
+func ValidateAllowedPackageRegistries(serverJSON apiv0.ServerJSON, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	var offending []string
+	for i, pkg := range serverJSON.Packages {
+		isAllowed := slices.ContainsFunc(allowed, func(registryType string) bool {
+			return strings.EqualFold(strings.TrimSpace(registryType), pkg.RegistryType)
+		})
+		if !isAllowed {
+			offending = append(offending, fmt.Sprintf("packages[%d] (%s) uses registry type '%s'", i, pkg.Identifier, pkg.RegistryType))
+		}
+	}
+
+	if len(offending) > 0 {
+		return fmt.Errorf("%w: %s; allowed registry types: %s", ErrDisallowedPackageRegistry, strings.Join(offending, ", "), strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// ValidateOCIDigestPinning checks that every OCI package references its image by digest
+// (e.g. 'ghcr.io/owner/image@sha256:...'), so the published image can't change under a mutable tag.
+// The error names each offending package.
+func ValidateOCIDigestPinning(serverJSON apiv0.ServerJSON) error {
+	var offending []string
+	for i, pkg := range serverJSON.Packages {
+		if pkg.RegistryType != model.RegistryTypeOCI {
+			continue
+		}
+		ref, err := name.ParseReference(pkg.Identifier)
+		if err != nil {
+			offending = append(offending, fmt.Sprintf("packages[%d] (%s) is not a valid OCI reference", i, pkg.Identifier))
+			continue
+		}
+		if _, pinned := ref.(name.Digest); !pinned {
+			offending = append(offending, fmt.Sprintf("packages[%d] (%s) uses a tag without a digest", i, pkg.Identifier))
+		}
+	}
+
+	if len(offending) > 0 {
+		return fmt.Errorf("%w: %s; reference images as 'image@sha256:<digest>'", ErrUnpinnedOCIPackage, strings.Join(offending, ", "))
+	}
+	return nil
+}
@@ -52,73 +52,61 @@ var (
 	dottedVersionLikeRe = regexp.MustCompile(`^\s*(?:v?\d+|x|X|\*)(?:\.(?:\d+|x|X|\*)){1,2}(?:-[0-9A-Za-z.-]+)?\s*$`)
 )
 
+// ValidateServerJSON validates the structure of a server.json, reporting every invalid field
+// rather than stopping at the first one. The returned error is a ValidationErrors.
 func ValidateServerJSON(serverJSON *apiv0.ServerJSON) error {
+	var errs ValidationErrors
+
 	// Validate schema version is provided and supported
 	// Note: Schema field is also marked as required in the ServerJSON struct definition
 	// for API-level validation and documentation
-	if serverJSON.Schema == "" {
-		return fmt.Errorf("$schema field is required")
-	}
-	if !strings.Contains(serverJSON.Schema, model.CurrentSchemaVersion) {
-		return fmt.Errorf("schema version %s is not supported. Please use schema version %s", serverJSON.Schema, model.CurrentSchemaVersion)
+	switch {
+	case serverJSON.Schema == "":
+		errs.add("$schema", fmt.Errorf("$schema field is required"))
+	case !strings.Contains(serverJSON.Schema, model.CurrentSchemaVersion):
+		errs.add("$schema", fmt.Errorf("schema version %s is not supported. Please use schema version %s", serverJSON.Schema, model.CurrentSchemaVersion))
 	}
 
 	// Validate server name exists and format
-	if _, err := parseServerName(*serverJSON); err != nil {
-		return err
-	}
+	_, nameErr := parseServerName(*serverJSON)
+	errs.add("name", nameErr)
 
 	// Validate top-level server version is a specific version (not a range) & not "latest"
-	if err := validateVersion(serverJSON.Version); err != nil {
-		return err
-	}
+	errs.add("version", validateVersion(serverJSON.Version))
 
 	// Validate repository
-	if err := validateRepository(serverJSON.Repository); err != nil {
-		return err
-	}
+	errs.add("repository", validateRepository(serverJSON.Repository))
 
 	// Validate website URL if provided
-	if err := validateWebsiteURL(serverJSON.WebsiteURL); err != nil {
-		return err
-	}
+	errs.add("websiteUrl", validateWebsiteURL(serverJSON.WebsiteURL))
 
 	// Validate title if provided
-	if err := validateTitle(serverJSON.Title); err != nil {
-		return err
-	}
+	errs.add("title", validateTitle(serverJSON.Title))
 
 	// Validate icons if provided
-	if err := validateIcons(serverJSON.Icons); err != nil {
-		return err
-	}
+	errs.add("icons", validateIcons(serverJSON.Icons))
 
 	// Validate all packages (basic field validation)
 	// Detailed package validation (including registry checks) is done during publish
-	for _, pkg := range serverJSON.Packages {
-		if err := validatePackageField(&pkg); err != nil {
-			return err
-		}
+	for i, pkg := range serverJSON.Packages {
+		errs.add(fmt.Sprintf("packages[%d]", i), validatePackageField(&pkg))
 	}
 
 	// Validate all remotes
-	for _, remote := range serverJSON.Remotes {
-		if err := validateRemoteTransport(&remote); err != nil {
-			return err
-		}
+	for i, remote := range serverJSON.Remotes {
+		errs.add(fmt.Sprintf("remotes[%d]", i), validateRemoteTransport(&remote))
 	}
 
-	// Validate reverse-DNS namespace matching for remote URLs
-	if err := validateRemoteNamespaceMatch(*serverJSON); err != nil {
-		return err
-	}
+	// Namespace matching needs a well-formed name; an invalid name has already been reported
+	if nameErr == nil {
+		// Validate reverse-DNS namespace matching for remote URLs
+		errs.add("remotes", validateRemoteNamespaceMatch(*serverJSON))
 
-	// Validate reverse-DNS namespace matching for website URL
-	if err := validateWebsiteURLNamespaceMatch(*serverJSON); err != nil {
-		return err
+		// Validate reverse-DNS namespace matching for website URL
+		errs.add("websiteUrl", validateWebsiteURLNamespaceMatch(*serverJSON))
 	}
 
-	return nil
+	return errs.errOrNil()
 }
 
 func validateRepository(obj *model.Repository) error {
@@ -424,27 +412,92 @@ func validateRemoteTransport(obj *model.Transport) error {
 	}
 }
 
-// ValidatePublishRequest validates a complete publish request including extensions
+// ValidatePublishRequest validates a complete publish request including extensions.
+// All failures are reported together as a ValidationErrors.
 func ValidatePublishRequest(ctx context.Context, req apiv0.ServerJSON, cfg *config.Config) error {
+	var errs ValidationErrors
+
 	// Validate publisher extensions in _meta
-	if err := validatePublisherExtensions(req); err != nil {
-		return err
+	errs.add("_meta", validatePublisherExtensions(req))
+	if req.Meta != nil && req.Meta.Provenance != nil {
+		errs.add("_meta.io.modelcontextprotocol.registry/provenance", validateProvenance(req.Meta.Provenance))
 	}
-
-	// Validate the server detail (includes all nested validation)
-	if err := ValidateServerJSON(&req); err != nil {
-		return err
+	if req.Meta != nil && req.Meta.Tags != nil {
+		errs.add("_meta.io.modelcontextprotocol.registry/tags", validateTags(req.Meta.Tags, cfg.AllowedTags))
 	}
 
-	// Validate registry ownership for all packages if validation is enabled
-	if cfg.EnableRegistryValidation {
+	// Validate the server detail (includes all nested validation) and, if enabled, registry ownership
+	errs.merge(ValidateServer(ctx, req, cfg.EnableRegistryValidation))
+
+	return errs.errOrNil()
+}
+
+// ValidateServer validates a server.json and, if validateRegistries is set, the registry ownership of
+// all its packages. All failures are reported together as a ValidationErrors.
+func ValidateServer(ctx context.Context, req apiv0.ServerJSON, validateRegistries bool) error {
+	var errs ValidationErrors
+
+	errs.merge(ValidateServerJSON(&req))
+
+	if validateRegistries {
 		for i, pkg := range req.Packages {
 			if err := ValidatePackage(ctx, pkg, req.Name); err != nil {
-				return fmt.Errorf("registry validation failed for package %d (%s): %w", i, pkg.Identifier, err)
+				errs.add(fmt.Sprintf("packages[%d]", i), fmt.Errorf("registry validation failed for package %d (%s): %w", i, pkg.Identifier, err))
 			}
 		}
 	}
 
+	return errs.errOrNil()
+}
+
+// sha256Pattern matches a hex-encoded SHA-256 hash
+var sha256Pattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// validateProvenance checks that provenance references at least one attestation of a known type,
+// hosted at an HTTPS URL and with a well-formed hash
+func validateProvenance(provenance *apiv0.Provenance) error {
+	if len(provenance.Attestations) == 0 {
+		return ErrNoAttestations
+	}
+	for i, attestation := range provenance.Attestations {
+		switch attestation.Type {
+		case apiv0.AttestationTypeSigstoreBundle, apiv0.AttestationTypeInToto:
+		default:
+			return fmt.Errorf("%w: attestations[%d] has type %q", ErrUnknownAttestationType, i, attestation.Type)
+		}
+		parsed, err := url.Parse(attestation.URL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("%w: attestations[%d] url %q must be an absolute https URL", ErrInvalidAttestationURL, i, attestation.URL)
+		}
+		if attestation.SHA256 != "" && !sha256Pattern.MatchString(attestation.SHA256) {
+			return fmt.Errorf("%w: attestations[%d] sha256 must be 64 lowercase hex characters", ErrInvalidAttestationHash, i)
+		}
+	}
+	return nil
+}
+
+// maxTags is how many tags a server version can carry
+const maxTags = 10
+
+// tagPattern matches a tag: lowercase letters, digits and single hyphens, such as "devtools" or "vector-search"
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateTags checks that tags are well-formed, unique and, when an allowlist is configured, on it
+func validateTags(tags, allowed []string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("%w: %d tags given, at most %d are allowed", ErrTooManyTags, len(tags), maxTags)
+	}
+	for i, tag := range tags {
+		if len(tag) > 32 || !tagPattern.MatchString(tag) {
+			return fmt.Errorf("%w: tags[%d] %q must be up to 32 lowercase letters, digits and hyphens", ErrInvalidTag, i, tag)
+		}
+		if slices.Contains(tags[:i], tag) {
+			return fmt.Errorf("%w: %q", ErrDuplicateTag, tag)
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, tag) {
+			return fmt.Errorf("%w: %q, expected one of %s", ErrTagNotAllowed, tag, strings.Join(allowed, ", "))
+		}
+	}
 	return nil
 }
 
@@ -462,12 +515,24 @@ func validatePublisherExtensions(req apiv0.ServerJSON) error {
 		}
 	}
 
-	// Note: ServerJSON._meta only contains PublisherProvided data
-	// Official registry metadata is handled separately in the response structure
+	// The official extension only carries the initial status of a new version
+	if req.Meta != nil && req.Meta.Official != nil {
+		switch req.Meta.Official.Status {
+		case "", model.StatusActive, model.StatusDeprecated, model.StatusDeleted:
+		default:
+			return fmt.Errorf("_meta.io.modelcontextprotocol.registry/official has unknown status %q", req.Meta.Official.Status)
+		}
+	}
 
 	return nil
 }
 
+// ValidateServerName checks that a server name has the required 'dns-namespace/name' format
+func ValidateServerName(name string) error {
+	_, err := parseServerName(apiv0.ServerJSON{Name: name})
+	return err
+}
+
 func parseServerName(serverJSON apiv0.ServerJSON) (string, error) {
 	name := serverJSON.Name
 	if name == "" {
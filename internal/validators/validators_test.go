@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/validators"
@@ -2032,3 +2033,45 @@ func TestValidateTitle(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	serverJSON := apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/multi-error-server",
+		Description: "A server with several problems",
+		Version:     "^1.0.0",
+		Repository: &model.Repository{
+			URL:    "not-a-url",
+			Source: "github",
+		},
+		Packages: []model.Package{
+			{
+				RegistryType: model.RegistryTypeNPM,
+				Identifier:   "has spaces",
+				Version:      "1.0.0",
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			},
+		},
+		Remotes: []model.Transport{
+			{Type: model.TransportTypeStdio, URL: "https://example.com/mcp"},
+		},
+	}
+
+	err := validators.ValidatePublishRequest(context.Background(), serverJSON, &config.Config{EnableRegistryValidation: false})
+	require.Error(t, err)
+
+	var validationErrs validators.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+
+	fields := make([]string, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		fields[i] = fieldErr.Field
+		assert.NotEmpty(t, fieldErr.Message)
+	}
+	assert.Equal(t, []string{"version", "repository", "packages[0]", "remotes[0]"}, fields)
+
+	// Sentinel errors remain matchable through the collected failures
+	assert.ErrorIs(t, err, validators.ErrVersionLooksLikeRange)
+	assert.ErrorIs(t, err, validators.ErrInvalidRepositoryURL)
+	assert.ErrorIs(t, err, validators.ErrPackageNameHasSpaces)
+}
@@ -7,10 +7,14 @@ import (
 )
 
 type RegistryExtensions struct {
-	Status      model.Status `json:"status" enum:"active,deprecated,deleted" doc:"Server lifecycle status"`
-	PublishedAt time.Time    `json:"publishedAt" format:"date-time" doc:"Timestamp when the server was first published to the registry"`
-	UpdatedAt   time.Time    `json:"updatedAt,omitempty" format:"date-time" doc:"Timestamp when the server entry was last updated"`
-	IsLatest    bool         `json:"isLatest" doc:"Whether this is the latest version of the server"`
+	Status       model.Status `json:"status" enum:"active,deprecated,deleted" doc:"Server lifecycle status"`
+	PublishedAt  time.Time    `json:"publishedAt" format:"date-time" doc:"Timestamp when the server was first published to the registry"`
+	UpdatedAt    time.Time    `json:"updatedAt,omitempty" format:"date-time" doc:"Timestamp when the server entry was last updated"`
+	IsLatest     bool         `json:"isLatest" doc:"Whether this is the latest version of the server"`
+	LatestPinned bool         `json:"latestPinned,omitempty" doc:"Whether an administrator made this the latest version, which keeps it the latest until a newer version is published"`
+	ReplacedBy   string       `json:"replacedBy,omitempty" doc:"Name of the server that replaces this one, set when the server is deprecated" example:"io.github.user/weather-v2"`
+	StatusReason string       `json:"statusReason,omitempty" doc:"Why the server was given its current status, e.g. why it was deprecated or deleted" example:"Contains a known security vulnerability"`
+	PublishedBy  string       `json:"publishedBy,omitempty" doc:"Identity that published this version, or 'anonymous' for anonymous publishes" example:"octocat"`
 }
 
 type ResponseMeta struct {
@@ -29,6 +33,32 @@ type ServerListResponse struct {
 
 type ServerMeta struct {
 	PublisherProvided map[string]interface{} `json:"io.modelcontextprotocol.registry/publisher-provided,omitempty" doc:"Publisher-provided metadata for downstream registries"`
+	Official          *PublishExtensions     `json:"io.modelcontextprotocol.registry/official,omitempty" doc:"Registry metadata requested for a newly published version. It is applied on publish and not stored with the server."`
+	Provenance        *Provenance            `json:"io.modelcontextprotocol.registry/provenance,omitempty" doc:"Signed attestations about how this server version was built"`
+	Tags              []string               `json:"io.modelcontextprotocol.registry/tags,omitempty" maxItems:"10" doc:"Categories the server belongs to, for discovery. Each tag is lowercase letters, digits and hyphens, e.g. 'database' or 'devtools'."`
+}
+
+// Attestation types - formats of signed statements that can be attached to a server version
+const (
+	AttestationTypeSigstoreBundle = "sigstore-bundle"
+	AttestationTypeInToto         = "in-toto"
+)
+
+// Provenance references the supply-chain attestations a publisher attached to a server version
+type Provenance struct {
+	Attestations []Attestation `json:"attestations" minItems:"1" doc:"Attestations for this server version"`
+}
+
+// Attestation references a signed attestation document hosted by the publisher
+type Attestation struct {
+	Type   string `json:"type" enum:"sigstore-bundle,in-toto" doc:"Format of the attestation document" example:"sigstore-bundle"`
+	URL    string `json:"url" format:"uri" doc:"HTTPS URL the attestation document can be downloaded from" example:"https://github.com/example/weather/releases/download/v1.0.2/weather.sigstore.json"`
+	SHA256 string `json:"sha256,omitempty" pattern:"^[a-f0-9]{64}$" doc:"SHA-256 hash of the attestation document, so clients can verify the download" example:"fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce"`
+}
+
+// PublishExtensions are the registry-managed fields a publisher or importer may set for a new server version
+type PublishExtensions struct {
+	Status model.Status `json:"status,omitempty" enum:"active,deprecated,deleted" doc:"Initial lifecycle status of the version, active when omitted"`
 }
 
 type ServerJSON struct {
@@ -47,5 +77,6 @@ type ServerJSON struct {
 
 type Metadata struct {
 	NextCursor string `json:"nextCursor,omitempty" doc:"Pagination cursor for retrieving the next page of results. Use this exact value in the cursor query parameter of your next request."`
+	NextOffset int    `json:"nextOffset,omitempty" doc:"Offset of the next page of results, when paginating by offset. Use this value in the offset query parameter of your next request."`
 	Count      int    `json:"count" doc:"Number of items in current page"`
 }